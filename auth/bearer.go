@@ -0,0 +1,320 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// A Token is an OAuth2-style bearer access token.
+type Token struct {
+	// AccessToken is the token that authorizes requests.
+	AccessToken string `json:"access_token"`
+
+	// TokenType is the authorization scheme to use with the token. If
+	// empty, "Bearer" is used.
+	TokenType string `json:"token_type,omitempty"`
+
+	// RefreshToken, if non-empty, can be used to obtain a new access
+	// token when this one expires (see RefreshTokenSource).
+	RefreshToken string `json:"refresh_token,omitempty"`
+
+	// Expiry is when the access token expires. If zero, the token
+	// never expires (but it may still be rejected by the server).
+	Expiry time.Time `json:"expiry,omitempty"`
+}
+
+// expiryDelta is how long before its Expiry a token is considered
+// expired, so that it isn't rejected by the server because of clock
+// skew or request latency.
+const expiryDelta = 10 * time.Second
+
+// Valid reports whether t is non-nil, has an access token, and is not
+// expired.
+func (t *Token) Valid() bool {
+	return t != nil && t.AccessToken != "" && (t.Expiry.IsZero() || time.Now().Add(expiryDelta).Before(t.Expiry))
+}
+
+// authorization returns the value of the Authorization header that
+// authenticates a request with t.
+func (t *Token) authorization() string {
+	typ := t.TokenType
+	if typ == "" || strings.EqualFold(typ, "bearer") {
+		typ = "Bearer" // normalize the common lowercase "bearer"
+	}
+	return typ + " " + t.AccessToken
+}
+
+// A TokenSource returns tokens. BearerTokenTransport calls Token when
+// it has no token, when its token has expired, and when the server
+// rejects its token, so Token should return a new token each time it
+// is called (if it can).
+type TokenSource interface {
+	Token() (*Token, error)
+}
+
+// A ContextTokenSource is a TokenSource that can get tokens using a
+// context (for example, to cancel a request to a token endpoint).
+// BearerTokenTransport calls TokenContext instead of Token if its
+// Source implements ContextTokenSource.
+type ContextTokenSource interface {
+	TokenSource
+	TokenContext(ctx context.Context) (*Token, error)
+}
+
+// tokenContext gets a token from s, using ctx if s is a
+// ContextTokenSource.
+func tokenContext(ctx context.Context, s TokenSource) (*Token, error) {
+	if cs, ok := s.(ContextTokenSource); ok {
+		return cs.TokenContext(ctx)
+	}
+	return s.Token()
+}
+
+// StaticTokenSource is a TokenSource that always returns the same
+// token. It is for tokens that can't be refreshed.
+type StaticTokenSource Token
+
+// Token implements TokenSource.
+func (s *StaticTokenSource) Token() (*Token, error) {
+	tok := Token(*s)
+	return &tok, nil
+}
+
+// RefreshTokenSource is a TokenSource that obtains new access tokens
+// using the OAuth2 refresh token grant (RFC 6749, section 6).
+type RefreshTokenSource struct {
+	// TokenURL is the URL of the OAuth2 token endpoint.
+	TokenURL string
+
+	// ClientID and ClientSecret are the OAuth2 client credentials
+	// (which are sent using HTTP Basic authentication). They may be
+	// empty if the token endpoint doesn't require them.
+	ClientID, ClientSecret string
+
+	// RefreshToken is the refresh token to use. If the token endpoint
+	// returns a new refresh token, it replaces this one.
+	RefreshToken string
+
+	// HTTPClient is the HTTP client used to call the token endpoint.
+	// If nil, a client that uses http.DefaultTransport and times out
+	// after refreshTimeout is used.
+	HTTPClient *http.Client
+
+	mu sync.Mutex // protects RefreshToken
+}
+
+// refreshTimeout is the timeout of requests to token endpoints made by
+// RefreshTokenSource's default HTTP client and by BearerTokenTransport
+// (so that a stalled token endpoint doesn't block requests forever).
+const refreshTimeout = 30 * time.Second
+
+var defaultRefreshClient = &http.Client{Timeout: refreshTimeout}
+
+// Token implements TokenSource.
+func (s *RefreshTokenSource) Token() (*Token, error) {
+	return s.TokenContext(context.Background())
+}
+
+// TokenContext implements ContextTokenSource.
+func (s *RefreshTokenSource) TokenContext(ctx context.Context) (*Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.RefreshToken == "" {
+		return nil, errors.New("auth: no refresh token")
+	}
+	form := url.Values{"grant_type": {"refresh_token"}, "refresh_token": {s.RefreshToken}}
+	req, err := http.NewRequestWithContext(ctx, "POST", s.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if s.ClientID != "" || s.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(s.ClientID), url.QueryEscape(s.ClientSecret))
+	}
+
+	httpClient := s.HTTPClient
+	if httpClient == nil {
+		httpClient = defaultRefreshClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("auth: refreshing token: %s", err)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("auth: refreshing token: %s", err)
+	}
+	if c := resp.StatusCode; c < 200 || c > 299 {
+		return nil, fmt.Errorf("auth: refreshing token: %s: %s", resp.Status, body)
+	}
+
+	var tr struct {
+		Token
+		ExpiresIn int64 `json:"expires_in"` // seconds
+	}
+	if err := json.Unmarshal(body, &tr); err != nil {
+		return nil, fmt.Errorf("auth: refreshing token: %s", err)
+	}
+	if tr.AccessToken == "" {
+		return nil, errors.New("auth: refreshing token: server returned no access token")
+	}
+	tok := &tr.Token
+	if tr.ExpiresIn > 0 {
+		tok.Expiry = time.Now().Add(time.Duration(tr.ExpiresIn) * time.Second)
+	}
+	if tok.RefreshToken != "" {
+		s.RefreshToken = tok.RefreshToken
+	} else {
+		tok.RefreshToken = s.RefreshToken
+	}
+	return tok, nil
+}
+
+// BearerTokenTransport is an HTTP transport that adds an
+// "Authorization: Bearer xxx" header to requests, using tokens from a
+// TokenSource.
+//
+// It gets a new token from the TokenSource when it has none, when its
+// token has expired, and when the server responds to a request with
+// 401 Unauthorized (in which case the request is resent once with the
+// new token, if its body can be replayed). Concurrent requests that
+// need a new token wait for a single call to the TokenSource. A
+// request stops waiting when its context is done, and the call to the
+// TokenSource is given up after refreshTimeout (if the TokenSource is
+// a ContextTokenSource).
+type BearerTokenTransport struct {
+	// Source provides tokens.
+	Source TokenSource
+
+	// Transport is the underlying HTTP transport to use. If nil,
+	// http.DefaultTransport is used.
+	Transport http.RoundTripper
+
+	mu         sync.Mutex // protects tok and refreshing
+	tok        *Token
+	refreshing *tokenRefresh // the call to Source in progress, if any
+}
+
+// A tokenRefresh is a call to a BearerTokenTransport's Source. Its
+// tok and err are set before done is closed.
+type tokenRefresh struct {
+	done chan struct{}
+	tok  *Token
+	err  error
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *BearerTokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var transport http.RoundTripper
+	if t.Transport != nil {
+		transport = t.Transport
+	} else {
+		transport = http.DefaultTransport
+	}
+
+	tok, err := t.token(req.Context(), nil)
+	if err != nil {
+		closeBody(req)
+		return nil, err
+	}
+
+	// To set extra headers, we must make a copy of the Request so
+	// that we don't modify the Request we were given. This is
+	// required by the specification of http.RoundTripper.
+	req2 := cloneRequest(req)
+	req2.Header.Set("Authorization", tok.authorization())
+	resp, err := transport.RoundTrip(req2)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return resp, nil // can't resend the request body
+	}
+
+	// The server rejected the token, so get a new one and try again.
+	newTok, err := t.token(req.Context(), tok)
+	if err != nil || newTok.AccessToken == tok.AccessToken {
+		return resp, nil
+	}
+	req3 := cloneRequest(req)
+	if req.GetBody != nil {
+		if req3.Body, err = req.GetBody(); err != nil {
+			return resp, nil
+		}
+	}
+	resp.Body.Close()
+	req3.Header.Set("Authorization", newTok.authorization())
+	return transport.RoundTrip(req3)
+}
+
+// token returns a valid token, getting a new one from t.Source if
+// t has no valid token or if its token is rejected (i.e., the server
+// rejected it). The transport's mutex is not held while t.Source is
+// called, so requests that have a valid token aren't blocked by it.
+func (t *BearerTokenTransport) token(ctx context.Context, rejected *Token) (*Token, error) {
+	t.mu.Lock()
+	if t.tok != rejected && t.tok.Valid() {
+		tok := t.tok
+		t.mu.Unlock()
+		return tok, nil
+	}
+	r := t.refreshing
+	if r == nil {
+		r = &tokenRefresh{done: make(chan struct{})}
+		t.refreshing = r
+		// The call is shared by all requests that need a new token,
+		// so it isn't canceled with the request that started it.
+		go t.refresh(context.WithoutCancel(ctx), r)
+	}
+	t.mu.Unlock()
+
+	select {
+	case <-r.done:
+		return r.tok, r.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// refresh gets a new token from t.Source and completes r.
+func (t *BearerTokenTransport) refresh(ctx context.Context, r *tokenRefresh) {
+	ctx, cancel := context.WithTimeout(ctx, refreshTimeout)
+	defer cancel()
+	r.tok, r.err = tokenContext(ctx, t.Source)
+
+	t.mu.Lock()
+	if r.err == nil {
+		t.tok = r.tok
+	}
+	t.refreshing = nil
+	t.mu.Unlock()
+	close(r.done)
+}
+
+// Token returns the transport's current token (which may be nil or
+// expired). It can be used to persist refreshed tokens.
+func (t *BearerTokenTransport) Token() *Token {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.tok
+}
+
+// closeBody closes req's body, as http.RoundTripper implementations
+// must do even when they return an error.
+func closeBody(req *http.Request) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+}
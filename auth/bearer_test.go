@@ -0,0 +1,218 @@
+package auth
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// tokenSourceFunc is a func that implements TokenSource.
+type tokenSourceFunc func() (*Token, error)
+
+func (f tokenSourceFunc) Token() (*Token, error) { return f() }
+
+func TestRefreshTokenSource(t *testing.T) {
+	var calls int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if user, pass, _ := r.BasicAuth(); user != "id" || pass != "secret" {
+			t.Errorf("got client credentials %q and %q, want id and secret", user, pass)
+		}
+		wantRefresh := "r1"
+		if calls > 1 {
+			wantRefresh = "r2"
+		}
+		if r.FormValue("grant_type") != "refresh_token" || r.FormValue("refresh_token") != wantRefresh {
+			t.Errorf("call %d: got form %v, want refresh_token grant with %s", calls, r.Form, wantRefresh)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if calls == 1 {
+			w.Write([]byte(`{"access_token":"a1","token_type":"bearer","expires_in":3600,"refresh_token":"r2"}`))
+		} else {
+			w.Write([]byte(`{"access_token":"a2"}`))
+		}
+	}))
+	defer ts.Close()
+
+	s := &RefreshTokenSource{TokenURL: ts.URL, ClientID: "id", ClientSecret: "secret", RefreshToken: "r1"}
+	tok, err := s.Token()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tok.AccessToken != "a1" || tok.RefreshToken != "r2" || !tok.Valid() || tok.Expiry.Before(time.Now().Add(59*time.Minute)) {
+		t.Errorf("got token %+v, want a1 expiring in an hour with refresh token r2", tok)
+	}
+	if got, want := tok.authorization(), "Bearer a1"; got != want {
+		t.Errorf("got Authorization %q, want %q", got, want)
+	}
+
+	// The rotated refresh token is used, and kept if the server doesn't
+	// return a new one.
+	tok, err = s.TokenContext(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tok.AccessToken != "a2" || tok.RefreshToken != "r2" || !tok.Expiry.IsZero() {
+		t.Errorf("got token %+v, want a2 with refresh token r2 and no expiry", tok)
+	}
+}
+
+func TestRefreshTokenSource_error(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid_grant", http.StatusBadRequest)
+	}))
+	defer ts.Close()
+
+	s := &RefreshTokenSource{TokenURL: ts.URL, RefreshToken: "r"}
+	if _, err := s.Token(); err == nil || !strings.Contains(err.Error(), "invalid_grant") {
+		t.Errorf("got error %v, want invalid_grant error", err)
+	}
+	if _, err := (&RefreshTokenSource{TokenURL: ts.URL}).Token(); err == nil {
+		t.Error("got nil error with no refresh token, want non-nil")
+	}
+}
+
+func TestRefreshTokenSource_contextCanceled(t *testing.T) {
+	stop := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-stop // stall
+	}))
+	defer ts.Close()
+	defer close(stop)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	s := &RefreshTokenSource{TokenURL: ts.URL, RefreshToken: "r"}
+	if _, err := s.TokenContext(ctx); err == nil {
+		t.Error("got nil error from stalled token endpoint, want non-nil")
+	}
+}
+
+func TestBearerTokenTransport_retryAfter401(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		bodies []string
+	)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		mu.Lock()
+		bodies = append(bodies, r.Header.Get("Authorization")+" "+string(body))
+		mu.Unlock()
+		if r.Header.Get("Authorization") != "Bearer new" {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer ts.Close()
+
+	var calls int
+	transport := &BearerTokenTransport{Source: tokenSourceFunc(func() (*Token, error) {
+		calls++
+		if calls == 1 {
+			return &Token{AccessToken: "old"}, nil
+		}
+		return &Token{AccessToken: "new"}, nil
+	})}
+	c := &http.Client{Transport: transport}
+
+	resp, err := c.Post(ts.URL, "text/plain", strings.NewReader("x"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("got status %d, want 200", resp.StatusCode)
+	}
+	if want := []string{"Bearer old x", "Bearer new x"}; strings.Join(bodies, ",") != strings.Join(want, ",") {
+		t.Errorf("got requests %q, want %q", bodies, want)
+	}
+	if tok := transport.Token(); tok == nil || tok.AccessToken != "new" {
+		t.Errorf("got transport token %+v, want new", tok)
+	}
+
+	// A request whose new token is rejected too is only retried once.
+	var attempts int32
+	rejecting := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer rejecting.Close()
+	var n int
+	c.Transport = &BearerTokenTransport{Source: tokenSourceFunc(func() (*Token, error) {
+		n++
+		return &Token{AccessToken: strings.Repeat("t", n)}, nil
+	})}
+	resp, err = c.Get(rejecting.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized || attempts != 2 {
+		t.Errorf("got status %d after %d requests, want 401 after 2", resp.StatusCode, attempts)
+	}
+}
+
+func TestBearerTokenTransport_singleFlight(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer t" {
+			t.Errorf("got Authorization %q, want Bearer t", r.Header.Get("Authorization"))
+		}
+	}))
+	defer ts.Close()
+
+	var calls int32
+	release := make(chan struct{})
+	c := &http.Client{Transport: &BearerTokenTransport{Source: tokenSourceFunc(func() (*Token, error) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return &Token{AccessToken: "t"}, nil
+	})}}
+
+	const n = 10
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := c.Get(ts.URL)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			resp.Body.Close()
+		}()
+	}
+	time.Sleep(20 * time.Millisecond) // let the requests wait for the token
+	close(release)
+	wg.Wait()
+
+	if calls != 1 {
+		t.Errorf("got %d calls to the token source, want 1", calls)
+	}
+}
+
+func TestBearerTokenTransport_stalledSource(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	release := make(chan struct{})
+	defer close(release)
+	c := &http.Client{Transport: &BearerTokenTransport{Source: tokenSourceFunc(func() (*Token, error) {
+		<-release
+		return &Token{AccessToken: "t"}, nil
+	})}}
+
+	// The request gives up waiting for the token when its context is
+	// done.
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, "GET", ts.URL, nil)
+	if _, err := c.Do(req); err == nil {
+		t.Error("got nil error while the token source is stalled, want non-nil")
+	}
+}
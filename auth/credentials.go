@@ -0,0 +1,174 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Credentials are the OAuth2 credentials used to authenticate to the
+// Sourcegraph API. They can be stored in a credentials file (as JSON)
+// or given in environment variables (see LoadCredentials).
+type Credentials struct {
+	// AccessToken is the current access token, if any.
+	AccessToken string `json:"access_token,omitempty"`
+
+	// Expiry is when AccessToken expires. If zero, it never expires.
+	Expiry time.Time `json:"expiry,omitempty"`
+
+	// RefreshToken, TokenURL, ClientID and ClientSecret are used to
+	// obtain new access tokens (see RefreshTokenSource). If
+	// RefreshToken or TokenURL is empty, tokens are not refreshed.
+	RefreshToken string `json:"refresh_token,omitempty"`
+	TokenURL     string `json:"token_url,omitempty"`
+	ClientID     string `json:"client_id,omitempty"`
+	ClientSecret string `json:"client_secret,omitempty"`
+}
+
+// Environment variables that LoadCredentials reads. Each one
+// overrides the corresponding field of the credentials file.
+const (
+	EnvCredentialsFile = "SRC_CREDENTIALS_FILE" // path of the credentials file
+	EnvAccessToken     = "SRC_ACCESS_TOKEN"
+	EnvRefreshToken    = "SRC_REFRESH_TOKEN"
+	EnvTokenURL        = "SRC_TOKEN_URL"
+	EnvClientID        = "SRC_CLIENT_ID"
+	EnvClientSecret    = "SRC_CLIENT_SECRET"
+)
+
+// ErrNoCredentials is returned by LoadCredentials when neither the
+// credentials file nor the environment provide credentials.
+var ErrNoCredentials = errors.New("auth: no Sourcegraph API credentials found")
+
+// CredentialsFile returns the path of the credentials file: the value
+// of the SRC_CREDENTIALS_FILE environment variable if it is set, and
+// otherwise ".sourcegraph-credentials.json" in the user's home
+// directory.
+func CredentialsFile() string {
+	if path := os.Getenv(EnvCredentialsFile); path != "" {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".sourcegraph-credentials.json")
+}
+
+// ReadCredentialsFile reads credentials from a JSON file.
+func ReadCredentialsFile(path string) (*Credentials, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var c Credentials
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// WriteCredentialsFile writes credentials to a JSON file that only
+// the current user can read (for example, to save a refreshed token).
+func WriteCredentialsFile(path string, c *Credentials) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, append(data, '\n'), 0600)
+}
+
+// LoadCredentials reads credentials from the credentials file (see
+// CredentialsFile), if it exists, and then applies the overrides
+// given in environment variables (SRC_ACCESS_TOKEN, etc.). It returns
+// ErrNoCredentials if neither provides an access token or a refresh
+// token.
+func LoadCredentials() (*Credentials, error) {
+	c := &Credentials{}
+	if path := CredentialsFile(); path != "" {
+		fc, err := ReadCredentialsFile(path)
+		if err == nil {
+			c = fc
+		} else if !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+
+	for env, field := range map[string]*string{
+		EnvAccessToken:  &c.AccessToken,
+		EnvRefreshToken: &c.RefreshToken,
+		EnvTokenURL:     &c.TokenURL,
+		EnvClientID:     &c.ClientID,
+		EnvClientSecret: &c.ClientSecret,
+	} {
+		if v := os.Getenv(env); v != "" {
+			*field = v
+			if env == EnvAccessToken {
+				c.Expiry = time.Time{} // the file's expiry doesn't apply
+			}
+		}
+	}
+
+	if c.AccessToken == "" && c.RefreshToken == "" {
+		return nil, ErrNoCredentials
+	}
+	return c, nil
+}
+
+// TokenSource returns a TokenSource that first returns the current
+// access token (if any) and then refreshes it (if the credentials
+// include a refresh token and token URL).
+func (c *Credentials) TokenSource() TokenSource {
+	s := &credentialsTokenSource{}
+	if c.AccessToken != "" {
+		s.tok = &Token{AccessToken: c.AccessToken, RefreshToken: c.RefreshToken, Expiry: c.Expiry}
+	}
+	if c.RefreshToken != "" && c.TokenURL != "" {
+		s.refresh = &RefreshTokenSource{
+			TokenURL:     c.TokenURL,
+			ClientID:     c.ClientID,
+			ClientSecret: c.ClientSecret,
+			RefreshToken: c.RefreshToken,
+		}
+	}
+	return s
+}
+
+// Transport returns a BearerTokenTransport that authenticates
+// requests with c's tokens, using the given underlying transport (or
+// http.DefaultTransport if nil).
+func (c *Credentials) Transport(transport http.RoundTripper) *BearerTokenTransport {
+	return &BearerTokenTransport{Source: c.TokenSource(), Transport: transport}
+}
+
+// credentialsTokenSource returns an initial token once and then
+// refreshes it (if it can).
+type credentialsTokenSource struct {
+	mu      sync.Mutex
+	tok     *Token              // initial token (cleared after use)
+	refresh *RefreshTokenSource // nil if tokens can't be refreshed
+}
+
+func (s *credentialsTokenSource) Token() (*Token, error) {
+	return s.TokenContext(context.Background())
+}
+
+func (s *credentialsTokenSource) TokenContext(ctx context.Context) (*Token, error) {
+	s.mu.Lock()
+	tok := s.tok
+	s.tok = nil
+	s.mu.Unlock()
+	if tok != nil && (tok.Valid() || s.refresh == nil) {
+		return tok, nil
+	}
+	if s.refresh == nil {
+		return nil, errors.New("auth: access token was rejected or expired, and credentials have no refresh token and token URL")
+	}
+	return s.refresh.TokenContext(ctx)
+}
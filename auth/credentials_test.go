@@ -0,0 +1,100 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// setCredentialsEnv sets the environment variables that
+// LoadCredentials reads (clearing those not in env) for the duration
+// of the test.
+func setCredentialsEnv(t *testing.T, env map[string]string) {
+	for _, name := range []string{EnvCredentialsFile, EnvAccessToken, EnvRefreshToken, EnvTokenURL, EnvClientID, EnvClientSecret} {
+		t.Setenv(name, env[name])
+	}
+}
+
+func TestLoadCredentials_file(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credentials.json")
+	want := &Credentials{
+		AccessToken:  "a",
+		Expiry:       time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC),
+		RefreshToken: "r",
+		TokenURL:     "https://example.com/token",
+		ClientID:     "id",
+	}
+	if err := WriteCredentialsFile(path, want); err != nil {
+		t.Fatal(err)
+	}
+	setCredentialsEnv(t, map[string]string{EnvCredentialsFile: path})
+
+	if got := CredentialsFile(); got != path {
+		t.Errorf("got credentials file %q, want %q", got, path)
+	}
+	c, err := LoadCredentials()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(c, want) {
+		t.Errorf("got credentials %+v, want %+v", c, want)
+	}
+}
+
+func TestLoadCredentials_env(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credentials.json")
+	if err := WriteCredentialsFile(path, &Credentials{AccessToken: "a", Expiry: time.Now().Add(time.Hour), ClientID: "id"}); err != nil {
+		t.Fatal(err)
+	}
+	setCredentialsEnv(t, map[string]string{EnvCredentialsFile: path, EnvAccessToken: "envtok", EnvClientSecret: "secret"})
+
+	c, err := LoadCredentials()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The file's expiry doesn't apply to the access token from the
+	// environment.
+	if want := (&Credentials{AccessToken: "envtok", ClientID: "id", ClientSecret: "secret"}); !reflect.DeepEqual(c, want) {
+		t.Errorf("got credentials %+v, want %+v", c, want)
+	}
+}
+
+func TestLoadCredentials_none(t *testing.T) {
+	setCredentialsEnv(t, map[string]string{EnvCredentialsFile: filepath.Join(t.TempDir(), "missing.json")})
+	if _, err := LoadCredentials(); err != ErrNoCredentials {
+		t.Errorf("got error %v, want ErrNoCredentials", err)
+	}
+}
+
+func TestCredentials_Transport(t *testing.T) {
+	token := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("refresh_token") != "r" {
+			t.Errorf("got refresh token %q, want r", r.FormValue("refresh_token"))
+		}
+		w.Write([]byte(`{"access_token":"refreshed"}`))
+	}))
+	defer token.Close()
+	var auths []string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auths = append(auths, r.Header.Get("Authorization"))
+		if r.Header.Get("Authorization") != "Bearer refreshed" {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer api.Close()
+
+	// The initial access token is used first, and refreshed when the
+	// server rejects it.
+	c := &Credentials{AccessToken: "initial", RefreshToken: "r", TokenURL: token.URL}
+	resp, err := (&http.Client{Transport: c.Transport(nil)}).Get(api.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if want := []string{"Bearer initial", "Bearer refreshed"}; resp.StatusCode != http.StatusOK || !reflect.DeepEqual(auths, want) {
+		t.Errorf("got status %d with Authorization headers %q, want 200 with %q", resp.StatusCode, auths, want)
+	}
+}
@@ -0,0 +1,215 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// A Principal is the authenticated client of a request, as determined
+// by Middleware.
+type Principal struct {
+	// Name identifies the authenticated user (for HTTP Basic
+	// authentication). It is empty if the request was authenticated
+	// only with tickets.
+	Name string
+
+	// Grants are the permissions that the principal has been granted.
+	Grants []Grant
+}
+
+// A Grant is a set of permissions on a resource.
+type Grant struct {
+	Resource    string
+	Permissions []string
+}
+
+// Allows reports whether p has been granted perm on resource.
+func (p *Principal) Allows(resource, perm string) bool {
+	if p == nil {
+		return false
+	}
+	for _, g := range p.Grants {
+		if g.Resource != resource {
+			continue
+		}
+		for _, gp := range g.Permissions {
+			if gp == perm {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of ctx with the given principal.
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFromContext returns the principal that Middleware stored in
+// a request's context, or nil if the request was not authenticated.
+func PrincipalFromContext(ctx context.Context) *Principal {
+	p, _ := ctx.Value(principalKey{}).(*Principal)
+	return p
+}
+
+// A BasicAuthenticator verifies HTTP Basic authentication credentials
+// (such as those sent by BasicAuthTransport). It returns an error if
+// the credentials are invalid.
+type BasicAuthenticator interface {
+	AuthenticateBasic(ctx context.Context, username, password string) (*Principal, error)
+}
+
+// BasicAuthenticatorFunc is an adapter that allows the use of an
+// ordinary function as a BasicAuthenticator.
+type BasicAuthenticatorFunc func(ctx context.Context, username, password string) (*Principal, error)
+
+// AuthenticateBasic implements BasicAuthenticator.
+func (f BasicAuthenticatorFunc) AuthenticateBasic(ctx context.Context, username, password string) (*Principal, error) {
+	return f(ctx, username, password)
+}
+
+// A TicketAuthenticator verifies signed ticket strings (such as those
+// sent by TicketAuthedTransport). It returns an error if the ticket is
+// invalid.
+type TicketAuthenticator interface {
+	AuthenticateTicket(ctx context.Context, signedTicket string) (*Principal, error)
+}
+
+// TicketVerifier is a TicketAuthenticator that verifies tickets with
+// VerifyTicket. The principal for a valid ticket has no Name and a
+// single Grant with the ticket's resource and permissions.
+type TicketVerifier struct {
+	// Keys are the keys that may have signed valid tickets.
+	Keys []VerificationKey
+
+	// Issuers, if non-empty, are the only ticket issuers that are
+	// accepted.
+	Issuers []string
+}
+
+// AuthenticateTicket implements TicketAuthenticator.
+func (v *TicketVerifier) AuthenticateTicket(ctx context.Context, signedTicket string) (*Principal, error) {
+	t, err := VerifyTicket(signedTicket, time.Now(), v.Keys...)
+	if err != nil {
+		return nil, err
+	}
+	if len(v.Issuers) > 0 && !contains(v.Issuers, t.Issuer) {
+		return nil, fmt.Errorf("auth: ticket issuer %q is not accepted", t.Issuer)
+	}
+	return &Principal{Grants: []Grant{{Resource: t.Resource, Permissions: t.Permissions}}}, nil
+}
+
+// Middleware is HTTP handler middleware that authenticates requests
+// with HTTP Basic authentication and "Authorization:
+// Sourcegraph-Ticket xxx..." headers, and stores the resulting
+// Principal in the request's context (see PrincipalFromContext).
+//
+// If a request has both Basic credentials and tickets (or multiple
+// tickets), all of them must be valid, and the principal combines
+// their grants. Requests with any invalid credentials are rejected
+// with 401 Unauthorized. Requests without credentials are passed
+// through without a principal, unless RequireAuth is set.
+type Middleware struct {
+	// Basic verifies HTTP Basic credentials. If nil, requests with
+	// Basic credentials are rejected.
+	Basic BasicAuthenticator
+
+	// Tickets verifies tickets. If nil, requests with tickets are
+	// rejected.
+	Tickets TicketAuthenticator
+
+	// RequireAuth is whether requests without credentials are
+	// rejected with 401 Unauthorized.
+	RequireAuth bool
+
+	// Realm is the realm in the WWW-Authenticate header of 401
+	// responses (if Basic is set). If empty, "Sourcegraph" is used.
+	Realm string
+}
+
+// Handler returns an http.Handler that authenticates requests and
+// passes them to h.
+func (m *Middleware) Handler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p, err := m.Authenticate(r)
+		if err != nil || (p == nil && m.RequireAuth) {
+			m.unauthorized(w)
+			return
+		}
+		if p != nil {
+			r = r.WithContext(WithPrincipal(r.Context(), p))
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// Authenticate verifies the credentials in r's headers and returns the
+// resulting principal, or nil if r has no credentials.
+func (m *Middleware) Authenticate(r *http.Request) (*Principal, error) {
+	var p *Principal
+	add := func(q *Principal) {
+		if p == nil {
+			p = &Principal{}
+		}
+		if q == nil {
+			return
+		}
+		if q.Name != "" {
+			p.Name = q.Name
+		}
+		p.Grants = append(p.Grants, q.Grants...)
+	}
+
+	ctx := r.Context()
+	if username, password, ok := r.BasicAuth(); ok {
+		if m.Basic == nil {
+			return nil, ErrUnsupportedCredentials
+		}
+		q, err := m.Basic.AuthenticateBasic(ctx, username, password)
+		if err != nil {
+			return nil, err
+		}
+		add(q)
+	}
+	for _, tstr := range GetSignedTicketStrings(r.Header) {
+		if m.Tickets == nil {
+			return nil, ErrUnsupportedCredentials
+		}
+		q, err := m.Tickets.AuthenticateTicket(ctx, tstr)
+		if err != nil {
+			return nil, err
+		}
+		add(q)
+	}
+	return p, nil
+}
+
+func (m *Middleware) unauthorized(w http.ResponseWriter) {
+	if m.Basic != nil {
+		realm := m.Realm
+		if realm == "" {
+			realm = "Sourcegraph"
+		}
+		w.Header().Set("WWW-Authenticate", `Basic realm="`+realm+`"`)
+	}
+	http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+}
+
+// ErrUnsupportedCredentials is returned by (*Middleware).Authenticate
+// for credentials that the Middleware has no authenticator for.
+var ErrUnsupportedCredentials = errors.New("auth: unsupported credentials")
+
+func contains(ss []string, s string) bool {
+	for _, s2 := range ss {
+		if s2 == s {
+			return true
+		}
+	}
+	return false
+}
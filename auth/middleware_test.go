@@ -0,0 +1,148 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMiddleware(t *testing.T) {
+	key := &HMACKey{Secret: []byte("secret")}
+	sign := func(ticket *Ticket) string { return TicketAuthScheme + mustSign(t, ticket, key) }
+	validTicket := sign(&Ticket{Resource: "github.com/o/r", Permissions: []string{PermRead}, Expiry: time.Now().Add(time.Hour)})
+	expiredTicket := sign(&Ticket{Resource: "github.com/o/r", Permissions: []string{PermRead}, Expiry: time.Now().Add(-time.Hour)})
+	forgedTicket := TicketAuthScheme + mustSign(t, &Ticket{Resource: "github.com/o/r", Permissions: []string{PermAdmin}, Expiry: time.Now().Add(time.Hour)}, &HMACKey{Secret: []byte("other")})
+
+	basic := BasicAuthenticatorFunc(func(ctx context.Context, username, password string) (*Principal, error) {
+		if username != "alice" || password != "pw" {
+			return nil, errors.New("bad password")
+		}
+		return &Principal{Name: "alice", Grants: []Grant{{Resource: "github.com/o/r2", Permissions: []string{PermWrite}}}}, nil
+	})
+
+	tests := map[string]struct {
+		mw            *Middleware
+		basicUser     string
+		basicPassword string
+		tickets       []string
+
+		wantStatus    int
+		wantPrincipal *Principal
+		wantChallenge string // WWW-Authenticate header
+	}{
+		"anonymous": {
+			mw:         &Middleware{Basic: basic},
+			wantStatus: http.StatusOK,
+		},
+		"anonymous with RequireAuth": {
+			mw:            &Middleware{Basic: basic, RequireAuth: true},
+			wantStatus:    http.StatusUnauthorized,
+			wantChallenge: `Basic realm="Sourcegraph"`,
+		},
+		"Basic": {
+			mw:            &Middleware{Basic: basic, RequireAuth: true},
+			basicUser:     "alice",
+			basicPassword: "pw",
+			wantStatus:    http.StatusOK,
+			wantPrincipal: &Principal{Name: "alice", Grants: []Grant{{Resource: "github.com/o/r2", Permissions: []string{PermWrite}}}},
+		},
+		"bad Basic password": {
+			mw:            &Middleware{Basic: basic, Realm: "r"},
+			basicUser:     "alice",
+			basicPassword: "wrong",
+			wantStatus:    http.StatusUnauthorized,
+			wantChallenge: `Basic realm="r"`,
+		},
+		"Basic unsupported": {
+			mw:            &Middleware{Tickets: &TicketVerifier{Keys: []VerificationKey{key}}},
+			basicUser:     "alice",
+			basicPassword: "pw",
+			wantStatus:    http.StatusUnauthorized,
+		},
+		"ticket": {
+			mw:            &Middleware{Tickets: &TicketVerifier{Keys: []VerificationKey{key}}, RequireAuth: true},
+			tickets:       []string{validTicket},
+			wantStatus:    http.StatusOK,
+			wantPrincipal: &Principal{Grants: []Grant{{Resource: "github.com/o/r", Permissions: []string{PermRead}}}},
+		},
+		"expired ticket": {
+			mw:         &Middleware{Tickets: &TicketVerifier{Keys: []VerificationKey{key}}},
+			tickets:    []string{expiredTicket},
+			wantStatus: http.StatusUnauthorized,
+		},
+		"forged ticket": {
+			mw:         &Middleware{Tickets: &TicketVerifier{Keys: []VerificationKey{key}}},
+			tickets:    []string{forgedTicket},
+			wantStatus: http.StatusUnauthorized,
+		},
+		"valid and forged tickets": {
+			mw:         &Middleware{Tickets: &TicketVerifier{Keys: []VerificationKey{key}}},
+			tickets:    []string{validTicket, forgedTicket},
+			wantStatus: http.StatusUnauthorized,
+		},
+		"ticket from unaccepted issuer": {
+			mw:         &Middleware{Tickets: &TicketVerifier{Keys: []VerificationKey{key}, Issuers: []string{"other"}}},
+			tickets:    []string{validTicket},
+			wantStatus: http.StatusUnauthorized,
+		},
+	}
+	for label, test := range tests {
+		var principal *Principal
+		h := test.mw.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal = PrincipalFromContext(r.Context())
+		}))
+
+		req := httptest.NewRequest("GET", "/", nil)
+		if test.basicUser != "" {
+			req.SetBasicAuth(test.basicUser, test.basicPassword)
+		}
+		for _, tstr := range test.tickets {
+			req.Header.Add("Authorization", tstr)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+
+		if rec.Code != test.wantStatus {
+			t.Errorf("%s: got status %d, want %d", label, rec.Code, test.wantStatus)
+		}
+		if !reflect.DeepEqual(principal, test.wantPrincipal) {
+			t.Errorf("%s: got principal %+v, want %+v", label, principal, test.wantPrincipal)
+		}
+		if got := rec.Header().Get("WWW-Authenticate"); got != test.wantChallenge {
+			t.Errorf("%s: got WWW-Authenticate %q, want %q", label, got, test.wantChallenge)
+		}
+	}
+}
+
+func TestMiddleware_Authenticate_combined(t *testing.T) {
+	key := &HMACKey{Secret: []byte("secret")}
+	mw := &Middleware{
+		Basic: BasicAuthenticatorFunc(func(ctx context.Context, username, password string) (*Principal, error) {
+			return &Principal{Name: username}, nil
+		}),
+		Tickets: &TicketVerifier{Keys: []VerificationKey{key}},
+	}
+	req := httptest.NewRequest("GET", "/", nil)
+	req.SetBasicAuth("alice", "pw")
+	req.Header.Add("Authorization", TicketAuthScheme+mustSign(t, &Ticket{Resource: "r", Permissions: []string{PermRead}, Expiry: time.Now().Add(time.Hour)}, key))
+
+	p, err := mw.Authenticate(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Name != "alice" || !p.Allows("r", PermRead) || p.Allows("r", PermWrite) {
+		t.Errorf("got principal %+v, want alice with read access to r", p)
+	}
+}
+
+func mustSign(t *testing.T, ticket *Ticket, key SigningKey) string {
+	s, err := SignTicket(ticket, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
@@ -0,0 +1,280 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// A Ticket grants permissions on a resource until it expires. Signed
+// tickets are sent in "Authorization: Sourcegraph-Ticket xxx..."
+// headers (see TicketAuthedTransport).
+//
+// A signed ticket string has the form HEADER.CLAIMS.SIGNATURE, where
+// each part is base64url-encoded (without padding), HEADER is a JSON
+// object with the signing algorithm ("alg") and key ID ("kid"),
+// CLAIMS is the JSON encoding of the Ticket, and SIGNATURE is the
+// signature of "HEADER.CLAIMS".
+type Ticket struct {
+	// Resource identifies what the ticket grants access to (such as a
+	// repository URI).
+	Resource string `json:"res"`
+
+	// Permissions are the permissions that the ticket grants on the
+	// resource (such as PermRead).
+	Permissions []string `json:"perms"`
+
+	// Expiry is when the ticket expires. It is required: SignTicket
+	// refuses to sign, and VerifyTicket rejects, tickets without an
+	// expiry.
+	Expiry time.Time `json:"exp"`
+
+	// NotBefore is when the ticket becomes valid. If zero, it is
+	// valid as soon as it is issued.
+	NotBefore time.Time `json:"nbf"`
+
+	// Issuer identifies who issued the ticket.
+	Issuer string `json:"iss,omitempty"`
+}
+
+// Permissions that tickets commonly grant.
+const (
+	PermRead  = "read"
+	PermWrite = "write"
+	PermAdmin = "admin"
+)
+
+// Allows reports whether t grants perm on resource.
+func (t *Ticket) Allows(resource, perm string) bool {
+	if t.Resource != resource {
+		return false
+	}
+	for _, p := range t.Permissions {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
+
+// Expired reports whether t has expired at time now. A ticket without
+// an Expiry never expires by this test (but see VerifyTicket).
+func (t *Ticket) Expired(now time.Time) bool {
+	return !t.Expiry.IsZero() && !now.Before(t.Expiry)
+}
+
+// NotYetValid reports whether t is not yet valid at time now.
+func (t *Ticket) NotYetValid(now time.Time) bool {
+	return !t.NotBefore.IsZero() && now.Before(t.NotBefore)
+}
+
+var (
+	// ErrMalformedTicket is returned when a signed ticket string
+	// can't be parsed.
+	ErrMalformedTicket = errors.New("auth: malformed ticket")
+
+	// ErrTicketSignature is returned when no verification key
+	// verifies a ticket's signature.
+	ErrTicketSignature = errors.New("auth: invalid ticket signature")
+
+	// ErrTicketNoExpiry is returned when signing or verifying a
+	// ticket that has no Expiry.
+	ErrTicketNoExpiry = errors.New("auth: ticket has no expiry")
+
+	// ErrTicketExpired is returned when verifying an expired ticket.
+	ErrTicketExpired = errors.New("auth: ticket has expired")
+
+	// ErrTicketNotYetValid is returned when verifying a ticket before
+	// its NotBefore time.
+	ErrTicketNotYetValid = errors.New("auth: ticket is not valid yet")
+)
+
+// A SigningKey signs tickets.
+type SigningKey interface {
+	// Algorithm is the name of the signature algorithm (such as
+	// "HS256"), which is stored in the ticket header.
+	Algorithm() string
+
+	// KeyID identifies the key, so that verifiers can choose the
+	// right verification key. It may be empty.
+	KeyID() string
+
+	// Sign returns the signature of data.
+	Sign(data []byte) ([]byte, error)
+}
+
+// A VerificationKey verifies ticket signatures.
+type VerificationKey interface {
+	// Algorithm and KeyID must match those of the SigningKey that
+	// signed the ticket. (An empty KeyID matches any key ID.)
+	Algorithm() string
+	KeyID() string
+
+	// Verify reports whether sig is a valid signature of data.
+	Verify(data, sig []byte) bool
+}
+
+// HMACKey is a secret key that signs and verifies tickets using
+// HMAC-SHA256 ("HS256").
+type HMACKey struct {
+	ID     string // key ID
+	Secret []byte
+}
+
+func (k *HMACKey) Algorithm() string { return "HS256" }
+func (k *HMACKey) KeyID() string     { return k.ID }
+
+func (k *HMACKey) Sign(data []byte) ([]byte, error) {
+	if len(k.Secret) == 0 {
+		return nil, errors.New("auth: empty HMAC secret")
+	}
+	mac := hmac.New(sha256.New, k.Secret)
+	mac.Write(data)
+	return mac.Sum(nil), nil
+}
+
+func (k *HMACKey) Verify(data, sig []byte) bool {
+	want, err := k.Sign(data)
+	return err == nil && hmac.Equal(sig, want)
+}
+
+// Ed25519SigningKey signs tickets using Ed25519 ("EdDSA").
+type Ed25519SigningKey struct {
+	ID  string // key ID
+	Key ed25519.PrivateKey
+}
+
+func (k *Ed25519SigningKey) Algorithm() string { return "EdDSA" }
+func (k *Ed25519SigningKey) KeyID() string     { return k.ID }
+
+func (k *Ed25519SigningKey) Sign(data []byte) ([]byte, error) {
+	if len(k.Key) != ed25519.PrivateKeySize {
+		return nil, errors.New("auth: invalid Ed25519 private key")
+	}
+	return ed25519.Sign(k.Key, data), nil
+}
+
+// VerificationKey returns the public key that verifies tickets signed
+// by k.
+func (k *Ed25519SigningKey) VerificationKey() *Ed25519VerificationKey {
+	return &Ed25519VerificationKey{ID: k.ID, Key: k.Key.Public().(ed25519.PublicKey)}
+}
+
+// Ed25519VerificationKey verifies tickets signed with an
+// Ed25519SigningKey.
+type Ed25519VerificationKey struct {
+	ID  string // key ID
+	Key ed25519.PublicKey
+}
+
+func (k *Ed25519VerificationKey) Algorithm() string { return "EdDSA" }
+func (k *Ed25519VerificationKey) KeyID() string     { return k.ID }
+
+func (k *Ed25519VerificationKey) Verify(data, sig []byte) bool {
+	return len(k.Key) == ed25519.PublicKeySize && ed25519.Verify(k.Key, data, sig)
+}
+
+// ticketHeader is the header of a signed ticket string.
+type ticketHeader struct {
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid,omitempty"`
+}
+
+var ticketEncoding = base64.RawURLEncoding
+
+// SignTicket returns the signed ticket string for t. The string does
+// not include the "Sourcegraph-Ticket " prefix (the auth scheme). It
+// returns ErrTicketNoExpiry if t has no Expiry.
+func SignTicket(t *Ticket, key SigningKey) (string, error) {
+	if t.Expiry.IsZero() {
+		return "", ErrTicketNoExpiry
+	}
+	hdr, err := json.Marshal(ticketHeader{Algorithm: key.Algorithm(), KeyID: key.KeyID()})
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(t)
+	if err != nil {
+		return "", err
+	}
+	signed := ticketEncoding.EncodeToString(hdr) + "." + ticketEncoding.EncodeToString(claims)
+	sig, err := key.Sign([]byte(signed))
+	if err != nil {
+		return "", err
+	}
+	return signed + "." + ticketEncoding.EncodeToString(sig), nil
+}
+
+// ParseTicket decodes a signed ticket string WITHOUT verifying its
+// signature or expiry. It can be used to inspect tickets (for
+// example, to see what a ticket grants and when it expires); use
+// VerifyTicket to check that a ticket may be trusted.
+func ParseTicket(s string) (*Ticket, error) {
+	_, t, _, _, err := parseTicket(s)
+	return t, err
+}
+
+// VerifyTicket decodes a signed ticket string and checks that it was
+// signed by one of keys (with a matching algorithm and key ID) and
+// that it is valid (it has an Expiry that has not passed, and it is
+// not before its NotBefore time) at time now. It returns
+// ErrTicketSignature, ErrTicketNoExpiry, ErrTicketExpired, or
+// ErrTicketNotYetValid if the ticket may not be trusted.
+func VerifyTicket(s string, now time.Time, keys ...VerificationKey) (*Ticket, error) {
+	hdr, t, signed, sig, err := parseTicket(s)
+	if err != nil {
+		return nil, err
+	}
+	verified := false
+	for _, key := range keys {
+		if key.Algorithm() != hdr.Algorithm || (key.KeyID() != "" && key.KeyID() != hdr.KeyID) {
+			continue
+		}
+		if key.Verify(signed, sig) {
+			verified = true
+			break
+		}
+	}
+	if !verified {
+		return nil, ErrTicketSignature
+	}
+	if t.Expiry.IsZero() {
+		return nil, ErrTicketNoExpiry
+	}
+	if t.Expired(now) {
+		return nil, ErrTicketExpired
+	}
+	if t.NotYetValid(now) {
+		return nil, ErrTicketNotYetValid
+	}
+	return t, nil
+}
+
+// parseTicket decodes the parts of a signed ticket string.
+func parseTicket(s string) (hdr *ticketHeader, t *Ticket, signed, sig []byte, err error) {
+	s = strings.TrimPrefix(s, TicketAuthScheme)
+	parts := strings.Split(s, ".")
+	if len(parts) != 3 {
+		return nil, nil, nil, nil, ErrMalformedTicket
+	}
+	var data [3][]byte
+	for i, part := range parts {
+		if data[i], err = ticketEncoding.DecodeString(part); err != nil {
+			return nil, nil, nil, nil, ErrMalformedTicket
+		}
+	}
+	hdr, t = &ticketHeader{}, &Ticket{}
+	if err := json.Unmarshal(data[0], hdr); err != nil {
+		return nil, nil, nil, nil, fmt.Errorf("%w: header: %s", ErrMalformedTicket, err)
+	}
+	if err := json.Unmarshal(data[1], t); err != nil {
+		return nil, nil, nil, nil, fmt.Errorf("%w: claims: %s", ErrMalformedTicket, err)
+	}
+	return hdr, t, []byte(parts[0] + "." + parts[1]), data[2], nil
+}
@@ -0,0 +1,205 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testNow = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func testTicket() *Ticket {
+	return &Ticket{
+		Resource:    "github.com/o/r",
+		Permissions: []string{PermRead},
+		Expiry:      testNow.Add(time.Hour),
+		Issuer:      "test",
+	}
+}
+
+func testEd25519Key(t *testing.T, id string) *Ed25519SigningKey {
+	_, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Ed25519SigningKey{ID: id, Key: priv}
+}
+
+func TestSignTicket_roundTrip(t *testing.T) {
+	hmacKey := &HMACKey{ID: "h1", Secret: []byte("secret")}
+	edKey := testEd25519Key(t, "e1")
+	tests := map[string]struct {
+		sign   SigningKey
+		verify VerificationKey
+	}{
+		"HS256": {hmacKey, hmacKey},
+		"EdDSA": {edKey, edKey.VerificationKey()},
+	}
+	for label, test := range tests {
+		want := testTicket()
+		s, err := SignTicket(want, test.sign)
+		if err != nil {
+			t.Fatalf("%s: %s", label, err)
+		}
+
+		got, err := VerifyTicket(s, testNow, test.verify)
+		if err != nil {
+			t.Errorf("%s: VerifyTicket: %s", label, err)
+		} else if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: got ticket %+v, want %+v", label, got, want)
+		}
+		if !got.Allows("github.com/o/r", PermRead) || got.Allows("github.com/o/r", PermWrite) {
+			t.Errorf("%s: got wrong permissions %v", label, got.Permissions)
+		}
+
+		// The auth scheme prefix is accepted, and ParseTicket decodes
+		// the ticket without verifying it.
+		if got, err := ParseTicket(TicketAuthScheme + s); err != nil || !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: ParseTicket: got ticket %+v (error %v), want %+v", label, got, err, want)
+		}
+	}
+}
+
+// tamper replaces part i of signed ticket string s with f's result.
+func tamper(s string, i int, f func([]byte) []byte) string {
+	parts := strings.Split(s, ".")
+	data, _ := ticketEncoding.DecodeString(parts[i])
+	parts[i] = ticketEncoding.EncodeToString(f(data))
+	return strings.Join(parts, ".")
+}
+
+// resign replaces the signature of signed ticket string s with one
+// made by key.
+func resign(s string, key SigningKey) string {
+	signed := s[:strings.LastIndex(s, ".")]
+	sig, _ := key.Sign([]byte(signed))
+	return signed + "." + ticketEncoding.EncodeToString(sig)
+}
+
+func TestSignTicket_noExpiry(t *testing.T) {
+	ticket := testTicket()
+	ticket.Expiry = time.Time{}
+	if _, err := SignTicket(ticket, &HMACKey{Secret: []byte("secret")}); err != ErrTicketNoExpiry {
+		t.Errorf("got error %v, want %v", err, ErrTicketNoExpiry)
+	}
+}
+
+func TestVerifyTicket_invalid(t *testing.T) {
+	key := &HMACKey{ID: "h1", Secret: []byte("secret")}
+	s, err := SignTicket(testTicket(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edKey := testEd25519Key(t, "h1")
+
+	withHeader := func(hdr ticketHeader) string {
+		return tamper(s, 0, func([]byte) []byte {
+			b, _ := json.Marshal(hdr)
+			return b
+		})
+	}
+	tests := map[string]struct {
+		ticket  string
+		keys    []VerificationKey
+		wantErr error
+	}{
+		"tampered claims": {
+			ticket: tamper(s, 1, func(b []byte) []byte {
+				return []byte(strings.Replace(string(b), PermRead, PermAdmin, 1))
+			}),
+			keys:    []VerificationKey{key},
+			wantErr: ErrTicketSignature,
+		},
+		"tampered signature": {
+			ticket: tamper(s, 2, func(b []byte) []byte {
+				b[0] ^= 1
+				return b
+			}),
+			keys:    []VerificationKey{key},
+			wantErr: ErrTicketSignature,
+		},
+		"wrong secret": {
+			ticket:  s,
+			keys:    []VerificationKey{&HMACKey{ID: "h1", Secret: []byte("other")}},
+			wantErr: ErrTicketSignature,
+		},
+		"alg mismatch": {
+			// An EdDSA key with the same key ID must not be used to
+			// verify an HS256 ticket.
+			ticket:  s,
+			keys:    []VerificationKey{edKey.VerificationKey()},
+			wantErr: ErrTicketSignature,
+		},
+		"alg changed in header": {
+			ticket:  withHeader(ticketHeader{Algorithm: "EdDSA", KeyID: "h1"}),
+			keys:    []VerificationKey{key, edKey.VerificationKey()},
+			wantErr: ErrTicketSignature,
+		},
+		"alg none": {
+			ticket:  strings.Join(strings.Split(withHeader(ticketHeader{Algorithm: "none"}), ".")[:2], ".") + ".",
+			keys:    []VerificationKey{key},
+			wantErr: ErrTicketSignature,
+		},
+		"unknown kid": {
+			ticket:  s,
+			keys:    []VerificationKey{&HMACKey{ID: "h2", Secret: []byte("secret")}},
+			wantErr: ErrTicketSignature,
+		},
+		"no keys": {
+			ticket:  s,
+			wantErr: ErrTicketSignature,
+		},
+		"no expiry": {
+			// Signed by another signer that doesn't require an Expiry.
+			ticket: resign(tamper(s, 1, func(b []byte) []byte {
+				var claims map[string]interface{}
+				json.Unmarshal(b, &claims)
+				delete(claims, "exp")
+				b, _ = json.Marshal(claims)
+				return b
+			}), key),
+			keys:    []VerificationKey{key},
+			wantErr: ErrTicketNoExpiry,
+		},
+		"malformed": {
+			ticket:  "a.b",
+			keys:    []VerificationKey{key},
+			wantErr: ErrMalformedTicket,
+		},
+	}
+	for label, test := range tests {
+		if _, err := VerifyTicket(test.ticket, testNow, test.keys...); !errors.Is(err, test.wantErr) {
+			t.Errorf("%s: got error %v, want %v", label, err, test.wantErr)
+		}
+	}
+}
+
+func TestVerifyTicket_validity(t *testing.T) {
+	key := &HMACKey{Secret: []byte("secret")}
+	ticket := testTicket()
+	ticket.NotBefore = testNow.Add(-time.Minute)
+	s, err := SignTicket(ticket, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		now     time.Time
+		wantErr error
+	}{
+		"valid":         {testNow, nil},
+		"at NotBefore":  {ticket.NotBefore, nil},
+		"not yet valid": {ticket.NotBefore.Add(-time.Second), ErrTicketNotYetValid},
+		"at Expiry":     {ticket.Expiry, ErrTicketExpired},
+		"expired":       {ticket.Expiry.Add(time.Second), ErrTicketExpired},
+	}
+	for label, test := range tests {
+		if _, err := VerifyTicket(s, test.now, key); err != test.wantErr {
+			t.Errorf("%s: got error %v, want %v", label, err, test.wantErr)
+		}
+	}
+}
@@ -0,0 +1,304 @@
+// Package cassette provides an HTTP transport that records the
+// requests made by a Sourcegraph API client (and the server's
+// responses) to a file, and that replays the recorded responses
+// later, so that tests against the client can run offline and
+// deterministically.
+//
+// To record a cassette, run the tests against a live server using a
+// Transport in Record mode, and then call Save:
+//
+//	tr := &cassette.Transport{Mode: cassette.Record, Cassette: &cassette.Cassette{}, BaseURL: baseURL}
+//	c := sourcegraph.NewClient(&http.Client{Transport: tr})
+//	c.BaseURL = baseURL
+//	// ... make API calls ...
+//	err := tr.Cassette.Save("testdata/foo.json")
+//
+// To replay it, Load the cassette and use a Transport in Replay mode.
+//
+// Recorded requests are keyed on their HTTP method, API route name,
+// route variables, and querystring, so replaying does not depend on
+// the server's host or on the order of requests for different keys.
+// Credentials in Authorization headers are redacted before they are
+// recorded.
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/sourcegraph"
+)
+
+// A Cassette is a list of recorded HTTP interactions.
+type Cassette struct {
+	Interactions []*Interaction
+}
+
+// An Interaction is a recorded HTTP request and its response.
+type Interaction struct {
+	// Key identifies the request for replaying; see Key.
+	Key string
+
+	Request  Request
+	Response Response
+}
+
+// Request is a recorded HTTP request.
+type Request struct {
+	Method string
+	URL    string
+	Header http.Header `json:",omitempty"`
+	Body   Body        `json:",omitempty"`
+}
+
+// Response is a recorded HTTP response.
+type Response struct {
+	StatusCode int
+	Header     http.Header `json:",omitempty"`
+	Body       Body        `json:",omitempty"`
+}
+
+// Body is a recorded request or response body. It is encoded in JSON
+// as a string if it is valid UTF-8 (as API responses are), and as an
+// object {"Base64": "..."} otherwise.
+type Body []byte
+
+// MarshalJSON implements json.Marshaler.
+func (b Body) MarshalJSON() ([]byte, error) {
+	if utf8.Valid(b) {
+		return json.Marshal(string(b))
+	}
+	return json.Marshal(struct{ Base64 []byte }{b})
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (b *Body) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*b = Body(s)
+		return nil
+	}
+	var v struct{ Base64 []byte }
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*b = v.Base64
+	return nil
+}
+
+// Load reads a cassette from the JSON file at path.
+func Load(path string) (*Cassette, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var c Cassette
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("cassette %s: %s", path, err)
+	}
+	return &c, nil
+}
+
+// Save writes the cassette to the JSON file at path.
+func (c *Cassette) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// Mode specifies whether a Transport records or replays.
+type Mode int
+
+const (
+	// Replay mode serves responses from the cassette and never sends
+	// requests to the server.
+	Replay Mode = iota
+
+	// Record mode sends requests to the server and appends them (and
+	// the responses) to the cassette.
+	Record
+)
+
+// RedactHeaders are the names of the request and response headers
+// whose credentials are redacted before they are recorded. The
+// authorization scheme (e.g., "Basic" or "Sourcegraph-Ticket") is
+// preserved.
+var RedactHeaders = []string{"Authorization", "Sourcegraph-Ticket"}
+
+// redacted replaces credentials in recorded headers.
+const redacted = "REDACTED"
+
+// A Transport is an http.RoundTripper that records or replays HTTP
+// interactions using a Cassette.
+type Transport struct {
+	Mode     Mode
+	Cassette *Cassette
+
+	// BaseURL is the base URL of the Sourcegraph API (the same as the
+	// client's BaseURL). It is used to determine the API route of
+	// each request URL. If nil, or if a request URL is not under it,
+	// requests are keyed on their URL path instead of their route.
+	BaseURL *url.URL
+
+	// Transport is the underlying HTTP transport used in Record mode.
+	// If nil, http.DefaultTransport is used.
+	Transport http.RoundTripper
+
+	mu       sync.Mutex
+	replayed map[string]int // number of times each key has been replayed
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	key := Key(req, t.BaseURL)
+	if t.Mode == Replay {
+		return t.replay(key, req)
+	}
+	return t.record(key, req)
+}
+
+// replay returns the recorded response to the next unreplayed request
+// with the given key. If all such requests have been replayed, the
+// last one's response is returned again.
+func (t *Transport) replay(key string, req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var matches []*Interaction
+	for _, in := range t.Cassette.Interactions {
+		if in.Key == key {
+			matches = append(matches, in)
+		}
+	}
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("cassette: no recorded response for %s", key)
+	}
+	if t.replayed == nil {
+		t.replayed = map[string]int{}
+	}
+	i := t.replayed[key]
+	if i >= len(matches) {
+		i = len(matches) - 1
+	}
+	t.replayed[key]++
+
+	r := matches[i].Response
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", r.StatusCode, http.StatusText(r.StatusCode)),
+		StatusCode:    r.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        r.Header.Clone(),
+		Body:          ioutil.NopCloser(bytes.NewReader(r.Body)),
+		ContentLength: int64(len(r.Body)),
+		Request:       req,
+	}, nil
+}
+
+// record sends req using the underlying transport and appends the
+// interaction to the cassette.
+func (t *Transport) record(key string, req *http.Request) (*http.Response, error) {
+	transport := t.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		reqBody, err = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		// Don't modify the Request we were given. This is required by
+		// the specification of http.RoundTripper.
+		req = req.Clone(req.Context())
+		req.Body = ioutil.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+
+	t.mu.Lock()
+	t.Cassette.Interactions = append(t.Cassette.Interactions, &Interaction{
+		Key: key,
+		Request: Request{
+			Method: req.Method,
+			URL:    req.URL.String(),
+			Header: redactHeader(req.Header),
+			Body:   reqBody,
+		},
+		Response: Response{
+			StatusCode: resp.StatusCode,
+			Header:     redactHeader(resp.Header),
+			Body:       respBody,
+		},
+	})
+	t.mu.Unlock()
+	return resp, nil
+}
+
+// redactHeader returns a copy of hdr with the credentials in the
+// RedactHeaders headers replaced.
+func redactHeader(hdr http.Header) http.Header {
+	hdr = hdr.Clone()
+	for _, name := range RedactHeaders {
+		vs := hdr[http.CanonicalHeaderKey(name)]
+		for i, v := range vs {
+			if sp := strings.Index(v, " "); sp != -1 {
+				vs[i] = v[:sp+1] + redacted
+			} else {
+				vs[i] = redacted
+			}
+		}
+	}
+	return hdr
+}
+
+// Key returns the key that identifies req in a cassette. It consists
+// of the request method, the name and variables of the API route
+// that the request URL matches (relative to baseURL), and the
+// querystring. If the request URL does not match an API route, the
+// URL path is used instead of the route name and variables.
+func Key(req *http.Request, baseURL *url.URL) string {
+	query := req.URL.Query().Encode()
+	if name, vars, ok := sourcegraph.MatchRoute(req.Method, req.URL, baseURL); ok {
+		v := url.Values{}
+		for k, val := range vars {
+			v.Set(k, val)
+		}
+		return strings.Join([]string{req.Method, name, v.Encode(), query}, " ")
+	}
+	return strings.Join([]string{req.Method, req.URL.Path, query}, " ")
+}
+
+// Exists reports whether a cassette file exists at path. It is useful
+// for choosing between Record and Replay mode in tests.
+func Exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,126 @@
+package cassette
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/auth"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/sourcegraph"
+)
+
+func TestTransport_recordAndReplay(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		switch r.URL.Path {
+		case "/api/repos/r.com/x":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"RID":1,"URI":"r.com/x"}`))
+		case "/api/repos":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`[{"RID":` + r.FormValue("Page") + `}]`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	baseURL, _ := url.Parse(server.URL + "/api/")
+	path := filepath.Join(t.TempDir(), "cassette.json")
+
+	// Record.
+	rec := &Transport{Mode: Record, Cassette: &Cassette{}, BaseURL: baseURL}
+	client := sourcegraph.NewClient(&http.Client{Transport: &auth.BasicAuthTransport{Username: "u", Password: "secret", Transport: rec}})
+	client.BaseURL = baseURL
+	wantRepo, _, err := client.Repos.Get(context.Background(), sourcegraph.RepoSpec{URI: "r.com/x"}, nil)
+	if err != nil {
+		t.Fatalf("Repos.Get returned error: %v", err)
+	}
+	for _, page := range []int{1, 2} {
+		if _, _, err := client.Repos.List(context.Background(), &sourcegraph.RepoListOptions{ListOptions: sourcegraph.ListOptions{Page: page}}); err != nil {
+			t.Fatalf("Repos.List returned error: %v", err)
+		}
+	}
+	if err := rec.Cassette.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 3 {
+		t.Errorf("got %d calls, want 3", calls)
+	}
+
+	cas, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cas.Interactions) != 3 {
+		t.Fatalf("got %d interactions, want 3", len(cas.Interactions))
+	}
+	in := cas.Interactions[0]
+	if want := "GET repo RepoSpec=r.com%2Fx "; in.Key != want {
+		t.Errorf("got key %q, want %q", in.Key, want)
+	}
+	if got := in.Request.Header.Get("Authorization"); got != "Basic REDACTED" {
+		t.Errorf("got recorded Authorization header %q, want it to be redacted", got)
+	}
+
+	// Replay against a different (and nonexistent) host.
+	server.Close()
+	replayBaseURL, _ := url.Parse("http://example.com/api/")
+	client = sourcegraph.NewClient(&http.Client{Transport: &Transport{Mode: Replay, Cassette: cas, BaseURL: replayBaseURL}})
+	client.BaseURL = replayBaseURL
+	repo, _, err := client.Repos.Get(context.Background(), sourcegraph.RepoSpec{URI: "r.com/x"}, nil)
+	if err != nil {
+		t.Fatalf("replayed Repos.Get returned error: %v", err)
+	}
+	if !reflect.DeepEqual(repo, wantRepo) {
+		t.Errorf("replayed Repos.Get returned %+v, want %+v", repo, wantRepo)
+	}
+	for _, page := range []int{2, 1} {
+		repos, _, err := client.Repos.List(context.Background(), &sourcegraph.RepoListOptions{ListOptions: sourcegraph.ListOptions{Page: page}})
+		if err != nil {
+			t.Fatalf("replayed Repos.List returned error: %v", err)
+		}
+		if len(repos) != 1 || repos[0].RID != page {
+			t.Errorf("replayed Repos.List page %d returned %+v", page, repos)
+		}
+	}
+
+	_, _, err = client.Repos.Get(context.Background(), sourcegraph.RepoSpec{URI: "r.com/y"}, nil)
+	if err == nil || !strings.Contains(err.Error(), "no recorded response for GET repo RepoSpec=r.com%2Fy") {
+		t.Errorf("got error %v, want no recorded response error", err)
+	}
+}
+
+func TestBody_JSON(t *testing.T) {
+	for _, b := range []Body{Body("hello"), Body{0xff, 0xfe, 0x00}} {
+		data, err := b.MarshalJSON()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got Body
+		if err := got.UnmarshalJSON(data); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, b) {
+			t.Errorf("%s: got %q, want %q", data, got, b)
+		}
+	}
+}
+
+func TestRedactHeader(t *testing.T) {
+	hdr := http.Header{"Authorization": {"Sourcegraph-Ticket abc", "token"}, "X-Other": {"v"}}
+	got := redactHeader(hdr)
+	want := http.Header{"Authorization": {"Sourcegraph-Ticket REDACTED", "REDACTED"}, "X-Other": {"v"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if hdr.Get("Authorization") != "Sourcegraph-Ticket abc" {
+		t.Error("redactHeader modified its argument")
+	}
+}
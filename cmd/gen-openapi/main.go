@@ -0,0 +1,44 @@
+// Command gen-openapi writes an OpenAPI 3 document that describes the
+// Sourcegraph API to stdout.
+//
+// Usage:
+//
+//	gen-openapi [-version=VERSION] [-server=URL] > openapi.json
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/openapi"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
+)
+
+var (
+	version = flag.String("version", "dev", "API version to include in the document's info")
+	server  = flag.String("server", "https://sourcegraph.com/api", "base URL of the API server")
+)
+
+func main() {
+	flag.Parse()
+	log.SetFlags(0)
+
+	doc, err := openapi.Generate(router.NewAPIRouter(nil), openapi.Info{
+		Title:   "Sourcegraph API",
+		Version: *version,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *server != "" {
+		doc.Servers = []openapi.Server{{URL: *server}}
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		log.Fatal(err)
+	}
+}
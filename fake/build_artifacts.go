@@ -0,0 +1,187 @@
+package fake
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"path"
+	"sort"
+	"strings"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/sourcegraph"
+	"sourcegraph.com/sourcegraph/rwvfs"
+)
+
+// BuildArtifactsService is a fake sourcegraph.BuildArtifactsService.
+type BuildArtifactsService struct {
+	s *Store
+}
+
+var _ sourcegraph.BuildArtifactsService = &BuildArtifactsService{}
+
+// DefaultMaxBuildArtifactSize is the size limit of artifacts unless
+// another limit is set with SetMaxBuildArtifactSize.
+const DefaultMaxBuildArtifactSize = 32 << 20
+
+// storedArtifact is an artifact and its contents.
+type storedArtifact struct {
+	sourcegraph.BuildArtifact
+	data []byte
+}
+
+// SetMaxBuildArtifactSize sets the size limit of artifacts that are
+// uploaded from now on.
+func (s *Store) SetMaxBuildArtifactSize(n int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.maxArtifactSize = n
+}
+
+// checkArtifactOwner returns an error if owner (a build, or a task if
+// owner.TaskID is nonzero) does not exist. The method and route of
+// the request are used in the error. The caller must hold s.mu.
+func (s *Store) checkArtifactOwner(owner sourcegraph.TaskSpec, method, route string) error {
+	if _, err := s.build(owner.BuildSpec); err != nil {
+		return err
+	}
+	if owner.TaskID == 0 {
+		return nil
+	}
+	for _, t := range s.tasks {
+		if t.BID == owner.BID && t.TaskID == owner.TaskID {
+			return nil
+		}
+	}
+	return httpError(method, route, owner.RouteVars(), 404, "task not found")
+}
+
+// artifact returns the index in s.artifacts of the artifact specified
+// by spec, or -1 if there is none. The caller must hold s.mu.
+func (s *Store) artifact(spec sourcegraph.BuildArtifactSpec) int {
+	for i, a := range s.artifacts {
+		if a.BID == spec.BID && a.TaskID == spec.TaskID && a.Path == spec.Path {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *BuildArtifactsService) List(ctx context.Context, owner sourcegraph.TaskSpec, opt *sourcegraph.BuildArtifactListOptions) ([]*sourcegraph.BuildArtifact, sourcegraph.Response, error) {
+	if opt == nil {
+		opt = &sourcegraph.BuildArtifactListOptions{}
+	}
+
+	s.s.mu.Lock()
+	defer s.s.mu.Unlock()
+	if err := s.s.checkArtifactOwner(owner, "GET", router.BuildTaskArtifacts); err != nil {
+		return nil, noTotal(), err
+	}
+	var artifacts []*sourcegraph.BuildArtifact
+	for _, a := range s.s.artifacts {
+		if a.BID == owner.BID && a.TaskID == owner.TaskID && strings.HasPrefix(a.Path, opt.Prefix) {
+			a2 := a.BuildArtifact
+			artifacts = append(artifacts, &a2)
+		}
+	}
+	sort.Slice(artifacts, func(i, j int) bool { return artifacts[i].Path < artifacts[j].Path })
+	return artifacts, &Response{Total: len(artifacts)}, nil
+}
+
+// Upload reads the contents into memory, up to the size limit (see
+// SetMaxBuildArtifactSize).
+func (s *BuildArtifactsService) Upload(ctx context.Context, artifact sourcegraph.BuildArtifactSpec, r io.Reader, opt *sourcegraph.BuildArtifactUploadOptions) (*sourcegraph.BuildArtifact, sourcegraph.Response, error) {
+	if opt == nil {
+		opt = &sourcegraph.BuildArtifactUploadOptions{}
+	}
+
+	route := router.BuildArtifactUpload
+	if artifact.TaskID != 0 {
+		route = router.BuildTaskArtifactUpload
+	}
+	if p := artifact.Path; p == "" || p == "." || path.Clean(p) != p || path.IsAbs(p) || p == ".." || strings.HasPrefix(p, "../") {
+		return nil, noTotal(), httpError("PUT", route, artifact.RouteVars(), 400, "invalid build artifact path")
+	}
+
+	s.s.mu.Lock()
+	err := s.s.checkArtifactOwner(artifact.TaskSpec, "PUT", route)
+	limit := s.s.maxArtifactSize
+	s.s.mu.Unlock()
+	if err != nil {
+		return nil, noTotal(), err
+	}
+	if limit == 0 {
+		limit = DefaultMaxBuildArtifactSize
+	}
+
+	// Read the contents without holding the lock.
+	data, err := ioutil.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, noTotal(), err
+	}
+	if int64(len(data)) > limit {
+		return nil, noTotal(), sourcegraph.ErrBuildArtifactTooLarge
+	}
+	sum := sha256.Sum256(data)
+	a := &storedArtifact{
+		BuildArtifact: sourcegraph.BuildArtifact{
+			BID:         artifact.BID,
+			TaskID:      artifact.TaskID,
+			Path:        artifact.Path,
+			Size:        int64(len(data)),
+			SHA256:      hex.EncodeToString(sum[:]),
+			ContentType: opt.ContentType,
+			CreatedAt:   now(),
+		},
+		data: data,
+	}
+	if opt.SHA256 != "" && !strings.EqualFold(opt.SHA256, a.SHA256) {
+		return nil, noTotal(), sourcegraph.ErrBuildArtifactHashMismatch
+	}
+
+	s.s.mu.Lock()
+	defer s.s.mu.Unlock()
+	if i := s.s.artifact(artifact); i != -1 {
+		s.s.artifacts[i] = a
+	} else {
+		s.s.artifacts = append(s.s.artifacts, a)
+	}
+	a2 := a.BuildArtifact
+	return &a2, noTotal(), nil
+}
+
+func (s *BuildArtifactsService) Download(ctx context.Context, artifact sourcegraph.BuildArtifactSpec) (io.ReadCloser, *sourcegraph.BuildArtifact, sourcegraph.Response, error) {
+	s.s.mu.Lock()
+	defer s.s.mu.Unlock()
+	if err := s.s.checkArtifactOwner(artifact.TaskSpec, "GET", router.BuildTaskArtifact); err != nil {
+		return nil, nil, noTotal(), err
+	}
+	i := s.s.artifact(artifact)
+	if i == -1 {
+		return nil, nil, noTotal(), sourcegraph.ErrBuildArtifactNotExist
+	}
+	a := s.s.artifacts[i]
+	a2 := a.BuildArtifact
+	return ioutil.NopCloser(bytes.NewReader(a.data)), &a2, noTotal(), nil
+}
+
+func (s *BuildArtifactsService) Delete(ctx context.Context, artifact sourcegraph.BuildArtifactSpec) (sourcegraph.Response, error) {
+	s.s.mu.Lock()
+	defer s.s.mu.Unlock()
+	if err := s.s.checkArtifactOwner(artifact.TaskSpec, "DELETE", router.BuildTaskArtifactDelete); err != nil {
+		return noTotal(), err
+	}
+	i := s.s.artifact(artifact)
+	if i == -1 {
+		return noTotal(), sourcegraph.ErrBuildArtifactNotExist
+	}
+	s.s.artifacts = append(s.s.artifacts[:i], s.s.artifacts[i+1:]...)
+	return noTotal(), nil
+}
+
+func (s *BuildArtifactsService) FileSystem(ctx context.Context, owner sourcegraph.TaskSpec) (rwvfs.FileSystem, error) {
+	return sourcegraph.BuildArtifactsFileSystem(ctx, s, owner), nil
+}
@@ -0,0 +1,83 @@
+package fake
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/sourcegraph"
+)
+
+func TestBuildArtifactsService(t *testing.T) {
+	client, store := NewClient()
+	ctx := context.Background()
+
+	repo := store.AddRepo(&sourcegraph.Repo{URI: "github.com/o/r"}).RepoSpec()
+	build, _, err := client.Builds.Create(ctx, sourcegraph.RepoRevSpec{RepoSpec: repo, Rev: "c"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tasks, _, err := client.Builds.CreateTasks(ctx, build.Spec(), []*sourcegraph.BuildTask{{Op: "graph"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	buildOwner := sourcegraph.TaskSpec{BuildSpec: build.Spec()}
+	taskOwner := tasks[0].Spec()
+
+	sum := sha256.Sum256([]byte("hello"))
+	hash := hex.EncodeToString(sum[:])
+	spec := sourcegraph.BuildArtifactSpec{TaskSpec: taskOwner, Path: "reports/a.txt"}
+	artifact, _, err := client.BuildArtifacts.Upload(ctx, spec, strings.NewReader("hello"), &sourcegraph.BuildArtifactUploadOptions{SHA256: hash, ContentType: "text/plain"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if artifact.Size != 5 || artifact.SHA256 != hash || artifact.TaskID != taskOwner.TaskID || artifact.ContentType != "text/plain" {
+		t.Errorf("got artifact %+v, want 5-byte task artifact with hash %s", artifact, hash)
+	}
+	if _, _, err := client.BuildArtifacts.Upload(ctx, sourcegraph.BuildArtifactSpec{TaskSpec: buildOwner, Path: "b.txt"}, strings.NewReader("b"), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// The artifacts of the build and of its task are separate.
+	if artifacts, _, err := client.BuildArtifacts.List(ctx, taskOwner, nil); err != nil || len(artifacts) != 1 || artifacts[0].Path != "reports/a.txt" {
+		t.Errorf("got task artifacts %+v (error %v), want only reports/a.txt", artifacts, err)
+	}
+	if artifacts, _, err := client.BuildArtifacts.List(ctx, buildOwner, &sourcegraph.BuildArtifactListOptions{Prefix: "reports/"}); err != nil || len(artifacts) != 0 {
+		t.Errorf("got build artifacts %+v (error %v) with prefix reports/, want none", artifacts, err)
+	}
+
+	rc, _, _, err := client.BuildArtifacts.Download(ctx, spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := ioutil.ReadAll(rc)
+	rc.Close()
+	if string(data) != "hello" {
+		t.Errorf("got contents %q, want %q", data, "hello")
+	}
+
+	store.SetMaxBuildArtifactSize(3)
+	if _, _, err := client.BuildArtifacts.Upload(ctx, spec, strings.NewReader("toolong"), nil); err != sourcegraph.ErrBuildArtifactTooLarge {
+		t.Errorf("got error %v uploading too-large artifact, want ErrBuildArtifactTooLarge", err)
+	}
+	if _, _, err := client.BuildArtifacts.Upload(ctx, spec, strings.NewReader("abc"), &sourcegraph.BuildArtifactUploadOptions{SHA256: hash}); err != sourcegraph.ErrBuildArtifactHashMismatch {
+		t.Errorf("got error %v uploading artifact with wrong hash, want ErrBuildArtifactHashMismatch", err)
+	}
+	if _, _, err := client.BuildArtifacts.Upload(ctx, sourcegraph.BuildArtifactSpec{TaskSpec: buildOwner, Path: "../x"}, strings.NewReader("x"), nil); err == nil {
+		t.Error("got nil error uploading artifact with invalid path, want non-nil")
+	}
+	missingTask := sourcegraph.TaskSpec{BuildSpec: build.Spec(), TaskID: 999}
+	if _, _, err := client.BuildArtifacts.List(ctx, missingTask, nil); err == nil {
+		t.Error("got nil error listing artifacts of nonexistent task, want non-nil")
+	}
+
+	if _, err := client.BuildArtifacts.Delete(ctx, spec); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, err := client.BuildArtifacts.Download(ctx, spec); err != sourcegraph.ErrBuildArtifactNotExist {
+		t.Errorf("got error %v downloading deleted artifact, want ErrBuildArtifactNotExist", err)
+	}
+}
@@ -0,0 +1,783 @@
+package fake
+
+import (
+	"context"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/db_common"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/sourcegraph"
+)
+
+// BuildsService is a fake sourcegraph.BuildsService.
+type BuildsService struct {
+	s *Store
+}
+
+var _ sourcegraph.BuildsService = &BuildsService{}
+
+// AppendBuildLog appends entries to a build's log.
+func (s *Store) AppendBuildLog(build sourcegraph.BuildSpec, entries ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.buildLogs[build.BID] = append(s.buildLogs[build.BID], entries...)
+}
+
+// AppendTaskLog appends entries to a task's log.
+func (s *Store) AppendTaskLog(task sourcegraph.TaskSpec, entries ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.taskLogs[task.TaskID] = append(s.taskLogs[task.TaskID], entries...)
+}
+
+// SetBuildConcurrencyLimits sets the limits that DequeueNext enforces.
+// By default, there are no limits.
+func (s *Store) SetBuildConcurrencyLimits(l sourcegraph.BuildConcurrencyLimits) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.buildLimits = l
+}
+
+// build returns the stored build specified by spec. The caller must
+// hold s.mu.
+func (s *Store) build(spec sourcegraph.BuildSpec) (*sourcegraph.Build, error) {
+	for _, b := range s.builds {
+		if b.BID == spec.BID {
+			return b, nil
+		}
+	}
+	return nil, sourcegraph.ErrBuildNotFound
+}
+
+// copyBuild returns a copy of b with RepoURI populated, as the real
+// server populates it in results. The caller must hold s.mu.
+func (s *Store) copyBuild(b *sourcegraph.Build) *sourcegraph.Build {
+	b2 := *b
+	for _, r := range s.repos {
+		if r.RID == b.Repo {
+			uri := r.URI
+			b2.RepoURI = &uri
+			break
+		}
+	}
+	return &b2
+}
+
+func (s *Store) hasSuccessfulBuild(rid int) bool {
+	for _, b := range s.builds {
+		if b.Repo == rid && b.Success {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *BuildsService) Get(ctx context.Context, build sourcegraph.BuildSpec, opt *sourcegraph.BuildGetOptions) (*sourcegraph.Build, sourcegraph.Response, error) {
+	s.s.mu.Lock()
+	defer s.s.mu.Unlock()
+	b, err := s.s.build(build)
+	if err != nil {
+		return nil, noTotal(), err
+	}
+	return s.s.copyBuild(b), noTotal(), nil
+}
+
+// List lists builds, newest first unless opt.Sort and opt.Direction
+// specify otherwise. Sort may be "created_at" (the default),
+// "started_at", "ended_at", "updated_at" (the most recent of the
+// three), or "priority".
+func (s *BuildsService) List(ctx context.Context, opt *sourcegraph.BuildListOptions) ([]*sourcegraph.Build, sourcegraph.Response, error) {
+	if opt == nil {
+		opt = &sourcegraph.BuildListOptions{}
+	}
+
+	s.s.mu.Lock()
+	defer s.s.mu.Unlock()
+
+	var builds []*sourcegraph.Build
+	for _, b := range s.s.builds {
+		if opt.Queued && !(b.Queue && !b.StartedAt.Valid) {
+			continue
+		}
+		if opt.Active && !(b.StartedAt.Valid && !b.EndedAt.Valid) {
+			continue
+		}
+		if opt.Ended && !b.EndedAt.Valid {
+			continue
+		}
+		if opt.Succeeded && !b.Success {
+			continue
+		}
+		if opt.Failed && !b.Failure {
+			continue
+		}
+		if opt.Canceled && !b.Canceled {
+			continue
+		}
+		if opt.Purged && !b.Purged {
+			continue
+		}
+		b2 := s.s.copyBuild(b)
+		if opt.Repo != "" && (b2.RepoURI == nil || *b2.RepoURI != opt.Repo) {
+			continue
+		}
+		if opt.CommitID != "" && b.CommitID != opt.CommitID {
+			continue
+		}
+		builds = append(builds, b2)
+	}
+
+	var key func(b *sourcegraph.Build) int64
+	switch opt.Sort {
+	case "", "created_at":
+		key = func(b *sourcegraph.Build) int64 { return b.CreatedAt.UnixNano() }
+	case "started_at":
+		key = func(b *sourcegraph.Build) int64 { return nullTimeKey(b.StartedAt) }
+	case "ended_at":
+		key = func(b *sourcegraph.Build) int64 { return nullTimeKey(b.EndedAt) }
+	case "updated_at":
+		key = func(b *sourcegraph.Build) int64 {
+			k := b.CreatedAt.UnixNano()
+			for _, t := range []db_common.NullTime{b.StartedAt, b.EndedAt} {
+				if t := nullTimeKey(t); t > k {
+					k = t
+				}
+			}
+			return k
+		}
+	case "priority":
+		key = func(b *sourcegraph.Build) int64 { return int64(b.Priority) }
+	}
+	if key != nil {
+		// Ties are broken by BID, so that builds created in the same
+		// instant are ordered as they were created.
+		sort.SliceStable(builds, func(i, j int) bool {
+			ki, kj := key(builds[i]), key(builds[j])
+			if ki != kj {
+				return ki < kj
+			}
+			return builds[i].BID < builds[j].BID
+		})
+	}
+	if opt.Direction != "asc" {
+		reverse(builds)
+	}
+
+	builds, resp := page(builds, opt.ListOptions)
+	return builds, resp, nil
+}
+
+func nullTimeKey(t db_common.NullTime) int64 {
+	if !t.Valid {
+		return 0
+	}
+	return t.Time.UnixNano()
+}
+
+// Create creates a build of the commit that repoRev resolves to (or,
+// if it can't be resolved using the commits, branches, and tags added
+// to the Store, of the commit ID repoRev.Rev).
+func (s *BuildsService) Create(ctx context.Context, repoRev sourcegraph.RepoRevSpec, opt *sourcegraph.BuildCreateOptions) (*sourcegraph.Build, sourcegraph.Response, error) {
+	if opt == nil {
+		opt = &sourcegraph.BuildCreateOptions{}
+	}
+
+	s.s.mu.Lock()
+	defer s.s.mu.Unlock()
+	r, err := s.s.repo(repoRev.RepoSpec)
+	if err != nil {
+		return nil, noTotal(), err
+	}
+	commitID, ok := s.s.resolveRev(r, repoRev)
+	if !ok {
+		commitID = repoRev.Rev
+	}
+
+	if !opt.Force {
+		for i := len(s.s.builds) - 1; i >= 0; i-- {
+			b := s.s.builds[i]
+			if b.Repo == r.RID && b.CommitID == commitID && b.BuildConfig == opt.BuildConfig {
+				if opt.PullRepo != 0 {
+					b.BuildMeta = opt.BuildMeta
+				}
+				return s.s.copyBuild(b), noTotal(), nil
+			}
+		}
+	}
+
+	s.s.lastBID++
+	b := &sourcegraph.Build{
+		BID:         s.s.lastBID,
+		Repo:        r.RID,
+		CommitID:    commitID,
+		CreatedAt:   now(),
+		BuildConfig: opt.BuildConfig,
+		BuildMeta:   opt.BuildMeta,
+	}
+	s.s.builds = append(s.s.builds, b)
+	if b.Queue {
+		s.s.addBuildEvent(sourcegraph.BuildEventQueued, b, nil)
+	}
+	return s.s.copyBuild(b), noTotal(), nil
+}
+
+func (s *BuildsService) Update(ctx context.Context, build sourcegraph.BuildSpec, info sourcegraph.BuildUpdate) (*sourcegraph.Build, sourcegraph.Response, error) {
+	s.s.mu.Lock()
+	defer s.s.mu.Unlock()
+	b, err := s.s.build(build)
+	if err != nil {
+		return nil, noTotal(), err
+	}
+	if b.EndedAt.Valid && info.SetsResult() {
+		return nil, noTotal(), sourcegraph.ErrBuildEnded
+	}
+	before := *b
+	if info.StartedAt != nil {
+		b.StartedAt = db_common.NullTime{Time: *info.StartedAt, Valid: true}
+	}
+	if info.EndedAt != nil {
+		b.EndedAt = db_common.NullTime{Time: *info.EndedAt, Valid: true}
+	}
+	if info.HeartbeatAt != nil {
+		b.HeartbeatAt = db_common.NullTime{Time: *info.HeartbeatAt, Valid: true}
+	}
+	if info.Host != nil {
+		b.Host = *info.Host
+	}
+	if info.Success != nil {
+		b.Success = *info.Success
+	}
+	if info.Purged != nil {
+		b.Purged = *info.Purged
+	}
+	if info.Failure != nil {
+		b.Failure = *info.Failure
+	}
+	if info.Killed != nil {
+		b.Killed = *info.Killed
+	}
+	if info.Priority != nil {
+		b.Priority = *info.Priority
+	}
+
+	if b.StartedAt.Valid && !before.StartedAt.Valid {
+		s.s.addBuildEvent(sourcegraph.BuildEventStarted, b, nil)
+	}
+	if info.HeartbeatAt != nil {
+		s.s.addBuildEvent(sourcegraph.BuildEventHeartbeat, b, nil)
+	}
+	if typ := endEvent(b); typ != "" && endEvent(&before) == "" {
+		s.s.addBuildEvent(typ, b, nil)
+	}
+	return s.s.copyBuild(b), noTotal(), nil
+}
+
+// Cancel marks a build that has not ended as ended and canceled.
+func (s *BuildsService) Cancel(ctx context.Context, build sourcegraph.BuildSpec) (*sourcegraph.Build, sourcegraph.Response, error) {
+	s.s.mu.Lock()
+	defer s.s.mu.Unlock()
+	b, err := s.s.build(build)
+	if err != nil {
+		return nil, noTotal(), err
+	}
+	if b.EndedAt.Valid {
+		return nil, noTotal(), sourcegraph.ErrBuildEnded
+	}
+	b.EndedAt = db_common.Now()
+	b.Canceled = true
+	s.s.addBuildEvent(sourcegraph.BuildEventCanceled, b, nil)
+	return s.s.copyBuild(b), noTotal(), nil
+}
+
+// Retry creates a new build that is a copy of an ended build, without
+// any of the ended build's results.
+func (s *BuildsService) Retry(ctx context.Context, build sourcegraph.BuildSpec) (*sourcegraph.Build, sourcegraph.Response, error) {
+	s.s.mu.Lock()
+	defer s.s.mu.Unlock()
+	b, err := s.s.build(build)
+	if err != nil {
+		return nil, noTotal(), err
+	}
+	if !b.EndedAt.Valid {
+		return nil, noTotal(), sourcegraph.ErrBuildNotEnded
+	}
+	s.s.lastBID++
+	b2 := &sourcegraph.Build{
+		BID:         s.s.lastBID,
+		Repo:        b.Repo,
+		CommitID:    b.CommitID,
+		CreatedAt:   now(),
+		BuildConfig: b.BuildConfig,
+		BuildMeta:   b.BuildMeta,
+	}
+	s.s.builds = append(s.s.builds, b2)
+	if b2.Queue {
+		s.s.addBuildEvent(sourcegraph.BuildEventQueued, b2, nil)
+	}
+	return s.s.copyBuild(b2), noTotal(), nil
+}
+
+func (s *BuildsService) ListBuildTasks(ctx context.Context, build sourcegraph.BuildSpec, opt *sourcegraph.BuildTaskListOptions) ([]*sourcegraph.BuildTask, sourcegraph.Response, error) {
+	if opt == nil {
+		opt = &sourcegraph.BuildTaskListOptions{}
+	}
+
+	s.s.mu.Lock()
+	defer s.s.mu.Unlock()
+	if _, err := s.s.build(build); err != nil {
+		return nil, noTotal(), err
+	}
+	var tasks []*sourcegraph.BuildTask
+	for _, t := range s.s.tasks {
+		if t.BID == build.BID {
+			t2 := *t
+			tasks = append(tasks, &t2)
+		}
+	}
+	tasks, resp := page(tasks, opt.ListOptions)
+	return tasks, resp, nil
+}
+
+// CreateTasks creates tasks after checking their dependencies with
+// sourcegraph.CheckTaskDeps, and replaces the negative entries in
+// their DependsOn fields with the TaskIDs of the tasks they refer to.
+func (s *BuildsService) CreateTasks(ctx context.Context, build sourcegraph.BuildSpec, tasks []*sourcegraph.BuildTask) ([]*sourcegraph.BuildTask, sourcegraph.Response, error) {
+	s.s.mu.Lock()
+	defer s.s.mu.Unlock()
+	if _, err := s.s.build(build); err != nil {
+		return nil, noTotal(), err
+	}
+	var existing []*sourcegraph.BuildTask
+	for _, t := range s.s.tasks {
+		if t.BID == build.BID {
+			existing = append(existing, t)
+		}
+	}
+	if err := sourcegraph.CheckTaskDeps(existing, tasks); err != nil {
+		return nil, noTotal(), err
+	}
+
+	firstID := s.s.lastTask + 1
+	created := make([]*sourcegraph.BuildTask, len(tasks))
+	for i, task := range tasks {
+		t := *task
+		s.s.lastTask++
+		t.TaskID = s.s.lastTask
+		t.BID = build.BID
+		if !t.CreatedAt.Valid {
+			t.CreatedAt = db_common.Now()
+		}
+		if t.DependsOn != nil {
+			t.DependsOn = make([]int64, len(task.DependsOn))
+			for j, dep := range task.DependsOn {
+				if dep < 0 {
+					dep = firstID - dep - 1
+				}
+				t.DependsOn[j] = dep
+			}
+		}
+		s.s.tasks = append(s.s.tasks, &t)
+		t2 := t
+		created[i] = &t2
+	}
+	return created, noTotal(), nil
+}
+
+func (s *BuildsService) UpdateTask(ctx context.Context, task sourcegraph.TaskSpec, info sourcegraph.TaskUpdate) (*sourcegraph.BuildTask, sourcegraph.Response, error) {
+	s.s.mu.Lock()
+	defer s.s.mu.Unlock()
+	b, err := s.s.build(task.BuildSpec)
+	if err != nil {
+		return nil, noTotal(), err
+	}
+	for _, t := range s.s.tasks {
+		if t.BID == task.BID && t.TaskID == task.TaskID {
+			ended := t.EndedAt.Valid
+			if info.StartedAt != nil {
+				t.StartedAt = db_common.NullTime{Time: *info.StartedAt, Valid: true}
+			}
+			if info.EndedAt != nil {
+				t.EndedAt = db_common.NullTime{Time: *info.EndedAt, Valid: true}
+			}
+			if info.Success != nil {
+				t.Success = *info.Success
+			}
+			if info.Failure != nil {
+				t.Failure = *info.Failure
+			}
+			if t.EndedAt.Valid && !ended {
+				s.s.addBuildEvent(sourcegraph.BuildEventTaskEnded, b, t)
+			}
+			t2 := *t
+			return &t2, noTotal(), nil
+		}
+	}
+	return nil, noTotal(), httpError("PUT", router.BuildTaskUpdate, task.RouteVars(), 404, "task not found")
+}
+
+func (s *BuildsService) GetLog(ctx context.Context, build sourcegraph.BuildSpec, opt *sourcegraph.BuildGetLogOptions) (*sourcegraph.LogEntries, sourcegraph.Response, error) {
+	s.s.mu.Lock()
+	defer s.s.mu.Unlock()
+	if _, err := s.s.build(build); err != nil {
+		return nil, noTotal(), err
+	}
+	return logEntries(s.s.buildLogs[build.BID], opt), noTotal(), nil
+}
+
+func (s *BuildsService) GetTaskLog(ctx context.Context, task sourcegraph.TaskSpec, opt *sourcegraph.BuildGetLogOptions) (*sourcegraph.LogEntries, sourcegraph.Response, error) {
+	s.s.mu.Lock()
+	defer s.s.mu.Unlock()
+	if _, err := s.s.build(task.BuildSpec); err != nil {
+		return nil, noTotal(), err
+	}
+	return logEntries(s.s.taskLogs[task.TaskID], opt), noTotal(), nil
+}
+
+// logEntries returns the log entries after opt.MinID. The ID of each
+// log entry is its 1-based line number.
+func logEntries(log []string, opt *sourcegraph.BuildGetLogOptions) *sourcegraph.LogEntries {
+	var minID int
+	if opt != nil && opt.MinID != "" {
+		minID, _ = strconv.Atoi(opt.MinID)
+	}
+	if minID > len(log) {
+		minID = len(log)
+	}
+	return &sourcegraph.LogEntries{
+		MaxID:   strconv.Itoa(len(log)),
+		Entries: append([]string{}, log[minID:]...),
+	}
+}
+
+// DequeueNext returns the queued, unstarted build with the highest
+// priority (and, of those, the oldest) and marks it as started.
+func (s *BuildsService) DequeueNext(ctx context.Context) (*sourcegraph.Build, sourcegraph.Response, error) {
+	s.s.mu.Lock()
+	defer s.s.mu.Unlock()
+	running := s.s.runningBuilds()
+	for _, b := range s.s.buildQueue() {
+		if !s.s.buildLimits.Allow(s.s.copyBuild(b), running) {
+			continue
+		}
+		b.StartedAt = db_common.Now()
+		s.s.addBuildEvent(sourcegraph.BuildEventStarted, b, nil)
+		return s.s.copyBuild(b), noTotal(), nil
+	}
+	return nil, noTotal(), nil
+}
+
+// waiting reports whether b is waiting in the queue.
+func waiting(b *sourcegraph.Build) bool {
+	return b.Queue && !b.StartedAt.Valid && !b.EndedAt.Valid
+}
+
+// buildQueue returns the builds that are waiting in the queue, in the
+// order in which they are dequeued. The caller must hold s.mu.
+func (s *Store) buildQueue() []*sourcegraph.Build {
+	var queue []*sourcegraph.Build
+	for _, b := range s.builds {
+		if waiting(b) {
+			queue = append(queue, b)
+		}
+	}
+	sourcegraph.SortBuildQueue(queue)
+	return queue
+}
+
+// runningBuilds returns copies (see copyBuild) of the builds that have
+// started but not ended. The caller must hold s.mu.
+func (s *Store) runningBuilds() []*sourcegraph.Build {
+	var running []*sourcegraph.Build
+	for _, b := range s.builds {
+		if b.StartedAt.Valid && !b.EndedAt.Valid {
+			running = append(running, s.copyBuild(b))
+		}
+	}
+	return running
+}
+
+// GetQueueStatus estimates the build's start time from the average
+// duration of the last 10 builds that ran to completion.
+func (s *BuildsService) GetQueueStatus(ctx context.Context, build sourcegraph.BuildSpec) (*sourcegraph.BuildQueueStatus, sourcegraph.Response, error) {
+	s.s.mu.Lock()
+	defer s.s.mu.Unlock()
+	b, err := s.s.build(build)
+	if err != nil {
+		return nil, noTotal(), err
+	}
+	if !waiting(b) {
+		return nil, noTotal(), sourcegraph.ErrBuildNotQueued
+	}
+
+	status := &sourcegraph.BuildQueueStatus{BID: b.BID, Running: len(s.s.runningBuilds())}
+	for _, qb := range s.s.buildQueue() {
+		status.Position++
+		if qb == b {
+			break
+		}
+		if status.AheadByRepo == nil {
+			status.AheadByRepo = map[string]int{}
+		}
+		if uri := s.s.copyBuild(qb).RepoURI; uri != nil {
+			status.AheadByRepo[*uri]++
+		}
+	}
+
+	var total time.Duration
+	var n int
+	for i := len(s.s.builds) - 1; i >= 0 && n < 10; i-- {
+		if eb := s.s.builds[i]; eb.StartedAt.Valid && eb.EndedAt.Valid && !eb.Canceled {
+			total += eb.EndedAt.Time.Sub(eb.StartedAt.Time)
+			n++
+		}
+	}
+	if n > 0 {
+		d := sourcegraph.EstimateBuildStart(status.Position-1, status.Running, total/time.Duration(n))
+		status.EstimatedStartAt = db_common.NullTime{Time: now().Add(d), Valid: true}
+	}
+	return status, noTotal(), nil
+}
+
+func (s *BuildsService) UpdatePriorities(ctx context.Context, update sourcegraph.BuildPriorityUpdate) ([]*sourcegraph.Build, sourcegraph.Response, error) {
+	s.s.mu.Lock()
+	defer s.s.mu.Unlock()
+
+	if len(update.BIDs) == 0 && update.Repo == "" {
+		return nil, noTotal(), httpError("POST", router.BuildsUpdatePriorities, nil, 400, "no builds specified (BIDs or Repo must be set)")
+	}
+	updated := map[*sourcegraph.Build]bool{}
+	for _, bid := range update.BIDs {
+		b, err := s.s.build(sourcegraph.BuildSpec{BID: bid})
+		if err != nil {
+			return nil, noTotal(), err
+		}
+		if !waiting(b) {
+			return nil, noTotal(), sourcegraph.ErrBuildNotQueued
+		}
+		updated[b] = true
+	}
+	if update.Repo != "" {
+		r, err := s.s.repo(sourcegraph.RepoSpec{URI: update.Repo})
+		if err != nil {
+			return nil, noTotal(), err
+		}
+		for _, b := range s.s.builds {
+			if b.Repo == r.RID && waiting(b) {
+				updated[b] = true
+			}
+		}
+	}
+
+	var builds []*sourcegraph.Build
+	for _, b := range s.s.buildQueue() {
+		if !updated[b] {
+			continue
+		}
+		if update.Priority != nil {
+			b.Priority = *update.Priority
+		} else {
+			b.Priority += update.Delta
+		}
+		builds = append(builds, b)
+	}
+	sourcegraph.SortBuildQueue(builds)
+	for i, b := range builds {
+		builds[i] = s.s.copyBuild(b)
+	}
+	return builds, &Response{Total: len(builds)}, nil
+}
+
+// endEvent returns the type of the event that ended b, or "" if b has
+// not ended.
+func endEvent(b *sourcegraph.Build) sourcegraph.BuildEventType {
+	switch {
+	case b.Canceled:
+		return sourcegraph.BuildEventCanceled
+	case b.Killed:
+		return sourcegraph.BuildEventKilled
+	case !b.EndedAt.Valid:
+		return ""
+	case b.Failure:
+		return sourcegraph.BuildEventFailed
+	case b.Success:
+		return sourcegraph.BuildEventSucceeded
+	}
+	return ""
+}
+
+// addBuildEvent records an event of build b (and task, if non-nil),
+// wakes up the ListEvents calls that are waiting for events, and, if
+// the event ends the build, notifies the build hooks of the build's
+// repository. The hooks are notified asynchronously (see
+// WaitForBuildHooks). The caller must hold s.mu.
+func (s *Store) addBuildEvent(typ sourcegraph.BuildEventType, b *sourcegraph.Build, task *sourcegraph.BuildTask) {
+	e := &sourcegraph.BuildEvent{
+		ID:    int64(len(s.events) + 1),
+		Type:  typ,
+		Time:  now(),
+		Build: s.copyBuild(b),
+	}
+	if task != nil {
+		t := *task
+		e.Task = &t
+	}
+	s.events = append(s.events, e)
+	close(s.eventsAdded)
+	s.eventsAdded = make(chan struct{})
+
+	if !typ.Ended() {
+		return
+	}
+	for _, hook := range s.hooks {
+		if hook.Repo == b.Repo {
+			hook := *hook
+			payload := &sourcegraph.BuildHookPayload{HookID: hook.ID, Event: typ, Build: e.Build}
+			s.pendingHooks.Add(1)
+			go s.deliverBuildHook(&hook, payload)
+		}
+	}
+}
+
+// buildHookTimeout is how long a build hook has to respond to a
+// notification.
+const buildHookTimeout = 10 * time.Second
+
+// A BuildHookDelivery records the notification of a build hook.
+type BuildHookDelivery struct {
+	HookID int64
+	Event  sourcegraph.BuildEventType
+	BID    int64
+
+	// Err is the error that the notification failed with, or nil if
+	// the hook responded with a 2xx status.
+	Err error
+}
+
+// deliverBuildHook notifies a build hook and records the result.
+func (s *Store) deliverBuildHook(hook *sourcegraph.BuildHook, payload *sourcegraph.BuildHookPayload) {
+	defer s.pendingHooks.Done()
+	ctx, cancel := context.WithTimeout(context.Background(), buildHookTimeout)
+	defer cancel()
+	err := sourcegraph.PostBuildHook(ctx, nil, hook, payload)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.hookDeliveries = append(s.hookDeliveries, &BuildHookDelivery{HookID: hook.ID, Event: payload.Event, BID: payload.Build.BID, Err: err})
+}
+
+// WaitForBuildHooks waits for the build hook notifications that were
+// started before it was called to be delivered (or to fail), and
+// returns all of the deliveries so far, in the order they completed.
+func (s *Store) WaitForBuildHooks() []*BuildHookDelivery {
+	s.pendingHooks.Wait()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	deliveries := make([]*BuildHookDelivery, len(s.hookDeliveries))
+	for i, d := range s.hookDeliveries {
+		d2 := *d
+		deliveries[i] = &d2
+	}
+	return deliveries
+}
+
+// ListEvents lists the events recorded by the fake services (all of
+// which are kept).
+func (s *BuildsService) ListEvents(ctx context.Context, opt *sourcegraph.BuildEventListOptions) ([]*sourcegraph.BuildEvent, sourcegraph.Response, error) {
+	if opt == nil {
+		opt = &sourcegraph.BuildEventListOptions{}
+	}
+
+	var timeout <-chan time.Time
+	if opt.Wait > 0 {
+		t := time.NewTimer(opt.Wait)
+		defer t.Stop()
+		timeout = t.C
+	}
+	for {
+		s.s.mu.Lock()
+		var events []*sourcegraph.BuildEvent
+		for _, e := range s.s.events {
+			if e.ID <= opt.After || (opt.BID != 0 && e.Build.BID != opt.BID) {
+				continue
+			}
+			if opt.Repo != "" && (e.Build.RepoURI == nil || *e.Build.RepoURI != opt.Repo) {
+				continue
+			}
+			e2 := *e
+			events = append(events, &e2)
+		}
+		added := s.s.eventsAdded
+		s.s.mu.Unlock()
+
+		if len(events) > 0 || timeout == nil {
+			return events, &Response{Total: len(events)}, nil
+		}
+		select {
+		case <-added:
+		case <-timeout:
+			timeout = nil // list once more, and return
+		case <-ctx.Done():
+			return nil, noTotal(), ctx.Err()
+		}
+	}
+}
+
+func (s *BuildsService) ListRepoHooks(ctx context.Context, repo sourcegraph.RepoSpec) ([]*sourcegraph.BuildHook, sourcegraph.Response, error) {
+	s.s.mu.Lock()
+	defer s.s.mu.Unlock()
+	r, err := s.s.repo(repo)
+	if err != nil {
+		return nil, noTotal(), err
+	}
+	var hooks []*sourcegraph.BuildHook
+	for _, h := range s.s.hooks {
+		if h.Repo == r.RID {
+			h2 := *h
+			h2.Secret = ""
+			hooks = append(hooks, &h2)
+		}
+	}
+	return hooks, &Response{Total: len(hooks)}, nil
+}
+
+func (s *BuildsService) CreateRepoHook(ctx context.Context, repo sourcegraph.RepoSpec, hook *sourcegraph.BuildHook) (*sourcegraph.BuildHook, sourcegraph.Response, error) {
+	s.s.mu.Lock()
+	defer s.s.mu.Unlock()
+	r, err := s.s.repo(repo)
+	if err != nil {
+		return nil, noTotal(), err
+	}
+	if u, err := url.Parse(hook.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, noTotal(), httpError("POST", router.RepoBuildHooksCreate, repo.RouteVars(), 400, "build hook URL must be an absolute HTTP(S) URL")
+	}
+	h := *hook
+	s.s.lastHookID++
+	h.ID = s.s.lastHookID
+	h.Repo = r.RID
+	h.CreatedAt = now()
+	s.s.hooks = append(s.s.hooks, &h)
+	h2 := h
+	h2.Secret = ""
+	return &h2, noTotal(), nil
+}
+
+func (s *BuildsService) DeleteRepoHook(ctx context.Context, repo sourcegraph.RepoSpec, hookID int64) (sourcegraph.Response, error) {
+	s.s.mu.Lock()
+	defer s.s.mu.Unlock()
+	r, err := s.s.repo(repo)
+	if err != nil {
+		return noTotal(), err
+	}
+	for i, h := range s.s.hooks {
+		if h.Repo == r.RID && h.ID == hookID {
+			s.s.hooks = append(s.s.hooks[:i], s.s.hooks[i+1:]...)
+			return noTotal(), nil
+		}
+	}
+	vars := repo.RouteVars()
+	vars["HookID"] = strconv.FormatInt(hookID, 10)
+	return noTotal(), httpError("DELETE", router.RepoBuildHookDelete, vars, 404, "build hook not found")
+}
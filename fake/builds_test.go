@@ -0,0 +1,427 @@
+package fake
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/go-github/github"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/sourcegraph"
+)
+
+func TestBuildsService(t *testing.T) {
+	client, store := NewClient()
+	ctx := context.Background()
+
+	repo := store.AddRepo(&sourcegraph.Repo{URI: "github.com/o/r"}).RepoSpec()
+	rev := sourcegraph.RepoRevSpec{RepoSpec: repo, Rev: "c"}
+
+	if _, _, err := client.Builds.Create(ctx, sourcegraph.RepoRevSpec{RepoSpec: sourcegraph.RepoSpec{URI: "x.com/y"}, Rev: "c"}, nil); err != sourcegraph.ErrNotExist {
+		t.Errorf("got error %v, want ErrNotExist", err)
+	}
+
+	b1, _, err := client.Builds.Create(ctx, rev, &sourcegraph.BuildCreateOptions{BuildConfig: sourcegraph.BuildConfig{Queue: true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b1.BID != 1 || b1.CommitID != "c" || b1.RepoURI == nil || *b1.RepoURI != repo.URI {
+		t.Errorf("got build %+v", b1)
+	}
+
+	// Creating an equivalent build returns the existing one, unless
+	// Force is set.
+	b, _, err := client.Builds.Create(ctx, rev, &sourcegraph.BuildCreateOptions{BuildConfig: sourcegraph.BuildConfig{Queue: true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.BID != b1.BID {
+		t.Errorf("got BID %d, want existing build %d", b.BID, b1.BID)
+	}
+	b2, _, err := client.Builds.Create(ctx, rev, &sourcegraph.BuildCreateOptions{BuildConfig: sourcegraph.BuildConfig{Queue: true, Priority: 5}, Force: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, _, err := client.Builds.Get(ctx, b1.Spec(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, b1) {
+		t.Errorf("got %+v, want %+v", got, b1)
+	}
+	if _, _, err := client.Builds.Get(ctx, sourcegraph.BuildSpec{BID: 99}, nil); err != sourcegraph.ErrBuildNotFound {
+		t.Errorf("got error %v, want ErrBuildNotFound", err)
+	}
+
+	// The higher-priority build is dequeued first.
+	for _, want := range []int64{b2.BID, b1.BID} {
+		b, _, err := client.Builds.DequeueNext(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if b == nil || b.BID != want || !b.StartedAt.Valid {
+			t.Errorf("got dequeued build %+v, want started build %d", b, want)
+		}
+	}
+	if b, _, err := client.Builds.DequeueNext(ctx); b != nil || err != nil {
+		t.Errorf("got %+v, %v from empty queue, want nil, nil", b, err)
+	}
+
+	if _, _, err := client.Builds.Update(ctx, b1.Spec(), sourcegraph.BuildUpdate{Failure: github.Bool(true)}); err != nil {
+		t.Fatal(err)
+	}
+	builds, resp, err := client.Builds.List(ctx, &sourcegraph.BuildListOptions{Failed: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(builds) != 1 || builds[0].BID != b1.BID || resp.TotalCount() != 1 {
+		t.Errorf("got failed builds %+v (total %d), want only build %d", builds, resp.TotalCount(), b1.BID)
+	}
+	builds, _, err = client.Builds.List(ctx, &sourcegraph.BuildListOptions{Repo: repo.URI})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(builds) != 2 || builds[0].BID != b2.BID {
+		t.Errorf("got builds %+v, want 2 builds, newest first", builds)
+	}
+}
+
+func TestBuildsService_cancelAndRetry(t *testing.T) {
+	client, store := NewClient()
+	ctx := context.Background()
+
+	repo := store.AddRepo(&sourcegraph.Repo{URI: "github.com/o/r"}).RepoSpec()
+	build, _, err := client.Builds.Create(ctx, sourcegraph.RepoRevSpec{RepoSpec: repo, Rev: "c"}, &sourcegraph.BuildCreateOptions{BuildConfig: sourcegraph.BuildConfig{Queue: true, Priority: 3}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := client.Builds.Retry(ctx, build.Spec()); err != sourcegraph.ErrBuildNotEnded {
+		t.Errorf("got error %v retrying unended build, want ErrBuildNotEnded", err)
+	}
+
+	canceled, _, err := client.Builds.Cancel(ctx, build.Spec())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !canceled.Canceled || !canceled.EndedAt.Valid || canceled.Failure || canceled.Killed {
+		t.Errorf("got canceled build %+v, want ended and canceled (but not failed or killed)", canceled)
+	}
+	if _, _, err := client.Builds.Cancel(ctx, build.Spec()); err != sourcegraph.ErrBuildEnded {
+		t.Errorf("got error %v canceling ended build, want ErrBuildEnded", err)
+	}
+	// A worker that finishes the build after it was canceled can't
+	// record a result.
+	ended, success, failure := time.Now(), true, false
+	if _, _, err := client.Builds.Update(ctx, build.Spec(), sourcegraph.BuildUpdate{EndedAt: &ended, Success: &success, Failure: &failure}); err != sourcegraph.ErrBuildEnded {
+		t.Errorf("got error %v recording result of canceled build, want ErrBuildEnded", err)
+	}
+	if b, _, _ := client.Builds.Get(ctx, build.Spec(), nil); b.Success || b.Failure || b.Killed || !b.Canceled {
+		t.Errorf("got build %+v after result update, want canceled build that neither succeeded nor failed", b)
+	}
+	if b, _, err := client.Builds.DequeueNext(ctx); b != nil || err != nil {
+		t.Errorf("got %+v, %v from queue with only a canceled build, want nil, nil", b, err)
+	}
+	builds, _, err := client.Builds.List(ctx, &sourcegraph.BuildListOptions{Canceled: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(builds) != 1 || builds[0].BID != build.BID {
+		t.Errorf("got canceled builds %+v, want only build %d", builds, build.BID)
+	}
+
+	retried, _, err := client.Builds.Retry(ctx, build.Spec())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if retried.BID == build.BID || retried.CommitID != build.CommitID || retried.BuildConfig != build.BuildConfig || retried.EndedAt.Valid || retried.Canceled {
+		t.Errorf("got retried build %+v, want new unended build with the config of %+v", retried, build)
+	}
+	if b, _, err := client.Builds.DequeueNext(ctx); err != nil || b == nil || b.BID != retried.BID {
+		t.Errorf("got dequeued build %+v (error %v), want retried build %d", b, err, retried.BID)
+	}
+}
+
+func TestBuildsService_tasksAndLogs(t *testing.T) {
+	client, store := NewClient()
+	ctx := context.Background()
+
+	repo := store.AddRepo(&sourcegraph.Repo{URI: "github.com/o/r"}).RepoSpec()
+	build, _, err := client.Builds.Create(ctx, sourcegraph.RepoRevSpec{RepoSpec: repo, Rev: "c"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tasks, _, err := client.Builds.CreateTasks(ctx, build.Spec(), []*sourcegraph.BuildTask{{Op: "a"}, {Op: "b"}, {Op: "c"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != 3 || tasks[2].TaskID != 3 || tasks[2].BID != build.BID || !tasks[2].CreatedAt.Valid {
+		t.Errorf("got created tasks %+v", tasks)
+	}
+	if _, _, err := client.Builds.UpdateTask(ctx, tasks[1].Spec(), sourcegraph.TaskUpdate{Success: github.Bool(true)}); err != nil {
+		t.Fatal(err)
+	}
+	listed, resp, err := client.Builds.ListBuildTasks(ctx, build.Spec(), &sourcegraph.BuildTaskListOptions{ListOptions: sourcegraph.ListOptions{PerPage: 2, Page: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(listed) != 2 || !listed[1].Success || resp.TotalCount() != 3 {
+		t.Errorf("got tasks %+v (total %d), want 2 of 3 with the 2nd succeeded", listed, resp.TotalCount())
+	}
+
+	store.AppendTaskLog(tasks[0].Spec(), "a", "b")
+	entries, _, err := client.Builds.GetTaskLog(ctx, tasks[0].Spec(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (&sourcegraph.LogEntries{MaxID: "2", Entries: []string{"a", "b"}}); !reflect.DeepEqual(entries, want) {
+		t.Errorf("got %+v, want %+v", entries, want)
+	}
+	store.AppendTaskLog(tasks[0].Spec(), "c")
+	entries, _, err = client.Builds.GetTaskLog(ctx, tasks[0].Spec(), &sourcegraph.BuildGetLogOptions{MinID: entries.MaxID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (&sourcegraph.LogEntries{MaxID: "3", Entries: []string{"c"}}); !reflect.DeepEqual(entries, want) {
+		t.Errorf("got %+v, want %+v", entries, want)
+	}
+}
+
+func TestBuildsService_taskDeps(t *testing.T) {
+	client, store := NewClient()
+	ctx := context.Background()
+
+	repo := store.AddRepo(&sourcegraph.Repo{URI: "github.com/o/r"}).RepoSpec()
+	build, _, err := client.Builds.Create(ctx, sourcegraph.RepoRevSpec{RepoSpec: repo, Rev: "c"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	graph, _, err := client.Builds.CreateTasks(ctx, build.Spec(), []*sourcegraph.BuildTask{{Op: "graph", Unit: "a"}, {Op: "graph", Unit: "b"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The import depends on an existing task and on a task created in
+	// the same call.
+	created, _, err := client.Builds.CreateTasks(ctx, build.Spec(), []*sourcegraph.BuildTask{
+		{Op: "graph", Unit: "c"},
+		{Op: sourcegraph.ImportTaskOp, Queue: true, DependsOn: []int64{graph[0].TaskID, -1}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int64{graph[0].TaskID, created[0].TaskID}; !reflect.DeepEqual(created[1].DependsOn, want) {
+		t.Errorf("got created import task deps %v, want %v", created[1].DependsOn, want)
+	}
+
+	tasks, _, err := client.Builds.ListBuildTasks(ctx, build.Spec(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != 4 || !reflect.DeepEqual(tasks[3].DependsOn, created[1].DependsOn) {
+		t.Errorf("got tasks %+v, want the import task listed with its deps", tasks)
+	}
+
+	if _, _, err := client.Builds.CreateTasks(ctx, build.Spec(), []*sourcegraph.BuildTask{{DependsOn: []int64{-2}}, {DependsOn: []int64{-1}}}); !errors.Is(err, sourcegraph.ErrTaskDepCycle) {
+		t.Errorf("got error %v creating cyclic tasks, want ErrTaskDepCycle", err)
+	}
+	if _, _, err := client.Builds.CreateTasks(ctx, build.Spec(), []*sourcegraph.BuildTask{{DependsOn: []int64{99}}}); !errors.Is(err, sourcegraph.ErrTaskDepNotExist) {
+		t.Errorf("got error %v creating task with nonexistent dep, want ErrTaskDepNotExist", err)
+	}
+	if tasks, _, _ := client.Builds.ListBuildTasks(ctx, build.Spec(), nil); len(tasks) != 4 {
+		t.Errorf("got %d tasks after invalid creations, want 4", len(tasks))
+	}
+}
+
+func TestBuildsService_eventsAndHooks(t *testing.T) {
+	client, store := NewClient()
+	ctx := context.Background()
+
+	payloads := make(chan *sourcegraph.BuildHookPayload, 1)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload, err := sourcegraph.ReadBuildHookPayload(r, "s")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		payloads <- payload
+	}))
+	defer ts.Close()
+
+	repo := store.AddRepo(&sourcegraph.Repo{URI: "github.com/o/r"}).RepoSpec()
+	if _, _, err := client.Builds.CreateRepoHook(ctx, repo, &sourcegraph.BuildHook{URL: "ftp://example.com"}); err == nil {
+		t.Error("got nil error creating hook with non-HTTP URL, want non-nil")
+	}
+	hook, _, err := client.Builds.CreateRepoHook(ctx, repo, &sourcegraph.BuildHook{URL: ts.URL, Secret: "s"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hook.ID == 0 || hook.Secret != "" {
+		t.Errorf("got created hook %+v, want ID set and Secret cleared", hook)
+	}
+
+	build, _, err := client.Builds.Create(ctx, sourcegraph.RepoRevSpec{RepoSpec: repo, Rev: "c"}, &sourcegraph.BuildCreateOptions{BuildConfig: sourcegraph.BuildConfig{Queue: true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := client.Builds.DequeueNext(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// A waiting ListEvents call returns when an event occurs.
+	go client.Builds.Cancel(ctx, build.Spec())
+	events, _, err := client.Builds.ListEvents(ctx, &sourcegraph.BuildEventListOptions{BID: build.BID, After: 2, Wait: time.Minute})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || events[0].Type != sourcegraph.BuildEventCanceled || !events[0].Build.Canceled {
+		t.Errorf("got events %+v, want canceled event", events)
+	}
+
+	events, _, err = client.Builds.ListEvents(ctx, &sourcegraph.BuildEventListOptions{Repo: repo.URI})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var types []sourcegraph.BuildEventType
+	for _, e := range events {
+		types = append(types, e.Type)
+	}
+	if want := []sourcegraph.BuildEventType{sourcegraph.BuildEventQueued, sourcegraph.BuildEventStarted, sourcegraph.BuildEventCanceled}; !reflect.DeepEqual(types, want) {
+		t.Errorf("got event types %v, want %v", types, want)
+	}
+
+	deliveries := store.WaitForBuildHooks()
+	if want := []*BuildHookDelivery{{HookID: hook.ID, Event: sourcegraph.BuildEventCanceled, BID: build.BID}}; !reflect.DeepEqual(deliveries, want) {
+		t.Errorf("got hook deliveries %+v, want %+v", deliveries, want)
+	}
+	select {
+	case payload := <-payloads:
+		if payload.HookID != hook.ID || payload.Event != sourcegraph.BuildEventCanceled || payload.Build.BID != build.BID {
+			t.Errorf("got hook payload %+v, want canceled event of build %d", payload, build.BID)
+		}
+	default:
+		t.Fatal("hook received no payload")
+	}
+
+	if _, err := client.Builds.DeleteRepoHook(ctx, repo, hook.ID); err != nil {
+		t.Fatal(err)
+	}
+	if hooks, _, err := client.Builds.ListRepoHooks(ctx, repo); err != nil || len(hooks) != 0 {
+		t.Errorf("got hooks %+v (error %v) after deleting, want none", hooks, err)
+	}
+	if _, err := client.Builds.DeleteRepoHook(ctx, repo, hook.ID); err == nil {
+		t.Error("got nil error deleting nonexistent hook, want non-nil")
+	}
+}
+
+func TestBuildsService_hookDeliveryError(t *testing.T) {
+	client, store := NewClient()
+	ctx := context.Background()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer ts.Close()
+
+	repo := store.AddRepo(&sourcegraph.Repo{URI: "github.com/o/r"}).RepoSpec()
+	hook, _, err := client.Builds.CreateRepoHook(ctx, repo, &sourcegraph.BuildHook{URL: ts.URL, Secret: "s"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	build, _, err := client.Builds.Create(ctx, sourcegraph.RepoRevSpec{RepoSpec: repo, Rev: "c"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ended, failure := time.Now(), true
+	if _, _, err := client.Builds.Update(ctx, build.Spec(), sourcegraph.BuildUpdate{EndedAt: &ended, Failure: &failure}); err != nil {
+		t.Fatal(err)
+	}
+
+	deliveries := store.WaitForBuildHooks()
+	if len(deliveries) != 1 || deliveries[0].HookID != hook.ID || deliveries[0].Event != sourcegraph.BuildEventFailed || deliveries[0].Err == nil {
+		t.Errorf("got hook deliveries %+v, want 1 failed delivery of failed event", deliveries)
+	}
+}
+
+func TestBuildsService_queue(t *testing.T) {
+	client, store := NewClient()
+	ctx := context.Background()
+
+	repoA := store.AddRepo(&sourcegraph.Repo{URI: "github.com/o/a"}).RepoSpec()
+	repoB := store.AddRepo(&sourcegraph.Repo{URI: "github.com/o/b"}).RepoSpec()
+	create := func(repo sourcegraph.RepoSpec, rev string, priority int) *sourcegraph.Build {
+		b, _, err := client.Builds.Create(ctx, sourcegraph.RepoRevSpec{RepoSpec: repo, Rev: rev}, &sourcegraph.BuildCreateOptions{BuildConfig: sourcegraph.BuildConfig{Queue: true, Priority: priority}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+	a1, a2, b1 := create(repoA, "1", 0), create(repoA, "2", 0), create(repoB, "1", 0)
+
+	status, _, err := client.Builds.GetQueueStatus(ctx, b1.Spec())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Position != 3 || !reflect.DeepEqual(status.AheadByRepo, map[string]int{repoA.URI: 2}) || status.EstimatedStartAt.Valid {
+		t.Errorf("got queue status %+v, want position 3 with 2 builds of %s ahead and no estimate", status, repoA.URI)
+	}
+
+	// Move the builds of repo B to the front.
+	builds, _, err := client.Builds.UpdatePriorities(ctx, sourcegraph.BuildPriorityUpdate{Repo: repoB.URI, Delta: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(builds) != 1 || builds[0].BID != b1.BID || builds[0].Priority != 10 {
+		t.Errorf("got updated builds %+v, want build %d with priority 10", builds, b1.BID)
+	}
+	if status, _, err := client.Builds.GetQueueStatus(ctx, b1.Spec()); err != nil || status.Position != 1 {
+		t.Errorf("got queue status %+v (error %v), want position 1", status, err)
+	}
+
+	// At most 1 build of each repo may run at once, so a2 is skipped
+	// while a1 is running.
+	store.SetBuildConcurrencyLimits(sourcegraph.BuildConcurrencyLimits{PerRepo: 1})
+	var dequeued []int64
+	for {
+		b, _, err := client.Builds.DequeueNext(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if b == nil {
+			break
+		}
+		dequeued = append(dequeued, b.BID)
+	}
+	if want := []int64{b1.BID, a1.BID}; !reflect.DeepEqual(dequeued, want) {
+		t.Errorf("got dequeued builds %v, want %v", dequeued, want)
+	}
+
+	if _, _, err := client.Builds.GetQueueStatus(ctx, a1.Spec()); err != sourcegraph.ErrBuildNotQueued {
+		t.Errorf("got error %v for running build, want ErrBuildNotQueued", err)
+	}
+	priority := 1
+	if _, _, err := client.Builds.UpdatePriorities(ctx, sourcegraph.BuildPriorityUpdate{BIDs: []int64{a2.BID, a1.BID}, Priority: &priority}); err != sourcegraph.ErrBuildNotQueued {
+		t.Errorf("got error %v updating running build, want ErrBuildNotQueued", err)
+	}
+
+	ended, success := time.Now(), true
+	if _, _, err := client.Builds.Update(ctx, a1.Spec(), sourcegraph.BuildUpdate{EndedAt: &ended, Success: &success}); err != nil {
+		t.Fatal(err)
+	}
+	status, _, err = client.Builds.GetQueueStatus(ctx, a2.Spec())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Position != 1 || status.Running != 1 || !status.EstimatedStartAt.Valid {
+		t.Errorf("got queue status %+v, want position 1 with 1 running build and an estimate", status)
+	}
+	if b, _, err := client.Builds.DequeueNext(ctx); err != nil || b == nil || b.BID != a2.BID {
+		t.Errorf("got dequeued build %+v (error %v), want %d", b, err, a2.BID)
+	}
+}
@@ -0,0 +1,201 @@
+// Package fake provides stateful, in-memory implementations of the
+// Sourcegraph API services, for use in tests of code that uses the
+// API client.
+//
+// Unlike the sourcegraph.Mock*Service types, which forward each call
+// to a user-provided function, the fake services store the objects
+// that are created (or seeded using the Store's Add* methods) and
+// return them from subsequent calls. They honor ListOptions
+// pagination and the common list filters, and they return the same
+// error values (sourcegraph.ErrNotExist, sourcegraph.ErrRenamed,
+// sourcegraph.ErrBuildNotFound, etc.) as the real server.
+//
+//	client, store := fake.NewClient()
+//	store.AddRepo(&sourcegraph.Repo{URI: "github.com/foo/bar"})
+//	build, _, err := client.Builds.Create(ctx, sourcegraph.RepoRevSpec{...}, nil)
+//
+// The fake services are safe for concurrent use. Objects are
+// (shallowly) copied when they are stored and when they are returned,
+// so callers may set the fields of the objects they pass in and get
+// back without affecting the stored state.
+package fake
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/sourcegraph"
+	"sourcegraph.com/sourcegraph/go-vcs/vcs"
+)
+
+// A Store holds the state shared by the fake services.
+type Store struct {
+	mu sync.Mutex
+
+	// repos
+	repos        []*sourcegraph.Repo
+	renamedRepos map[string]string // old URI -> new URI
+	repoSettings map[int]*sourcegraph.RepoSettings
+	statuses     map[repoCommit][]*sourcegraph.RepoStatus
+	commits      map[int][]*sourcegraph.Commit
+	branches     map[int][]*vcs.Branch
+	tags         map[int][]*vcs.Tag
+
+	// builds
+	builds    []*sourcegraph.Build
+	tasks     []*sourcegraph.BuildTask
+	buildLogs map[int64][]string
+	taskLogs  map[int64][]string
+	lastBID   int64
+	lastTask  int64
+
+	// build events and hooks
+	events      []*sourcegraph.BuildEvent
+	eventsAdded chan struct{} // closed (and replaced) when events are added
+	hooks       []*sourcegraph.BuildHook
+	lastHookID  int64
+
+	// build hook deliveries (see WaitForBuildHooks)
+	hookDeliveries []*BuildHookDelivery
+	pendingHooks   sync.WaitGroup
+
+	// build queue limits (see SetBuildConcurrencyLimits)
+	buildLimits sourcegraph.BuildConcurrencyLimits
+
+	// build artifacts
+	artifacts       []*storedArtifact
+	maxArtifactSize int64 // if 0, DefaultMaxBuildArtifactSize
+
+	// issues and pull requests (keyed on RID)
+	issues        map[int][]*sourcegraph.Issue
+	issueComments map[issueKey][]*sourcegraph.IssueComment
+	pulls         map[int][]*sourcegraph.PullRequest
+	pullComments  map[issueKey][]*sourcegraph.PullRequestComment
+	lastCommentID int
+
+	// users and orgs
+	users        []*sourcegraph.User
+	renamedUsers map[string]string // old login -> new login
+	userSettings map[int]*sourcegraph.UserSettings
+	orgSettings  map[int]*sourcegraph.OrgSettings
+	emails       map[int][]*sourcegraph.EmailAddr
+	members      map[int][]int // org UID -> member UIDs
+	lastUID      int
+
+	lastRID      int
+	lastGitHubID int // for repos and users created from GitHub
+}
+
+type repoCommit struct {
+	rid      int
+	commitID string
+}
+
+type issueKey struct {
+	rid    int
+	number int
+}
+
+// NewStore creates a new, empty Store.
+func NewStore() *Store {
+	return &Store{
+		renamedRepos:  map[string]string{},
+		repoSettings:  map[int]*sourcegraph.RepoSettings{},
+		statuses:      map[repoCommit][]*sourcegraph.RepoStatus{},
+		commits:       map[int][]*sourcegraph.Commit{},
+		branches:      map[int][]*vcs.Branch{},
+		tags:          map[int][]*vcs.Tag{},
+		buildLogs:     map[int64][]string{},
+		taskLogs:      map[int64][]string{},
+		eventsAdded:   make(chan struct{}),
+		issues:        map[int][]*sourcegraph.Issue{},
+		issueComments: map[issueKey][]*sourcegraph.IssueComment{},
+		pulls:         map[int][]*sourcegraph.PullRequest{},
+		pullComments:  map[issueKey][]*sourcegraph.PullRequestComment{},
+		renamedUsers:  map[string]string{},
+		userSettings:  map[int]*sourcegraph.UserSettings{},
+		orgSettings:   map[int]*sourcegraph.OrgSettings{},
+		emails:        map[int][]*sourcegraph.EmailAddr{},
+		members:       map[int][]int{},
+	}
+}
+
+// NewClient returns an API client whose Repos, Builds,
+// BuildArtifacts, Issues, PullRequests, Orgs, and Users services are
+// fakes backed by a new Store. The client's other services are zero-valued mocks (see the
+// sourcegraph.Mock*Service types) that panic when called unless their
+// function fields are set.
+func NewClient() (*sourcegraph.Client, *Store) {
+	s := NewStore()
+	c := sourcegraph.NewClient(nil)
+	c.BuildData = sourcegraph.MockBuildDataService{}
+	c.Deltas = sourcegraph.MockDeltasService{}
+	c.People = sourcegraph.MockPeopleService{}
+	c.RepoTree = sourcegraph.MockRepoTreeService{}
+	c.Search = sourcegraph.MockSearchService{}
+	c.Units = sourcegraph.MockUnitsService{}
+	c.Defs = sourcegraph.MockDefsService{}
+	c.Markdown = sourcegraph.MockMarkdownService{}
+
+	c.Repos = &ReposService{s}
+	c.Builds = &BuildsService{s}
+	c.BuildArtifacts = &BuildArtifactsService{s}
+	c.Issues = &IssuesService{s}
+	c.PullRequests = &PullRequestsService{s}
+	c.Orgs = &OrgsService{s}
+	c.Users = &UsersService{s}
+	return c, s
+}
+
+// Response is the sourcegraph.Response returned by the fake
+// services.
+type Response struct {
+	// Total is the total number of items in a list result (before
+	// pagination), or -1 for non-list results.
+	Total int
+}
+
+// TotalCount implements sourcegraph.Response.
+func (r *Response) TotalCount() int { return r.Total }
+
+// noTotal returns the Response for non-list results.
+func noTotal() *Response { return &Response{Total: -1} }
+
+// page returns the page of items specified by opt and a Response
+// holding the total number of items.
+func page[T any](items []T, opt sourcegraph.ListOptions) ([]T, *Response) {
+	resp := &Response{Total: len(items)}
+	start := opt.Offset()
+	if start > len(items) {
+		start = len(items)
+	}
+	end := start + opt.Limit()
+	if end > len(items) {
+		end = len(items)
+	}
+	return items[start:end], resp
+}
+
+// httpError returns an error equivalent to the one that the API
+// client returns when the server responds to a request for the given
+// route with an HTTP error status code. It satisfies
+// sourcegraph.IsHTTPErrorCode. If the route's URL can't be constructed
+// from routeVars, the error's request has a nil URL.
+func httpError(method, route string, routeVars map[string]string, statusCode int, msg string) error {
+	u, err := sourcegraph.URL(route, routeVars, nil)
+	if err != nil {
+		u = nil
+	}
+	return &sourcegraph.ErrorResponse{
+		Response: &http.Response{
+			StatusCode: statusCode,
+			Status:     http.StatusText(statusCode),
+			Request:    &http.Request{Method: method, URL: u},
+		},
+		Message: msg,
+	}
+}
+
+// now returns the current time, rounded as db_common.Now rounds it.
+func now() time.Time { return time.Now().In(time.UTC).Round(time.Millisecond) }
@@ -0,0 +1,19 @@
+package fake
+
+import (
+	"net/http"
+	"testing"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/sourcegraph"
+)
+
+func TestHTTPError_invalidRouteVars(t *testing.T) {
+	err := httpError("GET", router.Repo, nil, http.StatusNotFound, "not found")
+	if !sourcegraph.IsHTTPErrorCode(err, http.StatusNotFound) {
+		t.Errorf("got error %v, want 404 error", err)
+	}
+	if err.Error() == "" {
+		t.Error("got empty error message")
+	}
+}
@@ -0,0 +1,208 @@
+package fake
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sourcegraph/go-github/github"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/sourcegraph"
+)
+
+// IssuesService is a fake sourcegraph.IssuesService.
+type IssuesService struct {
+	s *Store
+}
+
+var _ sourcegraph.IssuesService = &IssuesService{}
+
+// AddIssue adds an issue to a repository and returns a copy of it. If
+// issue.Number is nil, the next unused issue or pull request number in
+// the repository is assigned. The State and HTMLURL fields default to
+// "open" and a GitHub-style URL.
+func (s *Store) AddIssue(repo sourcegraph.RepoSpec, issue *sourcegraph.Issue) (*sourcegraph.Issue, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, err := s.repo(repo)
+	if err != nil {
+		return nil, err
+	}
+	i := *issue
+	if i.Number == nil {
+		i.Number = github.Int(s.nextIssueNumber(r.RID))
+	}
+	if i.State == nil {
+		i.State = github.String("open")
+	}
+	if i.HTMLURL == nil {
+		i.HTMLURL = github.String(fmt.Sprintf("https://%s/issues/%d", r.URI, *i.Number))
+	}
+	if i.CreatedAt == nil {
+		t := now()
+		i.CreatedAt = &t
+	}
+	s.issues[r.RID] = append(s.issues[r.RID], &i)
+	i2 := i
+	return &i2, nil
+}
+
+// nextIssueNumber returns the next unused issue or pull request
+// number in the repository. As on GitHub, issues and pull requests
+// share a single sequence of numbers. The caller must hold s.mu.
+func (s *Store) nextIssueNumber(rid int) int {
+	var max int
+	for _, i := range s.issues[rid] {
+		if *i.Number > max {
+			max = *i.Number
+		}
+	}
+	for _, p := range s.pulls[rid] {
+		if *p.Number > max {
+			max = *p.Number
+		}
+	}
+	return max + 1
+}
+
+// issue returns the stored issue specified by spec. The caller must
+// hold s.mu.
+func (s *Store) issue(spec sourcegraph.IssueSpec) (*sourcegraph.Issue, int, error) {
+	r, err := s.repo(spec.Repo)
+	if err != nil {
+		return nil, 0, err
+	}
+	for _, i := range s.issues[r.RID] {
+		if *i.Number == spec.Number {
+			return i, r.RID, nil
+		}
+	}
+	return nil, 0, httpError("GET", router.RepoIssue, spec.RouteVars(), 404, "issue not found")
+}
+
+// matchesState reports whether an issue or pull request with the
+// given state is included in a list with the given state filter. As
+// on GitHub, the default filter is "open".
+func matchesState(state *string, filter string) bool {
+	if filter == "" {
+		filter = "open"
+	}
+	return filter == "all" || (state != nil && *state == filter)
+}
+
+func (s *IssuesService) Get(ctx context.Context, issue sourcegraph.IssueSpec, opt *sourcegraph.IssueGetOptions) (*sourcegraph.Issue, sourcegraph.Response, error) {
+	s.s.mu.Lock()
+	defer s.s.mu.Unlock()
+	i, _, err := s.s.issue(issue)
+	if err != nil {
+		return nil, noTotal(), err
+	}
+	i2 := *i
+	return &i2, noTotal(), nil
+}
+
+func (s *IssuesService) ListByRepo(ctx context.Context, repo sourcegraph.RepoSpec, opt *sourcegraph.IssueListOptions) ([]*sourcegraph.Issue, sourcegraph.Response, error) {
+	if opt == nil {
+		opt = &sourcegraph.IssueListOptions{}
+	}
+
+	s.s.mu.Lock()
+	defer s.s.mu.Unlock()
+	r, err := s.s.repo(repo)
+	if err != nil {
+		return nil, noTotal(), err
+	}
+	var issues []*sourcegraph.Issue
+	for _, i := range s.s.issues[r.RID] {
+		if matchesState(i.State, opt.State) {
+			i2 := *i
+			issues = append(issues, &i2)
+		}
+	}
+	issues, resp := page(issues, opt.ListOptions)
+	return issues, resp, nil
+}
+
+func (s *IssuesService) ListComments(ctx context.Context, issue sourcegraph.IssueSpec, opt *sourcegraph.IssueListCommentsOptions) ([]*sourcegraph.IssueComment, sourcegraph.Response, error) {
+	if opt == nil {
+		opt = &sourcegraph.IssueListCommentsOptions{}
+	}
+
+	s.s.mu.Lock()
+	defer s.s.mu.Unlock()
+	_, rid, err := s.s.issue(issue)
+	if err != nil {
+		return nil, noTotal(), err
+	}
+	var comments []*sourcegraph.IssueComment
+	for _, c := range s.s.issueComments[issueKey{rid, issue.Number}] {
+		c2 := *c
+		comments = append(comments, &c2)
+	}
+	comments, resp := page(comments, opt.ListOptions)
+	return comments, resp, nil
+}
+
+func (s *IssuesService) CreateComment(ctx context.Context, issue sourcegraph.IssueSpec, comment *sourcegraph.IssueComment) (*sourcegraph.IssueComment, sourcegraph.Response, error) {
+	s.s.mu.Lock()
+	defer s.s.mu.Unlock()
+	_, rid, err := s.s.issue(issue)
+	if err != nil {
+		return nil, noTotal(), err
+	}
+	c := *comment
+	s.s.lastCommentID++
+	c.ID = github.Int(s.s.lastCommentID)
+	t := now()
+	c.CreatedAt, c.UpdatedAt = &t, &t
+	key := issueKey{rid, issue.Number}
+	s.s.issueComments[key] = append(s.s.issueComments[key], &c)
+	c2 := c
+	return &c2, noTotal(), nil
+}
+
+// EditComment replaces the body of the comment whose ID is
+// comment.ID.
+func (s *IssuesService) EditComment(ctx context.Context, issue sourcegraph.IssueSpec, comment *sourcegraph.IssueComment) (*sourcegraph.IssueComment, sourcegraph.Response, error) {
+	s.s.mu.Lock()
+	defer s.s.mu.Unlock()
+	_, rid, err := s.s.issue(issue)
+	if err != nil {
+		return nil, noTotal(), err
+	}
+	for _, c := range s.s.issueComments[issueKey{rid, issue.Number}] {
+		if comment.ID != nil && *c.ID == *comment.ID {
+			t := now()
+			c.Body, c.UpdatedAt = comment.Body, &t
+			c2 := *c
+			return &c2, noTotal(), nil
+		}
+	}
+	return nil, noTotal(), httpError("PATCH", router.RepoIssueCommentsEdit, commentRouteVars(issue.RouteVars(), comment.ID), 404, "comment not found")
+}
+
+func (s *IssuesService) DeleteComment(ctx context.Context, issue sourcegraph.IssueSpec, commentID int) (sourcegraph.Response, error) {
+	s.s.mu.Lock()
+	defer s.s.mu.Unlock()
+	_, rid, err := s.s.issue(issue)
+	if err != nil {
+		return noTotal(), err
+	}
+	key := issueKey{rid, issue.Number}
+	for i, c := range s.s.issueComments[key] {
+		if *c.ID == commentID {
+			s.s.issueComments[key] = append(s.s.issueComments[key][:i:i], s.s.issueComments[key][i+1:]...)
+			return noTotal(), nil
+		}
+	}
+	return noTotal(), httpError("DELETE", router.RepoIssueCommentsDelete, commentRouteVars(issue.RouteVars(), &commentID), 404, "comment not found")
+}
+
+// commentRouteVars adds the CommentID route variable to routeVars.
+func commentRouteVars(routeVars map[string]string, id *int) map[string]string {
+	routeVars["CommentID"] = "0"
+	if id != nil {
+		routeVars["CommentID"] = fmt.Sprint(*id)
+	}
+	return routeVars
+}
@@ -0,0 +1,67 @@
+package fake
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sourcegraph/go-github/github"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/sourcegraph"
+)
+
+func TestIssuesService(t *testing.T) {
+	client, store := NewClient()
+	ctx := context.Background()
+
+	repo := store.AddRepo(&sourcegraph.Repo{URI: "github.com/o/r"}).RepoSpec()
+	issue, err := store.AddIssue(repo, &sourcegraph.Issue{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	closed := &sourcegraph.Issue{}
+	closed.State = github.String("closed")
+	if _, err := store.AddIssue(repo, closed); err != nil {
+		t.Fatal(err)
+	}
+	if spec := issue.Spec(); spec.Number != 1 || spec.Repo.URI != repo.URI {
+		t.Errorf("got issue spec %+v", spec)
+	}
+
+	for state, want := range map[string]int{"": 1, "closed": 1, "all": 2} {
+		issues, _, err := client.Issues.ListByRepo(ctx, repo, &sourcegraph.IssueListOptions{State: state})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(issues) != want {
+			t.Errorf("state %q: got %d issues, want %d", state, len(issues), want)
+		}
+	}
+	if _, _, err := client.Issues.Get(ctx, sourcegraph.IssueSpec{Repo: repo, Number: 9}, nil); !sourcegraph.IsHTTPErrorCode(err, 404) {
+		t.Errorf("got error %v, want 404", err)
+	}
+
+	spec := issue.Spec()
+	comment := &sourcegraph.IssueComment{}
+	comment.Body = github.String("a")
+	created, _, err := client.Issues.CreateComment(ctx, spec, comment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	created.Body = github.String("b")
+	if _, _, err := client.Issues.EditComment(ctx, spec, created); err != nil {
+		t.Fatal(err)
+	}
+	comments, _, err := client.Issues.ListComments(ctx, spec, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(comments) != 1 || *comments[0].Body != "b" {
+		t.Errorf("got comments %+v, want 1 edited comment", comments)
+	}
+	if _, err := client.Issues.DeleteComment(ctx, spec, *created.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Issues.DeleteComment(ctx, spec, *created.ID); !sourcegraph.IsHTTPErrorCode(err, 404) {
+		t.Errorf("got error %v deleting deleted comment, want 404", err)
+	}
+}
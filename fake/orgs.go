@@ -0,0 +1,106 @@
+package fake
+
+import (
+	"context"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/sourcegraph"
+)
+
+// OrgsService is a fake sourcegraph.OrgsService.
+type OrgsService struct {
+	s *Store
+}
+
+var _ sourcegraph.OrgsService = &OrgsService{}
+
+// AddOrg adds an organization with the given members to the store
+// and returns a copy of it. Organizations are stored as users whose
+// Type is "Organization", so they are also returned by the Users
+// service. If org.UID is 0, a new UID is assigned.
+func (s *Store) AddOrg(org *sourcegraph.Org, members ...sourcegraph.UserSpec) (*sourcegraph.Org, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var uids []int
+	for _, m := range members {
+		u, err := s.user(m)
+		if err != nil {
+			return nil, err
+		}
+		uids = append(uids, u.UID)
+	}
+	u := org.User
+	u.Type = "Organization"
+	o := &sourcegraph.Org{User: *s.addUser(&u)}
+	s.members[o.UID] = uids
+	return o, nil
+}
+
+// org returns the stored organization specified by spec. The caller
+// must hold s.mu.
+func (s *Store) org(spec sourcegraph.OrgSpec) (*sourcegraph.User, error) {
+	u, err := s.user(sourcegraph.UserSpec{Login: spec.Org, UID: spec.UID})
+	if err != nil {
+		return nil, err
+	}
+	if !u.IsOrganization() {
+		return nil, sourcegraph.ErrUserNotExist
+	}
+	return u, nil
+}
+
+func (s *OrgsService) Get(ctx context.Context, org sourcegraph.OrgSpec) (*sourcegraph.Org, sourcegraph.Response, error) {
+	s.s.mu.Lock()
+	defer s.s.mu.Unlock()
+	o, err := s.s.org(org)
+	if err != nil {
+		return nil, noTotal(), err
+	}
+	return &sourcegraph.Org{User: *o}, noTotal(), nil
+}
+
+func (s *OrgsService) ListMembers(ctx context.Context, org sourcegraph.OrgSpec, opt *sourcegraph.OrgListMembersOptions) ([]*sourcegraph.User, sourcegraph.Response, error) {
+	if opt == nil {
+		opt = &sourcegraph.OrgListMembersOptions{}
+	}
+
+	s.s.mu.Lock()
+	defer s.s.mu.Unlock()
+	o, err := s.s.org(org)
+	if err != nil {
+		return nil, noTotal(), err
+	}
+	var members []*sourcegraph.User
+	for _, uid := range s.s.members[o.UID] {
+		if u, err := s.s.user(sourcegraph.UserSpec{UID: uid}); err == nil {
+			u2 := *u
+			members = append(members, &u2)
+		}
+	}
+	members, resp := page(members, opt.ListOptions)
+	return members, resp, nil
+}
+
+func (s *OrgsService) GetSettings(ctx context.Context, org sourcegraph.OrgSpec) (*sourcegraph.OrgSettings, sourcegraph.Response, error) {
+	s.s.mu.Lock()
+	defer s.s.mu.Unlock()
+	o, err := s.s.org(org)
+	if err != nil {
+		return nil, noTotal(), err
+	}
+	var settings sourcegraph.OrgSettings
+	if st := s.s.orgSettings[o.UID]; st != nil {
+		settings = *st
+	}
+	return &settings, noTotal(), nil
+}
+
+func (s *OrgsService) UpdateSettings(ctx context.Context, org sourcegraph.OrgSpec, settings sourcegraph.OrgSettings) (sourcegraph.Response, error) {
+	s.s.mu.Lock()
+	defer s.s.mu.Unlock()
+	o, err := s.s.org(org)
+	if err != nil {
+		return noTotal(), err
+	}
+	s.s.orgSettings[o.UID] = &settings
+	return noTotal(), nil
+}
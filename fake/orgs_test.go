@@ -0,0 +1,45 @@
+package fake
+
+import (
+	"context"
+	"testing"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/sourcegraph"
+)
+
+func TestOrgsService(t *testing.T) {
+	client, store := NewClient()
+	ctx := context.Background()
+
+	alice := store.AddUser(&sourcegraph.User{Login: "alice"})
+	org, err := store.AddOrg(&sourcegraph.Org{User: sourcegraph.User{Login: "acme"}}, alice.Spec())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := client.Orgs.Get(ctx, sourcegraph.OrgSpec{Org: "alice"}); err != sourcegraph.ErrUserNotExist {
+		t.Errorf("got error %v getting a user as an org, want ErrUserNotExist", err)
+	}
+	got, _, err := client.Orgs.Get(ctx, sourcegraph.OrgSpec{UID: org.UID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.IsOrganization() || got.Login != "acme" {
+		t.Errorf("got org %+v", got)
+	}
+
+	members, _, err := client.Orgs.ListMembers(ctx, org.OrgSpec(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(members) != 1 || members[0].UID != alice.UID {
+		t.Errorf("got members %+v, want alice", members)
+	}
+	orgs, _, err := client.Users.ListOrgs(ctx, alice.Spec(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(orgs) != 1 || orgs[0].UID != org.UID {
+		t.Errorf("got orgs %+v, want acme", orgs)
+	}
+}
@@ -0,0 +1,196 @@
+package fake
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sourcegraph/go-github/github"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/sourcegraph"
+)
+
+// PullRequestsService is a fake sourcegraph.PullRequestsService.
+type PullRequestsService struct {
+	s *Store
+}
+
+var _ sourcegraph.PullRequestsService = &PullRequestsService{}
+
+// AddPullRequest adds a pull request to a repository and returns a
+// copy of it. If pull.Number is nil, the next unused issue or pull
+// request number in the repository is assigned. The State and HTMLURL
+// fields default to "open" and a GitHub-style URL.
+func (s *Store) AddPullRequest(repo sourcegraph.RepoSpec, pull *sourcegraph.PullRequest) (*sourcegraph.PullRequest, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, err := s.repo(repo)
+	if err != nil {
+		return nil, err
+	}
+	p := *pull
+	if p.Number == nil {
+		p.Number = github.Int(s.nextIssueNumber(r.RID))
+	}
+	if p.State == nil {
+		p.State = github.String("open")
+	}
+	if p.HTMLURL == nil {
+		p.HTMLURL = github.String(fmt.Sprintf("https://%s/pull/%d", r.URI, *p.Number))
+	}
+	if p.CreatedAt == nil {
+		t := now()
+		p.CreatedAt = &t
+	}
+	s.pulls[r.RID] = append(s.pulls[r.RID], &p)
+	p2 := p
+	return &p2, nil
+}
+
+// pull returns the stored pull request specified by spec. The caller
+// must hold s.mu.
+func (s *Store) pull(spec sourcegraph.PullRequestSpec) (*sourcegraph.PullRequest, int, error) {
+	r, err := s.repo(spec.Repo)
+	if err != nil {
+		return nil, 0, err
+	}
+	for _, p := range s.pulls[r.RID] {
+		if *p.Number == spec.Number {
+			return p, r.RID, nil
+		}
+	}
+	return nil, 0, httpError("GET", router.RepoPullRequest, spec.RouteVars(), 404, "pull request not found")
+}
+
+func (s *PullRequestsService) Get(ctx context.Context, pull sourcegraph.PullRequestSpec, opt *sourcegraph.PullRequestGetOptions) (*sourcegraph.PullRequest, sourcegraph.Response, error) {
+	s.s.mu.Lock()
+	defer s.s.mu.Unlock()
+	p, _, err := s.s.pull(pull)
+	if err != nil {
+		return nil, noTotal(), err
+	}
+	p2 := *p
+	return &p2, noTotal(), nil
+}
+
+func (s *PullRequestsService) ListByRepo(ctx context.Context, repo sourcegraph.RepoSpec, opt *sourcegraph.PullRequestListOptions) ([]*sourcegraph.PullRequest, sourcegraph.Response, error) {
+	if opt == nil {
+		opt = &sourcegraph.PullRequestListOptions{}
+	}
+
+	s.s.mu.Lock()
+	defer s.s.mu.Unlock()
+	r, err := s.s.repo(repo)
+	if err != nil {
+		return nil, noTotal(), err
+	}
+	var pulls []*sourcegraph.PullRequest
+	for _, p := range s.s.pulls[r.RID] {
+		if matchesState(p.State, opt.State) {
+			p2 := *p
+			pulls = append(pulls, &p2)
+		}
+	}
+	pulls, resp := page(pulls, opt.ListOptions)
+	return pulls, resp, nil
+}
+
+func (s *PullRequestsService) ListComments(ctx context.Context, pull sourcegraph.PullRequestSpec, opt *sourcegraph.PullRequestListCommentsOptions) ([]*sourcegraph.PullRequestComment, sourcegraph.Response, error) {
+	if opt == nil {
+		opt = &sourcegraph.PullRequestListCommentsOptions{}
+	}
+
+	s.s.mu.Lock()
+	defer s.s.mu.Unlock()
+	_, rid, err := s.s.pull(pull)
+	if err != nil {
+		return nil, noTotal(), err
+	}
+	var comments []*sourcegraph.PullRequestComment
+	for _, c := range s.s.pullComments[issueKey{rid, pull.Number}] {
+		c2 := *c
+		comments = append(comments, &c2)
+	}
+	comments, resp := page(comments, opt.ListOptions)
+	return comments, resp, nil
+}
+
+func (s *PullRequestsService) CreateComment(ctx context.Context, pull sourcegraph.PullRequestSpec, comment *sourcegraph.PullRequestComment) (*sourcegraph.PullRequestComment, sourcegraph.Response, error) {
+	s.s.mu.Lock()
+	defer s.s.mu.Unlock()
+	_, rid, err := s.s.pull(pull)
+	if err != nil {
+		return nil, noTotal(), err
+	}
+	c := *comment
+	s.s.lastCommentID++
+	c.ID = github.Int(s.s.lastCommentID)
+	t := now()
+	c.CreatedAt, c.UpdatedAt = &t, &t
+	c.Published = true
+	key := issueKey{rid, pull.Number}
+	s.s.pullComments[key] = append(s.s.pullComments[key], &c)
+	c2 := c
+	return &c2, noTotal(), nil
+}
+
+// EditComment replaces the body of the comment whose ID is
+// comment.ID.
+func (s *PullRequestsService) EditComment(ctx context.Context, pull sourcegraph.PullRequestSpec, comment *sourcegraph.PullRequestComment) (*sourcegraph.PullRequestComment, sourcegraph.Response, error) {
+	s.s.mu.Lock()
+	defer s.s.mu.Unlock()
+	_, rid, err := s.s.pull(pull)
+	if err != nil {
+		return nil, noTotal(), err
+	}
+	for _, c := range s.s.pullComments[issueKey{rid, pull.Number}] {
+		if comment.ID != nil && *c.ID == *comment.ID {
+			t := now()
+			c.Body, c.UpdatedAt = comment.Body, &t
+			c2 := *c
+			return &c2, noTotal(), nil
+		}
+	}
+	return nil, noTotal(), httpError("PATCH", router.RepoPullRequestCommentsEdit, commentRouteVars(pull.RouteVars(), comment.ID), 404, "comment not found")
+}
+
+func (s *PullRequestsService) DeleteComment(ctx context.Context, pull sourcegraph.PullRequestSpec, commentID int) (sourcegraph.Response, error) {
+	s.s.mu.Lock()
+	defer s.s.mu.Unlock()
+	_, rid, err := s.s.pull(pull)
+	if err != nil {
+		return noTotal(), err
+	}
+	key := issueKey{rid, pull.Number}
+	for i, c := range s.s.pullComments[key] {
+		if *c.ID == commentID {
+			s.s.pullComments[key] = append(s.s.pullComments[key][:i:i], s.s.pullComments[key][i+1:]...)
+			return noTotal(), nil
+		}
+	}
+	return noTotal(), httpError("DELETE", router.RepoPullRequestCommentsDelete, commentRouteVars(pull.RouteVars(), &commentID), 404, "comment not found")
+}
+
+// Merge marks an open pull request as merged and closed. As on GitHub,
+// merging a pull request that is not open fails with HTTP status 405.
+func (s *PullRequestsService) Merge(ctx context.Context, pull sourcegraph.PullRequestSpec, mergeRequest *sourcegraph.PullRequestMergeRequest) (*sourcegraph.PullRequestMergeResult, sourcegraph.Response, error) {
+	s.s.mu.Lock()
+	defer s.s.mu.Unlock()
+	p, _, err := s.s.pull(pull)
+	if err != nil {
+		return nil, noTotal(), err
+	}
+	if p.State == nil || *p.State != "open" {
+		return nil, noTotal(), httpError("PUT", router.RepoPullRequestMerge, pull.RouteVars(), 405, "Pull Request is not mergeable")
+	}
+	t := now()
+	p.State, p.Merged, p.MergedAt, p.ClosedAt = github.String("closed"), github.Bool(true), &t, &t
+
+	var result sourcegraph.PullRequestMergeResult
+	result.Merged = github.Bool(true)
+	result.Message = github.String("Pull Request successfully merged")
+	if p.Head != nil && p.Head.SHA != nil {
+		result.SHA = p.Head.SHA
+	}
+	return &result, noTotal(), nil
+}
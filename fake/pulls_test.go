@@ -0,0 +1,52 @@
+package fake
+
+import (
+	"context"
+	"testing"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/sourcegraph"
+)
+
+func TestPullRequestsService_Merge(t *testing.T) {
+	client, store := NewClient()
+	ctx := context.Background()
+
+	repo := store.AddRepo(&sourcegraph.Repo{URI: "github.com/o/r"}).RepoSpec()
+	if _, err := store.AddIssue(repo, &sourcegraph.Issue{}); err != nil {
+		t.Fatal(err)
+	}
+	pull, err := store.AddPullRequest(repo, &sourcegraph.PullRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	spec := pull.Spec()
+	if spec.Number != 2 {
+		t.Errorf("got pull number %d, want 2 (after issue #1)", spec.Number)
+	}
+
+	result, _, err := client.PullRequests.Merge(ctx, spec, &sourcegraph.PullRequestMergeRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !*result.Merged {
+		t.Error("got !Merged")
+	}
+	merged, _, err := client.PullRequests.Get(ctx, spec, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *merged.State != "closed" || !*merged.Merged {
+		t.Errorf("got pull %+v, want merged and closed", merged)
+	}
+	if _, _, err := client.PullRequests.Merge(ctx, spec, &sourcegraph.PullRequestMergeRequest{}); !sourcegraph.IsHTTPErrorCode(err, 405) {
+		t.Errorf("got error %v merging closed pull, want 405", err)
+	}
+
+	pulls, _, err := client.PullRequests.ListByRepo(ctx, repo, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pulls) != 0 {
+		t.Errorf("got %d open pulls, want 0", len(pulls))
+	}
+}
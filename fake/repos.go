@@ -0,0 +1,656 @@
+package fake
+
+import (
+	"context"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/sourcegraph/go-github/github"
+
+	"sourcegraph.com/sourcegraph/go-nnz/nnz"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/sourcegraph"
+	"sourcegraph.com/sourcegraph/go-vcs/vcs"
+	"sourcegraph.com/sourcegraph/vcsstore/vcsclient"
+)
+
+// ReposService is a fake sourcegraph.ReposService.
+//
+// Statistics, README files, badges, counters, and the
+// people/dependency analysis methods (ListAuthors, ListClients, etc.)
+// are not simulated; they return empty results for existing
+// repositories.
+type ReposService struct {
+	s *Store
+}
+
+var _ sourcegraph.ReposService = &ReposService{}
+
+// AddRepo adds a repository to the store and returns a copy of it. If
+// repo.RID is 0, a new RID is assigned. The Name, VCS, and
+// HTTPCloneURL fields default to values inferred from repo.URI, and
+// CreatedAt defaults to now.
+func (s *Store) AddRepo(repo *sourcegraph.Repo) *sourcegraph.Repo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addRepo(repo)
+}
+
+func (s *Store) addRepo(repo *sourcegraph.Repo) *sourcegraph.Repo {
+	r := *repo
+	if r.RID == 0 {
+		s.lastRID++
+		r.RID = s.lastRID
+	} else if r.RID > s.lastRID {
+		s.lastRID = r.RID
+	}
+	if r.Name == "" {
+		r.Name = r.URI[strings.LastIndex(r.URI, "/")+1:]
+	}
+	if r.VCS == "" {
+		r.VCS = sourcegraph.Git
+	}
+	if r.HTTPCloneURL == "" {
+		r.HTTPCloneURL = "https://" + r.URI + ".git"
+	}
+	if r.DefaultBranch == "" {
+		r.DefaultBranch = "master"
+	}
+	if r.CreatedAt.IsZero() {
+		r.CreatedAt = now()
+	}
+	s.repos = append(s.repos, &r)
+	delete(s.renamedRepos, r.URI)
+	r2 := r
+	return &r2
+}
+
+// RenameRepo changes the URI of the repository at oldURI to
+// newURI. Subsequent lookups of oldURI return a sourcegraph.ErrRenamed
+// error.
+func (s *Store) RenameRepo(oldURI, newURI string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, err := s.repo(sourcegraph.RepoSpec{URI: oldURI})
+	if err != nil {
+		return err
+	}
+	r.URI = newURI
+	s.renamedRepos[oldURI] = newURI
+	delete(s.renamedRepos, newURI)
+	for old, new := range s.renamedRepos {
+		if new == oldURI {
+			s.renamedRepos[old] = newURI
+		}
+	}
+	return nil
+}
+
+// AddCommits adds commits to a repository. Commits are listed by
+// Repos.ListCommits in the order in which they were added, so they
+// should be added newest first.
+func (s *Store) AddCommits(repo sourcegraph.RepoSpec, commits ...*sourcegraph.Commit) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, err := s.repo(repo)
+	if err != nil {
+		return err
+	}
+	s.commits[r.RID] = append(s.commits[r.RID], commits...)
+	return nil
+}
+
+// AddBranches adds branches to a repository.
+func (s *Store) AddBranches(repo sourcegraph.RepoSpec, branches ...*vcs.Branch) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, err := s.repo(repo)
+	if err != nil {
+		return err
+	}
+	s.branches[r.RID] = append(s.branches[r.RID], branches...)
+	return nil
+}
+
+// AddTags adds tags to a repository.
+func (s *Store) AddTags(repo sourcegraph.RepoSpec, tags ...*vcs.Tag) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, err := s.repo(repo)
+	if err != nil {
+		return err
+	}
+	s.tags[r.RID] = append(s.tags[r.RID], tags...)
+	return nil
+}
+
+// repo returns the stored repository specified by spec. The caller
+// must hold s.mu.
+func (s *Store) repo(spec sourcegraph.RepoSpec) (*sourcegraph.Repo, error) {
+	for _, r := range s.repos {
+		if (spec.RID != 0 && r.RID == spec.RID) || (spec.RID == 0 && r.URI == spec.URI) {
+			return r, nil
+		}
+	}
+	if newURI, renamed := s.renamedRepos[spec.URI]; renamed && spec.RID == 0 {
+		return nil, sourcegraph.ErrRenamed{OldURI: spec.URI, NewURI: newURI}
+	}
+	return nil, sourcegraph.ErrNotExist
+}
+
+// resolveRev returns the commit ID that rev refers to in the
+// repository. CommitID is used if set; otherwise Rev is resolved as a
+// branch, tag, or (possibly abbreviated) commit ID, and an empty Rev
+// refers to the default branch. The caller must hold s.mu.
+func (s *Store) resolveRev(r *sourcegraph.Repo, rev sourcegraph.RepoRevSpec) (string, bool) {
+	if rev.CommitID != "" {
+		return rev.CommitID, true
+	}
+	name := rev.Rev
+	if name == "" {
+		name = r.DefaultBranch
+	}
+	for _, b := range s.branches[r.RID] {
+		if b.Name == name {
+			return string(b.Head), true
+		}
+	}
+	for _, t := range s.tags[r.RID] {
+		if t.Name == name {
+			return string(t.CommitID), true
+		}
+	}
+	if rev.Rev != "" {
+		for _, c := range s.commits[r.RID] {
+			if strings.HasPrefix(string(c.ID), rev.Rev) {
+				return string(c.ID), true
+			}
+		}
+	}
+	return "", false
+}
+
+func (s *ReposService) Get(ctx context.Context, repo sourcegraph.RepoSpec, opt *sourcegraph.RepoGetOptions) (*sourcegraph.Repo, sourcegraph.Response, error) {
+	s.s.mu.Lock()
+	defer s.s.mu.Unlock()
+	r, err := s.s.repo(repo)
+	if err != nil {
+		return nil, noTotal(), err
+	}
+	r2 := *r
+	return &r2, noTotal(), nil
+}
+
+func (s *ReposService) GetStats(ctx context.Context, repo sourcegraph.RepoRevSpec) (sourcegraph.RepoStats, sourcegraph.Response, error) {
+	if _, _, err := s.Get(ctx, repo.RepoSpec, nil); err != nil {
+		return nil, noTotal(), err
+	}
+	return sourcegraph.RepoStats{}, noTotal(), nil
+}
+
+func (s *ReposService) CreateStatus(ctx context.Context, spec sourcegraph.RepoRevSpec, st sourcegraph.RepoStatus) (*sourcegraph.RepoStatus, sourcegraph.Response, error) {
+	s.s.mu.Lock()
+	defer s.s.mu.Unlock()
+	r, err := s.s.repo(spec.RepoSpec)
+	if err != nil {
+		return nil, noTotal(), err
+	}
+	commitID, ok := s.s.resolveRev(r, spec)
+	if !ok {
+		return nil, noTotal(), httpError("POST", router.RepoStatusCreate, spec.RouteVars(), 404, "revision not found")
+	}
+	t := now()
+	st.CreatedAt, st.UpdatedAt = &t, &t
+	key := repoCommit{r.RID, commitID}
+	s.s.statuses[key] = append(s.s.statuses[key], &st)
+	st2 := st
+	return &st2, noTotal(), nil
+}
+
+func (s *ReposService) GetCombinedStatus(ctx context.Context, spec sourcegraph.RepoRevSpec) (*sourcegraph.CombinedStatus, sourcegraph.Response, error) {
+	s.s.mu.Lock()
+	defer s.s.mu.Unlock()
+	r, err := s.s.repo(spec.RepoSpec)
+	if err != nil {
+		return nil, noTotal(), err
+	}
+	commitID, ok := s.s.resolveRev(r, spec)
+	if !ok {
+		return nil, noTotal(), httpError("GET", router.RepoCombinedStatus, spec.RouteVars(), 404, "revision not found")
+	}
+
+	// As on GitHub, the combined status consists of the latest status
+	// for each context, and its state is "failure" if any of them is
+	// "error" or "failure", "pending" if any of them is "pending" (or
+	// if there are none), and "success" otherwise.
+	var statuses []github.RepoStatus
+	seen := map[string]int{}
+	for _, st := range s.s.statuses[repoCommit{r.RID, commitID}] {
+		var context string
+		if st.Context != nil {
+			context = *st.Context
+		}
+		if i, ok := seen[context]; ok {
+			statuses[i] = st.RepoStatus
+		} else {
+			seen[context] = len(statuses)
+			statuses = append(statuses, st.RepoStatus)
+		}
+	}
+	state := "success"
+	if len(statuses) == 0 {
+		state = "pending"
+	}
+	for _, st := range statuses {
+		if st.State == nil {
+			continue
+		}
+		switch *st.State {
+		case "error", "failure":
+			state = "failure"
+		case "pending":
+			if state != "failure" {
+				state = "pending"
+			}
+		}
+	}
+
+	var combined sourcegraph.CombinedStatus
+	total := len(statuses)
+	combined.State, combined.SHA, combined.TotalCount = &state, &commitID, &total
+	combined.Statuses = statuses
+	return &combined, noTotal(), nil
+}
+
+func (s *ReposService) GetOrCreate(ctx context.Context, repo sourcegraph.RepoSpec, opt *sourcegraph.RepoGetOptions) (*sourcegraph.Repo, sourcegraph.Response, error) {
+	s.s.mu.Lock()
+	defer s.s.mu.Unlock()
+	r, err := s.s.repo(repo)
+	if err == sourcegraph.ErrNotExist && repo.RID == 0 {
+		if !sourcegraph.IsGitHubRepoURI(repo.URI) {
+			return nil, noTotal(), sourcegraph.ErrNonStandardURI
+		}
+		s.s.lastGitHubID++
+		return s.s.addRepo(&sourcegraph.Repo{URI: repo.URI, GitHubID: nnz.Int(s.s.lastGitHubID)}), noTotal(), nil
+	} else if err != nil {
+		return nil, noTotal(), err
+	}
+	r2 := *r
+	return &r2, noTotal(), nil
+}
+
+func (s *ReposService) GetSettings(ctx context.Context, repo sourcegraph.RepoSpec) (*sourcegraph.RepoSettings, sourcegraph.Response, error) {
+	s.s.mu.Lock()
+	defer s.s.mu.Unlock()
+	r, err := s.s.repo(repo)
+	if err != nil {
+		return nil, noTotal(), err
+	}
+	var settings sourcegraph.RepoSettings
+	if st := s.s.repoSettings[r.RID]; st != nil {
+		settings = *st
+	}
+	return &settings, noTotal(), nil
+}
+
+// UpdateSettings updates the non-nil fields of settings.
+func (s *ReposService) UpdateSettings(ctx context.Context, repo sourcegraph.RepoSpec, settings sourcegraph.RepoSettings) (sourcegraph.Response, error) {
+	s.s.mu.Lock()
+	defer s.s.mu.Unlock()
+	r, err := s.s.repo(repo)
+	if err != nil {
+		return noTotal(), err
+	}
+	st := s.s.repoSettings[r.RID]
+	if st == nil {
+		st = &sourcegraph.RepoSettings{}
+		s.s.repoSettings[r.RID] = st
+	}
+	for _, f := range []struct{ dst, src **bool }{
+		{&st.Enabled, &settings.Enabled},
+		{&st.BuildPushes, &settings.BuildPushes},
+		{&st.ExternalCommitStatuses, &settings.ExternalCommitStatuses},
+		{&st.UnsuccessfulExternalCommitStatuses, &settings.UnsuccessfulExternalCommitStatuses},
+		{&st.UseSSHPrivateKey, &settings.UseSSHPrivateKey},
+	} {
+		if *f.src != nil {
+			v := **f.src
+			*f.dst = &v
+		}
+	}
+	return noTotal(), nil
+}
+
+func (s *ReposService) RefreshProfile(ctx context.Context, repo sourcegraph.RepoSpec) (sourcegraph.Response, error) {
+	_, resp, err := s.Get(ctx, repo, nil)
+	return resp, err
+}
+
+func (s *ReposService) RefreshVCSData(ctx context.Context, repo sourcegraph.RepoSpec) (sourcegraph.Response, error) {
+	_, resp, err := s.Get(ctx, repo, nil)
+	return resp, err
+}
+
+func (s *ReposService) ComputeStats(ctx context.Context, repo sourcegraph.RepoRevSpec) (sourcegraph.Response, error) {
+	_, resp, err := s.Get(ctx, repo.RepoSpec, nil)
+	return resp, err
+}
+
+func (s *ReposService) GetBuild(ctx context.Context, repo sourcegraph.RepoRevSpec, opt *sourcegraph.RepoGetBuildOptions) (*sourcegraph.RepoBuildInfo, sourcegraph.Response, error) {
+	if opt == nil {
+		opt = &sourcegraph.RepoGetBuildOptions{}
+	}
+
+	s.s.mu.Lock()
+	defer s.s.mu.Unlock()
+	r, err := s.s.repo(repo.RepoSpec)
+	if err != nil {
+		return nil, noTotal(), err
+	}
+	commitID, ok := s.s.resolveRev(r, repo)
+	if !ok {
+		return nil, noTotal(), sourcegraph.ErrNoRepoBuild
+	}
+
+	// newestBuild returns the newest build of commitID, optionally
+	// only considering successful builds.
+	newestBuild := func(commitID string, successful bool) *sourcegraph.Build {
+		for i := len(s.s.builds) - 1; i >= 0; i-- {
+			b := s.s.builds[i]
+			if b.Repo == r.RID && b.CommitID == commitID && (!successful || b.Success) {
+				return s.s.copyBuild(b)
+			}
+		}
+		return nil
+	}
+
+	var info sourcegraph.RepoBuildInfo
+	info.Exact = newestBuild(commitID, false)
+	if info.Exact != nil && info.Exact.Success {
+		info.LastSuccessful = info.Exact
+	}
+	commits := s.s.commits[r.RID]
+	start := -1
+	for i, c := range commits {
+		if string(c.ID) == commitID {
+			start = i
+			if info.LastSuccessful != nil {
+				info.LastSuccessfulCommit = c
+			}
+			break
+		}
+	}
+	if info.LastSuccessful == nil && !opt.Exact && start != -1 {
+		for i, c := range commits[start+1:] {
+			if b := newestBuild(string(c.ID), true); b != nil {
+				info.LastSuccessful = b
+				info.LastSuccessfulCommit = c
+				info.CommitsBehind = i + 1
+				break
+			}
+		}
+	}
+	if info.Exact == nil && info.LastSuccessful == nil {
+		return nil, noTotal(), sourcegraph.ErrNoRepoBuild
+	}
+	return &info, noTotal(), nil
+}
+
+// Create adds a repository. The repository's URI is the clone URL's
+// host and path (without any ".git" suffix).
+func (s *ReposService) Create(ctx context.Context, newRepoSpec sourcegraph.NewRepoSpec) (*sourcegraph.Repo, sourcegraph.Response, error) {
+	u, err := url.Parse(newRepoSpec.CloneURLStr)
+	if err != nil {
+		return nil, noTotal(), err
+	}
+	if u.Scheme == "" {
+		return nil, noTotal(), sourcegraph.ErrNoScheme
+	}
+	uri := u.Host + strings.TrimSuffix(u.Path, ".git")
+
+	s.s.mu.Lock()
+	defer s.s.mu.Unlock()
+	for _, r := range s.s.repos {
+		if r.URI == uri || r.HTTPCloneURL == newRepoSpec.CloneURLStr {
+			r2 := *r
+			return &r2, noTotal(), nil
+		}
+	}
+	return s.s.addRepo(&sourcegraph.Repo{URI: uri, VCS: newRepoSpec.Type, HTTPCloneURL: newRepoSpec.CloneURLStr}), noTotal(), nil
+}
+
+func (s *ReposService) GetReadme(ctx context.Context, repo sourcegraph.RepoRevSpec) (*vcsclient.TreeEntry, sourcegraph.Response, error) {
+	if _, _, err := s.Get(ctx, repo.RepoSpec, nil); err != nil {
+		return nil, noTotal(), err
+	}
+	return nil, noTotal(), httpError("GET", router.RepoReadme, repo.RouteVars(), 404, "no README found")
+}
+
+// List lists repositories. The Stats option is ignored.
+func (s *ReposService) List(ctx context.Context, opt *sourcegraph.RepoListOptions) ([]*sourcegraph.Repo, sourcegraph.Response, error) {
+	if opt == nil {
+		opt = &sourcegraph.RepoListOptions{}
+	}
+
+	s.s.mu.Lock()
+	defer s.s.mu.Unlock()
+
+	var repos []*sourcegraph.Repo
+	for _, r := range s.s.repos {
+		if len(opt.URIs) > 0 && !contains(opt.URIs, r.URI) {
+			continue
+		}
+		if opt.Name != "" && r.Name != opt.Name {
+			continue
+		}
+		if opt.Query != "" && !strings.Contains(strings.ToLower(r.URI), strings.ToLower(opt.Query)) {
+			continue
+		}
+		if opt.NoFork && r.Fork {
+			continue
+		}
+		if (opt.Type == "public" && r.Private) || (opt.Type == "private" && !r.Private) {
+			continue
+		}
+		if opt.State != "" {
+			st := s.s.repoSettings[r.RID]
+			enabled := st != nil && st.Enabled != nil && *st.Enabled
+			if (opt.State == "enabled") != enabled {
+				continue
+			}
+		}
+		if opt.Owner != "" {
+			if parts := strings.Split(r.URI, "/"); len(parts) < 2 || parts[1] != opt.Owner {
+				continue
+			}
+		}
+		if opt.BuiltOnly && !s.s.hasSuccessfulBuild(r.RID) {
+			continue
+		}
+		r2 := *r
+		repos = append(repos, &r2)
+	}
+
+	if opt.Query == "" {
+		var less func(a, b *sourcegraph.Repo) bool
+		switch opt.Sort {
+		case "uri":
+			less = func(a, b *sourcegraph.Repo) bool { return a.URI < b.URI }
+		case "name":
+			less = func(a, b *sourcegraph.Repo) bool { return a.Name < b.Name }
+		case "created":
+			less = func(a, b *sourcegraph.Repo) bool { return a.CreatedAt.Before(b.CreatedAt) }
+		case "updated":
+			less = func(a, b *sourcegraph.Repo) bool { return a.UpdatedAt.Before(b.UpdatedAt) }
+		case "pushed":
+			less = func(a, b *sourcegraph.Repo) bool { return a.PushedAt.Before(b.PushedAt) }
+		}
+		if less != nil {
+			sort.SliceStable(repos, func(i, j int) bool { return less(repos[i], repos[j]) })
+		}
+		if opt.Direction == "desc" {
+			reverse(repos)
+		}
+	}
+
+	repos, resp := page(repos, opt.ListOptions)
+	return repos, resp, nil
+}
+
+// ListCommits lists the repository's commits, starting at opt.Head (or
+// the default branch) and stopping before opt.Base (if set).
+func (s *ReposService) ListCommits(ctx context.Context, repo sourcegraph.RepoSpec, opt *sourcegraph.RepoListCommitsOptions) ([]*sourcegraph.Commit, sourcegraph.Response, error) {
+	if opt == nil {
+		opt = &sourcegraph.RepoListCommitsOptions{}
+	}
+
+	s.s.mu.Lock()
+	defer s.s.mu.Unlock()
+	r, err := s.s.repo(repo)
+	if err != nil {
+		return nil, noTotal(), err
+	}
+	all := s.s.commits[r.RID]
+	head, ok := s.s.resolveRev(r, sourcegraph.RepoRevSpec{RepoSpec: repo, Rev: opt.Head})
+	if !ok {
+		if opt.Head != "" {
+			return nil, noTotal(), httpError("GET", router.RepoCommits, repo.RouteVars(), 404, "head revision not found")
+		}
+		if len(all) > 0 {
+			head = string(all[0].ID)
+		}
+	}
+	var base string
+	if opt.Base != "" {
+		if base, ok = s.s.resolveRev(r, sourcegraph.RepoRevSpec{RepoSpec: repo, Rev: opt.Base}); !ok {
+			return nil, noTotal(), httpError("GET", router.RepoCommits, repo.RouteVars(), 404, "base revision not found")
+		}
+	}
+
+	var commits []*sourcegraph.Commit
+	for _, c := range all {
+		if string(c.ID) == base {
+			break
+		}
+		if len(commits) > 0 || string(c.ID) == head {
+			commits = append(commits, c)
+		}
+	}
+	commits, resp := page(commits, opt.ListOptions)
+	return commits, resp, nil
+}
+
+func (s *ReposService) GetCommit(ctx context.Context, rev sourcegraph.RepoRevSpec, opt *sourcegraph.RepoGetCommitOptions) (*sourcegraph.Commit, sourcegraph.Response, error) {
+	s.s.mu.Lock()
+	defer s.s.mu.Unlock()
+	r, err := s.s.repo(rev.RepoSpec)
+	if err != nil {
+		return nil, noTotal(), err
+	}
+	if commitID, ok := s.s.resolveRev(r, rev); ok {
+		for _, c := range s.s.commits[r.RID] {
+			if string(c.ID) == commitID {
+				return c, noTotal(), nil
+			}
+		}
+	}
+	return nil, noTotal(), httpError("GET", router.RepoCommit, rev.RouteVars(), 404, "commit not found")
+}
+
+func (s *ReposService) ListBranches(ctx context.Context, repo sourcegraph.RepoSpec, opt *sourcegraph.RepoListBranchesOptions) ([]*vcs.Branch, sourcegraph.Response, error) {
+	if opt == nil {
+		opt = &sourcegraph.RepoListBranchesOptions{}
+	}
+	s.s.mu.Lock()
+	defer s.s.mu.Unlock()
+	r, err := s.s.repo(repo)
+	if err != nil {
+		return nil, noTotal(), err
+	}
+	branches, resp := page(s.s.branches[r.RID], opt.ListOptions)
+	return branches, resp, nil
+}
+
+func (s *ReposService) ListTags(ctx context.Context, repo sourcegraph.RepoSpec, opt *sourcegraph.RepoListTagsOptions) ([]*vcs.Tag, sourcegraph.Response, error) {
+	if opt == nil {
+		opt = &sourcegraph.RepoListTagsOptions{}
+	}
+	s.s.mu.Lock()
+	defer s.s.mu.Unlock()
+	r, err := s.s.repo(repo)
+	if err != nil {
+		return nil, noTotal(), err
+	}
+	tags, resp := page(s.s.tags[r.RID], opt.ListOptions)
+	return tags, resp, nil
+}
+
+func (s *ReposService) ListBadges(ctx context.Context, repo sourcegraph.RepoSpec) ([]*sourcegraph.Badge, sourcegraph.Response, error) {
+	if _, _, err := s.Get(ctx, repo, nil); err != nil {
+		return nil, noTotal(), err
+	}
+	return []*sourcegraph.Badge{}, &Response{}, nil
+}
+
+func (s *ReposService) ListCounters(ctx context.Context, repo sourcegraph.RepoSpec) ([]*sourcegraph.Counter, sourcegraph.Response, error) {
+	if _, _, err := s.Get(ctx, repo, nil); err != nil {
+		return nil, noTotal(), err
+	}
+	return []*sourcegraph.Counter{}, &Response{}, nil
+}
+
+func (s *ReposService) ListAuthors(ctx context.Context, repo sourcegraph.RepoRevSpec, opt *sourcegraph.RepoListAuthorsOptions) ([]*sourcegraph.AugmentedRepoAuthor, sourcegraph.Response, error) {
+	if _, _, err := s.Get(ctx, repo.RepoSpec, nil); err != nil {
+		return nil, noTotal(), err
+	}
+	return []*sourcegraph.AugmentedRepoAuthor{}, &Response{}, nil
+}
+
+func (s *ReposService) ListClients(ctx context.Context, repo sourcegraph.RepoSpec, opt *sourcegraph.RepoListClientsOptions) ([]*sourcegraph.AugmentedRepoClient, sourcegraph.Response, error) {
+	if _, _, err := s.Get(ctx, repo, nil); err != nil {
+		return nil, noTotal(), err
+	}
+	return []*sourcegraph.AugmentedRepoClient{}, &Response{}, nil
+}
+
+func (s *ReposService) ListDependencies(ctx context.Context, repo sourcegraph.RepoRevSpec, opt *sourcegraph.RepoListDependenciesOptions) ([]*sourcegraph.AugmentedRepoDependency, sourcegraph.Response, error) {
+	if _, _, err := s.Get(ctx, repo.RepoSpec, nil); err != nil {
+		return nil, noTotal(), err
+	}
+	return []*sourcegraph.AugmentedRepoDependency{}, &Response{}, nil
+}
+
+func (s *ReposService) ListDependents(ctx context.Context, repo sourcegraph.RepoSpec, opt *sourcegraph.RepoListDependentsOptions) ([]*sourcegraph.AugmentedRepoDependent, sourcegraph.Response, error) {
+	if _, _, err := s.Get(ctx, repo, nil); err != nil {
+		return nil, noTotal(), err
+	}
+	return []*sourcegraph.AugmentedRepoDependent{}, &Response{}, nil
+}
+
+func (s *ReposService) ListByContributor(ctx context.Context, user sourcegraph.UserSpec, opt *sourcegraph.RepoListByContributorOptions) ([]*sourcegraph.AugmentedRepoContribution, sourcegraph.Response, error) {
+	return []*sourcegraph.AugmentedRepoContribution{}, &Response{}, nil
+}
+
+func (s *ReposService) ListByClient(ctx context.Context, user sourcegraph.UserSpec, opt *sourcegraph.RepoListByClientOptions) ([]*sourcegraph.AugmentedRepoUsageByClient, sourcegraph.Response, error) {
+	return []*sourcegraph.AugmentedRepoUsageByClient{}, &Response{}, nil
+}
+
+func (s *ReposService) ListByRefdAuthor(ctx context.Context, user sourcegraph.UserSpec, opt *sourcegraph.RepoListByRefdAuthorOptions) ([]*sourcegraph.AugmentedRepoUsageOfAuthor, sourcegraph.Response, error) {
+	return []*sourcegraph.AugmentedRepoUsageOfAuthor{}, &Response{}, nil
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func reverse[T any](s []T) {
+	for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
+		s[i], s[j] = s[j], s[i]
+	}
+}
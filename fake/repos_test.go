@@ -0,0 +1,216 @@
+package fake
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/sourcegraph/go-github/github"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/sourcegraph"
+	"sourcegraph.com/sourcegraph/go-vcs/vcs"
+)
+
+func TestReposService_Get(t *testing.T) {
+	client, store := NewClient()
+	ctx := context.Background()
+
+	want := store.AddRepo(&sourcegraph.Repo{URI: "github.com/o/r"})
+	if want.RID != 1 || want.Name != "r" {
+		t.Errorf("got added repo %+v, want RID 1 and Name r", want)
+	}
+
+	for _, spec := range []sourcegraph.RepoSpec{{URI: "github.com/o/r"}, {RID: 1}} {
+		repo, _, err := client.Repos.Get(ctx, spec, nil)
+		if err != nil {
+			t.Fatalf("Repos.Get(%+v) returned error: %v", spec, err)
+		}
+		if !reflect.DeepEqual(repo, want) {
+			t.Errorf("Repos.Get(%+v) returned %+v, want %+v", spec, repo, want)
+		}
+	}
+
+	if _, _, err := client.Repos.Get(ctx, sourcegraph.RepoSpec{URI: "github.com/o/x"}, nil); err != sourcegraph.ErrNotExist {
+		t.Errorf("got error %v, want ErrNotExist", err)
+	}
+
+	if err := store.RenameRepo("github.com/o/r", "github.com/o/r2"); err != nil {
+		t.Fatal(err)
+	}
+	_, _, err := client.Repos.Get(ctx, sourcegraph.RepoSpec{URI: "github.com/o/r"}, nil)
+	if want := (sourcegraph.ErrRenamed{OldURI: "github.com/o/r", NewURI: "github.com/o/r2"}); err != want {
+		t.Errorf("got error %v, want %v", err, want)
+	}
+}
+
+func TestReposService_Create(t *testing.T) {
+	client, _ := NewClient()
+	ctx := context.Background()
+
+	if _, _, err := client.Repos.Create(ctx, sourcegraph.NewRepoSpec{CloneURLStr: "example.com/r"}); err != sourcegraph.ErrNoScheme {
+		t.Errorf("got error %v, want ErrNoScheme", err)
+	}
+
+	repo, _, err := client.Repos.Create(ctx, sourcegraph.NewRepoSpec{Type: "git", CloneURLStr: "https://example.com/r.git"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if repo.URI != "example.com/r" {
+		t.Errorf("got URI %q, want example.com/r", repo.URI)
+	}
+	again, _, err := client.Repos.Create(ctx, sourcegraph.NewRepoSpec{Type: "git", CloneURLStr: "https://example.com/r.git"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.RID != repo.RID {
+		t.Errorf("got RID %d on second Create, want existing repo's RID %d", again.RID, repo.RID)
+	}
+
+	if _, _, err := client.Repos.GetOrCreate(ctx, sourcegraph.RepoSpec{URI: "example.com/x"}, nil); err != sourcegraph.ErrNonStandardURI {
+		t.Errorf("got error %v, want ErrNonStandardURI", err)
+	}
+	gh, _, err := client.Repos.GetOrCreate(ctx, sourcegraph.RepoSpec{URI: "github.com/o/r"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !gh.IsGitHubRepo() {
+		t.Errorf("got repo %+v, want a GitHub repo", gh)
+	}
+}
+
+func TestReposService_List(t *testing.T) {
+	client, store := NewClient()
+	ctx := context.Background()
+
+	store.AddRepo(&sourcegraph.Repo{URI: "github.com/a/z"})
+	store.AddRepo(&sourcegraph.Repo{URI: "github.com/b/y", Private: true})
+	store.AddRepo(&sourcegraph.Repo{URI: "github.com/a/x", Fork: true})
+
+	tests := []struct {
+		opt  *sourcegraph.RepoListOptions
+		want []string
+	}{
+		{nil, []string{"github.com/a/z", "github.com/b/y", "github.com/a/x"}},
+		{&sourcegraph.RepoListOptions{Sort: "name"}, []string{"github.com/a/x", "github.com/b/y", "github.com/a/z"}},
+		{&sourcegraph.RepoListOptions{Sort: "uri", Direction: "desc"}, []string{"github.com/b/y", "github.com/a/z", "github.com/a/x"}},
+		{&sourcegraph.RepoListOptions{Owner: "a", NoFork: true}, []string{"github.com/a/z"}},
+		{&sourcegraph.RepoListOptions{Type: "private"}, []string{"github.com/b/y"}},
+		{&sourcegraph.RepoListOptions{URIs: []string{"github.com/a/x"}}, []string{"github.com/a/x"}},
+		{&sourcegraph.RepoListOptions{Query: "/A/"}, []string{"github.com/a/z", "github.com/a/x"}},
+		{&sourcegraph.RepoListOptions{ListOptions: sourcegraph.ListOptions{PerPage: 2, Page: 2}}, []string{"github.com/a/x"}},
+	}
+	for _, test := range tests {
+		repos, resp, err := client.Repos.List(ctx, test.opt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if uris := sourcegraph.Repos(repos).URIs(); !reflect.DeepEqual(uris, test.want) {
+			t.Errorf("%+v: got %v, want %v", test.opt, uris, test.want)
+		}
+		if test.opt != nil && test.opt.PerPage == 2 && resp.TotalCount() != 3 {
+			t.Errorf("got TotalCount %d, want 3", resp.TotalCount())
+		}
+	}
+}
+
+func TestReposService_commits(t *testing.T) {
+	client, store := NewClient()
+	ctx := context.Background()
+
+	repo := store.AddRepo(&sourcegraph.Repo{URI: "github.com/o/r"}).RepoSpec()
+	store.AddCommits(repo,
+		&sourcegraph.Commit{Commit: &vcs.Commit{ID: "ccc"}},
+		&sourcegraph.Commit{Commit: &vcs.Commit{ID: "bbb"}},
+		&sourcegraph.Commit{Commit: &vcs.Commit{ID: "aaa"}},
+	)
+	store.AddBranches(repo, &vcs.Branch{Name: "master", Head: "ccc"}, &vcs.Branch{Name: "b", Head: "bbb"})
+
+	commits, _, err := client.Repos.ListCommits(ctx, repo, &sourcegraph.RepoListCommitsOptions{Head: "b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(commits) != 2 || commits[0].ID != "bbb" || commits[1].ID != "aaa" {
+		t.Errorf("got commits %v, want bbb and aaa", commits)
+	}
+
+	commit, _, err := client.Repos.GetCommit(ctx, sourcegraph.RepoRevSpec{RepoSpec: repo}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if commit.ID != "ccc" {
+		t.Errorf("got commit %s, want default branch head ccc", commit.ID)
+	}
+	if _, _, err := client.Repos.GetCommit(ctx, sourcegraph.RepoRevSpec{RepoSpec: repo, Rev: "zzz"}, nil); !sourcegraph.IsHTTPErrorCode(err, 404) {
+		t.Errorf("got error %v, want 404", err)
+	}
+
+	// GetBuild finds the last successful build behind master.
+	build, _, err := client.Builds.Create(ctx, sourcegraph.RepoRevSpec{RepoSpec: repo, Rev: "aaa"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := client.Builds.Update(ctx, build.Spec(), sourcegraph.BuildUpdate{Success: github.Bool(true)}); err != nil {
+		t.Fatal(err)
+	}
+	info, _, err := client.Repos.GetBuild(ctx, sourcegraph.RepoRevSpec{RepoSpec: repo, Rev: "master"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Exact != nil || info.LastSuccessful.BID != build.BID || info.CommitsBehind != 2 || info.LastSuccessfulCommit.ID != "aaa" {
+		t.Errorf("got build info %+v, want last successful build %d 2 commits behind", info, build.BID)
+	}
+	if _, _, err := client.Repos.GetBuild(ctx, sourcegraph.RepoRevSpec{RepoSpec: repo, Rev: "master"}, &sourcegraph.RepoGetBuildOptions{Exact: true}); err != sourcegraph.ErrNoRepoBuild {
+		t.Errorf("got error %v, want ErrNoRepoBuild", err)
+	}
+}
+
+func TestReposService_statuses(t *testing.T) {
+	client, store := NewClient()
+	ctx := context.Background()
+
+	repo := store.AddRepo(&sourcegraph.Repo{URI: "github.com/o/r"}).RepoSpec()
+	rev := sourcegraph.RepoRevSpec{RepoSpec: repo, Rev: "c", CommitID: "c"}
+	for _, st := range []struct{ context, state string }{{"a", "pending"}, {"b", "success"}, {"a", "success"}} {
+		var status sourcegraph.RepoStatus
+		status.Context, status.State = github.String(st.context), github.String(st.state)
+		if _, _, err := client.Repos.CreateStatus(ctx, rev, status); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	combined, _, err := client.Repos.GetCombinedStatus(ctx, rev)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *combined.State != "success" || *combined.TotalCount != 2 || *combined.SHA != "c" {
+		t.Errorf("got combined status %+v, want success with 2 statuses", combined)
+	}
+}
+
+func TestReposService_UpdateSettings(t *testing.T) {
+	client, store := NewClient()
+	ctx := context.Background()
+
+	repo := store.AddRepo(&sourcegraph.Repo{URI: "github.com/o/r"}).RepoSpec()
+	if _, err := client.Repos.UpdateSettings(ctx, repo, sourcegraph.RepoSettings{Enabled: github.Bool(true)}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Repos.UpdateSettings(ctx, repo, sourcegraph.RepoSettings{BuildPushes: github.Bool(false)}); err != nil {
+		t.Fatal(err)
+	}
+	settings, _, err := client.Repos.GetSettings(ctx, repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if settings.Enabled == nil || !*settings.Enabled || settings.BuildPushes == nil || *settings.BuildPushes {
+		t.Errorf("got settings %+v, want Enabled and !BuildPushes", settings)
+	}
+
+	repos, _, err := client.Repos.List(ctx, &sourcegraph.RepoListOptions{State: "enabled"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(repos) != 1 {
+		t.Errorf("got %d enabled repos, want 1", len(repos))
+	}
+}
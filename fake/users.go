@@ -0,0 +1,261 @@
+package fake
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"sourcegraph.com/sourcegraph/go-nnz/nnz"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/sourcegraph"
+)
+
+// UsersService is a fake sourcegraph.UsersService.
+//
+// The people analysis methods (ListAuthors and ListClients) are not
+// simulated; they return empty results for existing users.
+type UsersService struct {
+	s *Store
+}
+
+var _ sourcegraph.UsersService = &UsersService{}
+
+// AddUser adds a user to the store and returns a copy of it. If
+// user.UID is 0, a new UID is assigned. The Type field defaults to
+// "User".
+func (s *Store) AddUser(user *sourcegraph.User) *sourcegraph.User {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if user.Type == "" {
+		u := *user
+		u.Type = "User"
+		user = &u
+	}
+	return s.addUser(user)
+}
+
+func (s *Store) addUser(user *sourcegraph.User) *sourcegraph.User {
+	u := *user
+	if u.UID == 0 {
+		s.lastUID++
+		u.UID = s.lastUID
+	} else if u.UID > s.lastUID {
+		s.lastUID = u.UID
+	}
+	s.users = append(s.users, &u)
+	delete(s.renamedUsers, strings.ToLower(u.Login))
+	u2 := u
+	return &u2
+}
+
+// RenameUser changes the login of the user (or organization) whose
+// login is oldLogin to newLogin. Subsequent lookups of oldLogin return
+// a sourcegraph.ErrUserRenamed error.
+func (s *Store) RenameUser(oldLogin, newLogin string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u, err := s.user(sourcegraph.UserSpec{Login: oldLogin})
+	if err != nil {
+		return err
+	}
+	u.Login = newLogin
+	s.renamedUsers[strings.ToLower(oldLogin)] = newLogin
+	delete(s.renamedUsers, strings.ToLower(newLogin))
+	for old, new := range s.renamedUsers {
+		if strings.EqualFold(new, oldLogin) {
+			s.renamedUsers[old] = newLogin
+		}
+	}
+	return nil
+}
+
+// AddUserEmails adds email addresses to a user.
+func (s *Store) AddUserEmails(user sourcegraph.UserSpec, emails ...*sourcegraph.EmailAddr) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u, err := s.user(user)
+	if err != nil {
+		return err
+	}
+	for _, e := range emails {
+		e2 := *e
+		s.emails[u.UID] = append(s.emails[u.UID], &e2)
+	}
+	return nil
+}
+
+// user returns the stored user (or organization) specified by
+// spec. Logins are compared case-insensitively. The caller must hold
+// s.mu.
+func (s *Store) user(spec sourcegraph.UserSpec) (*sourcegraph.User, error) {
+	for _, u := range s.users {
+		if (spec.UID != 0 && u.UID == spec.UID) || (spec.UID == 0 && spec.Login != "" && strings.EqualFold(u.Login, spec.Login)) {
+			return u, nil
+		}
+	}
+	if newLogin, renamed := s.renamedUsers[strings.ToLower(spec.Login)]; renamed && spec.UID == 0 {
+		return nil, sourcegraph.ErrUserRenamed{OldLogin: spec.Login, NewLogin: newLogin}
+	}
+	return nil, sourcegraph.ErrUserNotExist
+}
+
+func (s *UsersService) Get(ctx context.Context, user sourcegraph.UserSpec, opt *sourcegraph.UserGetOptions) (*sourcegraph.User, sourcegraph.Response, error) {
+	s.s.mu.Lock()
+	defer s.s.mu.Unlock()
+	u, err := s.s.user(user)
+	if err != nil {
+		return nil, noTotal(), err
+	}
+	u2 := *u
+	return &u2, noTotal(), nil
+}
+
+func (s *UsersService) GetSettings(ctx context.Context, user sourcegraph.UserSpec) (*sourcegraph.UserSettings, sourcegraph.Response, error) {
+	s.s.mu.Lock()
+	defer s.s.mu.Unlock()
+	u, err := s.s.user(user)
+	if err != nil {
+		return nil, noTotal(), err
+	}
+	var settings sourcegraph.UserSettings
+	if st := s.s.userSettings[u.UID]; st != nil {
+		settings = *st
+	}
+	return &settings, noTotal(), nil
+}
+
+func (s *UsersService) UpdateSettings(ctx context.Context, user sourcegraph.UserSpec, settings sourcegraph.UserSettings) (sourcegraph.Response, error) {
+	s.s.mu.Lock()
+	defer s.s.mu.Unlock()
+	u, err := s.s.user(user)
+	if err != nil {
+		return noTotal(), err
+	}
+	s.s.userSettings[u.UID] = &settings
+	return noTotal(), nil
+}
+
+func (s *UsersService) ListEmails(ctx context.Context, user sourcegraph.UserSpec) ([]*sourcegraph.EmailAddr, sourcegraph.Response, error) {
+	s.s.mu.Lock()
+	defer s.s.mu.Unlock()
+	u, err := s.s.user(user)
+	if err != nil {
+		return nil, noTotal(), err
+	}
+	emails := make([]*sourcegraph.EmailAddr, len(s.s.emails[u.UID]))
+	for i, e := range s.s.emails[u.UID] {
+		e2 := *e
+		emails[i] = &e2
+	}
+	return emails, &Response{Total: len(emails)}, nil
+}
+
+// GetOrCreateFromGitHub returns the user with the given GitHub ID (or,
+// if user.ID is 0, login), creating the user if no such user exists.
+func (s *UsersService) GetOrCreateFromGitHub(ctx context.Context, user sourcegraph.GitHubUserSpec, opt *sourcegraph.UserGetOptions) (*sourcegraph.User, sourcegraph.Response, error) {
+	s.s.mu.Lock()
+	defer s.s.mu.Unlock()
+	for _, u := range s.s.users {
+		if (user.ID != 0 && int(u.GitHubID) == user.ID) || (user.ID == 0 && strings.EqualFold(u.Login, user.Login)) {
+			u2 := *u
+			return &u2, noTotal(), nil
+		}
+	}
+	if user.Login == "" {
+		return nil, noTotal(), sourcegraph.ErrUserNotExist
+	}
+	githubID := user.ID
+	if githubID == 0 {
+		s.s.lastGitHubID++
+		githubID = s.s.lastGitHubID
+	}
+	return s.s.addUser(&sourcegraph.User{Login: user.Login, GitHubID: nnz.Int(githubID), Type: "User"}), noTotal(), nil
+}
+
+func (s *UsersService) RefreshProfile(ctx context.Context, userSpec sourcegraph.UserSpec) (sourcegraph.Response, error) {
+	_, resp, err := s.Get(ctx, userSpec, nil)
+	return resp, err
+}
+
+func (s *UsersService) ComputeStats(ctx context.Context, userSpec sourcegraph.UserSpec) (sourcegraph.Response, error) {
+	_, resp, err := s.Get(ctx, userSpec, nil)
+	return resp, err
+}
+
+// List lists users and organizations. Sort may be "uid" (the default),
+// "login", or "name".
+func (s *UsersService) List(ctx context.Context, opt *sourcegraph.UsersListOptions) ([]*sourcegraph.User, sourcegraph.Response, error) {
+	if opt == nil {
+		opt = &sourcegraph.UsersListOptions{}
+	}
+
+	s.s.mu.Lock()
+	defer s.s.mu.Unlock()
+	var users []*sourcegraph.User
+	for _, u := range s.s.users {
+		if opt.Query != "" && !strings.HasPrefix(strings.ToLower(u.Login), strings.ToLower(opt.Query)) {
+			continue
+		}
+		u2 := *u
+		users = append(users, &u2)
+	}
+
+	var less func(a, b *sourcegraph.User) bool
+	switch opt.Sort {
+	case "", "uid":
+		less = func(a, b *sourcegraph.User) bool { return a.UID < b.UID }
+	case "login":
+		less = func(a, b *sourcegraph.User) bool { return strings.ToLower(a.Login) < strings.ToLower(b.Login) }
+	case "name":
+		less = func(a, b *sourcegraph.User) bool { return a.Name < b.Name }
+	}
+	if less != nil {
+		sort.SliceStable(users, func(i, j int) bool { return less(users[i], users[j]) })
+	}
+	if opt.Direction == "desc" {
+		reverse(users)
+	}
+
+	users, resp := page(users, opt.ListOptions)
+	return users, resp, nil
+}
+
+func (s *UsersService) ListAuthors(ctx context.Context, user sourcegraph.UserSpec, opt *sourcegraph.UsersListAuthorsOptions) ([]*sourcegraph.AugmentedPersonUsageByClient, sourcegraph.Response, error) {
+	if _, _, err := s.Get(ctx, user, nil); err != nil {
+		return nil, noTotal(), err
+	}
+	return []*sourcegraph.AugmentedPersonUsageByClient{}, &Response{}, nil
+}
+
+func (s *UsersService) ListClients(ctx context.Context, user sourcegraph.UserSpec, opt *sourcegraph.UsersListClientsOptions) ([]*sourcegraph.AugmentedPersonUsageOfAuthor, sourcegraph.Response, error) {
+	if _, _, err := s.Get(ctx, user, nil); err != nil {
+		return nil, noTotal(), err
+	}
+	return []*sourcegraph.AugmentedPersonUsageOfAuthor{}, &Response{}, nil
+}
+
+func (s *UsersService) ListOrgs(ctx context.Context, member sourcegraph.UserSpec, opt *sourcegraph.UsersListOrgsOptions) ([]*sourcegraph.Org, sourcegraph.Response, error) {
+	if opt == nil {
+		opt = &sourcegraph.UsersListOrgsOptions{}
+	}
+
+	s.s.mu.Lock()
+	defer s.s.mu.Unlock()
+	u, err := s.s.user(member)
+	if err != nil {
+		return nil, noTotal(), err
+	}
+	var orgs []*sourcegraph.Org
+	for _, o := range s.s.users {
+		if !o.IsOrganization() {
+			continue
+		}
+		for _, uid := range s.s.members[o.UID] {
+			if uid == u.UID {
+				orgs = append(orgs, &sourcegraph.Org{User: *o})
+				break
+			}
+		}
+	}
+	orgs, resp := page(orgs, opt.ListOptions)
+	return orgs, resp, nil
+}
@@ -0,0 +1,55 @@
+package fake
+
+import (
+	"context"
+	"testing"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/sourcegraph"
+)
+
+func TestUsersService(t *testing.T) {
+	client, store := NewClient()
+	ctx := context.Background()
+
+	alice := store.AddUser(&sourcegraph.User{Login: "alice"})
+	store.AddUser(&sourcegraph.User{Login: "bob"})
+	store.AddUser(&sourcegraph.User{Login: "alfred"})
+
+	u, _, err := client.Users.Get(ctx, sourcegraph.UserSpec{Login: "Alice"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.UID != alice.UID {
+		t.Errorf("got UID %d, want %d", u.UID, alice.UID)
+	}
+	if _, _, err := client.Users.Get(ctx, sourcegraph.UserSpec{Login: "carol"}, nil); err != sourcegraph.ErrUserNotExist {
+		t.Errorf("got error %v, want ErrUserNotExist", err)
+	}
+	if err := store.RenameUser("alice", "alice2"); err != nil {
+		t.Fatal(err)
+	}
+	_, _, err = client.Users.Get(ctx, sourcegraph.UserSpec{Login: "alice"}, nil)
+	if want := (sourcegraph.ErrUserRenamed{OldLogin: "alice", NewLogin: "alice2"}); err != want {
+		t.Errorf("got error %v, want %v", err, want)
+	}
+
+	users, resp, err := client.Users.List(ctx, &sourcegraph.UsersListOptions{Query: "al", Sort: "login", Direction: "desc"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 2 || users[0].Login != "alice2" || users[1].Login != "alfred" || resp.TotalCount() != 2 {
+		t.Errorf("got users %+v, want alice2 and alfred", users)
+	}
+
+	gh, _, err := client.Users.GetOrCreateFromGitHub(ctx, sourcegraph.GitHubUserSpec{Login: "dave", ID: 123}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, _, err := client.Users.GetOrCreateFromGitHub(ctx, sourcegraph.GitHubUserSpec{Login: "dave", ID: 123}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gh.GitHubLogin() != "dave" || again.UID != gh.UID {
+		t.Errorf("got %+v and %+v, want the same GitHub user", gh, again)
+	}
+}
@@ -0,0 +1,16 @@
+module sourcegraph.com/sourcegraph/go-sourcegraph
+
+// Go 1.21 is required for context.WithoutCancel, context.AfterFunc and
+// context.WithCancelCause.
+go 1.21
+
+require (
+	github.com/google/go-querystring v1.1.0
+	github.com/gorilla/schema v1.2.0
+	github.com/kr/pretty v0.3.1
+)
+
+require (
+	github.com/kr/text v0.2.0 // indirect
+	github.com/rogpeppe/go-internal v1.9.0 // indirect
+)
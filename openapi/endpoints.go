@@ -0,0 +1,177 @@
+package openapi
+
+import (
+	"reflect"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/sourcegraph"
+	"sourcegraph.com/sourcegraph/go-vcs/vcs"
+	"sourcegraph.com/sourcegraph/srclib/unit"
+	"sourcegraph.com/sourcegraph/vcsstore/vcsclient"
+)
+
+// An Endpoint describes how the API client calls a route.
+type Endpoint struct {
+	// Method is the name of the API client method that calls the
+	// route, qualified by its service (such as "Repos.Get"), or ""
+	// if the route is not called by the API client.
+	Method string
+
+	// Options is the type of the options struct that is encoded in
+	// the URL query string (using its "url" struct tags), or nil if
+	// the route takes no options.
+	Options reflect.Type
+
+	// Body is the type of the JSON request body, or nil if the route
+	// takes no request body. If Body is the []byte type, the request
+	// body is raw data.
+	Body reflect.Type
+
+	// Result is the type of the JSON response body, or nil if the
+	// response has no body. If Result is the []byte type, the response
+	// body is raw data.
+	Result reflect.Type
+}
+
+// typeOf returns the reflect.Type of T.
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+// Endpoints maps the name of each route defined by router.NewAPIRouter
+// to the Endpoint that calls it.
+var Endpoints = map[string]Endpoint{
+	router.Build:            {"Builds.Get", typeOf[sourcegraph.BuildGetOptions](), nil, typeOf[*sourcegraph.Build]()},
+	router.Builds:           {"Builds.List", typeOf[sourcegraph.BuildListOptions](), nil, typeOf[[]*sourcegraph.Build]()},
+	router.RepoBuildsCreate: {"Builds.Create", nil, typeOf[sourcegraph.BuildCreateOptions](), typeOf[*sourcegraph.Build]()},
+	router.BuildUpdate:      {"Builds.Update", nil, typeOf[sourcegraph.BuildUpdate](), typeOf[*sourcegraph.Build]()},
+	router.BuildCancel:      {"Builds.Cancel", nil, nil, typeOf[*sourcegraph.Build]()},
+	router.BuildRetry:       {"Builds.Retry", nil, nil, typeOf[*sourcegraph.Build]()},
+	router.BuildTasks:       {"Builds.ListBuildTasks", typeOf[sourcegraph.BuildTaskListOptions](), nil, typeOf[[]*sourcegraph.BuildTask]()},
+	router.BuildTasksCreate: {"Builds.CreateTasks", nil, typeOf[[]*sourcegraph.BuildTask](), typeOf[[]*sourcegraph.BuildTask]()},
+	router.BuildTaskUpdate:  {"Builds.UpdateTask", nil, typeOf[sourcegraph.TaskUpdate](), typeOf[*sourcegraph.BuildTask]()},
+	router.BuildLog:         {"Builds.GetLog", typeOf[sourcegraph.BuildGetLogOptions](), nil, typeOf[*sourcegraph.LogEntries]()},
+	router.BuildTaskLog:     {"Builds.GetTaskLog", typeOf[sourcegraph.BuildGetLogOptions](), nil, typeOf[*sourcegraph.LogEntries]()},
+	router.BuildDequeueNext: {"Builds.DequeueNext", nil, nil, typeOf[*sourcegraph.Build]()},
+	router.BuildEvents:      {"Builds.ListEvents", typeOf[sourcegraph.BuildEventListOptions](), nil, typeOf[[]*sourcegraph.BuildEvent]()},
+
+	router.BuildQueueStatus:       {"Builds.GetQueueStatus", nil, nil, typeOf[*sourcegraph.BuildQueueStatus]()},
+	router.BuildsUpdatePriorities: {"Builds.UpdatePriorities", nil, typeOf[sourcegraph.BuildPriorityUpdate](), typeOf[[]*sourcegraph.Build]()},
+
+	router.BuildArtifacts:          {"BuildArtifacts.List", typeOf[sourcegraph.BuildArtifactListOptions](), nil, typeOf[[]*sourcegraph.BuildArtifact]()},
+	router.BuildArtifact:           {"BuildArtifacts.Download", nil, nil, typeOf[[]byte]()},
+	router.BuildArtifactUpload:     {"BuildArtifacts.Upload", typeOf[sourcegraph.BuildArtifactUploadOptions](), typeOf[[]byte](), typeOf[*sourcegraph.BuildArtifact]()},
+	router.BuildArtifactDelete:     {"BuildArtifacts.Delete", nil, nil, nil},
+	router.BuildTaskArtifacts:      {"BuildArtifacts.List", typeOf[sourcegraph.BuildArtifactListOptions](), nil, typeOf[[]*sourcegraph.BuildArtifact]()},
+	router.BuildTaskArtifact:       {"BuildArtifacts.Download", nil, nil, typeOf[[]byte]()},
+	router.BuildTaskArtifactUpload: {"BuildArtifacts.Upload", typeOf[sourcegraph.BuildArtifactUploadOptions](), typeOf[[]byte](), typeOf[*sourcegraph.BuildArtifact]()},
+	router.BuildTaskArtifactDelete: {"BuildArtifacts.Delete", nil, nil, nil},
+
+	router.RepoBuildHooks:       {"Builds.ListRepoHooks", nil, nil, typeOf[[]*sourcegraph.BuildHook]()},
+	router.RepoBuildHooksCreate: {"Builds.CreateRepoHook", nil, typeOf[sourcegraph.BuildHook](), typeOf[*sourcegraph.BuildHook]()},
+	router.RepoBuildHookDelete:  {"Builds.DeleteRepoHook", nil, nil, nil},
+
+	router.Def:           {"Defs.Get", typeOf[sourcegraph.DefGetOptions](), nil, typeOf[*sourcegraph.Def]()},
+	router.Defs:          {"Defs.List", typeOf[sourcegraph.DefListOptions](), nil, typeOf[[]*sourcegraph.Def]()},
+	router.DefRefs:       {"Defs.ListRefs", typeOf[sourcegraph.DefListRefsOptions](), nil, typeOf[[]*sourcegraph.Ref]()},
+	router.DefExamples:   {"Defs.ListExamples", typeOf[sourcegraph.DefListExamplesOptions](), nil, typeOf[[]*sourcegraph.Example]()},
+	router.DefAuthors:    {"Defs.ListAuthors", typeOf[sourcegraph.DefListAuthorsOptions](), nil, typeOf[[]*sourcegraph.AugmentedDefAuthor]()},
+	router.DefClients:    {"Defs.ListClients", typeOf[sourcegraph.DefListClientsOptions](), nil, typeOf[[]*sourcegraph.AugmentedDefClient]()},
+	router.DefDependents: {"Defs.ListDependents", typeOf[sourcegraph.DefListDependentsOptions](), nil, typeOf[[]*sourcegraph.AugmentedDefDependent]()},
+	router.DefVersions:   {"Defs.ListVersions", typeOf[sourcegraph.DefListVersionsOptions](), nil, typeOf[[]*sourcegraph.Def]()},
+
+	router.Delta:                   {"Deltas.Get", typeOf[sourcegraph.DeltaGetOptions](), nil, typeOf[*sourcegraph.Delta]()},
+	router.DeltaUnits:              {"Deltas.ListUnits", typeOf[sourcegraph.DeltaListUnitsOptions](), nil, typeOf[[]*sourcegraph.UnitDelta]()},
+	router.DeltaDefs:               {"Deltas.ListDefs", typeOf[sourcegraph.DeltaListDefsOptions](), nil, typeOf[*sourcegraph.DeltaDefs]()},
+	router.DeltaDependencies:       {"Deltas.ListDependencies", typeOf[sourcegraph.DeltaListDependenciesOptions](), nil, typeOf[*sourcegraph.DeltaDependencies]()},
+	router.DeltaFiles:              {"Deltas.ListFiles", typeOf[sourcegraph.DeltaListFilesOptions](), nil, typeOf[*sourcegraph.DeltaFiles]()},
+	router.DeltaAffectedAuthors:    {"Deltas.ListAffectedAuthors", typeOf[sourcegraph.DeltaListAffectedAuthorsOptions](), nil, typeOf[[]*sourcegraph.DeltaAffectedPerson]()},
+	router.DeltaAffectedClients:    {"Deltas.ListAffectedClients", typeOf[sourcegraph.DeltaListAffectedClientsOptions](), nil, typeOf[[]*sourcegraph.DeltaAffectedPerson]()},
+	router.DeltaAffectedDependents: {"Deltas.ListAffectedDependents", typeOf[sourcegraph.DeltaListAffectedDependentsOptions](), nil, typeOf[[]*sourcegraph.DeltaAffectedRepo]()},
+	router.DeltaReviewers:          {"Deltas.ListReviewers", typeOf[sourcegraph.DeltaListReviewersOptions](), nil, typeOf[[]*sourcegraph.DeltaReviewer]()},
+	router.DeltasIncoming:          {"Deltas.ListIncoming", typeOf[sourcegraph.DeltaListIncomingOptions](), nil, typeOf[[]*sourcegraph.Delta]()},
+
+	router.RepoIssue:               {"Issues.Get", typeOf[sourcegraph.IssueGetOptions](), nil, typeOf[*sourcegraph.Issue]()},
+	router.RepoIssues:              {"Issues.ListByRepo", typeOf[sourcegraph.IssueListOptions](), nil, typeOf[[]*sourcegraph.Issue]()},
+	router.RepoIssueComments:       {"Issues.ListComments", typeOf[sourcegraph.IssueListCommentsOptions](), nil, typeOf[[]*sourcegraph.IssueComment]()},
+	router.RepoIssueCommentsCreate: {"Issues.CreateComment", nil, typeOf[sourcegraph.IssueComment](), typeOf[*sourcegraph.IssueComment]()},
+	router.RepoIssueCommentsEdit:   {"Issues.EditComment", nil, typeOf[sourcegraph.IssueComment](), typeOf[*sourcegraph.IssueComment]()},
+	router.RepoIssueCommentsDelete: {"Issues.DeleteComment", nil, nil, nil},
+
+	router.Markdown: {"Markdown.Render", nil, typeOf[sourcegraph.MarkdownRequestBody](), typeOf[*sourcegraph.MarkdownData]()},
+
+	router.Org:               {"Orgs.Get", nil, nil, typeOf[*sourcegraph.Org]()},
+	router.OrgMembers:        {"Orgs.ListMembers", typeOf[sourcegraph.OrgListMembersOptions](), nil, typeOf[[]*sourcegraph.User]()},
+	router.OrgSettings:       {"Orgs.GetSettings", nil, nil, typeOf[*sourcegraph.OrgSettings]()},
+	router.OrgSettingsUpdate: {"Orgs.UpdateSettings", nil, typeOf[sourcegraph.OrgSettings](), nil},
+
+	router.Person: {"People.Get", nil, nil, typeOf[*sourcegraph.Person]()},
+
+	router.RepoPullRequest:               {"PullRequests.Get", typeOf[sourcegraph.PullRequestGetOptions](), nil, typeOf[*sourcegraph.PullRequest]()},
+	router.RepoPullRequests:              {"PullRequests.ListByRepo", typeOf[sourcegraph.PullRequestListOptions](), nil, typeOf[[]*sourcegraph.PullRequest]()},
+	router.RepoPullRequestComments:       {"PullRequests.ListComments", typeOf[sourcegraph.PullRequestListCommentsOptions](), nil, typeOf[[]*sourcegraph.PullRequestComment]()},
+	router.RepoPullRequestCommentsCreate: {"PullRequests.CreateComment", nil, typeOf[sourcegraph.PullRequestComment](), typeOf[*sourcegraph.PullRequestComment]()},
+	router.RepoPullRequestCommentsEdit:   {"PullRequests.EditComment", nil, typeOf[sourcegraph.PullRequestComment](), typeOf[*sourcegraph.PullRequestComment]()},
+	router.RepoPullRequestCommentsDelete: {"PullRequests.DeleteComment", nil, nil, nil},
+	router.RepoPullRequestMerge:          {"PullRequests.Merge", nil, typeOf[sourcegraph.PullRequestMergeRequest](), typeOf[*sourcegraph.PullRequestMergeResult]()},
+
+	router.Repo:                  {"Repos.Get", typeOf[sourcegraph.RepoGetOptions](), nil, typeOf[*sourcegraph.Repo]()},
+	router.RepoStats:             {"Repos.GetStats", nil, nil, typeOf[sourcegraph.RepoStats]()},
+	router.RepoStatusCreate:      {"Repos.CreateStatus", nil, typeOf[sourcegraph.RepoStatus](), typeOf[*sourcegraph.RepoStatus]()},
+	router.RepoCombinedStatus:    {"Repos.GetCombinedStatus", nil, nil, typeOf[*sourcegraph.CombinedStatus]()},
+	router.ReposGetOrCreate:      {"Repos.GetOrCreate", typeOf[sourcegraph.RepoGetOptions](), nil, typeOf[*sourcegraph.Repo]()},
+	router.RepoSettings:          {"Repos.GetSettings", nil, nil, typeOf[*sourcegraph.RepoSettings]()},
+	router.RepoSettingsUpdate:    {"Repos.UpdateSettings", nil, typeOf[sourcegraph.RepoSettings](), nil},
+	router.RepoRefreshProfile:    {"Repos.RefreshProfile", nil, nil, nil},
+	router.RepoRefreshVCSData:    {"Repos.RefreshVCSData", nil, nil, nil},
+	router.RepoComputeStats:      {"Repos.ComputeStats", nil, nil, nil},
+	router.RepoBuild:             {"Repos.GetBuild", typeOf[sourcegraph.RepoGetBuildOptions](), nil, typeOf[*sourcegraph.RepoBuildInfo]()},
+	router.ReposCreate:           {"Repos.Create", nil, typeOf[sourcegraph.NewRepoSpec](), typeOf[*sourcegraph.Repo]()},
+	router.RepoReadme:            {"Repos.GetReadme", nil, nil, typeOf[*vcsclient.TreeEntry]()},
+	router.Repos:                 {"Repos.List", typeOf[sourcegraph.RepoListOptions](), nil, typeOf[[]*sourcegraph.Repo]()},
+	router.RepoCommits:           {"Repos.ListCommits", typeOf[sourcegraph.RepoListCommitsOptions](), nil, typeOf[[]*sourcegraph.Commit]()},
+	router.RepoCommit:            {"Repos.GetCommit", typeOf[sourcegraph.RepoGetCommitOptions](), nil, typeOf[*sourcegraph.Commit]()},
+	router.RepoBranches:          {"Repos.ListBranches", typeOf[sourcegraph.RepoListBranchesOptions](), nil, typeOf[[]*vcs.Branch]()},
+	router.RepoTags:              {"Repos.ListTags", typeOf[sourcegraph.RepoListTagsOptions](), nil, typeOf[[]*vcs.Tag]()},
+	router.RepoBadges:            {"Repos.ListBadges", nil, nil, typeOf[[]*sourcegraph.Badge]()},
+	router.RepoCounters:          {"Repos.ListCounters", nil, nil, typeOf[[]*sourcegraph.Counter]()},
+	router.RepoAuthors:           {"Repos.ListAuthors", typeOf[sourcegraph.RepoListAuthorsOptions](), nil, typeOf[[]*sourcegraph.AugmentedRepoAuthor]()},
+	router.RepoClients:           {"Repos.ListClients", typeOf[sourcegraph.RepoListClientsOptions](), nil, typeOf[[]*sourcegraph.AugmentedRepoClient]()},
+	router.RepoDependencies:      {"Repos.ListDependencies", typeOf[sourcegraph.RepoListDependenciesOptions](), nil, typeOf[[]*sourcegraph.AugmentedRepoDependency]()},
+	router.RepoDependents:        {"Repos.ListDependents", typeOf[sourcegraph.RepoListDependentsOptions](), nil, typeOf[[]*sourcegraph.AugmentedRepoDependent]()},
+	router.UserRepoContributions: {"Repos.ListByContributor", typeOf[sourcegraph.RepoListByContributorOptions](), nil, typeOf[[]*sourcegraph.AugmentedRepoContribution]()},
+	router.UserRepoDependencies:  {"Repos.ListByClient", typeOf[sourcegraph.RepoListByClientOptions](), nil, typeOf[[]*sourcegraph.AugmentedRepoUsageByClient]()},
+	router.UserRepoDependents:    {"Repos.ListByRefdAuthor", typeOf[sourcegraph.RepoListByRefdAuthorOptions](), nil, typeOf[[]*sourcegraph.AugmentedRepoUsageOfAuthor]()},
+
+	router.RepoBuildDataEntry: {"BuildData.FileSystem", nil, typeOf[[]byte](), typeOf[[]byte]()},
+
+	router.RepoTreeEntry:  {"RepoTree.Get", typeOf[sourcegraph.RepoTreeGetOptions](), nil, typeOf[*sourcegraph.TreeEntry]()},
+	router.RepoTreeSearch: {"RepoTree.Search", typeOf[sourcegraph.RepoTreeSearchOptions](), nil, typeOf[[]*vcs.SearchResult]()},
+
+	router.Search:            {"Search.Search", typeOf[sourcegraph.SearchOptions](), nil, typeOf[*sourcegraph.SearchResults]()},
+	router.SearchComplete:    {"Search.Complete", typeOf[sourcegraph.RawQuery](), nil, typeOf[*sourcegraph.Completions]()},
+	router.SearchSuggestions: {"Search.Suggest", typeOf[sourcegraph.RawQuery](), nil, typeOf[[]*sourcegraph.Suggestion]()},
+
+	router.Unit:  {"Units.Get", nil, nil, typeOf[*unit.RepoSourceUnit]()},
+	router.Units: {"Units.List", typeOf[sourcegraph.UnitListOptions](), nil, typeOf[[]*unit.RepoSourceUnit]()},
+
+	router.User:               {"Users.Get", typeOf[sourcegraph.UserGetOptions](), nil, typeOf[*sourcegraph.User]()},
+	router.UserEmails:         {"Users.ListEmails", nil, nil, typeOf[[]*sourcegraph.EmailAddr]()},
+	router.UserSettings:       {"Users.GetSettings", nil, nil, typeOf[*sourcegraph.UserSettings]()},
+	router.UserSettingsUpdate: {"Users.UpdateSettings", nil, typeOf[sourcegraph.UserSettings](), nil},
+	router.UserFromGitHub:     {"Users.GetOrCreateFromGitHub", typeOf[sourcegraph.UserGetOptions](), nil, typeOf[*sourcegraph.User]()},
+	router.UserRefreshProfile: {"Users.RefreshProfile", nil, nil, nil},
+	router.UserComputeStats:   {"Users.ComputeStats", nil, nil, nil},
+	router.Users:              {"Users.List", typeOf[sourcegraph.UsersListOptions](), nil, typeOf[[]*sourcegraph.User]()},
+	router.UserAuthors:        {"Users.ListAuthors", typeOf[sourcegraph.UsersListAuthorsOptions](), nil, typeOf[[]*sourcegraph.AugmentedPersonUsageByClient]()},
+	router.UserClients:        {"Users.ListClients", typeOf[sourcegraph.UsersListClientsOptions](), nil, typeOf[[]*sourcegraph.AugmentedPersonUsageOfAuthor]()},
+	router.UserOrgs:           {"Users.ListOrgs", typeOf[sourcegraph.UsersListOrgsOptions](), nil, typeOf[[]*sourcegraph.Org]()},
+
+	// Routes that are not called by the API client.
+	router.RepoBadge:                        {},
+	router.RepoCounter:                      {},
+	router.RepoCompareCommits:               {},
+	router.Snippet:                          {},
+	router.ExtGitHubReceiveWebhook:          {},
+	router.RedirectOldRepoBadgesAndCounters: {},
+}
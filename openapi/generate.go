@@ -0,0 +1,422 @@
+package openapi
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sourcegraph/go-github/github"
+	"github.com/sourcegraph/mux"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/db_common"
+)
+
+// Generate returns an OpenAPI document that describes the named routes
+// of r, which is typically the router returned by
+// router.NewAPIRouter. Each route must have an entry in Endpoints.
+func Generate(r *mux.Router, info Info) (*Document, error) {
+	g := &generator{schemas: map[string]*Schema{}, names: map[reflect.Type]string{}}
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    info,
+		Paths:   map[string]*PathItem{},
+	}
+
+	err := r.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		name := route.GetName()
+		if name == "" {
+			return nil
+		}
+		e, ok := Endpoints[name]
+		if !ok {
+			return fmt.Errorf("no endpoint is defined for route %q", name)
+		}
+		tpl, err := route.GetPathTemplate()
+		if err != nil {
+			return fmt.Errorf("route %q: %s", name, err)
+		}
+		methods, err := route.GetMethods()
+		if err != nil {
+			return fmt.Errorf("route %q: %s", name, err)
+		}
+
+		path := pathTemplate(tpl)
+		item := doc.Paths[path]
+		if item == nil {
+			item = &PathItem{}
+			doc.Paths[path] = item
+		}
+		for _, method := range methods {
+			if !isOperationMethod(method) {
+				continue
+			}
+			op := g.operation(name, e, path, method)
+			if len(methods) > 1 {
+				op.OperationID += "-" + strings.ToLower(method)
+			}
+			(*item)[strings.ToLower(method)] = op
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	g.schemas["Error"] = &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"Message": {Type: "string"},
+			"Code":    {Type: "string", Description: "Machine-readable error code (see the ErrorCode constants in the sourcegraph package)."},
+			"Details": {Description: "Data about the error, for error codes that carry data (such as the old and new URIs of a renamed repository)."},
+		},
+	}
+	doc.Components.Schemas = g.schemas
+	return doc, nil
+}
+
+// isOperationMethod reports whether method is an HTTP method that an
+// OpenAPI path item may describe.
+func isOperationMethod(method string) bool {
+	switch method {
+	case "GET", "PUT", "POST", "DELETE", "OPTIONS", "HEAD", "PATCH", "TRACE":
+		return true
+	}
+	return false
+}
+
+var (
+	routeVarPattern = regexp.MustCompile(`\{(\w+)(?::[^{}]*)?\}`)
+	pathVarPattern  = regexp.MustCompile(`\{(\w+)\}`)
+)
+
+// pathTemplate converts a mux route path template (such as
+// "/repos/{RepoSpec:...}{Rev:...}/.tree{Path:...}") to an OpenAPI path
+// template (such as "/repos/{RepoSpec}@{Rev}/.tree/{Path}").
+func pathTemplate(tpl string) string {
+	p := routeVarPattern.ReplaceAllString(tpl, "{$1}")
+
+	// The Rev variable's pattern includes its leading "@", and the
+	// Path variable's pattern includes its leading "/".
+	p = strings.Replace(p, "{RepoSpec}{Rev}", "{RepoSpec}@{Rev}", -1)
+	p = strings.Replace(p, "{Path}", "/{Path}", -1)
+
+	// The def route's raw unit variable is renamed to Unit by
+	// router.FixDefUnitVars.
+	p = strings.Replace(p, "{rawUnit}", "{Unit}", -1)
+	return p
+}
+
+// pathParamDescriptions describes the route variables.
+var pathParamDescriptions = map[string]string{
+	"RepoSpec":       `Repository URI (such as "github.com/foo/bar"), or "R$" followed by the repository's RID.`,
+	"Rev":            `Revision specifier (such as a branch name or commit ID), optionally followed by "===" and the commit ID it resolves to. The "@{Rev}" path component may be omitted to use the repository's default branch.`,
+	"DeltaHeadRev":   `Revision specifier of the head of the delta. If the head is in a different repository than the base, it is the base64-encoded head repository spec, ":", and the revision specifier.`,
+	"Path":           `Slash-separated file path ("." for the root directory).`,
+	"UnitType":       `Source unit type.`,
+	"Unit":           `Source unit name.`,
+	"UserSpec":       `User login, or "$" followed by the user's UID.`,
+	"OrgSpec":        `Organization login, or "$" followed by the organization's UID.`,
+	"PersonSpec":     `Email address, login, or "$" followed by the UID of a person.`,
+	"GitHubUserSpec": `GitHub user login.`,
+	"BID":            `Build ID.`,
+	"TaskID":         `Build task ID.`,
+	"Issue":          `Issue number.`,
+	"Pull":           `Pull request number.`,
+	"CommentID":      `Comment ID.`,
+}
+
+func (g *generator) operation(route string, e Endpoint, path, method string) *Operation {
+	op := &Operation{
+		OperationID: route,
+		Summary:     e.Method,
+		Responses:   map[string]*Response{},
+	}
+	if e.Method != "" {
+		op.OperationID = e.Method
+		op.Tags = []string{e.Method[:strings.Index(e.Method, ".")]}
+	} else {
+		op.Description = "This route is not called by the API client."
+	}
+
+	for _, m := range pathVarPattern.FindAllStringSubmatch(path, -1) {
+		op.Parameters = append(op.Parameters, &Parameter{
+			Name:        m[1],
+			In:          "path",
+			Description: pathParamDescriptions[m[1]],
+			Required:    true,
+			Schema:      &Schema{Type: "string"},
+		})
+	}
+	if e.Options != nil {
+		op.Parameters = append(op.Parameters, queryParams(e.Options)...)
+	}
+
+	if e.Body != nil && (method == "POST" || method == "PUT" || method == "PATCH") {
+		op.RequestBody = &RequestBody{Required: true, Content: g.content(e.Body)}
+	}
+
+	switch {
+	case e.Result != nil && method != "HEAD":
+		resp := &Response{Description: "OK", Content: g.content(e.Result)}
+		if e.Result.Kind() == reflect.Slice && e.Result != bytesType {
+			resp.Headers = map[string]*Header{
+				"X-Total-Count": {
+					Description: "Total number of results (before pagination).",
+					Schema:      &Schema{Type: "integer"},
+				},
+			}
+		}
+		op.Responses[strconv.Itoa(http.StatusOK)] = resp
+	case e.Method != "":
+		op.Responses[strconv.Itoa(http.StatusNoContent)] = &Response{Description: "No Content"}
+	default:
+		op.Responses[strconv.Itoa(http.StatusOK)] = &Response{Description: "OK"}
+	}
+	op.Responses["default"] = &Response{
+		Description: "Error",
+		Content: map[string]*MediaType{
+			"application/json": {Schema: &Schema{Ref: "#/components/schemas/Error"}},
+		},
+	}
+	return op
+}
+
+var bytesType = reflect.TypeOf([]byte(nil))
+
+// content returns the content of a request or response body of type
+// t.
+func (g *generator) content(t reflect.Type) map[string]*MediaType {
+	if t == bytesType {
+		return map[string]*MediaType{
+			"application/octet-stream": {Schema: &Schema{Type: "string", Format: "binary"}},
+		}
+	}
+	return map[string]*MediaType{"application/json": {Schema: g.schema(t)}}
+}
+
+// queryParams returns the query parameters of an options struct type,
+// as encoded by the API client (using go-querystring and the fields'
+// "url" struct tags). Fields whose types cannot be encoded as query
+// parameters are omitted.
+func queryParams(t reflect.Type) []*Parameter {
+	var params []*Parameter
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		tag := strings.Split(f.Tag.Get("url"), ",")
+		if tag[0] == "-" {
+			continue
+		}
+		if f.Anonymous && tag[0] == "" && f.Type.Kind() == reflect.Struct {
+			params = append(params, queryParams(f.Type)...)
+			continue
+		}
+
+		name := f.Name
+		if tag[0] != "" {
+			name = tag[0]
+		}
+		p := &Parameter{Name: name, In: "query"}
+		ft := f.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if ft.Kind() == reflect.Slice || ft.Kind() == reflect.Array {
+			items := scalarSchema(ft.Elem())
+			if items == nil {
+				continue
+			}
+			p.Schema = &Schema{Type: "array", Items: items}
+			p.Style = "form"
+			explode := !contains(tag[1:], "comma")
+			p.Explode = &explode
+		} else if p.Schema = scalarSchema(ft); p.Schema == nil {
+			continue
+		}
+		params = append(params, p)
+	}
+	return params
+}
+
+func contains(a []string, s string) bool {
+	for _, v := range a {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// scalarSchema returns the schema of a boolean, numeric, string, or
+// time type, or nil if t is not such a type.
+func scalarSchema(t reflect.Type) *Schema {
+	if s, ok := schemaOverrides[t]; ok {
+		s2 := *s
+		return &s2
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	}
+	return nil
+}
+
+// schemaOverrides are the schemas of types whose JSON encoding is not
+// apparent from their Go type.
+var schemaOverrides = map[reflect.Type]*Schema{
+	typeOf[time.Time]():          {Type: "string", Format: "date-time"},
+	typeOf[github.Timestamp]():   {Type: "string", Format: "date-time"},
+	typeOf[db_common.NullTime](): {Type: "string", Format: "date-time", Nullable: true},
+	typeOf[db_common.NullInt]():  {Type: "integer", Format: "int64", Nullable: true},
+	typeOf[json.RawMessage]():    {},
+}
+
+var (
+	jsonMarshalerType = typeOf[json.Marshaler]()
+	textMarshalerType = typeOf[encoding.TextMarshaler]()
+)
+
+type generator struct {
+	schemas map[string]*Schema      // named schemas, by name
+	names   map[reflect.Type]string // schema name of each named struct type
+}
+
+// schema returns the schema of the JSON encoding of values of type t.
+// Named struct types are added to g.schemas and referred to by $ref.
+// It returns nil for types that cannot be encoded as JSON.
+func (g *generator) schema(t reflect.Type) *Schema {
+	if s := scalarSchema(t); s != nil {
+		return s
+	}
+	if t.Implements(jsonMarshalerType) || reflect.PtrTo(t).Implements(jsonMarshalerType) {
+		return &Schema{}
+	}
+	if t.Implements(textMarshalerType) || reflect.PtrTo(t).Implements(textMarshalerType) {
+		return &Schema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		s := g.schema(t.Elem())
+		if s != nil && s.Ref == "" {
+			s.Nullable = true
+		}
+		return s
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		items := g.schema(t.Elem())
+		if items == nil {
+			return nil
+		}
+		return &Schema{Type: "array", Items: items}
+	case reflect.Map:
+		values := g.schema(t.Elem())
+		if values == nil {
+			return nil
+		}
+		return &Schema{Type: "object", AdditionalProperties: values}
+	case reflect.Interface:
+		return &Schema{}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		name, ok := g.names[t]
+		if !ok {
+			name = g.schemaName(t)
+			g.names[t] = name
+			s := &Schema{}
+			g.schemas[name] = s // added before recursing, for recursive types
+			*s = *g.structSchema(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + name}
+	}
+	return nil
+}
+
+// schemaName returns a unique name for the schema of the named type
+// t. Types in package sourcegraph are named by their type name, and
+// other types are qualified by their package name (such as
+// "vcs.Commit").
+func (g *generator) schemaName(t reflect.Type) string {
+	name := t.Name()
+	if !strings.HasSuffix(t.PkgPath(), "/go-sourcegraph/sourcegraph") {
+		name = t.String()
+	}
+	unique := name
+	for i := 2; g.schemas[unique] != nil; i++ {
+		unique = name + strconv.Itoa(i)
+	}
+	return unique
+}
+
+// structSchema returns the schema of a struct type, following the
+// encoding/json rules for field names and embedded structs.
+func (g *generator) structSchema(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	var embedded []reflect.Type
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := strings.Split(f.Tag.Get("json"), ",")
+		if tag[0] == "-" && len(tag) == 1 {
+			continue
+		}
+		if f.Anonymous && tag[0] == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				embedded = append(embedded, ft)
+				continue
+			}
+		}
+		if f.PkgPath != "" {
+			continue
+		}
+
+		name := f.Name
+		if tag[0] != "" {
+			name = tag[0]
+		}
+		fs := g.schema(f.Type)
+		if fs == nil {
+			continue
+		}
+		if contains(tag[1:], "string") && fs.Type != "" {
+			fs = &Schema{Type: "string"}
+		}
+		s.Properties[name] = fs
+	}
+
+	// Fields of embedded structs are promoted unless they are
+	// shadowed by fields of the outer struct.
+	for _, et := range embedded {
+		for name, fs := range g.structSchema(et).Properties {
+			if _, present := s.Properties[name]; !present {
+				s.Properties[name] = fs
+			}
+		}
+	}
+	return s
+}
@@ -0,0 +1,128 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sourcegraph/mux"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/sourcegraph"
+)
+
+func TestEndpoints(t *testing.T) {
+	routes := map[string]bool{}
+	router.NewAPIRouter(nil).Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		if name := route.GetName(); name != "" {
+			routes[name] = true
+		}
+		return nil
+	})
+	for name := range routes {
+		if _, ok := Endpoints[name]; !ok {
+			t.Errorf("route %q has no endpoint", name)
+		}
+	}
+
+	clientType := reflect.TypeOf(sourcegraph.Client{})
+	for name, e := range Endpoints {
+		if !routes[name] {
+			t.Errorf("endpoint %q has no route", name)
+		}
+		if e.Method == "" {
+			continue
+		}
+		parts := strings.Split(e.Method, ".")
+		svc, ok := clientType.FieldByName(parts[0])
+		if !ok || len(parts) != 2 {
+			t.Errorf("endpoint %q: no such client service for method %q", name, e.Method)
+			continue
+		}
+		if _, ok := svc.Type.MethodByName(parts[1]); !ok {
+			t.Errorf("endpoint %q: no such client method %q", name, e.Method)
+		}
+	}
+}
+
+func TestPathTemplate(t *testing.T) {
+	r := router.NewAPIRouter(nil)
+	tests := map[string]string{
+		router.Repos:         "/repos",
+		router.Repo:          "/repos/{RepoSpec}",
+		router.RepoBuild:     "/repos/{RepoSpec}@{Rev}/.build",
+		router.RepoTreeEntry: "/repos/{RepoSpec}@{Rev}/.tree/{Path}",
+		router.Def:           "/repos/{RepoSpec}@{Rev}/.defs/.{UnitType}/{Unit}.def/{Path}",
+		router.Delta:         "/repos/{RepoSpec}/.deltas/{Rev}..{DeltaHeadRev}",
+		router.BuildTaskLog:  "/builds/{BID}/tasks/{TaskID}/log",
+	}
+	for name, want := range tests {
+		tpl, err := r.Get(name).GetPathTemplate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := pathTemplate(tpl); got != want {
+			t.Errorf("%s: got path %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	doc, err := Generate(router.NewAPIRouter(nil), Info{Title: "Sourcegraph API", Version: "test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := json.Marshal(doc); err != nil {
+		t.Fatal(err)
+	}
+
+	item := doc.Paths["/repos"]
+	if item == nil || (*item)["get"] == nil || (*item)["post"] == nil {
+		t.Fatalf("got /repos path item %+v, want get and post operations", item)
+	}
+	list := (*item)["get"]
+	if list.OperationID != "Repos.List" || !reflect.DeepEqual(list.Tags, []string{"Repos"}) {
+		t.Errorf("got operation %+v, want Repos.List", list)
+	}
+	var uris, perPage *Parameter
+	for _, p := range list.Parameters {
+		switch p.Name {
+		case "URIs":
+			uris = p
+		case "PerPage":
+			perPage = p
+		}
+	}
+	if uris == nil || uris.Schema.Type != "array" || uris.Explode == nil || *uris.Explode {
+		t.Errorf("got URIs parameter %+v, want comma-separated array", uris)
+	}
+	if perPage == nil || perPage.Schema.Type != "integer" {
+		t.Errorf("got PerPage parameter %+v, want integer (from embedded ListOptions)", perPage)
+	}
+	resp := list.Responses["200"]
+	if resp == nil || resp.Headers["X-Total-Count"] == nil {
+		t.Errorf("got 200 response %+v, want X-Total-Count header", resp)
+	}
+	if s := resp.Content["application/json"].Schema; s.Type != "array" || s.Items.Ref != "#/components/schemas/Repo" {
+		t.Errorf("got response schema %+v, want array of Repo", s)
+	}
+
+	repo := doc.Components.Schemas["Repo"]
+	if repo == nil {
+		t.Fatal("no Repo schema")
+	}
+	if s := repo.Properties["URI"]; s == nil || s.Type != "string" {
+		t.Errorf("got Repo.URI schema %+v, want string", s)
+	}
+
+	update := (*doc.Paths["/repos/{RepoSpec}/.settings"])["put"]
+	if update == nil || update.RequestBody == nil || update.Responses["204"] == nil {
+		t.Errorf("got settings update operation %+v, want request body and 204 response", update)
+	}
+
+	edit := doc.Paths["/repos/{RepoSpec}/.issues/{Issue}/comments/{CommentID}"]
+	if (*edit)["patch"].OperationID != "Issues.EditComment-patch" || (*edit)["put"].OperationID != "Issues.EditComment-put" {
+		t.Errorf("got operation IDs %q and %q, want unique IDs for each method", (*edit)["patch"].OperationID, (*edit)["put"].OperationID)
+	}
+}
@@ -0,0 +1,107 @@
+// Package openapi generates an OpenAPI 3 document that describes the
+// Sourcegraph API.
+//
+// The document is generated from the routes defined by
+// router.NewAPIRouter and from the Endpoints table, which maps each
+// route to the API client method that calls it, the options struct
+// that the client encodes in the URL query string, and the types of
+// the JSON request and response bodies. It can be used to generate API
+// clients in other languages and to review changes to the API surface
+// between versions of this library.
+package openapi
+
+// Version is the version of the OpenAPI specification that generated
+// documents conform to.
+const Version = "3.0.3"
+
+// A Document is an OpenAPI document. Only the subset of the OpenAPI
+// specification that is needed to describe the Sourcegraph API is
+// supported.
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Servers    []Server             `json:"servers,omitempty"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components Components           `json:"components"`
+}
+
+// Info describes the API.
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// A Server is a URL of a server that serves the API.
+type Server struct {
+	URL string `json:"url"`
+}
+
+// A PathItem maps lowercase HTTP methods (such as "get") to the
+// operations on a path.
+type PathItem map[string]*Operation
+
+// An Operation describes an API operation (a route and HTTP method).
+type Operation struct {
+	OperationID string               `json:"operationId"`
+	Summary     string               `json:"summary,omitempty"`
+	Description string               `json:"description,omitempty"`
+	Tags        []string             `json:"tags,omitempty"`
+	Parameters  []*Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+}
+
+// A Parameter describes a path or query parameter of an operation.
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"` // "path" or "query"
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Style       string  `json:"style,omitempty"`
+	Explode     *bool   `json:"explode,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// A RequestBody describes the request body of an operation.
+type RequestBody struct {
+	Required bool                  `json:"required,omitempty"`
+	Content  map[string]*MediaType `json:"content"`
+}
+
+// A MediaType describes the content of a request or response body
+// with a given media type.
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// A Response describes a response of an operation.
+type Response struct {
+	Description string                `json:"description"`
+	Headers     map[string]*Header    `json:"headers,omitempty"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+// A Header describes a response header.
+type Header struct {
+	Description string  `json:"description,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// Components holds the named schemas that are referred to elsewhere in
+// the document.
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas,omitempty"`
+}
+
+// A Schema describes a data type. An empty Schema allows any value.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+}
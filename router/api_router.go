@@ -5,7 +5,10 @@ import "github.com/sourcegraph/mux"
 const (
 	Build            = "build"
 	BuildDequeueNext = "build.dequeue-next"
+	BuildEvents      = "build.events"
 	BuildUpdate      = "build.update"
+	BuildCancel      = "build.cancel"
+	BuildRetry       = "build.retry"
 	BuildLog         = "build.log"
 	Builds           = "builds"
 	BuildTasks       = "build.tasks"
@@ -13,6 +16,18 @@ const (
 	BuildTasksCreate = "build.tasks.create"
 	BuildTaskLog     = "build.task.log"
 
+	BuildQueueStatus       = "build.queue-status"
+	BuildsUpdatePriorities = "builds.update-priorities"
+
+	BuildArtifacts          = "build.artifacts"
+	BuildArtifact           = "build.artifact"
+	BuildArtifactUpload     = "build.artifact.upload"
+	BuildArtifactDelete     = "build.artifact.delete"
+	BuildTaskArtifacts      = "build.task.artifacts"
+	BuildTaskArtifact       = "build.task.artifact"
+	BuildTaskArtifactUpload = "build.task.artifact.upload"
+	BuildTaskArtifactDelete = "build.task.artifact.delete"
+
 	Org               = "org"
 	OrgMembers        = "org.members"
 	OrgSettings       = "org.settings"
@@ -80,6 +95,10 @@ const (
 
 	RepoBuild = "repo.build"
 
+	RepoBuildHooks       = "repo.build-hooks"
+	RepoBuildHooksCreate = "repo.build-hooks.create"
+	RepoBuildHookDelete  = "repo.build-hook.delete"
+
 	RepoCommits        = "repo.commits"
 	RepoCommit         = "repo.commit"
 	RepoCompareCommits = "repo.compare-commits"
@@ -140,15 +159,30 @@ func NewAPIRouter(base *mux.Router) *mux.Router {
 	base.Path("/builds").Methods("GET").Name(Builds)
 	builds := base.PathPrefix("/builds").Subrouter()
 	builds.Path("/next").Methods("POST").Name(BuildDequeueNext)
+	builds.Path("/events").Methods("GET").Name(BuildEvents)
+	builds.Path("/priorities").Methods("POST").Name(BuildsUpdatePriorities)
 	buildPath := "/{BID}"
 	builds.Path(buildPath).Methods("GET").Name(Build)
 	builds.Path(buildPath).Methods("PUT").Name(BuildUpdate)
 	build := builds.PathPrefix(buildPath).Subrouter()
+	build.Path("/cancel").Methods("POST").Name(BuildCancel)
+	build.Path("/retry").Methods("POST").Name(BuildRetry)
+	build.Path("/queue").Methods("GET").Name(BuildQueueStatus)
 	build.Path("/log").Methods("GET").Name(BuildLog)
 	build.Path("/tasks").Methods("GET").Name(BuildTasks)
 	build.Path("/tasks").Methods("POST").Name(BuildTasksCreate)
 	build.Path("/tasks/{TaskID}").Methods("PUT").Name(BuildTaskUpdate)
 	build.Path("/tasks/{TaskID}/log").Methods("GET").Name(BuildTaskLog)
+	build.Path("/artifacts").Methods("GET").Name(BuildArtifacts)
+	build.Path("/tasks/{TaskID}/artifacts").Methods("GET").Name(BuildTaskArtifacts)
+	artifactPath := "/artifacts" + TreeEntryPathPattern
+	build.Path(artifactPath).Methods("GET", "HEAD").PostMatchFunc(FixTreeEntryVars).BuildVarsFunc(PrepareTreeEntryRouteVars).Name(BuildArtifact)
+	build.Path(artifactPath).Methods("PUT").PostMatchFunc(FixTreeEntryVars).BuildVarsFunc(PrepareTreeEntryRouteVars).Name(BuildArtifactUpload)
+	build.Path(artifactPath).Methods("DELETE").PostMatchFunc(FixTreeEntryVars).BuildVarsFunc(PrepareTreeEntryRouteVars).Name(BuildArtifactDelete)
+	taskArtifactPath := "/tasks/{TaskID}" + artifactPath
+	build.Path(taskArtifactPath).Methods("GET", "HEAD").PostMatchFunc(FixTreeEntryVars).BuildVarsFunc(PrepareTreeEntryRouteVars).Name(BuildTaskArtifact)
+	build.Path(taskArtifactPath).Methods("PUT").PostMatchFunc(FixTreeEntryVars).BuildVarsFunc(PrepareTreeEntryRouteVars).Name(BuildTaskArtifactUpload)
+	build.Path(taskArtifactPath).Methods("DELETE").PostMatchFunc(FixTreeEntryVars).BuildVarsFunc(PrepareTreeEntryRouteVars).Name(BuildTaskArtifactDelete)
 
 	base.Path("/repos").Methods("GET").Name(Repos)
 	base.Path("/repos").Methods("POST").Name(ReposCreate)
@@ -179,6 +213,9 @@ func NewAPIRouter(base *mux.Router) *mux.Router {
 	repo.Path("/.vcs-data").Methods("PUT").Name(RepoRefreshVCSData)
 	repo.Path("/.settings").Methods("GET").Name(RepoSettings)
 	repo.Path("/.settings").Methods("PUT").Name(RepoSettingsUpdate)
+	repo.Path("/.build-hooks").Methods("GET").Name(RepoBuildHooks)
+	repo.Path("/.build-hooks").Methods("POST").Name(RepoBuildHooksCreate)
+	repo.Path("/.build-hooks/{HookID}").Methods("DELETE").Name(RepoBuildHookDelete)
 	repo.Path("/.commits").Methods("GET").Name(RepoCommits)
 	repo.Path("/.commits/{Rev:" + PathComponentNoLeadingDot + "}/.compare").Methods("GET").Name(RepoCompareCommits)
 	repo.Path("/.commits/{Rev:" + PathComponentNoLeadingDot + "}").Methods("GET").Name(RepoCommit)
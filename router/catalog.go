@@ -0,0 +1,268 @@
+package router
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/sourcegraph/mux"
+)
+
+// A RouteInfo describes a named route: the HTTP methods it matches,
+// its URL path template, and the route variables that must be given
+// to generate its URL.
+type RouteInfo struct {
+	// Name is the route name (such as "repo.tree.entry").
+	Name string
+
+	// Methods are the HTTP methods that the route matches, or nil if
+	// it matches all methods.
+	Methods []string
+
+	// PathTemplate is the route's URL path template, including the
+	// regexp pattern of each route variable (such as
+	// "/builds/{BID}/tasks/{TaskID}").
+	PathTemplate string
+
+	// Vars are the route variables that are used to generate the
+	// route's URL, in the order that they appear in the path.
+	Vars []RouteVar
+}
+
+// A RouteVar describes a route variable.
+type RouteVar struct {
+	// Name is the name of the variable in the routeVars map passed to
+	// generate URLs (such as "RepoSpec").
+	Name string
+
+	// Pattern is the regexp that the variable's value in the URL path
+	// must match, or "" if the variable matches a single non-empty
+	// path component.
+	Pattern string
+
+	// Optional is whether the variable may be omitted when generating
+	// the route's URL. Optional variables are filled in by the
+	// route's mux.BuildVarsFunc (for example, an omitted Rev refers to
+	// the repository's default branch).
+	Optional bool
+}
+
+// RequiredVars returns the names of the route variables that must be
+// given to generate the route's URL.
+func (ri *RouteInfo) RequiredVars() []string {
+	var names []string
+	for _, v := range ri.Vars {
+		if !v.Optional {
+			names = append(names, v.Name)
+		}
+	}
+	return names
+}
+
+// CheckVars returns a *RouteVarsError if vars lacks a required route
+// variable or has an empty value for a required route variable that
+// must be non-empty. Other problems (such as a value that doesn't
+// match the variable's pattern) are reported by mux when the URL is
+// generated.
+func (ri *RouteInfo) CheckVars(vars map[string]string) error {
+	var missing, empty []string
+	for _, v := range ri.Vars {
+		if v.Optional {
+			continue
+		}
+		val, present := vars[v.Name]
+		if !present {
+			missing = append(missing, v.Name)
+		} else if val == "" && !matchesEmpty(v.Pattern) {
+			empty = append(empty, v.Name)
+		}
+	}
+	if missing != nil || empty != nil {
+		return &RouteVarsError{Route: ri.Name, Missing: missing, Empty: empty}
+	}
+	return nil
+}
+
+// A RouteVarsError is returned by (*RouteInfo).CheckVars when the
+// route variables needed to generate a route's URL are incomplete.
+type RouteVarsError struct {
+	Route   string   // route name
+	Missing []string // required route variables that were not given
+	Empty   []string // required route variables that were given empty values
+}
+
+func (e *RouteVarsError) Error() string {
+	var probs []string
+	if len(e.Missing) > 0 {
+		probs = append(probs, "missing route variables "+strings.Join(e.Missing, ", "))
+	}
+	if len(e.Empty) > 0 {
+		probs = append(probs, "empty values for route variables "+strings.Join(e.Empty, ", "))
+	}
+	return fmt.Sprintf("can't generate URL for route %q: %s", e.Route, strings.Join(probs, "; "))
+}
+
+// Catalog returns information about all of r's named routes, sorted by
+// name.
+func Catalog(r *mux.Router) ([]*RouteInfo, error) {
+	var routes []*RouteInfo
+	err := r.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		if route.GetName() == "" {
+			return nil
+		}
+		ri, err := describe(route)
+		if err != nil {
+			return err
+		}
+		routes = append(routes, ri)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Sort(routeInfosByName(routes))
+	return routes, nil
+}
+
+type routeInfosByName []*RouteInfo
+
+func (v routeInfosByName) Len() int           { return len(v) }
+func (v routeInfosByName) Less(i, j int) bool { return v[i].Name < v[j].Name }
+func (v routeInfosByName) Swap(i, j int)      { v[i], v[j] = v[j], v[i] }
+
+// describeCache caches the result of Describe for each route, because
+// routes don't change after they are set up and URL generation calls
+// Describe on every request. It is cleared by ClearDescribeCache when
+// a router is discarded.
+var describeCache struct {
+	sync.Mutex
+	m map[*mux.Route]*RouteInfo
+}
+
+// Describe returns information about a route. The returned RouteInfo
+// is shared and must not be modified.
+func Describe(route *mux.Route) (*RouteInfo, error) {
+	describeCache.Lock()
+	ri, ok := describeCache.m[route]
+	describeCache.Unlock()
+	if ok {
+		return ri, nil
+	}
+
+	ri, err := describe(route)
+	if err != nil {
+		return nil, err
+	}
+
+	describeCache.Lock()
+	if describeCache.m == nil {
+		describeCache.m = map[*mux.Route]*RouteInfo{}
+	}
+	describeCache.m[route] = ri
+	describeCache.Unlock()
+	return ri, nil
+}
+
+// ClearDescribeCache clears the cached results of Describe. It should
+// be called when routers whose routes were described are discarded
+// (such as when the API client's router is reconstructed), so that
+// their routes can be garbage collected.
+func ClearDescribeCache() {
+	describeCache.Lock()
+	describeCache.m = nil
+	describeCache.Unlock()
+}
+
+// describe returns information about a route, without caching it.
+func describe(route *mux.Route) (*RouteInfo, error) {
+	tpl, err := route.GetPathTemplate()
+	if err != nil {
+		return nil, fmt.Errorf("route %q: %s", route.GetName(), err)
+	}
+	methods, _ := route.GetMethods() // errors if the route matches all methods
+	return &RouteInfo{
+		Name:         route.GetName(),
+		Methods:      methods,
+		PathTemplate: tpl,
+		Vars:         templateVars(tpl),
+	}, nil
+}
+
+// templateVars returns the route variables in a path template. The
+// dummy variables that BuildVarsFuncs in this package derive from
+// other variables (such as rawUnit, which is derived from Unit) are
+// reported under the name of the variable that callers provide.
+func templateVars(tpl string) []RouteVar {
+	var vars []RouteVar
+	for _, def := range varDefs(tpl) {
+		v := RouteVar{Name: def, Optional: optionalVarDefs[def]}
+		if i := strings.Index(def, ":"); i != -1 {
+			v.Name, v.Pattern = def[:i], def[i+1:]
+		}
+		if name, ok := derivedVars[v.Name]; ok {
+			v.Name = name
+		}
+		vars = append(vars, v)
+	}
+	return vars
+}
+
+// varDefs returns the contents of the top-level "{...}" variable
+// definitions in a path template.
+func varDefs(tpl string) []string {
+	var defs []string
+	level, start := 0, 0
+	for i := 0; i < len(tpl); i++ {
+		switch tpl[i] {
+		case '{':
+			if level == 0 {
+				start = i + 1
+			}
+			level++
+		case '}':
+			level--
+			if level == 0 {
+				defs = append(defs, tpl[start:i])
+			}
+		}
+	}
+	return defs
+}
+
+// derivedVars maps dummy route variables that appear in path
+// templates to the route variables they are derived from by this
+// package's BuildVarsFuncs.
+var derivedVars = map[string]string{
+	"rawUnit": "Unit", // see PrepareDefRouteVars
+}
+
+// optionalVarDefs is the set of route variable definitions whose
+// values are filled in by this package's BuildVarsFuncs when they are
+// omitted.
+var optionalVarDefs = map[string]bool{}
+
+func init() {
+	for pat, name := range map[string]string{
+		RepoRevSpecPattern:   "Rev",  // see PrepareRepoRevSpecRouteVars
+		TreeEntryPathPattern: "Path", // see PrepareTreeEntryRouteVars
+		DefPathPattern:       "Path", // see PrepareDefRouteVars
+	} {
+		for _, def := range varDefs(pat) {
+			if strings.HasPrefix(def, name+":") {
+				optionalVarDefs[def] = true
+			}
+		}
+	}
+}
+
+// matchesEmpty reports whether a route variable pattern (or the default
+// pattern, if pat is "") matches the empty string.
+func matchesEmpty(pat string) bool {
+	if pat == "" {
+		return false
+	}
+	re, err := regexp.Compile("^(?:" + pat + ")$")
+	return err == nil && re.MatchString("")
+}
@@ -0,0 +1,162 @@
+package router
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCatalog(t *testing.T) {
+	routes, err := Catalog(NewAPIRouter(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	byName := map[string]*RouteInfo{}
+	for i, ri := range routes {
+		if i > 0 && routes[i-1].Name >= ri.Name {
+			t.Errorf("routes not sorted by name: %q before %q", routes[i-1].Name, ri.Name)
+		}
+		byName[ri.Name] = ri
+	}
+
+	tests := []struct {
+		route        string
+		wantMethods  []string
+		wantVars     []string
+		wantRequired []string
+	}{
+		{
+			route:       Repos,
+			wantMethods: []string{"GET"},
+		},
+		{
+			route:        Repo,
+			wantMethods:  []string{"GET"},
+			wantVars:     []string{"RepoSpec"},
+			wantRequired: []string{"RepoSpec"},
+		},
+		{
+			route:        RepoTreeEntry,
+			wantMethods:  []string{"GET"},
+			wantVars:     []string{"RepoSpec", "Rev", "Path"},
+			wantRequired: []string{"RepoSpec"},
+		},
+		{
+			route:        RepoCommit,
+			wantMethods:  []string{"GET"},
+			wantVars:     []string{"RepoSpec", "Rev"},
+			wantRequired: []string{"RepoSpec", "Rev"},
+		},
+		{
+			route:        Def,
+			wantMethods:  []string{"GET"},
+			wantVars:     []string{"RepoSpec", "Rev", "UnitType", "Unit", "Path"},
+			wantRequired: []string{"RepoSpec", "UnitType", "Unit"},
+		},
+		{
+			route:        RepoIssueCommentsEdit,
+			wantMethods:  []string{"PATCH", "PUT"},
+			wantVars:     []string{"RepoSpec", "Issue", "CommentID"},
+			wantRequired: []string{"RepoSpec", "Issue", "CommentID"},
+		},
+		{
+			route:        BuildTaskLog,
+			wantMethods:  []string{"GET"},
+			wantVars:     []string{"BID", "TaskID"},
+			wantRequired: []string{"BID", "TaskID"},
+		},
+	}
+	for _, test := range tests {
+		ri := byName[test.route]
+		if ri == nil {
+			t.Errorf("%s: not in catalog", test.route)
+			continue
+		}
+		if !reflect.DeepEqual(ri.Methods, test.wantMethods) {
+			t.Errorf("%s: got methods %v, want %v", test.route, ri.Methods, test.wantMethods)
+		}
+		var vars []string
+		for _, v := range ri.Vars {
+			vars = append(vars, v.Name)
+		}
+		if !reflect.DeepEqual(vars, test.wantVars) {
+			t.Errorf("%s: got vars %v, want %v", test.route, vars, test.wantVars)
+		}
+		if required := ri.RequiredVars(); !reflect.DeepEqual(required, test.wantRequired) {
+			t.Errorf("%s: got required vars %v, want %v", test.route, required, test.wantRequired)
+		}
+	}
+}
+
+func TestRouteInfo_CheckVars(t *testing.T) {
+	r := NewAPIRouter(nil)
+	tests := []struct {
+		route   string
+		vars    map[string]string
+		wantErr *RouteVarsError
+	}{
+		{
+			route: RepoTreeEntry,
+			vars:  map[string]string{"RepoSpec": "a.com/b"},
+		},
+		{
+			route: RepoTreeEntry,
+			vars:  map[string]string{"RepoSpec": "a.com/b", "Rev": "v1", "Path": "c"},
+		},
+		{
+			route:   RepoTreeEntry,
+			vars:    map[string]string{"Rev": "v1"},
+			wantErr: &RouteVarsError{Route: RepoTreeEntry, Missing: []string{"RepoSpec"}},
+		},
+		{
+			route:   Def,
+			vars:    map[string]string{"RepoSpec": "", "UnitType": "t"},
+			wantErr: &RouteVarsError{Route: Def, Missing: []string{"Unit"}, Empty: []string{"RepoSpec"}},
+		},
+		{
+			route: Unit,
+			vars:  map[string]string{"RepoSpec": "a.com/b", "UnitType": "t", "Unit": ""},
+		},
+	}
+	for _, test := range tests {
+		ri, err := Describe(r.Get(test.route))
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = ri.CheckVars(test.vars)
+		if test.wantErr == nil {
+			if err != nil {
+				t.Errorf("%s %v: got error %q, want nil", test.route, test.vars, err)
+			}
+			continue
+		}
+		if !reflect.DeepEqual(err, test.wantErr) {
+			t.Errorf("%s %v: got error %v, want %v", test.route, test.vars, err, test.wantErr)
+		}
+	}
+}
+
+func TestClearDescribeCache(t *testing.T) {
+	ClearDescribeCache()
+	r := NewAPIRouter(nil)
+	if _, err := Catalog(r); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Describe(r.Get(Repo)); err != nil {
+		t.Fatal(err)
+	}
+
+	describeCache.Lock()
+	n := len(describeCache.m)
+	describeCache.Unlock()
+	if n != 1 {
+		t.Errorf("got %d cached routes after Catalog and Describe, want 1", n)
+	}
+
+	ClearDescribeCache()
+	describeCache.Lock()
+	n = len(describeCache.m)
+	describeCache.Unlock()
+	if n != 0 {
+		t.Errorf("got %d cached routes after ClearDescribeCache, want 0", n)
+	}
+}
@@ -0,0 +1,100 @@
+package server
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/sourcegraph/mux"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/sourcegraph"
+)
+
+func (h *handler) buildArtifactsRoutes() map[string]http.Handler {
+	return map[string]http.Handler{
+		router.BuildArtifacts:          handlerFunc(h.serveBuildArtifacts),
+		router.BuildArtifact:           http.HandlerFunc(h.serveBuildArtifact),
+		router.BuildArtifactUpload:     handlerFunc(h.serveBuildArtifactUpload),
+		router.BuildArtifactDelete:     handlerFunc(h.serveBuildArtifactDelete),
+		router.BuildTaskArtifacts:      handlerFunc(h.serveBuildArtifacts),
+		router.BuildTaskArtifact:       http.HandlerFunc(h.serveBuildArtifact),
+		router.BuildTaskArtifactUpload: handlerFunc(h.serveBuildArtifactUpload),
+		router.BuildTaskArtifactDelete: handlerFunc(h.serveBuildArtifactDelete),
+	}
+}
+
+// artifactOwner returns the build or task (if the route has a TaskID
+// variable) whose artifacts the request refers to.
+func artifactOwner(r *http.Request) (sourcegraph.TaskSpec, error) {
+	if _, ok := mux.Vars(r)["TaskID"]; ok {
+		return taskSpec(r)
+	}
+	build, err := buildSpec(r)
+	return sourcegraph.TaskSpec{BuildSpec: build}, err
+}
+
+func buildArtifactSpec(r *http.Request) (sourcegraph.BuildArtifactSpec, error) {
+	owner, err := artifactOwner(r)
+	return sourcegraph.BuildArtifactSpec{TaskSpec: owner, Path: mux.Vars(r)["Path"]}, err
+}
+
+func (h *handler) serveBuildArtifacts(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	owner, err := artifactOwner(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	var opt sourcegraph.BuildArtifactListOptions
+	if err := decodeOptions(r, &opt); err != nil {
+		return nil, nil, err
+	}
+	return h.BuildArtifacts.List(r.Context(), owner, &opt)
+}
+
+// serveBuildArtifact serves the raw contents of an artifact, with its
+// other fields in the response headers (see
+// sourcegraph.SetBuildArtifactHeader).
+func (h *handler) serveBuildArtifact(w http.ResponseWriter, r *http.Request) {
+	spec, err := buildArtifactSpec(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	rc, artifact, _, err := h.BuildArtifacts.Download(r.Context(), spec)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	defer rc.Close()
+
+	sourcegraph.SetBuildArtifactHeader(w.Header(), artifact)
+	// Artifacts may be large, so keep clients from caching them in
+	// memory (see sourcegraph.Client.Cache).
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	if r.Method != "HEAD" {
+		io.Copy(w, rc)
+	}
+}
+
+// serveBuildArtifactUpload streams the request body to the service,
+// which enforces its size limit.
+func (h *handler) serveBuildArtifactUpload(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	spec, err := buildArtifactSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	var opt sourcegraph.BuildArtifactUploadOptions
+	if err := decodeOptions(r, &opt); err != nil {
+		return nil, nil, err
+	}
+	return h.BuildArtifacts.Upload(r.Context(), spec, r.Body, &opt)
+}
+
+func (h *handler) serveBuildArtifactDelete(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	spec, err := buildArtifactSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	resp, err := h.BuildArtifacts.Delete(r.Context(), spec)
+	return nil, resp, err
+}
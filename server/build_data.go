@@ -0,0 +1,42 @@
+package server
+
+import (
+	"net/http"
+	"path"
+
+	"github.com/sourcegraph/mux"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
+	"sourcegraph.com/sourcegraph/rwvfs"
+)
+
+func (h *handler) buildDataRoutes() map[string]http.Handler {
+	return map[string]http.Handler{
+		router.RepoBuildDataEntry: http.HandlerFunc(h.serveRepoBuildDataEntry),
+	}
+}
+
+// serveRepoBuildDataEntry serves the build data file system of the
+// repository revision using the rwvfs HTTP protocol, which the
+// client's BuildDataService.FileSystem speaks.
+func (h *handler) serveRepoBuildDataEntry(w http.ResponseWriter, r *http.Request) {
+	repoRev, err := repoRevSpec(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	fs, err := h.BuildData.FileSystem(r.Context(), repoRev)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	// The rwvfs handler serves the file at the request's URL path,
+	// so make it relative to the build data root.
+	r2 := new(http.Request)
+	*r2 = *r
+	u := *r.URL
+	u.Path = path.Join("/", mux.Vars(r)["Path"])
+	r2.URL = &u
+	rwvfs.HTTPHandler(fs, nil).ServeHTTP(w, r2)
+}
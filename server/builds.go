@@ -0,0 +1,314 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/sourcegraph"
+)
+
+func (h *handler) buildsRoutes() map[string]http.Handler {
+	return map[string]http.Handler{
+		router.Build:            handlerFunc(h.serveBuild),
+		router.Builds:           handlerFunc(h.serveBuilds),
+		router.RepoBuildsCreate: handlerFunc(h.serveRepoBuildsCreate),
+		router.BuildUpdate:      handlerFunc(h.serveBuildUpdate),
+		router.BuildCancel:      handlerFunc(h.serveBuildCancel),
+		router.BuildRetry:       handlerFunc(h.serveBuildRetry),
+		router.BuildTasks:       handlerFunc(h.serveBuildTasks),
+		router.BuildTasksCreate: handlerFunc(h.serveBuildTasksCreate),
+		router.BuildTaskUpdate:  handlerFunc(h.serveBuildTaskUpdate),
+		router.BuildLog:         handlerFunc(h.serveBuildLog),
+		router.BuildTaskLog:     handlerFunc(h.serveBuildTaskLog),
+		router.BuildDequeueNext: handlerFunc(h.serveBuildDequeueNext),
+		router.BuildEvents:      http.HandlerFunc(h.serveBuildEvents),
+
+		router.BuildQueueStatus:       handlerFunc(h.serveBuildQueueStatus),
+		router.BuildsUpdatePriorities: handlerFunc(h.serveBuildsUpdatePriorities),
+
+		router.RepoBuildHooks:       handlerFunc(h.serveRepoBuildHooks),
+		router.RepoBuildHooksCreate: handlerFunc(h.serveRepoBuildHooksCreate),
+		router.RepoBuildHookDelete:  handlerFunc(h.serveRepoBuildHookDelete),
+	}
+}
+
+func buildSpec(r *http.Request) (sourcegraph.BuildSpec, error) {
+	bid, err := routeVarInt64(r, "BID")
+	return sourcegraph.BuildSpec{BID: bid}, err
+}
+
+func taskSpec(r *http.Request) (sourcegraph.TaskSpec, error) {
+	build, err := buildSpec(r)
+	if err != nil {
+		return sourcegraph.TaskSpec{}, err
+	}
+	taskID, err := routeVarInt64(r, "TaskID")
+	return sourcegraph.TaskSpec{BuildSpec: build, TaskID: taskID}, err
+}
+
+func (h *handler) serveBuild(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	build, err := buildSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	var opt sourcegraph.BuildGetOptions
+	if err := decodeOptions(r, &opt); err != nil {
+		return nil, nil, err
+	}
+	return h.Builds.Get(r.Context(), build, &opt)
+}
+
+func (h *handler) serveBuilds(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	var opt sourcegraph.BuildListOptions
+	if err := decodeOptions(r, &opt); err != nil {
+		return nil, nil, err
+	}
+	return h.Builds.List(r.Context(), &opt)
+}
+
+func (h *handler) serveRepoBuildsCreate(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	repoRev, err := repoRevSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	var opt sourcegraph.BuildCreateOptions
+	if err := decodeBody(r, &opt); err != nil {
+		return nil, nil, err
+	}
+	return h.Builds.Create(r.Context(), repoRev, &opt)
+}
+
+func (h *handler) serveBuildUpdate(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	build, err := buildSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	var info sourcegraph.BuildUpdate
+	if err := decodeBody(r, &info); err != nil {
+		return nil, nil, err
+	}
+	return h.Builds.Update(r.Context(), build, info)
+}
+
+func (h *handler) serveBuildCancel(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	build, err := buildSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	return h.Builds.Cancel(r.Context(), build)
+}
+
+func (h *handler) serveBuildRetry(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	build, err := buildSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	return h.Builds.Retry(r.Context(), build)
+}
+
+func (h *handler) serveBuildTasks(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	build, err := buildSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	var opt sourcegraph.BuildTaskListOptions
+	if err := decodeOptions(r, &opt); err != nil {
+		return nil, nil, err
+	}
+	return h.Builds.ListBuildTasks(r.Context(), build, &opt)
+}
+
+func (h *handler) serveBuildTasksCreate(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	build, err := buildSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	var tasks []*sourcegraph.BuildTask
+	if err := decodeBody(r, &tasks); err != nil {
+		return nil, nil, err
+	}
+	return h.Builds.CreateTasks(r.Context(), build, tasks)
+}
+
+func (h *handler) serveBuildTaskUpdate(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	task, err := taskSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	var info sourcegraph.TaskUpdate
+	if err := decodeBody(r, &info); err != nil {
+		return nil, nil, err
+	}
+	return h.Builds.UpdateTask(r.Context(), task, info)
+}
+
+func (h *handler) serveBuildLog(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	build, err := buildSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	var opt sourcegraph.BuildGetLogOptions
+	if err := decodeOptions(r, &opt); err != nil {
+		return nil, nil, err
+	}
+	return h.Builds.GetLog(r.Context(), build, &opt)
+}
+
+func (h *handler) serveBuildTaskLog(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	task, err := taskSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	var opt sourcegraph.BuildGetLogOptions
+	if err := decodeOptions(r, &opt); err != nil {
+		return nil, nil, err
+	}
+	return h.Builds.GetTaskLog(r.Context(), task, &opt)
+}
+
+// serveBuildDequeueNext responds with HTTP status 404 if there are no
+// queued builds, which the client's DequeueNext method treats as a
+// nil build.
+func (h *handler) serveBuildDequeueNext(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	build, resp, err := h.Builds.DequeueNext(r.Context())
+	if err == nil && build == nil {
+		err = &statusError{http.StatusNotFound, errors.New("no queued builds")}
+	}
+	return build, resp, err
+}
+
+func (h *handler) serveBuildQueueStatus(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	build, err := buildSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	return h.Builds.GetQueueStatus(r.Context(), build)
+}
+
+func (h *handler) serveBuildsUpdatePriorities(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	var update sourcegraph.BuildPriorityUpdate
+	if err := decodeBody(r, &update); err != nil {
+		return nil, nil, err
+	}
+	return h.Builds.UpdatePriorities(r.Context(), update)
+}
+
+// eventStreamWait is how long each poll for build events waits when
+// streaming them, and the longest that a long-polling request for
+// build events may wait. A comment is sent after each streaming poll
+// that returns no events, to keep the connection alive.
+var eventStreamWait = 15 * time.Second
+
+// serveBuildEvents responds with the build events as JSON (long
+// polling, like the other routes), or, if the request accepts
+// "text/event-stream", streams them as Server-Sent Events until the
+// client disconnects. Each streamed event's SSE id is its ID, so that
+// clients that reconnect with a Last-Event-ID header resume after it.
+func (h *handler) serveBuildEvents(w http.ResponseWriter, r *http.Request) {
+	if !strings.Contains(r.Header.Get("Accept"), "text/event-stream") {
+		handlerFunc(h.serveBuildEventsJSON).ServeHTTP(w, r)
+		return
+	}
+
+	var opt sourcegraph.BuildEventListOptions
+	if err := decodeOptions(r, &opt); err != nil {
+		writeError(w, err)
+		return
+	}
+	if id := r.Header.Get("Last-Event-ID"); id != "" {
+		after, err := strconv.ParseInt(id, 10, 64)
+		if err != nil {
+			writeError(w, badRequest(err))
+			return
+		}
+		opt.After = after
+	}
+	opt.Wait = eventStreamWait
+
+	flush := func() {
+		if f, ok := w.(http.Flusher); ok {
+			f.Flush()
+		}
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flush()
+
+	ctx := r.Context()
+	for {
+		events, _, err := h.Builds.ListEvents(ctx, &opt)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			data, _ := json.Marshal(sourcegraph.NewErrorResponse(err))
+			fmt.Fprintf(w, "event: error\ndata: %s\n\n", data)
+			flush()
+			return
+		}
+		if len(events) == 0 {
+			io.WriteString(w, ": keepalive\n\n")
+		}
+		for _, e := range events {
+			data, err := json.Marshal(e)
+			if err != nil {
+				return
+			}
+			fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", e.ID, e.Type, data)
+			opt.After = e.ID
+		}
+		flush()
+	}
+}
+
+func (h *handler) serveBuildEventsJSON(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	var opt sourcegraph.BuildEventListOptions
+	if err := decodeOptions(r, &opt); err != nil {
+		return nil, nil, err
+	}
+	if opt.Wait > eventStreamWait {
+		opt.Wait = eventStreamWait
+	}
+	return h.Builds.ListEvents(r.Context(), &opt)
+}
+
+func (h *handler) serveRepoBuildHooks(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	repo, err := repoSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	return h.Builds.ListRepoHooks(r.Context(), repo)
+}
+
+func (h *handler) serveRepoBuildHooksCreate(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	repo, err := repoSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	var hook sourcegraph.BuildHook
+	if err := decodeBody(r, &hook); err != nil {
+		return nil, nil, err
+	}
+	return h.Builds.CreateRepoHook(r.Context(), repo, &hook)
+}
+
+func (h *handler) serveRepoBuildHookDelete(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	repo, err := repoSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	id, err := routeVarInt64(r, "HookID")
+	if err != nil {
+		return nil, nil, err
+	}
+	resp, err := h.Builds.DeleteRepoHook(r.Context(), repo, id)
+	return nil, resp, err
+}
@@ -0,0 +1,138 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/sourcegraph/mux"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/sourcegraph"
+)
+
+func (h *handler) defsRoutes() map[string]http.Handler {
+	return map[string]http.Handler{
+		router.Def:           handlerFunc(h.serveDef),
+		router.Defs:          handlerFunc(h.serveDefs),
+		router.DefRefs:       handlerFunc(h.serveDefRefs),
+		router.DefExamples:   handlerFunc(h.serveDefExamples),
+		router.DefAuthors:    handlerFunc(h.serveDefAuthors),
+		router.DefClients:    handlerFunc(h.serveDefClients),
+		router.DefDependents: handlerFunc(h.serveDefDependents),
+		router.DefVersions:   handlerFunc(h.serveDefVersions),
+	}
+}
+
+// defSpec returns the DefSpec specified by the route variables of a
+// def route. If the Rev route variable includes a resolved commit ID,
+// it is used as the DefSpec's CommitID; otherwise the unresolved
+// revspec is used.
+func defSpec(r *http.Request) (sourcegraph.DefSpec, error) {
+	repoRev, err := repoRevSpec(r)
+	if err != nil {
+		return sourcegraph.DefSpec{}, err
+	}
+	commitID := repoRev.CommitID
+	if commitID == "" {
+		commitID = repoRev.Rev
+	}
+	v := mux.Vars(r)
+	return sourcegraph.DefSpec{
+		Repo:     repoRev.URI,
+		CommitID: commitID,
+		UnitType: v["UnitType"],
+		Unit:     v["Unit"],
+		Path:     v["Path"],
+	}, nil
+}
+
+func (h *handler) serveDef(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	def, err := defSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	var opt sourcegraph.DefGetOptions
+	if err := decodeOptions(r, &opt); err != nil {
+		return nil, nil, err
+	}
+	return h.Defs.Get(r.Context(), def, &opt)
+}
+
+func (h *handler) serveDefs(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	var opt sourcegraph.DefListOptions
+	if err := decodeOptions(r, &opt); err != nil {
+		return nil, nil, err
+	}
+	return h.Defs.List(r.Context(), &opt)
+}
+
+func (h *handler) serveDefRefs(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	def, err := defSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	var opt sourcegraph.DefListRefsOptions
+	if err := decodeOptions(r, &opt); err != nil {
+		return nil, nil, err
+	}
+	return h.Defs.ListRefs(r.Context(), def, &opt)
+}
+
+func (h *handler) serveDefExamples(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	def, err := defSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	var opt sourcegraph.DefListExamplesOptions
+	if err := decodeOptions(r, &opt); err != nil {
+		return nil, nil, err
+	}
+	return h.Defs.ListExamples(r.Context(), def, &opt)
+}
+
+func (h *handler) serveDefAuthors(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	def, err := defSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	var opt sourcegraph.DefListAuthorsOptions
+	if err := decodeOptions(r, &opt); err != nil {
+		return nil, nil, err
+	}
+	return h.Defs.ListAuthors(r.Context(), def, &opt)
+}
+
+func (h *handler) serveDefClients(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	def, err := defSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	var opt sourcegraph.DefListClientsOptions
+	if err := decodeOptions(r, &opt); err != nil {
+		return nil, nil, err
+	}
+	return h.Defs.ListClients(r.Context(), def, &opt)
+}
+
+func (h *handler) serveDefDependents(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	def, err := defSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	var opt sourcegraph.DefListDependentsOptions
+	if err := decodeOptions(r, &opt); err != nil {
+		return nil, nil, err
+	}
+	return h.Defs.ListDependents(r.Context(), def, &opt)
+}
+
+func (h *handler) serveDefVersions(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	def, err := defSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	var opt sourcegraph.DefListVersionsOptions
+	if err := decodeOptions(r, &opt); err != nil {
+		return nil, nil, err
+	}
+	return h.Defs.ListVersions(r.Context(), def, &opt)
+}
@@ -0,0 +1,153 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/sourcegraph/mux"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/sourcegraph"
+)
+
+func (h *handler) deltasRoutes() map[string]http.Handler {
+	return map[string]http.Handler{
+		router.Delta:                   handlerFunc(h.serveDelta),
+		router.DeltaUnits:              handlerFunc(h.serveDeltaUnits),
+		router.DeltaDefs:               handlerFunc(h.serveDeltaDefs),
+		router.DeltaDependencies:       handlerFunc(h.serveDeltaDependencies),
+		router.DeltaFiles:              handlerFunc(h.serveDeltaFiles),
+		router.DeltaAffectedAuthors:    handlerFunc(h.serveDeltaAffectedAuthors),
+		router.DeltaAffectedClients:    handlerFunc(h.serveDeltaAffectedClients),
+		router.DeltaAffectedDependents: handlerFunc(h.serveDeltaAffectedDependents),
+		router.DeltaReviewers:          handlerFunc(h.serveDeltaReviewers),
+		router.DeltasIncoming:          handlerFunc(h.serveDeltasIncoming),
+	}
+}
+
+func deltaSpec(r *http.Request) (sourcegraph.DeltaSpec, error) {
+	ds, err := sourcegraph.UnmarshalDeltaSpec(mux.Vars(r))
+	if err != nil {
+		return sourcegraph.DeltaSpec{}, badRequest(err)
+	}
+	return ds, nil
+}
+
+func (h *handler) serveDelta(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	ds, err := deltaSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	var opt sourcegraph.DeltaGetOptions
+	if err := decodeOptions(r, &opt); err != nil {
+		return nil, nil, err
+	}
+	return h.Deltas.Get(r.Context(), ds, &opt)
+}
+
+func (h *handler) serveDeltaUnits(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	ds, err := deltaSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	var opt sourcegraph.DeltaListUnitsOptions
+	if err := decodeOptions(r, &opt); err != nil {
+		return nil, nil, err
+	}
+	return h.Deltas.ListUnits(r.Context(), ds, &opt)
+}
+
+func (h *handler) serveDeltaDefs(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	ds, err := deltaSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	var opt sourcegraph.DeltaListDefsOptions
+	if err := decodeOptions(r, &opt); err != nil {
+		return nil, nil, err
+	}
+	return h.Deltas.ListDefs(r.Context(), ds, &opt)
+}
+
+func (h *handler) serveDeltaDependencies(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	ds, err := deltaSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	var opt sourcegraph.DeltaListDependenciesOptions
+	if err := decodeOptions(r, &opt); err != nil {
+		return nil, nil, err
+	}
+	return h.Deltas.ListDependencies(r.Context(), ds, &opt)
+}
+
+func (h *handler) serveDeltaFiles(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	ds, err := deltaSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	var opt sourcegraph.DeltaListFilesOptions
+	if err := decodeOptions(r, &opt); err != nil {
+		return nil, nil, err
+	}
+	return h.Deltas.ListFiles(r.Context(), ds, &opt)
+}
+
+func (h *handler) serveDeltaAffectedAuthors(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	ds, err := deltaSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	var opt sourcegraph.DeltaListAffectedAuthorsOptions
+	if err := decodeOptions(r, &opt); err != nil {
+		return nil, nil, err
+	}
+	return h.Deltas.ListAffectedAuthors(r.Context(), ds, &opt)
+}
+
+func (h *handler) serveDeltaAffectedClients(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	ds, err := deltaSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	var opt sourcegraph.DeltaListAffectedClientsOptions
+	if err := decodeOptions(r, &opt); err != nil {
+		return nil, nil, err
+	}
+	return h.Deltas.ListAffectedClients(r.Context(), ds, &opt)
+}
+
+func (h *handler) serveDeltaAffectedDependents(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	ds, err := deltaSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	var opt sourcegraph.DeltaListAffectedDependentsOptions
+	if err := decodeOptions(r, &opt); err != nil {
+		return nil, nil, err
+	}
+	return h.Deltas.ListAffectedDependents(r.Context(), ds, &opt)
+}
+
+func (h *handler) serveDeltaReviewers(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	ds, err := deltaSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	var opt sourcegraph.DeltaListReviewersOptions
+	if err := decodeOptions(r, &opt); err != nil {
+		return nil, nil, err
+	}
+	return h.Deltas.ListReviewers(r.Context(), ds, &opt)
+}
+
+func (h *handler) serveDeltasIncoming(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	repoRev, err := repoRevSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	var opt sourcegraph.DeltaListIncomingOptions
+	if err := decodeOptions(r, &opt); err != nil {
+		return nil, nil, err
+	}
+	return h.Deltas.ListIncoming(r.Context(), repoRev, &opt)
+}
@@ -0,0 +1,119 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/sourcegraph/go-github/github"
+	"github.com/sourcegraph/mux"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/sourcegraph"
+)
+
+func (h *handler) issuesRoutes() map[string]http.Handler {
+	return map[string]http.Handler{
+		router.RepoIssue:               handlerFunc(h.serveRepoIssue),
+		router.RepoIssues:              handlerFunc(h.serveRepoIssues),
+		router.RepoIssueComments:       handlerFunc(h.serveRepoIssueComments),
+		router.RepoIssueCommentsCreate: handlerFunc(h.serveRepoIssueCommentsCreate),
+		router.RepoIssueCommentsEdit:   handlerFunc(h.serveRepoIssueCommentsEdit),
+		router.RepoIssueCommentsDelete: handlerFunc(h.serveRepoIssueCommentsDelete),
+	}
+}
+
+func issueSpec(r *http.Request) (sourcegraph.IssueSpec, error) {
+	issue, err := sourcegraph.UnmarshalIssueSpec(mux.Vars(r))
+	if err != nil {
+		return sourcegraph.IssueSpec{}, badRequest(err)
+	}
+	return issue, nil
+}
+
+// commentID returns the CommentID route variable of an issue or pull
+// request comment route.
+func commentID(r *http.Request) (int, error) {
+	id, err := strconv.Atoi(mux.Vars(r)["CommentID"])
+	if err != nil {
+		return 0, badRequest(err)
+	}
+	return id, nil
+}
+
+func (h *handler) serveRepoIssue(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	issue, err := issueSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	var opt sourcegraph.IssueGetOptions
+	if err := decodeOptions(r, &opt); err != nil {
+		return nil, nil, err
+	}
+	return h.Issues.Get(r.Context(), issue, &opt)
+}
+
+func (h *handler) serveRepoIssues(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	repo, err := repoSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	var opt sourcegraph.IssueListOptions
+	if err := decodeOptions(r, &opt); err != nil {
+		return nil, nil, err
+	}
+	return h.Issues.ListByRepo(r.Context(), repo, &opt)
+}
+
+func (h *handler) serveRepoIssueComments(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	issue, err := issueSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	var opt sourcegraph.IssueListCommentsOptions
+	if err := decodeOptions(r, &opt); err != nil {
+		return nil, nil, err
+	}
+	return h.Issues.ListComments(r.Context(), issue, &opt)
+}
+
+func (h *handler) serveRepoIssueCommentsCreate(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	issue, err := issueSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	var comment sourcegraph.IssueComment
+	if err := decodeBody(r, &comment); err != nil {
+		return nil, nil, err
+	}
+	return h.Issues.CreateComment(r.Context(), issue, &comment)
+}
+
+func (h *handler) serveRepoIssueCommentsEdit(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	issue, err := issueSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	id, err := commentID(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	var comment sourcegraph.IssueComment
+	if err := decodeBody(r, &comment); err != nil {
+		return nil, nil, err
+	}
+	comment.ID = github.Int(id)
+	return h.Issues.EditComment(r.Context(), issue, &comment)
+}
+
+func (h *handler) serveRepoIssueCommentsDelete(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	issue, err := issueSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	id, err := commentID(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	resp, err := h.Issues.DeleteComment(r.Context(), issue, id)
+	return nil, resp, err
+}
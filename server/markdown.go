@@ -0,0 +1,22 @@
+package server
+
+import (
+	"net/http"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/sourcegraph"
+)
+
+func (h *handler) markdownRoutes() map[string]http.Handler {
+	return map[string]http.Handler{
+		router.Markdown: handlerFunc(h.serveMarkdown),
+	}
+}
+
+func (h *handler) serveMarkdown(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	var body sourcegraph.MarkdownRequestBody
+	if err := decodeBody(r, &body); err != nil {
+		return nil, nil, err
+	}
+	return h.Markdown.Render(r.Context(), body.Markdown, body.MarkdownOpt)
+}
@@ -0,0 +1,68 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/sourcegraph/mux"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/sourcegraph"
+)
+
+func (h *handler) orgsRoutes() map[string]http.Handler {
+	return map[string]http.Handler{
+		router.Org:               handlerFunc(h.serveOrg),
+		router.OrgMembers:        handlerFunc(h.serveOrgMembers),
+		router.OrgSettings:       handlerFunc(h.serveOrgSettings),
+		router.OrgSettingsUpdate: handlerFunc(h.serveOrgSettingsUpdate),
+	}
+}
+
+func orgSpec(r *http.Request) (sourcegraph.OrgSpec, error) {
+	org, err := sourcegraph.ParseOrgSpec(mux.Vars(r)["OrgSpec"])
+	if err != nil {
+		return sourcegraph.OrgSpec{}, badRequest(err)
+	}
+	return org, nil
+}
+
+func (h *handler) serveOrg(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	org, err := orgSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	return h.Orgs.Get(r.Context(), org)
+}
+
+func (h *handler) serveOrgMembers(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	org, err := orgSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	var opt sourcegraph.OrgListMembersOptions
+	if err := decodeOptions(r, &opt); err != nil {
+		return nil, nil, err
+	}
+	return h.Orgs.ListMembers(r.Context(), org, &opt)
+}
+
+func (h *handler) serveOrgSettings(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	org, err := orgSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	return h.Orgs.GetSettings(r.Context(), org)
+}
+
+func (h *handler) serveOrgSettingsUpdate(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	org, err := orgSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	var settings sourcegraph.OrgSettings
+	if err := decodeBody(r, &settings); err != nil {
+		return nil, nil, err
+	}
+	resp, err := h.Orgs.UpdateSettings(r.Context(), org, settings)
+	return nil, resp, err
+}
@@ -0,0 +1,24 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/sourcegraph/mux"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/sourcegraph"
+)
+
+func (h *handler) peopleRoutes() map[string]http.Handler {
+	return map[string]http.Handler{
+		router.Person: handlerFunc(h.servePerson),
+	}
+}
+
+func (h *handler) servePerson(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	person, err := sourcegraph.ParsePersonSpec(mux.Vars(r)["PersonSpec"])
+	if err != nil {
+		return nil, nil, badRequest(err)
+	}
+	return h.People.Get(r.Context(), person)
+}
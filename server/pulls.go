@@ -0,0 +1,121 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/sourcegraph/go-github/github"
+	"github.com/sourcegraph/mux"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/sourcegraph"
+)
+
+func (h *handler) pullRequestsRoutes() map[string]http.Handler {
+	return map[string]http.Handler{
+		router.RepoPullRequest:               handlerFunc(h.serveRepoPullRequest),
+		router.RepoPullRequests:              handlerFunc(h.serveRepoPullRequests),
+		router.RepoPullRequestComments:       handlerFunc(h.serveRepoPullRequestComments),
+		router.RepoPullRequestCommentsCreate: handlerFunc(h.serveRepoPullRequestCommentsCreate),
+		router.RepoPullRequestCommentsEdit:   handlerFunc(h.serveRepoPullRequestCommentsEdit),
+		router.RepoPullRequestCommentsDelete: handlerFunc(h.serveRepoPullRequestCommentsDelete),
+		router.RepoPullRequestMerge:          handlerFunc(h.serveRepoPullRequestMerge),
+	}
+}
+
+func pullRequestSpec(r *http.Request) (sourcegraph.PullRequestSpec, error) {
+	pull, err := sourcegraph.UnmarshalPullRequestSpec(mux.Vars(r))
+	if err != nil {
+		return sourcegraph.PullRequestSpec{}, badRequest(err)
+	}
+	return pull, nil
+}
+
+func (h *handler) serveRepoPullRequest(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	pull, err := pullRequestSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	var opt sourcegraph.PullRequestGetOptions
+	if err := decodeOptions(r, &opt); err != nil {
+		return nil, nil, err
+	}
+	return h.PullRequests.Get(r.Context(), pull, &opt)
+}
+
+func (h *handler) serveRepoPullRequests(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	repo, err := repoSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	var opt sourcegraph.PullRequestListOptions
+	if err := decodeOptions(r, &opt); err != nil {
+		return nil, nil, err
+	}
+	return h.PullRequests.ListByRepo(r.Context(), repo, &opt)
+}
+
+func (h *handler) serveRepoPullRequestComments(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	pull, err := pullRequestSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	var opt sourcegraph.PullRequestListCommentsOptions
+	if err := decodeOptions(r, &opt); err != nil {
+		return nil, nil, err
+	}
+	return h.PullRequests.ListComments(r.Context(), pull, &opt)
+}
+
+func (h *handler) serveRepoPullRequestCommentsCreate(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	pull, err := pullRequestSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	var comment sourcegraph.PullRequestComment
+	if err := decodeBody(r, &comment); err != nil {
+		return nil, nil, err
+	}
+	return h.PullRequests.CreateComment(r.Context(), pull, &comment)
+}
+
+func (h *handler) serveRepoPullRequestCommentsEdit(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	pull, err := pullRequestSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	id, err := commentID(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	var comment sourcegraph.PullRequestComment
+	if err := decodeBody(r, &comment); err != nil {
+		return nil, nil, err
+	}
+	comment.ID = github.Int(id)
+	return h.PullRequests.EditComment(r.Context(), pull, &comment)
+}
+
+func (h *handler) serveRepoPullRequestCommentsDelete(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	pull, err := pullRequestSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	id, err := commentID(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	resp, err := h.PullRequests.DeleteComment(r.Context(), pull, id)
+	return nil, resp, err
+}
+
+func (h *handler) serveRepoPullRequestMerge(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	pull, err := pullRequestSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	var mergeRequest sourcegraph.PullRequestMergeRequest
+	if err := decodeBody(r, &mergeRequest); err != nil {
+		return nil, nil, err
+	}
+	return h.PullRequests.Merge(r.Context(), pull, &mergeRequest)
+}
@@ -0,0 +1,342 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/sourcegraph/mux"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/sourcegraph"
+)
+
+func (h *handler) reposRoutes() map[string]http.Handler {
+	return map[string]http.Handler{
+		router.Repo:                  handlerFunc(h.serveRepo),
+		router.RepoStats:             handlerFunc(h.serveRepoStats),
+		router.RepoStatusCreate:      handlerFunc(h.serveRepoStatusCreate),
+		router.RepoCombinedStatus:    handlerFunc(h.serveRepoCombinedStatus),
+		router.ReposGetOrCreate:      handlerFunc(h.serveReposGetOrCreate),
+		router.RepoSettings:          handlerFunc(h.serveRepoSettings),
+		router.RepoSettingsUpdate:    handlerFunc(h.serveRepoSettingsUpdate),
+		router.RepoRefreshProfile:    handlerFunc(h.serveRepoRefreshProfile),
+		router.RepoRefreshVCSData:    handlerFunc(h.serveRepoRefreshVCSData),
+		router.RepoComputeStats:      handlerFunc(h.serveRepoComputeStats),
+		router.RepoBuild:             handlerFunc(h.serveRepoBuild),
+		router.ReposCreate:           handlerFunc(h.serveReposCreate),
+		router.RepoReadme:            handlerFunc(h.serveRepoReadme),
+		router.Repos:                 handlerFunc(h.serveRepos),
+		router.RepoCommits:           handlerFunc(h.serveRepoCommits),
+		router.RepoCommit:            handlerFunc(h.serveRepoCommit),
+		router.RepoBranches:          handlerFunc(h.serveRepoBranches),
+		router.RepoTags:              handlerFunc(h.serveRepoTags),
+		router.RepoBadges:            handlerFunc(h.serveRepoBadges),
+		router.RepoCounters:          handlerFunc(h.serveRepoCounters),
+		router.RepoAuthors:           handlerFunc(h.serveRepoAuthors),
+		router.RepoClients:           handlerFunc(h.serveRepoClients),
+		router.RepoDependencies:      handlerFunc(h.serveRepoDependencies),
+		router.RepoDependents:        handlerFunc(h.serveRepoDependents),
+		router.UserRepoContributions: handlerFunc(h.serveUserRepoContributions),
+		router.UserRepoDependencies:  handlerFunc(h.serveUserRepoDependencies),
+		router.UserRepoDependents:    handlerFunc(h.serveUserRepoDependents),
+	}
+}
+
+func repoSpec(r *http.Request) (sourcegraph.RepoSpec, error) {
+	repo, err := sourcegraph.UnmarshalRepoSpec(mux.Vars(r))
+	if err != nil {
+		return sourcegraph.RepoSpec{}, badRequest(err)
+	}
+	return repo, nil
+}
+
+func repoRevSpec(r *http.Request) (sourcegraph.RepoRevSpec, error) {
+	repoRev, err := sourcegraph.UnmarshalRepoRevSpec(mux.Vars(r))
+	if err != nil {
+		return sourcegraph.RepoRevSpec{}, badRequest(err)
+	}
+	return repoRev, nil
+}
+
+func (h *handler) serveRepo(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	repo, err := repoSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	var opt sourcegraph.RepoGetOptions
+	if err := decodeOptions(r, &opt); err != nil {
+		return nil, nil, err
+	}
+	return h.Repos.Get(r.Context(), repo, &opt)
+}
+
+func (h *handler) serveRepoStats(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	repoRev, err := repoRevSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	return h.Repos.GetStats(r.Context(), repoRev)
+}
+
+func (h *handler) serveRepoStatusCreate(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	repoRev, err := repoRevSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	var st sourcegraph.RepoStatus
+	if err := decodeBody(r, &st); err != nil {
+		return nil, nil, err
+	}
+	return h.Repos.CreateStatus(r.Context(), repoRev, st)
+}
+
+func (h *handler) serveRepoCombinedStatus(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	repoRev, err := repoRevSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	return h.Repos.GetCombinedStatus(r.Context(), repoRev)
+}
+
+func (h *handler) serveReposGetOrCreate(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	repo, err := repoSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	var opt sourcegraph.RepoGetOptions
+	if err := decodeOptions(r, &opt); err != nil {
+		return nil, nil, err
+	}
+	return h.Repos.GetOrCreate(r.Context(), repo, &opt)
+}
+
+func (h *handler) serveRepoSettings(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	repo, err := repoSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	return h.Repos.GetSettings(r.Context(), repo)
+}
+
+func (h *handler) serveRepoSettingsUpdate(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	repo, err := repoSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	var settings sourcegraph.RepoSettings
+	if err := decodeBody(r, &settings); err != nil {
+		return nil, nil, err
+	}
+	resp, err := h.Repos.UpdateSettings(r.Context(), repo, settings)
+	return nil, resp, err
+}
+
+func (h *handler) serveRepoRefreshProfile(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	repo, err := repoSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	resp, err := h.Repos.RefreshProfile(r.Context(), repo)
+	return nil, resp, err
+}
+
+func (h *handler) serveRepoRefreshVCSData(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	repo, err := repoSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	resp, err := h.Repos.RefreshVCSData(r.Context(), repo)
+	return nil, resp, err
+}
+
+func (h *handler) serveRepoComputeStats(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	repoRev, err := repoRevSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	resp, err := h.Repos.ComputeStats(r.Context(), repoRev)
+	return nil, resp, err
+}
+
+func (h *handler) serveRepoBuild(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	repoRev, err := repoRevSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	var opt sourcegraph.RepoGetBuildOptions
+	if err := decodeOptions(r, &opt); err != nil {
+		return nil, nil, err
+	}
+	return h.Repos.GetBuild(r.Context(), repoRev, &opt)
+}
+
+func (h *handler) serveReposCreate(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	var newRepoSpec sourcegraph.NewRepoSpec
+	if err := decodeBody(r, &newRepoSpec); err != nil {
+		return nil, nil, err
+	}
+	return h.Repos.Create(r.Context(), newRepoSpec)
+}
+
+func (h *handler) serveRepoReadme(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	repoRev, err := repoRevSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	return h.Repos.GetReadme(r.Context(), repoRev)
+}
+
+func (h *handler) serveRepos(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	var opt sourcegraph.RepoListOptions
+	if err := decodeOptions(r, &opt); err != nil {
+		return nil, nil, err
+	}
+	return h.Repos.List(r.Context(), &opt)
+}
+
+func (h *handler) serveRepoCommits(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	repo, err := repoSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	var opt sourcegraph.RepoListCommitsOptions
+	if err := decodeOptions(r, &opt); err != nil {
+		return nil, nil, err
+	}
+	return h.Repos.ListCommits(r.Context(), repo, &opt)
+}
+
+func (h *handler) serveRepoCommit(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	repoRev, err := repoRevSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	var opt sourcegraph.RepoGetCommitOptions
+	if err := decodeOptions(r, &opt); err != nil {
+		return nil, nil, err
+	}
+	return h.Repos.GetCommit(r.Context(), repoRev, &opt)
+}
+
+func (h *handler) serveRepoBranches(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	repo, err := repoSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	var opt sourcegraph.RepoListBranchesOptions
+	if err := decodeOptions(r, &opt); err != nil {
+		return nil, nil, err
+	}
+	return h.Repos.ListBranches(r.Context(), repo, &opt)
+}
+
+func (h *handler) serveRepoTags(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	repo, err := repoSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	var opt sourcegraph.RepoListTagsOptions
+	if err := decodeOptions(r, &opt); err != nil {
+		return nil, nil, err
+	}
+	return h.Repos.ListTags(r.Context(), repo, &opt)
+}
+
+func (h *handler) serveRepoBadges(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	repo, err := repoSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	return h.Repos.ListBadges(r.Context(), repo)
+}
+
+func (h *handler) serveRepoCounters(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	repo, err := repoSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	return h.Repos.ListCounters(r.Context(), repo)
+}
+
+func (h *handler) serveRepoAuthors(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	repoRev, err := repoRevSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	var opt sourcegraph.RepoListAuthorsOptions
+	if err := decodeOptions(r, &opt); err != nil {
+		return nil, nil, err
+	}
+	return h.Repos.ListAuthors(r.Context(), repoRev, &opt)
+}
+
+func (h *handler) serveRepoClients(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	repo, err := repoSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	var opt sourcegraph.RepoListClientsOptions
+	if err := decodeOptions(r, &opt); err != nil {
+		return nil, nil, err
+	}
+	return h.Repos.ListClients(r.Context(), repo, &opt)
+}
+
+func (h *handler) serveRepoDependencies(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	repoRev, err := repoRevSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	var opt sourcegraph.RepoListDependenciesOptions
+	if err := decodeOptions(r, &opt); err != nil {
+		return nil, nil, err
+	}
+	return h.Repos.ListDependencies(r.Context(), repoRev, &opt)
+}
+
+func (h *handler) serveRepoDependents(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	repo, err := repoSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	var opt sourcegraph.RepoListDependentsOptions
+	if err := decodeOptions(r, &opt); err != nil {
+		return nil, nil, err
+	}
+	return h.Repos.ListDependents(r.Context(), repo, &opt)
+}
+
+func (h *handler) serveUserRepoContributions(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	user, err := userSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	var opt sourcegraph.RepoListByContributorOptions
+	if err := decodeOptions(r, &opt); err != nil {
+		return nil, nil, err
+	}
+	return h.Repos.ListByContributor(r.Context(), user, &opt)
+}
+
+func (h *handler) serveUserRepoDependencies(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	user, err := userSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	var opt sourcegraph.RepoListByClientOptions
+	if err := decodeOptions(r, &opt); err != nil {
+		return nil, nil, err
+	}
+	return h.Repos.ListByClient(r.Context(), user, &opt)
+}
+
+func (h *handler) serveUserRepoDependents(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	user, err := userSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	var opt sourcegraph.RepoListByRefdAuthorOptions
+	if err := decodeOptions(r, &opt); err != nil {
+		return nil, nil, err
+	}
+	return h.Repos.ListByRefdAuthor(r.Context(), user, &opt)
+}
@@ -0,0 +1,42 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/sourcegraph/mux"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/sourcegraph"
+)
+
+func (h *handler) repoTreeRoutes() map[string]http.Handler {
+	return map[string]http.Handler{
+		router.RepoTreeEntry:  handlerFunc(h.serveRepoTreeEntry),
+		router.RepoTreeSearch: handlerFunc(h.serveRepoTreeSearch),
+	}
+}
+
+func (h *handler) serveRepoTreeEntry(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	repoRev, err := repoRevSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	entry := sourcegraph.TreeEntrySpec{RepoRev: repoRev, Path: mux.Vars(r)["Path"]}
+	var opt sourcegraph.RepoTreeGetOptions
+	if err := decodeOptions(r, &opt); err != nil {
+		return nil, nil, err
+	}
+	return h.RepoTree.Get(r.Context(), entry, &opt)
+}
+
+func (h *handler) serveRepoTreeSearch(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	repoRev, err := repoRevSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	var opt sourcegraph.RepoTreeSearchOptions
+	if err := decodeOptions(r, &opt); err != nil {
+		return nil, nil, err
+	}
+	return h.RepoTree.Search(r.Context(), repoRev, &opt)
+}
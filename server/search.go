@@ -0,0 +1,40 @@
+package server
+
+import (
+	"net/http"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/sourcegraph"
+)
+
+func (h *handler) searchRoutes() map[string]http.Handler {
+	return map[string]http.Handler{
+		router.Search:            handlerFunc(h.serveSearch),
+		router.SearchComplete:    handlerFunc(h.serveSearchComplete),
+		router.SearchSuggestions: handlerFunc(h.serveSearchSuggestions),
+	}
+}
+
+func (h *handler) serveSearch(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	var opt sourcegraph.SearchOptions
+	if err := decodeOptions(r, &opt); err != nil {
+		return nil, nil, err
+	}
+	return h.Search.Search(r.Context(), &opt)
+}
+
+func (h *handler) serveSearchComplete(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	var q sourcegraph.RawQuery
+	if err := decodeOptions(r, &q); err != nil {
+		return nil, nil, err
+	}
+	return h.Search.Complete(r.Context(), q)
+}
+
+func (h *handler) serveSearchSuggestions(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	var q sourcegraph.RawQuery
+	if err := decodeOptions(r, &q); err != nil {
+		return nil, nil, err
+	}
+	return h.Search.Suggest(r.Context(), q)
+}
@@ -0,0 +1,315 @@
+// Package server serves the Sourcegraph API over HTTP using any
+// implementation of the service interfaces in package sourcegraph.
+//
+// NewHandler attaches a handler to each named route of the API router
+// (see router.NewAPIRouter). Each handler unmarshals the route
+// variables and URL query options, calls the corresponding service
+// method, and encodes the result as JSON, in the format that
+// sourcegraph.Client expects. This makes it possible to serve any
+// implementation, such as the in-memory services in package fake, to
+// an API client:
+//
+//	client, store := fake.NewClient()
+//	s := httptest.NewServer(server.NewHandler(server.ServicesFromClient(client)))
+//	remote := sourcegraph.NewClient(nil)
+//	remote.BaseURL, _ = url.Parse(s.URL)
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/schema"
+	"github.com/sourcegraph/mux"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/sourcegraph"
+)
+
+// Services holds the service implementations that are served by a
+// handler. Requests to routes of a nil service fail with HTTP status
+// 501 (Not Implemented).
+type Services struct {
+	BuildArtifacts sourcegraph.BuildArtifactsService
+	BuildData      sourcegraph.BuildDataService
+	Builds         sourcegraph.BuildsService
+	Deltas         sourcegraph.DeltasService
+	Issues         sourcegraph.IssuesService
+	Orgs           sourcegraph.OrgsService
+	People         sourcegraph.PeopleService
+	PullRequests   sourcegraph.PullRequestsService
+	Repos          sourcegraph.ReposService
+	RepoTree       sourcegraph.RepoTreeService
+	Search         sourcegraph.SearchService
+	Units          sourcegraph.UnitsService
+	Users          sourcegraph.UsersService
+	Defs           sourcegraph.DefsService
+	Markdown       sourcegraph.MarkdownService
+}
+
+// ServicesFromClient returns the services of c.
+func ServicesFromClient(c *sourcegraph.Client) Services {
+	return Services{
+		BuildArtifacts: c.BuildArtifacts,
+		BuildData:      c.BuildData,
+		Builds:         c.Builds,
+		Deltas:         c.Deltas,
+		Issues:         c.Issues,
+		Orgs:           c.Orgs,
+		People:         c.People,
+		PullRequests:   c.PullRequests,
+		Repos:          c.Repos,
+		RepoTree:       c.RepoTree,
+		Search:         c.Search,
+		Units:          c.Units,
+		Users:          c.Users,
+		Defs:           c.Defs,
+		Markdown:       c.Markdown,
+	}
+}
+
+// unservedRoutes are the API routes that are not backed by a service
+// method. They always fail with HTTP status 501 (Not Implemented).
+var unservedRoutes = []string{
+	router.RepoBadge,
+	router.RepoCounter,
+	router.RepoCompareCommits,
+	router.Snippet,
+	router.ExtGitHubReceiveWebhook,
+	router.RedirectOldRepoBadgesAndCounters,
+}
+
+// NewHandler returns an HTTP handler that serves the API routes using
+// the given services.
+func NewHandler(svcs Services) http.Handler {
+	r := router.NewAPIRouter(nil)
+	h := &handler{svcs}
+
+	for _, g := range []struct {
+		enabled bool
+		routes  map[string]http.Handler
+	}{
+		{svcs.BuildArtifacts != nil, h.buildArtifactsRoutes()},
+		{svcs.BuildData != nil, h.buildDataRoutes()},
+		{svcs.Builds != nil, h.buildsRoutes()},
+		{svcs.Deltas != nil, h.deltasRoutes()},
+		{svcs.Issues != nil, h.issuesRoutes()},
+		{svcs.Orgs != nil, h.orgsRoutes()},
+		{svcs.People != nil, h.peopleRoutes()},
+		{svcs.PullRequests != nil, h.pullRequestsRoutes()},
+		{svcs.Repos != nil, h.reposRoutes()},
+		{svcs.RepoTree != nil, h.repoTreeRoutes()},
+		{svcs.Search != nil, h.searchRoutes()},
+		{svcs.Units != nil, h.unitsRoutes()},
+		{svcs.Users != nil, h.usersRoutes()},
+		{svcs.Defs != nil, h.defsRoutes()},
+		{svcs.Markdown != nil, h.markdownRoutes()},
+	} {
+		for name, handler := range g.routes {
+			if !g.enabled {
+				handler = notImplemented
+			}
+			r.Get(name).Handler(handler)
+		}
+	}
+	for _, name := range unservedRoutes {
+		r.Get(name).Handler(notImplemented)
+	}
+	return r
+}
+
+type handler struct {
+	Services
+}
+
+// A handlerFunc serves an API request by calling a service method. It
+// returns the value to encode as the JSON response body (or nil if the
+// response has no body), the service method's Response, and the
+// service method's error.
+type handlerFunc func(r *http.Request) (interface{}, sourcegraph.Response, error)
+
+func (f handlerFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	v, resp, err := f(r)
+	if resp != nil {
+		if n := resp.TotalCount(); n >= 0 {
+			w.Header().Set("X-Total-Count", strconv.Itoa(n))
+		}
+	}
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if v == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	writeJSON(w, http.StatusOK, v)
+}
+
+var notImplemented = handlerFunc(func(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	return nil, nil, &statusError{http.StatusNotImplemented, errors.New("not implemented")}
+})
+
+func writeJSON(w http.ResponseWriter, statusCode int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes an ErrorResponse JSON body describing err (with
+// an error code, if it has one), with the HTTP status code given by
+// errorStatusCode.
+func writeError(w http.ResponseWriter, err error) {
+	writeJSON(w, errorStatusCode(err), sourcegraph.NewErrorResponse(err))
+}
+
+// errorStatusCode returns the HTTP status code for an error returned
+// by a service method.
+func errorStatusCode(err error) int {
+	var (
+		renamed     sourcegraph.ErrRenamed
+		userRenamed sourcegraph.ErrUserRenamed
+		redirect    sourcegraph.ErrRedirect
+		redirectPtr *sourcegraph.ErrRedirect
+		errResp     *sourcegraph.ErrorResponse
+		httpErr     interface{ HTTPStatusCode() int }
+	)
+	switch {
+	case errors.Is(err, sourcegraph.ErrNotExist),
+		errors.Is(err, sourcegraph.ErrNotPersisted),
+		errors.Is(err, sourcegraph.ErrNoRepoBuild),
+		errors.Is(err, sourcegraph.ErrBuildNotFound),
+		errors.Is(err, sourcegraph.ErrBuildArtifactNotExist),
+		errors.Is(err, sourcegraph.ErrUserNotExist):
+		return http.StatusNotFound
+	case errors.Is(err, sourcegraph.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, sourcegraph.ErrBuildEnded),
+		errors.Is(err, sourcegraph.ErrBuildNotEnded),
+		errors.Is(err, sourcegraph.ErrBuildNotQueued):
+		return http.StatusConflict
+	case errors.Is(err, sourcegraph.ErrNoScheme),
+		errors.Is(err, sourcegraph.ErrNonStandardURI),
+		errors.Is(err, sourcegraph.ErrTaskDepCycle),
+		errors.Is(err, sourcegraph.ErrTaskDepNotExist),
+		errors.Is(err, sourcegraph.ErrBuildArtifactHashMismatch):
+		return http.StatusBadRequest
+	case errors.Is(err, sourcegraph.ErrBuildArtifactTooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.As(err, &renamed), errors.As(err, &userRenamed),
+		errors.As(err, &redirect), errors.As(err, &redirectPtr):
+		// No Location header is set, so that clients do not follow
+		// the redirect automatically.
+		return http.StatusMovedPermanently
+	case errors.As(err, &errResp):
+		if errResp.Response != nil {
+			return errResp.Response.StatusCode
+		}
+	case errors.As(err, &httpErr):
+		return httpErr.HTTPStatusCode()
+	}
+	return http.StatusInternalServerError
+}
+
+// A statusError is an error that is served with a specific HTTP
+// status code.
+type statusError struct {
+	statusCode int
+	err        error
+}
+
+func (e *statusError) Error() string       { return e.err.Error() }
+func (e *statusError) HTTPStatusCode() int { return e.statusCode }
+
+func badRequest(err error) error {
+	return &statusError{http.StatusBadRequest, err}
+}
+
+var schemaDecoder = schema.NewDecoder()
+
+func init() {
+	schemaDecoder.IgnoreUnknownKeys(true)
+
+	// go-querystring encodes durations with their String method.
+	schemaDecoder.RegisterConverter(time.Duration(0), func(s string) reflect.Value {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return reflect.Value{}
+		}
+		return reflect.ValueOf(d)
+	})
+}
+
+// decodeOptions decodes the URL query of r (as encoded by
+// sourcegraph.Client, using the "url" struct tags) into opt, which
+// must be a pointer to an options struct.
+func decodeOptions(r *http.Request, opt interface{}) error {
+	q := r.URL.Query()
+	if len(q) == 0 {
+		return nil
+	}
+	splitCommaValues(q, reflect.TypeOf(opt).Elem())
+	if err := schemaDecoder.Decode(opt, q); err != nil {
+		return badRequest(err)
+	}
+	return nil
+}
+
+// splitCommaValues splits the comma-separated values in q of fields in
+// the struct type t (and its embedded structs) whose "url" tag has the
+// "comma" option, because gorilla/schema does not support the "comma"
+// option.
+func splitCommaValues(q map[string][]string, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous && f.Type.Kind() == reflect.Struct {
+			splitCommaValues(q, f.Type)
+			continue
+		}
+		tag := strings.Split(f.Tag.Get("url"), ",")
+		if f.Type.Kind() != reflect.Slice || len(tag) < 2 {
+			continue
+		}
+		for _, o := range tag[1:] {
+			if o != "comma" {
+				continue
+			}
+			name := f.Name
+			if tag[0] != "" {
+				name = tag[0]
+			}
+			var vals []string
+			for _, v := range q[name] {
+				vals = append(vals, strings.Split(v, ",")...)
+			}
+			if vals != nil {
+				q[name] = vals
+			}
+		}
+	}
+}
+
+// decodeBody decodes the JSON request body of r into v. An empty
+// body leaves v unchanged.
+func decodeBody(r *http.Request, v interface{}) error {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil && err != io.EOF {
+		return badRequest(err)
+	}
+	return nil
+}
+
+// routeVarInt64 parses the route variable with the given name as a
+// decimal integer.
+func routeVarInt64(r *http.Request, name string) (int64, error) {
+	n, err := strconv.ParseInt(mux.Vars(r)[name], 10, 64)
+	if err != nil {
+		return 0, badRequest(err)
+	}
+	return n, nil
+}
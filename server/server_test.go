@@ -0,0 +1,382 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/go-github/github"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/fake"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/sourcegraph"
+)
+
+// newTestServer serves the fake services over HTTP and returns an API
+// client for the server, along with the fake services' store.
+func newTestServer(t *testing.T) (*sourcegraph.Client, *fake.Store) {
+	fakeClient, store := fake.NewClient()
+	s := httptest.NewServer(NewHandler(ServicesFromClient(fakeClient)))
+	t.Cleanup(s.Close)
+
+	client := sourcegraph.NewClient(nil)
+	client.BaseURL, _ = url.Parse(s.URL)
+	return client, store
+}
+
+func TestRepos(t *testing.T) {
+	client, store := newTestServer(t)
+	ctx := context.Background()
+
+	want := store.AddRepo(&sourcegraph.Repo{URI: "github.com/o/r"})
+	store.AddRepo(&sourcegraph.Repo{URI: "github.com/o/r2"})
+	store.AddRepo(&sourcegraph.Repo{URI: "github.com/o/r3"})
+
+	repo, _, err := client.Repos.Get(ctx, sourcegraph.RepoSpec{URI: "github.com/o/r"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if repo.RID != want.RID || repo.URI != want.URI || repo.DefaultBranch != want.DefaultBranch {
+		t.Errorf("got repo %+v, want %+v", repo, want)
+	}
+
+	repos, resp, err := client.Repos.List(ctx, &sourcegraph.RepoListOptions{
+		URIs:        []string{"github.com/o/r", "github.com/o/r3"},
+		ListOptions: sourcegraph.ListOptions{PerPage: 1},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(repos) != 1 || repos[0].URI != "github.com/o/r" {
+		t.Errorf("got repos %+v, want only github.com/o/r", repos)
+	}
+	if got, want := resp.TotalCount(), 2; got != want {
+		t.Errorf("got TotalCount %d, want %d", got, want)
+	}
+
+	settings := sourcegraph.RepoSettings{Enabled: github.Bool(true)}
+	if _, err := client.Repos.UpdateSettings(ctx, repo.RepoSpec(), settings); err != nil {
+		t.Fatal(err)
+	}
+	gotSettings, _, err := client.Repos.GetSettings(ctx, repo.RepoSpec())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(*gotSettings, settings) {
+		t.Errorf("got settings %+v, want %+v", gotSettings, settings)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	client, store := newTestServer(t)
+	ctx := context.Background()
+
+	_, _, err := client.Repos.Get(ctx, sourcegraph.RepoSpec{URI: "github.com/o/x"}, nil)
+	if !sourcegraph.IsHTTPErrorCode(err, http.StatusNotFound) {
+		t.Errorf("got error %v, want HTTP 404", err)
+	}
+	if err, ok := err.(*sourcegraph.ErrorResponse); !ok || err.Message != sourcegraph.ErrNotExist.Error() {
+		t.Errorf("got error %v, want message %q", err, sourcegraph.ErrNotExist)
+	}
+
+	store.AddRepo(&sourcegraph.Repo{URI: "github.com/o/r"})
+	if err := store.RenameRepo("github.com/o/r", "github.com/o/r2"); err != nil {
+		t.Fatal(err)
+	}
+	_, _, err = client.Repos.Get(ctx, sourcegraph.RepoSpec{URI: "github.com/o/r"}, nil)
+	if !sourcegraph.IsHTTPErrorCode(err, http.StatusMovedPermanently) || !strings.Contains(err.Error(), "github.com/o/r2") {
+		t.Errorf("got error %v, want HTTP 301 naming the new URI", err)
+	}
+	var renamed sourcegraph.ErrRenamed
+	if !errors.As(err, &renamed) || renamed.NewURI != "github.com/o/r2" {
+		t.Errorf("got error %v, want ErrRenamed with the new URI", err)
+	}
+
+	_, _, err = client.Builds.Get(ctx, sourcegraph.BuildSpec{BID: 123}, nil)
+	if !sourcegraph.IsHTTPErrorCode(err, http.StatusNotFound) {
+		t.Errorf("got error %v, want HTTP 404", err)
+	}
+	if !errors.Is(err, sourcegraph.ErrBuildNotFound) {
+		t.Errorf("got error %v, want ErrBuildNotFound", err)
+	}
+}
+
+func TestNotImplemented(t *testing.T) {
+	client, _ := newTestServer(t)
+	s := httptest.NewServer(NewHandler(Services{}))
+	defer s.Close()
+	client.BaseURL, _ = url.Parse(s.URL)
+
+	_, _, err := client.Repos.Get(context.Background(), sourcegraph.RepoSpec{URI: "github.com/o/r"}, nil)
+	if !sourcegraph.IsHTTPErrorCode(err, http.StatusNotImplemented) {
+		t.Errorf("got error %v, want HTTP 501", err)
+	}
+}
+
+func TestBuilds(t *testing.T) {
+	client, store := newTestServer(t)
+	ctx := context.Background()
+
+	build, _, err := client.Builds.DequeueNext(ctx)
+	if err != nil || build != nil {
+		t.Fatalf("got build %+v and error %v with no queued builds, want nil and nil", build, err)
+	}
+
+	repo := store.AddRepo(&sourcegraph.Repo{URI: "github.com/o/r"})
+	repoRev := sourcegraph.RepoRevSpec{RepoSpec: repo.RepoSpec(), Rev: "c", CommitID: "c"}
+	created, _, err := client.Builds.Create(ctx, repoRev, &sourcegraph.BuildCreateOptions{BuildConfig: sourcegraph.BuildConfig{Queue: true, Priority: 5}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.CommitID != "c" || !created.Queue || created.Priority != 5 {
+		t.Errorf("got created build %+v, want queued build of commit c with priority 5", created)
+	}
+
+	build, _, err = client.Builds.DequeueNext(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if build == nil || build.BID != created.BID || !build.StartedAt.Valid {
+		t.Errorf("got dequeued build %+v, want started build %d", build, created.BID)
+	}
+	if _, _, err := client.Builds.GetQueueStatus(ctx, created.Spec()); !errors.Is(err, sourcegraph.ErrBuildNotQueued) || !sourcegraph.IsHTTPErrorCode(err, http.StatusConflict) {
+		t.Errorf("got error %v getting queue status of running build, want ErrBuildNotQueued (HTTP 409)", err)
+	}
+
+	store.AppendBuildLog(created.Spec(), "a", "b")
+	log, _, err := client.Builds.GetLog(ctx, created.Spec(), &sourcegraph.BuildGetLogOptions{MinID: "1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"b"}; !reflect.DeepEqual(log.Entries, want) {
+		t.Errorf("got log entries %q, want %q", log.Entries, want)
+	}
+
+	if _, _, err := client.Builds.Retry(ctx, created.Spec()); !errors.Is(err, sourcegraph.ErrBuildNotEnded) || !sourcegraph.IsHTTPErrorCode(err, http.StatusConflict) {
+		t.Errorf("got error %v retrying running build, want ErrBuildNotEnded (HTTP 409)", err)
+	}
+	canceled, _, err := client.Builds.Cancel(ctx, created.Spec())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !canceled.Canceled || !canceled.EndedAt.Valid {
+		t.Errorf("got canceled build %+v, want ended and canceled", canceled)
+	}
+	if _, _, err := client.Builds.Cancel(ctx, created.Spec()); !errors.Is(err, sourcegraph.ErrBuildEnded) {
+		t.Errorf("got error %v canceling ended build, want ErrBuildEnded", err)
+	}
+	retried, _, err := client.Builds.Retry(ctx, created.Spec())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if retried.BID == created.BID || retried.CommitID != "c" || retried.Priority != 5 {
+		t.Errorf("got retried build %+v, want new build of commit c with priority 5", retried)
+	}
+	priority := 7
+	updated, _, err := client.Builds.UpdatePriorities(ctx, sourcegraph.BuildPriorityUpdate{BIDs: []int64{retried.BID}, Priority: &priority})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(updated) != 1 || updated[0].Priority != 7 {
+		t.Errorf("got updated builds %+v, want retried build with priority 7", updated)
+	}
+	if status, _, err := client.Builds.GetQueueStatus(ctx, retried.Spec()); err != nil || status.Position != 1 {
+		t.Errorf("got queue status %+v (error %v), want position 1", status, err)
+	}
+
+	tasks, _, err := client.Builds.CreateTasks(ctx, retried.Spec(), []*sourcegraph.BuildTask{{Op: "graph"}, {Op: "import", DependsOn: []int64{-1}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int64{tasks[0].TaskID}; !reflect.DeepEqual(tasks[1].DependsOn, want) {
+		t.Errorf("got task deps %v, want %v", tasks[1].DependsOn, want)
+	}
+	if _, _, err := client.Builds.CreateTasks(ctx, retried.Spec(), []*sourcegraph.BuildTask{{DependsOn: []int64{-1}}}); !errors.Is(err, sourcegraph.ErrTaskDepCycle) || !sourcegraph.IsHTTPErrorCode(err, http.StatusBadRequest) {
+		t.Errorf("got error %v creating cyclic task, want ErrTaskDepCycle (HTTP 400)", err)
+	}
+}
+
+func TestBuildEvents(t *testing.T) {
+	client, store := newTestServer(t)
+	ctx := context.Background()
+
+	repo := store.AddRepo(&sourcegraph.Repo{URI: "github.com/o/r"})
+	repoRev := sourcegraph.RepoRevSpec{RepoSpec: repo.RepoSpec(), Rev: "c", CommitID: "c"}
+	build, _, err := client.Builds.Create(ctx, repoRev, &sourcegraph.BuildCreateOptions{BuildConfig: sourcegraph.BuildConfig{Queue: true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Long polling.
+	go client.Builds.DequeueNext(ctx)
+	events, _, err := client.Builds.ListEvents(ctx, &sourcegraph.BuildEventListOptions{After: 1, Wait: time.Minute})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || events[0].Type != sourcegraph.BuildEventStarted || events[0].Build.BID != build.BID {
+		t.Errorf("got events %+v, want started event of build %d", events, build.BID)
+	}
+
+	// Server-Sent Events, resuming after the first event.
+	u, err := client.URL(router.BuildEvents, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	req, _ := http.NewRequest("GET", u.String(), nil)
+	req = req.WithContext(ctx)
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set("Last-Event-ID", "1")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("got Content-Type %q, want text/event-stream", ct)
+	}
+	r := bufio.NewReader(resp.Body)
+	var frame []string
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+		if line == "\n" {
+			break
+		}
+		frame = append(frame, strings.TrimSuffix(line, "\n"))
+	}
+	if len(frame) != 3 || frame[0] != "id: 2" || frame[1] != "event: started" || !strings.HasPrefix(frame[2], "data: ") {
+		t.Errorf("got SSE frame %q, want started event with ID 2", frame)
+	}
+}
+
+func TestBuildEvents_waitLimit(t *testing.T) {
+	defer func(orig time.Duration) { eventStreamWait = orig }(eventStreamWait)
+	eventStreamWait = 10 * time.Millisecond
+
+	client, _ := newTestServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// The server waits at most eventStreamWait, however long the client
+	// asks it to wait.
+	events, _, err := client.Builds.ListEvents(ctx, &sourcegraph.BuildEventListOptions{Wait: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 0 {
+		t.Errorf("got events %+v, want none", events)
+	}
+}
+
+func TestBuildArtifacts(t *testing.T) {
+	client, store := newTestServer(t)
+	ctx := context.Background()
+
+	repo := store.AddRepo(&sourcegraph.Repo{URI: "github.com/o/r"})
+	repoRev := sourcegraph.RepoRevSpec{RepoSpec: repo.RepoSpec(), Rev: "c", CommitID: "c"}
+	build, _, err := client.Builds.Create(ctx, repoRev, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	spec := sourcegraph.BuildArtifactSpec{TaskSpec: sourcegraph.TaskSpec{BuildSpec: build.Spec()}, Path: "reports/junit.xml"}
+
+	// The contents are streamed (with no Content-Length).
+	pr, pw := io.Pipe()
+	go func() {
+		io.WriteString(pw, "<testsuites/>")
+		pw.Close()
+	}()
+	created, _, err := client.BuildArtifacts.Upload(ctx, spec, pr, &sourcegraph.BuildArtifactUploadOptions{ContentType: "application/xml"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.Path != spec.Path || created.Size != 13 || created.SHA256 == "" {
+		t.Errorf("got created artifact %+v, want 13-byte artifact at %s", created, spec.Path)
+	}
+
+	rc, artifact, _, err := client.BuildArtifacts.Download(ctx, spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := ioutil.ReadAll(rc)
+	rc.Close()
+	if string(data) != "<testsuites/>" {
+		t.Errorf("got contents %q", data)
+	}
+	if artifact.Size != created.Size || artifact.SHA256 != created.SHA256 || artifact.ContentType != "application/xml" {
+		t.Errorf("got downloaded artifact %+v, want %+v", artifact, created)
+	}
+
+	fs, err := client.BuildArtifacts.FileSystem(ctx, spec.TaskSpec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fis, err := fs.ReadDir("reports")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fis) != 1 || fis[0].Name() != "junit.xml" || fis[0].Size() != 13 {
+		t.Errorf("got dir entries %+v, want junit.xml", fis)
+	}
+
+	store.SetMaxBuildArtifactSize(4)
+	if _, _, err := client.BuildArtifacts.Upload(ctx, spec, strings.NewReader("12345"), nil); !errors.Is(err, sourcegraph.ErrBuildArtifactTooLarge) || !sourcegraph.IsHTTPErrorCode(err, http.StatusRequestEntityTooLarge) {
+		t.Errorf("got error %v uploading too-large artifact, want ErrBuildArtifactTooLarge (HTTP 413)", err)
+	}
+
+	if _, err := client.BuildArtifacts.Delete(ctx, spec); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, err := client.BuildArtifacts.Download(ctx, spec); !errors.Is(err, sourcegraph.ErrBuildArtifactNotExist) || !sourcegraph.IsHTTPErrorCode(err, http.StatusNotFound) {
+		t.Errorf("got error %v downloading deleted artifact, want ErrBuildArtifactNotExist (HTTP 404)", err)
+	}
+}
+
+func TestIssueComments(t *testing.T) {
+	client, store := newTestServer(t)
+	ctx := context.Background()
+
+	repo := store.AddRepo(&sourcegraph.Repo{URI: "github.com/o/r"})
+	issue, err := store.AddIssue(repo.RepoSpec(), &sourcegraph.Issue{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	spec := sourcegraph.IssueSpec{Repo: repo.RepoSpec(), Number: *issue.Number}
+
+	comment, _, err := client.Issues.CreateComment(ctx, spec, &sourcegraph.IssueComment{IssueComment: github.IssueComment{Body: github.String("a")}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	comment.Body = github.String("b")
+	if _, _, err := client.Issues.EditComment(ctx, spec, comment); err != nil {
+		t.Fatal(err)
+	}
+
+	comments, _, err := client.Issues.ListComments(ctx, spec, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(comments) != 1 || *comments[0].Body != "b" {
+		t.Errorf("got comments %+v, want 1 comment with body b", comments)
+	}
+
+	if _, err := client.Issues.DeleteComment(ctx, spec, *comment.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Issues.DeleteComment(ctx, spec, *comment.ID); !sourcegraph.IsHTTPErrorCode(err, http.StatusNotFound) {
+		t.Errorf("got error %v deleting deleted comment, want HTTP 404", err)
+	}
+}
@@ -0,0 +1,33 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/sourcegraph/mux"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/sourcegraph"
+)
+
+func (h *handler) unitsRoutes() map[string]http.Handler {
+	return map[string]http.Handler{
+		router.Unit:  handlerFunc(h.serveUnit),
+		router.Units: handlerFunc(h.serveUnits),
+	}
+}
+
+func (h *handler) serveUnit(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	unit, err := sourcegraph.UnmarshalUnitSpec(mux.Vars(r))
+	if err != nil {
+		return nil, nil, badRequest(err)
+	}
+	return h.Units.Get(r.Context(), unit)
+}
+
+func (h *handler) serveUnits(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	var opt sourcegraph.UnitListOptions
+	if err := decodeOptions(r, &opt); err != nil {
+		return nil, nil, err
+	}
+	return h.Units.List(r.Context(), &opt)
+}
@@ -0,0 +1,146 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/sourcegraph/mux"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/sourcegraph"
+)
+
+func (h *handler) usersRoutes() map[string]http.Handler {
+	return map[string]http.Handler{
+		router.User:               handlerFunc(h.serveUser),
+		router.UserEmails:         handlerFunc(h.serveUserEmails),
+		router.UserSettings:       handlerFunc(h.serveUserSettings),
+		router.UserSettingsUpdate: handlerFunc(h.serveUserSettingsUpdate),
+		router.UserFromGitHub:     handlerFunc(h.serveUserFromGitHub),
+		router.UserRefreshProfile: handlerFunc(h.serveUserRefreshProfile),
+		router.UserComputeStats:   handlerFunc(h.serveUserComputeStats),
+		router.Users:              handlerFunc(h.serveUsers),
+		router.UserAuthors:        handlerFunc(h.serveUserAuthors),
+		router.UserClients:        handlerFunc(h.serveUserClients),
+		router.UserOrgs:           handlerFunc(h.serveUserOrgs),
+	}
+}
+
+func userSpec(r *http.Request) (sourcegraph.UserSpec, error) {
+	user, err := sourcegraph.ParseUserSpec(mux.Vars(r)["UserSpec"])
+	if err != nil {
+		return sourcegraph.UserSpec{}, badRequest(err)
+	}
+	return user, nil
+}
+
+func (h *handler) serveUser(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	user, err := userSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	var opt sourcegraph.UserGetOptions
+	if err := decodeOptions(r, &opt); err != nil {
+		return nil, nil, err
+	}
+	return h.Users.Get(r.Context(), user, &opt)
+}
+
+func (h *handler) serveUserEmails(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	user, err := userSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	return h.Users.ListEmails(r.Context(), user)
+}
+
+func (h *handler) serveUserSettings(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	user, err := userSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	return h.Users.GetSettings(r.Context(), user)
+}
+
+func (h *handler) serveUserSettingsUpdate(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	user, err := userSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	var settings sourcegraph.UserSettings
+	if err := decodeBody(r, &settings); err != nil {
+		return nil, nil, err
+	}
+	resp, err := h.Users.UpdateSettings(r.Context(), user, settings)
+	return nil, resp, err
+}
+
+func (h *handler) serveUserFromGitHub(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	user := sourcegraph.GitHubUserSpec{Login: mux.Vars(r)["GitHubUserSpec"]}
+	var opt sourcegraph.UserGetOptions
+	if err := decodeOptions(r, &opt); err != nil {
+		return nil, nil, err
+	}
+	return h.Users.GetOrCreateFromGitHub(r.Context(), user, &opt)
+}
+
+func (h *handler) serveUserRefreshProfile(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	user, err := userSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	resp, err := h.Users.RefreshProfile(r.Context(), user)
+	return nil, resp, err
+}
+
+func (h *handler) serveUserComputeStats(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	user, err := userSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	resp, err := h.Users.ComputeStats(r.Context(), user)
+	return nil, resp, err
+}
+
+func (h *handler) serveUsers(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	var opt sourcegraph.UsersListOptions
+	if err := decodeOptions(r, &opt); err != nil {
+		return nil, nil, err
+	}
+	return h.Users.List(r.Context(), &opt)
+}
+
+func (h *handler) serveUserAuthors(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	user, err := userSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	var opt sourcegraph.UsersListAuthorsOptions
+	if err := decodeOptions(r, &opt); err != nil {
+		return nil, nil, err
+	}
+	return h.Users.ListAuthors(r.Context(), user, &opt)
+}
+
+func (h *handler) serveUserClients(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	user, err := userSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	var opt sourcegraph.UsersListClientsOptions
+	if err := decodeOptions(r, &opt); err != nil {
+		return nil, nil, err
+	}
+	return h.Users.ListClients(r.Context(), user, &opt)
+}
+
+func (h *handler) serveUserOrgs(r *http.Request) (interface{}, sourcegraph.Response, error) {
+	user, err := userSpec(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	var opt sourcegraph.UsersListOrgsOptions
+	if err := decodeOptions(r, &opt); err != nil {
+		return nil, nil, err
+	}
+	return h.Users.ListOrgs(r.Context(), user, &opt)
+}
@@ -0,0 +1,317 @@
+package sourcegraph
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
+	"sourcegraph.com/sourcegraph/rwvfs"
+)
+
+// BuildArtifactsService communicates with the build artifact-related
+// endpoints in the Sourcegraph API.
+//
+// Artifacts are files that builds and their tasks produce other than
+// srclib build data (which is stored with BuildDataService), such as
+// test reports, coverage data, and profiles. Each artifact belongs to
+// a build or to one of its tasks, and is identified by its path. The
+// artifacts of a build do not include those of its tasks.
+type BuildArtifactsService interface {
+	// List lists the artifacts of a build (if owner.TaskID is 0) or
+	// of one of its tasks, ordered by path.
+	List(ctx context.Context, owner TaskSpec, opt *BuildArtifactListOptions) ([]*BuildArtifact, Response, error)
+
+	// Upload creates an artifact (or replaces an existing one) with
+	// the contents read from r, which is streamed to the server. It
+	// returns ErrBuildArtifactTooLarge if the contents exceed the
+	// server's size limit, and ErrBuildArtifactHashMismatch if
+	// opt.SHA256 is set and does not match the contents. In both
+	// cases, no artifact is created.
+	Upload(ctx context.Context, artifact BuildArtifactSpec, r io.Reader, opt *BuildArtifactUploadOptions) (*BuildArtifact, Response, error)
+
+	// Download opens an artifact for reading. The caller must close
+	// the returned reader (unless an error is returned). If the
+	// server sends the artifact's hash, reading the contents to the
+	// end returns ErrBuildArtifactHashMismatch (instead of io.EOF) if
+	// they do not match it.
+	Download(ctx context.Context, artifact BuildArtifactSpec) (io.ReadCloser, *BuildArtifact, Response, error)
+
+	// Delete deletes an artifact.
+	Delete(ctx context.Context, artifact BuildArtifactSpec) (Response, error)
+
+	// FileSystem returns a virtual filesystem interface to the
+	// artifacts of a build (if owner.TaskID is 0) or of one of its
+	// tasks (see BuildArtifactsFileSystem).
+	FileSystem(ctx context.Context, owner TaskSpec) (rwvfs.FileSystem, error)
+}
+
+type buildArtifactsService struct {
+	client *Client
+}
+
+var _ BuildArtifactsService = &buildArtifactsService{}
+
+var (
+	// ErrBuildArtifactNotExist is returned when an artifact does not
+	// exist.
+	ErrBuildArtifactNotExist = errors.New("build artifact does not exist")
+
+	// ErrBuildArtifactTooLarge is returned by
+	// BuildArtifactsService.Upload when an artifact exceeds the
+	// server's size limit.
+	ErrBuildArtifactTooLarge = errors.New("build artifact is too large")
+
+	// ErrBuildArtifactHashMismatch is returned by
+	// BuildArtifactsService.Upload when the uploaded contents do not
+	// match the expected hash, and by the reader returned by
+	// BuildArtifactsService.Download when the downloaded contents do
+	// not match the hash sent by the server.
+	ErrBuildArtifactHashMismatch = errors.New("build artifact contents do not match hash")
+)
+
+// A BuildArtifact is a file produced by a build or by one of its
+// tasks.
+type BuildArtifact struct {
+	// BID is the build that the artifact belongs to.
+	BID int64
+
+	// TaskID is the task that the artifact belongs to, or 0 if it
+	// belongs to the build itself.
+	TaskID int64 `json:",omitempty"`
+
+	// Path is the slash-separated path of the artifact, relative to
+	// the artifacts of its build or task (such as
+	// "reports/junit.xml").
+	Path string
+
+	// Size is the size of the artifact's contents in bytes.
+	Size int64
+
+	// SHA256 is the hex-encoded SHA-256 hash of the artifact's
+	// contents.
+	SHA256 string
+
+	// ContentType is the MIME type of the artifact, if known.
+	ContentType string `json:",omitempty"`
+
+	// CreatedAt is when the artifact was uploaded.
+	CreatedAt time.Time
+}
+
+// Spec returns the spec that identifies the artifact.
+func (a *BuildArtifact) Spec() BuildArtifactSpec {
+	return BuildArtifactSpec{TaskSpec: TaskSpec{BuildSpec: BuildSpec{BID: a.BID}, TaskID: a.TaskID}, Path: a.Path}
+}
+
+// BuildArtifactSpec specifies an artifact of a build (if TaskID is 0)
+// or of one of its tasks.
+type BuildArtifactSpec struct {
+	TaskSpec
+	Path string
+}
+
+// RouteVars returns route variables used to construct URLs to an
+// artifact.
+func (s *BuildArtifactSpec) RouteVars() map[string]string {
+	v := buildArtifactsRouteVars(s.TaskSpec)
+	v["Path"] = s.Path
+	return v
+}
+
+// buildArtifactsRouteVars returns the route variables of the
+// artifacts of owner, whose routes are chosen with
+// buildArtifactRoute.
+func buildArtifactsRouteVars(owner TaskSpec) map[string]string {
+	if owner.TaskID == 0 {
+		return owner.BuildSpec.RouteVars()
+	}
+	return owner.RouteVars()
+}
+
+// buildArtifactRoute returns buildRoute if owner is a build, and
+// taskRoute if it is a task.
+func buildArtifactRoute(owner TaskSpec, buildRoute, taskRoute string) string {
+	if owner.TaskID == 0 {
+		return buildRoute
+	}
+	return taskRoute
+}
+
+// Headers of build artifact download responses, in addition to the
+// standard Content-Type, Content-Length, and Last-Modified headers.
+const (
+	// BuildArtifactSHA256Header holds the hex-encoded SHA-256 hash of
+	// the artifact's contents (see BuildArtifact.SHA256).
+	BuildArtifactSHA256Header = "X-Sourcegraph-Artifact-SHA256"
+)
+
+// SetBuildArtifactHeader sets the headers of a response that serves
+// the contents of artifact a.
+func SetBuildArtifactHeader(h http.Header, a *BuildArtifact) {
+	contentType := a.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	h.Set("Content-Type", contentType)
+	h.Set("Content-Length", strconv.FormatInt(a.Size, 10))
+	h.Set("Last-Modified", a.CreatedAt.UTC().Format(http.TimeFormat))
+	h.Set("Etag", `"`+a.SHA256+`"`)
+	h.Set(BuildArtifactSHA256Header, a.SHA256)
+}
+
+// buildArtifactFromHeader returns the artifact whose contents are
+// served by a response with the header h (see SetBuildArtifactHeader).
+func buildArtifactFromHeader(spec BuildArtifactSpec, h http.Header) *BuildArtifact {
+	a := &BuildArtifact{BID: spec.BID, TaskID: spec.TaskID, Path: spec.Path, SHA256: h.Get(BuildArtifactSHA256Header)}
+	a.Size, _ = strconv.ParseInt(h.Get("Content-Length"), 10, 64)
+	if ct := h.Get("Content-Type"); ct != "application/octet-stream" {
+		a.ContentType = ct
+	}
+	a.CreatedAt, _ = http.ParseTime(h.Get("Last-Modified"))
+	return a
+}
+
+// BuildArtifactListOptions specifies options for listing artifacts.
+type BuildArtifactListOptions struct {
+	// Prefix, if set, only lists artifacts whose paths begin with
+	// Prefix.
+	Prefix string `url:",omitempty"`
+}
+
+func (s *buildArtifactsService) List(ctx context.Context, owner TaskSpec, opt *BuildArtifactListOptions) ([]*BuildArtifact, Response, error) {
+	url, err := s.client.URL(buildArtifactRoute(owner, router.BuildArtifacts, router.BuildTaskArtifacts), buildArtifactsRouteVars(owner), opt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.client.NewRequest(ctx, "GET", url.String(), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var artifacts []*BuildArtifact
+	resp, err := s.client.Do(req, &artifacts)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return artifacts, resp, nil
+}
+
+// BuildArtifactUploadOptions specifies options for uploading an
+// artifact.
+type BuildArtifactUploadOptions struct {
+	// SHA256, if set, is the expected hex-encoded SHA-256 hash of the
+	// contents. The upload fails if the contents don't match it.
+	SHA256 string `url:",omitempty"`
+
+	// ContentType is the MIME type of the artifact, if known.
+	ContentType string `url:",omitempty"`
+}
+
+func (s *buildArtifactsService) Upload(ctx context.Context, artifact BuildArtifactSpec, r io.Reader, opt *BuildArtifactUploadOptions) (*BuildArtifact, Response, error) {
+	url, err := s.client.URL(buildArtifactRoute(artifact.TaskSpec, router.BuildArtifactUpload, router.BuildTaskArtifactUpload), artifact.RouteVars(), opt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The contents are sent as is (not JSON-encoded, as NewRequest
+	// would), without buffering them. If r is a *bytes.Reader (or
+	// another type that http.NewRequest recognizes), the request's
+	// Content-Length is set, and the request can be retried.
+	req, err := http.NewRequestWithContext(ctx, "PUT", url.String(), r)
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Set("User-Agent", s.client.UserAgent)
+
+	var created *BuildArtifact
+	resp, err := s.client.Do(req, &created)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return created, resp, nil
+}
+
+func (s *buildArtifactsService) Download(ctx context.Context, artifact BuildArtifactSpec) (io.ReadCloser, *BuildArtifact, Response, error) {
+	url, err := s.client.URL(buildArtifactRoute(artifact.TaskSpec, router.BuildArtifact, router.BuildTaskArtifact), artifact.RouteVars(), nil)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	req, err := s.client.NewRequest(ctx, "GET", url.String(), nil)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	resp, err := s.client.Do(req, preserveBody)
+	hr, ok := resp.(*HTTPResponse)
+	if err != nil {
+		if ok && hr.Response != nil && hr.Body != nil {
+			hr.Body.Close()
+		}
+		return nil, nil, resp, err
+	}
+	if !ok || hr.Response == nil {
+		return nil, nil, resp, errors.New("build artifact download returned no HTTP response")
+	}
+
+	a := buildArtifactFromHeader(artifact, hr.Header)
+	body := hr.Body
+	if a.SHA256 != "" {
+		body = &hashVerifyingReader{ReadCloser: body, h: sha256.New(), want: strings.ToLower(a.SHA256)}
+	}
+	return body, a, resp, nil
+}
+
+// hashVerifyingReader hashes the data read from the underlying reader
+// and returns ErrBuildArtifactHashMismatch at EOF if the hex-encoded
+// hash does not equal want.
+type hashVerifyingReader struct {
+	io.ReadCloser
+	h    hash.Hash
+	want string
+}
+
+func (r *hashVerifyingReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.h.Write(p[:n])
+	if err == io.EOF && hex.EncodeToString(r.h.Sum(nil)) != r.want {
+		err = ErrBuildArtifactHashMismatch
+	}
+	return n, err
+}
+
+func (s *buildArtifactsService) Delete(ctx context.Context, artifact BuildArtifactSpec) (Response, error) {
+	url, err := s.client.URL(buildArtifactRoute(artifact.TaskSpec, router.BuildArtifactDelete, router.BuildTaskArtifactDelete), artifact.RouteVars(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := s.client.NewRequest(ctx, "DELETE", url.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.Do(req, nil)
+	if err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+func (s *buildArtifactsService) FileSystem(ctx context.Context, owner TaskSpec) (rwvfs.FileSystem, error) {
+	return BuildArtifactsFileSystem(ctx, s, owner), nil
+}
+
+var _ BuildArtifactsService = &MockBuildArtifactsService{}
@@ -0,0 +1,208 @@
+package sourcegraph
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"sourcegraph.com/sourcegraph/rwvfs"
+)
+
+// BuildArtifactsFileSystem returns a virtual filesystem interface to
+// the artifacts of a build (if owner.TaskID is 0) or of one of its
+// tasks, which calls the methods of s with ctx. The paths of the
+// artifacts are the filesystem's file paths; directories exist
+// implicitly wherever there are artifacts below them (so Mkdir does
+// nothing, and a directory disappears when its last artifact is
+// removed).
+//
+// Open reads the whole artifact into memory (so that it can be
+// seeked). The os.FileInfo of each file returned by Stat, Lstat, and
+// ReadDir has the file's *BuildArtifact as its Sys value.
+func BuildArtifactsFileSystem(ctx context.Context, s BuildArtifactsService, owner TaskSpec) rwvfs.FileSystem {
+	return &artifactFS{ctx: ctx, s: s, owner: owner}
+}
+
+type artifactFS struct {
+	ctx   context.Context
+	s     BuildArtifactsService
+	owner TaskSpec
+}
+
+// cleanArtifactPath returns the clean form of a path in the artifact
+// filesystem (the artifact path that it refers to), which is "" for
+// the root. Leading slashes are ignored.
+func cleanArtifactPath(p string) string {
+	return strings.TrimPrefix(path.Clean("/"+p), "/")
+}
+
+func (fs *artifactFS) spec(name string) BuildArtifactSpec {
+	return BuildArtifactSpec{TaskSpec: fs.owner, Path: cleanArtifactPath(name)}
+}
+
+// pathError returns err as an *os.PathError, translating
+// ErrBuildArtifactNotExist to os.ErrNotExist.
+func pathError(op, name string, err error) error {
+	if errors.Is(err, ErrBuildArtifactNotExist) {
+		err = os.ErrNotExist
+	}
+	return &os.PathError{Op: op, Path: name, Err: err}
+}
+
+func (fs *artifactFS) Open(name string) (rwvfs.ReadSeekCloser, error) {
+	rc, _, _, err := fs.s.Download(fs.ctx, fs.spec(name))
+	if err != nil {
+		return nil, pathError("open", name, err)
+	}
+	defer rc.Close()
+	data, err := ioutil.ReadAll(rc)
+	if err != nil {
+		return nil, pathError("open", name, err)
+	}
+	return nopCloser{bytes.NewReader(data)}, nil
+}
+
+type nopCloser struct {
+	io.ReadSeeker
+}
+
+func (nopCloser) Close() error { return nil }
+
+func (fs *artifactFS) Lstat(name string) (os.FileInfo, error) { return fs.Stat(name) }
+
+func (fs *artifactFS) Stat(name string) (os.FileInfo, error) {
+	p := cleanArtifactPath(name)
+	if p == "" {
+		return artifactDirInfo("."), nil
+	}
+	artifacts, _, err := fs.s.List(fs.ctx, fs.owner, &BuildArtifactListOptions{Prefix: p})
+	if err != nil {
+		return nil, pathError("stat", name, err)
+	}
+	for _, a := range artifacts {
+		if a.Path == p {
+			return artifactFileInfo{a}, nil
+		}
+		if strings.HasPrefix(a.Path, p+"/") {
+			return artifactDirInfo(path.Base(p)), nil
+		}
+	}
+	return nil, pathError("stat", name, os.ErrNotExist)
+}
+
+func (fs *artifactFS) ReadDir(name string) ([]os.FileInfo, error) {
+	p := cleanArtifactPath(name)
+	var prefix string
+	if p != "" {
+		prefix = p + "/"
+	}
+	artifacts, _, err := fs.s.List(fs.ctx, fs.owner, &BuildArtifactListOptions{Prefix: prefix})
+	if err != nil {
+		return nil, pathError("readdir", name, err)
+	}
+
+	var fis []os.FileInfo
+	dirs := map[string]bool{}
+	for _, a := range artifacts {
+		if !strings.HasPrefix(a.Path, prefix) {
+			continue
+		}
+		rest := strings.TrimPrefix(a.Path, prefix)
+		if i := strings.Index(rest, "/"); i != -1 {
+			if dir := rest[:i]; !dirs[dir] {
+				dirs[dir] = true
+				fis = append(fis, artifactDirInfo(dir))
+			}
+			continue
+		}
+		fis = append(fis, artifactFileInfo{a})
+	}
+	if len(fis) == 0 && p != "" {
+		if fi, err := fs.Stat(name); err != nil {
+			return nil, err
+		} else if !fi.IsDir() {
+			return nil, pathError("readdir", name, errors.New("not a directory"))
+		}
+	}
+	sort.Slice(fis, func(i, j int) bool { return fis[i].Name() < fis[j].Name() })
+	return fis, nil
+}
+
+func (fs *artifactFS) String() string {
+	if fs.owner.TaskID == 0 {
+		return "build artifacts (" + fs.owner.BuildSpec.IDString() + ")"
+	}
+	return "build artifacts (" + fs.owner.IDString() + ")"
+}
+
+// Create returns a writer whose writes are streamed to the artifact as
+// they occur. The artifact is created when the writer is closed, which
+// returns the upload's error.
+func (fs *artifactFS) Create(name string) (io.WriteCloser, error) {
+	spec := fs.spec(name)
+	if spec.Path == "" {
+		return nil, pathError("create", name, errors.New("is a directory"))
+	}
+	pr, pw := io.Pipe()
+	w := &artifactWriter{pw: pw, done: make(chan error, 1)}
+	go func() {
+		_, _, err := fs.s.Upload(fs.ctx, spec, pr, nil)
+		if err != nil {
+			err = pathError("create", name, err)
+		}
+		pr.CloseWithError(err)
+		w.done <- err
+	}()
+	return w, nil
+}
+
+type artifactWriter struct {
+	pw   *io.PipeWriter
+	done chan error
+}
+
+func (w *artifactWriter) Write(p []byte) (int, error) { return w.pw.Write(p) }
+
+func (w *artifactWriter) Close() error {
+	w.pw.Close()
+	return <-w.done
+}
+
+// Mkdir does nothing, because directories exist implicitly.
+func (fs *artifactFS) Mkdir(name string) error { return nil }
+
+func (fs *artifactFS) Remove(name string) error {
+	if _, err := fs.s.Delete(fs.ctx, fs.spec(name)); err != nil {
+		return pathError("remove", name, err)
+	}
+	return nil
+}
+
+// artifactFileInfo describes an artifact.
+type artifactFileInfo struct {
+	a *BuildArtifact
+}
+
+func (fi artifactFileInfo) Name() string       { return path.Base(fi.a.Path) }
+func (fi artifactFileInfo) Size() int64        { return fi.a.Size }
+func (fi artifactFileInfo) Mode() os.FileMode  { return 0444 }
+func (fi artifactFileInfo) ModTime() time.Time { return fi.a.CreatedAt }
+func (fi artifactFileInfo) IsDir() bool        { return false }
+func (fi artifactFileInfo) Sys() interface{}   { return fi.a }
+
+// artifactDirInfo describes an implicit directory of artifacts.
+type artifactDirInfo string
+
+func (fi artifactDirInfo) Name() string       { return string(fi) }
+func (fi artifactDirInfo) Size() int64        { return 0 }
+func (fi artifactDirInfo) Mode() os.FileMode  { return os.ModeDir | 0555 }
+func (fi artifactDirInfo) ModTime() time.Time { return time.Time{} }
+func (fi artifactDirInfo) IsDir() bool        { return true }
+func (fi artifactDirInfo) Sys() interface{}   { return nil }
@@ -0,0 +1,159 @@
+package sourcegraph
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// memArtifacts returns a BuildArtifactsService that stores the
+// artifacts of any build or task in memory, keyed on their paths.
+func memArtifacts() BuildArtifactsService {
+	var mu sync.Mutex
+	files := map[string][]byte{}
+	return MockBuildArtifactsService{
+		List_: func(ctx context.Context, owner TaskSpec, opt *BuildArtifactListOptions) ([]*BuildArtifact, Response, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			var artifacts []*BuildArtifact
+			for p, data := range files {
+				if strings.HasPrefix(p, opt.Prefix) {
+					artifacts = append(artifacts, &BuildArtifact{Path: p, Size: int64(len(data))})
+				}
+			}
+			return artifacts, nil, nil
+		},
+		Upload_: func(ctx context.Context, artifact BuildArtifactSpec, r io.Reader, opt *BuildArtifactUploadOptions) (*BuildArtifact, Response, error) {
+			data, err := ioutil.ReadAll(r)
+			if err != nil {
+				return nil, nil, err
+			}
+			if len(data) > 10 {
+				return nil, nil, ErrBuildArtifactTooLarge
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			files[artifact.Path] = data
+			return &BuildArtifact{Path: artifact.Path, Size: int64(len(data))}, nil, nil
+		},
+		Download_: func(ctx context.Context, artifact BuildArtifactSpec) (io.ReadCloser, *BuildArtifact, Response, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			data, ok := files[artifact.Path]
+			if !ok {
+				return nil, nil, nil, ErrBuildArtifactNotExist
+			}
+			return ioutil.NopCloser(bytes.NewReader(data)), &BuildArtifact{Path: artifact.Path, Size: int64(len(data))}, nil, nil
+		},
+		Delete_: func(ctx context.Context, artifact BuildArtifactSpec) (Response, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			if _, ok := files[artifact.Path]; !ok {
+				return nil, ErrBuildArtifactNotExist
+			}
+			delete(files, artifact.Path)
+			return nil, nil
+		},
+	}
+}
+
+func TestBuildArtifactsFileSystem(t *testing.T) {
+	fs := BuildArtifactsFileSystem(context.Background(), memArtifacts(), TaskSpec{BuildSpec: BuildSpec{BID: 1}})
+
+	for name, contents := range map[string]string{"/a.txt": "a", "d/b.txt": "bb", "d/e/c.txt": "ccc"} {
+		w, err := fs.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := io.WriteString(w, contents); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	f, err := fs.Open("d/e/c.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadAll(f)
+	f.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "ccc" {
+		t.Errorf("got contents %q, want %q", data, "ccc")
+	}
+
+	if fi, err := fs.Stat("d/b.txt"); err != nil || fi.IsDir() || fi.Size() != 2 || fi.Name() != "b.txt" {
+		t.Errorf("got file info %+v (error %v), want 2-byte file b.txt", fi, err)
+	}
+	for _, dir := range []string{"/", ".", "d", "d/e/"} {
+		if fi, err := fs.Stat(dir); err != nil || !fi.IsDir() {
+			t.Errorf("%s: got file info %+v (error %v), want dir", dir, fi, err)
+		}
+	}
+	for _, name := range []string{"x", "d/b", "a.txt/x"} {
+		if _, err := fs.Stat(name); !os.IsNotExist(err) {
+			t.Errorf("%s: got error %v, want not-exist error", name, err)
+		}
+	}
+
+	readDir := func(name string) []string {
+		fis, err := fs.ReadDir(name)
+		if err != nil {
+			t.Fatalf("ReadDir(%q): %v", name, err)
+		}
+		var names []string
+		for _, fi := range fis {
+			n := fi.Name()
+			if fi.IsDir() {
+				n += "/"
+			}
+			names = append(names, n)
+		}
+		return names
+	}
+	if got, want := readDir("."), []string{"a.txt", "d/"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got root entries %v, want %v", got, want)
+	}
+	if got, want := readDir("d"), []string{"b.txt", "e/"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got d entries %v, want %v", got, want)
+	}
+	if _, err := fs.ReadDir("x"); !os.IsNotExist(err) {
+		t.Errorf("got error %v reading nonexistent dir, want not-exist error", err)
+	}
+
+	if err := fs.Remove("d/e/c.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fs.Stat("d/e"); !os.IsNotExist(err) {
+		t.Errorf("got error %v for dir whose last artifact was removed, want not-exist error", err)
+	}
+	if err := fs.Remove("d/e/c.txt"); !os.IsNotExist(err) {
+		t.Errorf("got error %v removing nonexistent artifact, want not-exist error", err)
+	}
+	if _, err := fs.Open("d/e/c.txt"); !os.IsNotExist(err) {
+		t.Errorf("got error %v opening nonexistent artifact, want not-exist error", err)
+	}
+}
+
+func TestBuildArtifactsFileSystem_createError(t *testing.T) {
+	fs := BuildArtifactsFileSystem(context.Background(), memArtifacts(), TaskSpec{BuildSpec: BuildSpec{BID: 1}})
+	w, err := fs.Create("big")
+	if err != nil {
+		t.Fatal(err)
+	}
+	io.WriteString(w, "more than 10 bytes")
+	if err := w.Close(); !errors.Is(err, ErrBuildArtifactTooLarge) {
+		t.Errorf("got error %v, want ErrBuildArtifactTooLarge", err)
+	}
+}
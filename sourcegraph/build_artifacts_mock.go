@@ -0,0 +1,36 @@
+package sourcegraph
+
+import (
+	"context"
+	"io"
+
+	"sourcegraph.com/sourcegraph/rwvfs"
+)
+
+type MockBuildArtifactsService struct {
+	List_       func(ctx context.Context, owner TaskSpec, opt *BuildArtifactListOptions) ([]*BuildArtifact, Response, error)
+	Upload_     func(ctx context.Context, artifact BuildArtifactSpec, r io.Reader, opt *BuildArtifactUploadOptions) (*BuildArtifact, Response, error)
+	Download_   func(ctx context.Context, artifact BuildArtifactSpec) (io.ReadCloser, *BuildArtifact, Response, error)
+	Delete_     func(ctx context.Context, artifact BuildArtifactSpec) (Response, error)
+	FileSystem_ func(ctx context.Context, owner TaskSpec) (rwvfs.FileSystem, error)
+}
+
+func (s MockBuildArtifactsService) List(ctx context.Context, owner TaskSpec, opt *BuildArtifactListOptions) ([]*BuildArtifact, Response, error) {
+	return s.List_(ctx, owner, opt)
+}
+
+func (s MockBuildArtifactsService) Upload(ctx context.Context, artifact BuildArtifactSpec, r io.Reader, opt *BuildArtifactUploadOptions) (*BuildArtifact, Response, error) {
+	return s.Upload_(ctx, artifact, r, opt)
+}
+
+func (s MockBuildArtifactsService) Download(ctx context.Context, artifact BuildArtifactSpec) (io.ReadCloser, *BuildArtifact, Response, error) {
+	return s.Download_(ctx, artifact)
+}
+
+func (s MockBuildArtifactsService) Delete(ctx context.Context, artifact BuildArtifactSpec) (Response, error) {
+	return s.Delete_(ctx, artifact)
+}
+
+func (s MockBuildArtifactsService) FileSystem(ctx context.Context, owner TaskSpec) (rwvfs.FileSystem, error) {
+	return s.FileSystem_(ctx, owner)
+}
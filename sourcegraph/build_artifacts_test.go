@@ -0,0 +1,155 @@
+package sourcegraph
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
+)
+
+func TestBuildArtifactsService_List(t *testing.T) {
+	setup()
+	defer teardown()
+
+	want := []*BuildArtifact{{BID: 1, TaskID: 2, Path: "reports/junit.xml", Size: 3, SHA256: "abc"}}
+
+	var called bool
+	mux.HandleFunc(urlPath(t, router.BuildTaskArtifacts, map[string]string{"BID": "1", "TaskID": "2"}), func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		testMethod(t, r, "GET")
+		testFormValues(t, r, values{"Prefix": "reports/"})
+
+		writeJSON(w, want)
+	})
+
+	artifacts, _, err := client.BuildArtifacts.List(context.Background(), TaskSpec{BuildSpec: BuildSpec{BID: 1}, TaskID: 2}, &BuildArtifactListOptions{Prefix: "reports/"})
+	if err != nil {
+		t.Errorf("BuildArtifacts.List returned error: %v", err)
+	}
+
+	if !called {
+		t.Fatal("!called")
+	}
+
+	if !reflect.DeepEqual(artifacts, want) {
+		t.Errorf("BuildArtifacts.List returned %+v, want %+v", artifacts, want)
+	}
+}
+
+func TestBuildArtifactsService_Upload(t *testing.T) {
+	setup()
+	defer teardown()
+
+	want := &BuildArtifact{BID: 1, Path: "a/b.txt", Size: 5, SHA256: "abc"}
+
+	var called bool
+	mux.HandleFunc(urlPath(t, router.BuildArtifactUpload, map[string]string{"BID": "1", "Path": "a/b.txt"}), func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		testMethod(t, r, "PUT")
+		testFormValues(t, r, values{"SHA256": "abc", "ContentType": "text/plain"})
+		testBody(t, r, "hello")
+
+		writeJSON(w, want)
+	})
+
+	spec := BuildArtifactSpec{TaskSpec: TaskSpec{BuildSpec: BuildSpec{BID: 1}}, Path: "a/b.txt"}
+	artifact, _, err := client.BuildArtifacts.Upload(context.Background(), spec, strings.NewReader("hello"), &BuildArtifactUploadOptions{SHA256: "abc", ContentType: "text/plain"})
+	if err != nil {
+		t.Errorf("BuildArtifacts.Upload returned error: %v", err)
+	}
+
+	if !called {
+		t.Fatal("!called")
+	}
+
+	artifact.CreatedAt = want.CreatedAt
+	if !reflect.DeepEqual(artifact, want) {
+		t.Errorf("BuildArtifacts.Upload returned %+v, want %+v", artifact, want)
+	}
+}
+
+// helloSHA256 is the hex-encoded SHA-256 hash of "hello".
+const helloSHA256 = "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+
+func TestBuildArtifactsService_Download(t *testing.T) {
+	setup()
+	defer teardown()
+
+	want := &BuildArtifact{BID: 1, TaskID: 2, Path: "a/b.txt", Size: 5, SHA256: helloSHA256, ContentType: "text/plain", CreatedAt: time.Date(2015, 1, 2, 3, 4, 5, 0, time.UTC)}
+
+	var called bool
+	mux.HandleFunc(urlPath(t, router.BuildTaskArtifact, map[string]string{"BID": "1", "TaskID": "2", "Path": "a/b.txt"}), func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		testMethod(t, r, "GET")
+
+		SetBuildArtifactHeader(w.Header(), want)
+		w.Write([]byte("hello"))
+	})
+
+	rc, artifact, _, err := client.BuildArtifacts.Download(context.Background(), want.Spec())
+	if err != nil {
+		t.Fatalf("BuildArtifacts.Download returned error: %v", err)
+	}
+	defer rc.Close()
+
+	if !called {
+		t.Fatal("!called")
+	}
+
+	data, err := ioutil.ReadAll(rc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "hello" {
+		t.Errorf("got contents %q, want %q", data, "hello")
+	}
+	if !reflect.DeepEqual(artifact, want) {
+		t.Errorf("BuildArtifacts.Download returned %+v, want %+v", artifact, want)
+	}
+}
+
+func TestBuildArtifactsService_Download_hashMismatch(t *testing.T) {
+	setup()
+	defer teardown()
+
+	artifact := &BuildArtifact{BID: 1, Path: "a", Size: 5, SHA256: helloSHA256}
+	mux.HandleFunc(urlPath(t, router.BuildArtifact, map[string]string{"BID": "1", "Path": "a"}), func(w http.ResponseWriter, r *http.Request) {
+		SetBuildArtifactHeader(w.Header(), artifact)
+		w.Write([]byte("HELLO"))
+	})
+
+	rc, _, _, err := client.BuildArtifacts.Download(context.Background(), artifact.Spec())
+	if err != nil {
+		t.Fatalf("BuildArtifacts.Download returned error: %v", err)
+	}
+	defer rc.Close()
+
+	if _, err := ioutil.ReadAll(rc); err != ErrBuildArtifactHashMismatch {
+		t.Errorf("got error %v, want %v", err, ErrBuildArtifactHashMismatch)
+	}
+}
+
+func TestBuildArtifactsService_Delete(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var called bool
+	mux.HandleFunc(urlPath(t, router.BuildArtifactDelete, map[string]string{"BID": "1", "Path": "a/b.txt"}), func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		testMethod(t, r, "DELETE")
+	})
+
+	_, err := client.BuildArtifacts.Delete(context.Background(), BuildArtifactSpec{TaskSpec: TaskSpec{BuildSpec: BuildSpec{BID: 1}}, Path: "a/b.txt"})
+	if err != nil {
+		t.Errorf("BuildArtifacts.Delete returned error: %v", err)
+	}
+
+	if !called {
+		t.Fatal("!called")
+	}
+}
@@ -1,7 +1,9 @@
 package sourcegraph
 
 import (
+	"context"
 	"io"
+	"net/http"
 	"os"
 
 	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
@@ -13,7 +15,7 @@ import (
 type BuildDataService interface {
 	// FileSystem returns a virtual filesystem interface to the build
 	// data for a repo at a specific commit.
-	FileSystem(repo RepoRevSpec) (rwvfs.FileSystem, error)
+	FileSystem(ctx context.Context, repo RepoRevSpec) (rwvfs.FileSystem, error)
 }
 
 type buildDataService struct {
@@ -22,14 +24,39 @@ type buildDataService struct {
 
 var _ BuildDataService = &buildDataService{}
 
-func (s *buildDataService) FileSystem(repo RepoRevSpec) (rwvfs.FileSystem, error) {
+func (s *buildDataService) FileSystem(ctx context.Context, repo RepoRevSpec) (rwvfs.FileSystem, error) {
 	v := repo.RouteVars()
 	v["Path"] = "."
 	baseURL, err := s.client.URL(router.RepoBuildDataEntry, v, nil)
 	if err != nil {
 		return nil, err
 	}
-	return rwvfs.HTTP(s.client.BaseURL.ResolveReference(baseURL), s.client.httpClient), nil
+	return rwvfs.HTTP(s.client.BaseURL.ResolveReference(baseURL), contextHTTPClient(ctx, s.client.httpClient)), nil
+}
+
+// contextHTTPClient returns a copy of c whose requests are all bound
+// to ctx. It is used where requests are made on our behalf by other
+// packages (such as rwvfs) that don't accept a context.
+func contextHTTPClient(ctx context.Context, c *http.Client) *http.Client {
+	c2 := *c
+	c2.Transport = &contextTransport{ctx: ctx, transport: c.Transport}
+	return &c2
+}
+
+// contextTransport is an HTTP transport that binds each request to
+// ctx before sending it with the underlying transport.
+type contextTransport struct {
+	ctx       context.Context
+	transport http.RoundTripper // if nil, http.DefaultTransport is used
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *contextTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport := t.transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return transport.RoundTrip(req.WithContext(t.ctx))
 }
 
 // BuildDataFileSpec specifies a new or existing build data file in a
@@ -50,8 +77,8 @@ func (s *BuildDataFileSpec) RouteVars() map[string]string {
 // GetBuildDataFile is a helper function that calls Stat and Open on
 // the FileSystem returned for file's RepoRevSpec. Callers are
 // responsible for closing the file (unless an error is returned).
-func GetBuildDataFile(s BuildDataService, file BuildDataFileSpec) (io.ReadCloser, os.FileInfo, error) {
-	fs, err := s.FileSystem(file.RepoRev)
+func GetBuildDataFile(ctx context.Context, s BuildDataService, file BuildDataFileSpec) (io.ReadCloser, os.FileInfo, error) {
+	fs, err := s.FileSystem(ctx, file.RepoRev)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -1,11 +1,15 @@
 package sourcegraph
 
-import "sourcegraph.com/sourcegraph/rwvfs"
+import (
+	"context"
+
+	"sourcegraph.com/sourcegraph/rwvfs"
+)
 
 type MockBuildDataService struct {
-	FileSystem_ func(repo RepoRevSpec) (rwvfs.FileSystem, error)
+	FileSystem_ func(ctx context.Context, repo RepoRevSpec) (rwvfs.FileSystem, error)
 }
 
-func (s MockBuildDataService) FileSystem(repo RepoRevSpec) (rwvfs.FileSystem, error) {
-	return s.FileSystem_(repo)
+func (s MockBuildDataService) FileSystem(ctx context.Context, repo RepoRevSpec) (rwvfs.FileSystem, error) {
+	return s.FileSystem_(ctx, repo)
 }
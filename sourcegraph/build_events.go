@@ -0,0 +1,188 @@
+package sourcegraph
+
+import (
+	"context"
+	"time"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
+)
+
+// A BuildEventType is the kind of state transition that a BuildEvent
+// describes.
+type BuildEventType string
+
+// Build event types.
+const (
+	BuildEventQueued    BuildEventType = "queued"     // a queued build was created
+	BuildEventStarted   BuildEventType = "started"    // a build started
+	BuildEventHeartbeat BuildEventType = "heartbeat"  // a running build's worker sent a heartbeat
+	BuildEventTaskEnded BuildEventType = "task-ended" // a task of a build ended
+	BuildEventSucceeded BuildEventType = "succeeded"  // a build ended successfully
+	BuildEventFailed    BuildEventType = "failed"     // a build ended with a failure
+	BuildEventKilled    BuildEventType = "killed"     // a build was killed
+	BuildEventCanceled  BuildEventType = "canceled"   // a build was canceled
+)
+
+// Ended reports whether events of type t are sent when a build ends
+// (which is when build hooks are notified).
+func (t BuildEventType) Ended() bool {
+	switch t {
+	case BuildEventSucceeded, BuildEventFailed, BuildEventKilled, BuildEventCanceled:
+		return true
+	}
+	return false
+}
+
+// A BuildEvent describes a state transition of a build or of one of
+// its tasks.
+type BuildEvent struct {
+	// ID identifies the event. Event IDs increase over time, so that
+	// clients can resume listing events after the last one they saw
+	// (see BuildEventListOptions.After).
+	ID int64
+
+	// Type is the kind of transition.
+	Type BuildEventType
+
+	// Time is when the transition occurred.
+	Time time.Time
+
+	// Build is the build, as of right after the transition.
+	Build *Build
+
+	// Task is the task that ended (for BuildEventTaskEnded events),
+	// as of right after the transition.
+	Task *BuildTask `json:",omitempty"`
+}
+
+// BuildEventListOptions specifies options for listing build events.
+type BuildEventListOptions struct {
+	// BID, if nonzero, only lists events of the build with this BID.
+	BID int64 `url:",omitempty"`
+
+	// Repo, if set, only lists events of builds of the repository with
+	// this URI.
+	Repo string `url:",omitempty"`
+
+	// After only lists events whose ID is greater than After. To watch
+	// for new events, set each subsequent request's After to the ID of
+	// the last event of the previous request.
+	After int64 `url:",omitempty"`
+
+	// Wait is how long to wait for an event to occur if there are no
+	// events to list (long polling). The server may wait less. If zero,
+	// the server responds immediately.
+	Wait time.Duration `url:",omitempty"`
+}
+
+func (s *buildsService) ListEvents(ctx context.Context, opt *BuildEventListOptions) ([]*BuildEvent, Response, error) {
+	url, err := s.client.URL(router.BuildEvents, nil, opt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.client.NewRequest(ctx, "GET", url.String(), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var events []*BuildEvent
+	resp, err := s.client.Do(req, &events)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return events, resp, nil
+}
+
+// A BuildEventWatcher watches for build events by long polling
+// BuildsService.ListEvents.
+type BuildEventWatcher struct {
+	events chan *BuildEvent
+	cancel context.CancelFunc
+	err    error // set before events is closed
+}
+
+// WatchBuildEvents starts watching for the build events that match
+// opt. If opt.Wait is zero, 30 seconds is used. Watching continues
+// until ctx is canceled, Close is called, or listing events fails.
+//
+// To receive only events that occur from now on, set opt.After to the
+// ID of the latest event; if it is zero, previous events that the
+// server still has are also received.
+func WatchBuildEvents(ctx context.Context, s BuildsService, opt BuildEventListOptions) *BuildEventWatcher {
+	if opt.Wait == 0 {
+		opt.Wait = 30 * time.Second
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	w := &BuildEventWatcher{events: make(chan *BuildEvent), cancel: cancel}
+	go func() {
+		defer close(w.events)
+		defer cancel()
+		w.err = w.watch(ctx, s, opt)
+	}()
+	return w
+}
+
+func (w *BuildEventWatcher) watch(ctx context.Context, s BuildsService, opt BuildEventListOptions) error {
+	for {
+		events, _, err := s.ListEvents(ctx, &opt)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+		for _, e := range events {
+			select {
+			case w.events <- e:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			if e.ID > opt.After {
+				opt.After = e.ID
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+}
+
+// Events returns the channel that the events are sent on, in order of
+// their IDs. It is closed when watching stops, after which Err reports
+// why.
+func (w *BuildEventWatcher) Events() <-chan *BuildEvent { return w.events }
+
+// Err returns the error that stopped watching. It may only be called
+// after the Events channel is closed.
+func (w *BuildEventWatcher) Err() error { return w.err }
+
+// Close stops watching. The Events channel is closed soon afterward.
+func (w *BuildEventWatcher) Close() error {
+	w.cancel()
+	return nil
+}
+
+// WaitForBuild waits for a build to end (instead of polling
+// BuildsService.Get) and returns the ended build.
+func WaitForBuild(ctx context.Context, s BuildsService, build BuildSpec) (*Build, error) {
+	b, _, err := s.Get(ctx, build, nil)
+	if err != nil {
+		return nil, err
+	}
+	if b.EndedAt.Valid || b.Killed {
+		return b, nil
+	}
+
+	// Start at the first event, so that an end that occurred after the
+	// call to Get is not missed.
+	w := WatchBuildEvents(ctx, s, BuildEventListOptions{BID: build.BID})
+	defer w.Close()
+	for e := range w.Events() {
+		if e.Type.Ended() {
+			return e.Build, nil
+		}
+	}
+	return nil, w.Err()
+}
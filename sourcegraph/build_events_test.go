@@ -0,0 +1,156 @@
+package sourcegraph
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
+)
+
+func TestBuildsService_ListEvents(t *testing.T) {
+	setup()
+	defer teardown()
+
+	want := []*BuildEvent{{ID: 2, Type: BuildEventStarted, Build: &Build{BID: 1}}}
+
+	var called bool
+	mux.HandleFunc(urlPath(t, router.BuildEvents, nil), func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		testMethod(t, r, "GET")
+		testFormValues(t, r, values{"BID": "1", "After": "1", "Wait": "1s"})
+
+		writeJSON(w, want)
+	})
+
+	events, _, err := client.Builds.ListEvents(context.Background(), &BuildEventListOptions{BID: 1, After: 1, Wait: time.Second})
+	if err != nil {
+		t.Errorf("Builds.ListEvents returned error: %v", err)
+	}
+
+	if !called {
+		t.Fatal("!called")
+	}
+
+	for _, e := range events {
+		normalizeBuildTime(e.Build)
+		e.Time = time.Time{}
+	}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("Builds.ListEvents returned %+v, want %+v", events, want)
+	}
+}
+
+// eventBuilds is an in-memory BuildsService with a single build (BID 1)
+// whose events are added with add.
+type eventBuilds struct {
+	mu     sync.Mutex
+	build  Build
+	events []*BuildEvent
+	added  chan struct{}
+}
+
+func newEventBuilds() *eventBuilds {
+	return &eventBuilds{build: Build{BID: 1}, added: make(chan struct{})}
+}
+
+func (s *eventBuilds) add(typ BuildEventType, f func(b *Build)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f(&s.build)
+	b := s.build
+	s.events = append(s.events, &BuildEvent{ID: int64(len(s.events) + 1), Type: typ, Build: &b})
+	close(s.added)
+	s.added = make(chan struct{})
+}
+
+func (s *eventBuilds) service() BuildsService {
+	return MockBuildsService{
+		Get_: func(ctx context.Context, build BuildSpec, opt *BuildGetOptions) (*Build, Response, error) {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			b := s.build
+			return &b, nil, nil
+		},
+		ListEvents_: func(ctx context.Context, opt *BuildEventListOptions) ([]*BuildEvent, Response, error) {
+			for {
+				s.mu.Lock()
+				var events []*BuildEvent
+				for _, e := range s.events {
+					if e.ID > opt.After {
+						events = append(events, e)
+					}
+				}
+				added := s.added
+				s.mu.Unlock()
+				if len(events) > 0 {
+					return events, nil, nil
+				}
+				select {
+				case <-added:
+				case <-ctx.Done():
+					return nil, nil, ctx.Err()
+				}
+			}
+		},
+	}
+}
+
+func TestWatchBuildEvents(t *testing.T) {
+	s := newEventBuilds()
+	s.add(BuildEventQueued, func(b *Build) {})
+
+	w := WatchBuildEvents(context.Background(), s.service(), BuildEventListOptions{})
+	var types []BuildEventType
+	for e := range w.Events() {
+		types = append(types, e.Type)
+		switch e.Type {
+		case BuildEventQueued:
+			s.add(BuildEventStarted, func(b *Build) {})
+			s.add(BuildEventHeartbeat, func(b *Build) {})
+		case BuildEventHeartbeat:
+			w.Close()
+		}
+	}
+	if err := w.Err(); !errors.Is(err, context.Canceled) {
+		t.Errorf("got error %v, want context.Canceled", err)
+	}
+	if want := []BuildEventType{BuildEventQueued, BuildEventStarted, BuildEventHeartbeat}; !reflect.DeepEqual(types, want) {
+		t.Errorf("got event types %v, want %v", types, want)
+	}
+}
+
+func TestWaitForBuild(t *testing.T) {
+	s := newEventBuilds()
+	s.add(BuildEventStarted, func(b *Build) { b.StartedAt = logEnded })
+	go func() {
+		s.add(BuildEventHeartbeat, func(b *Build) {})
+		s.add(BuildEventFailed, func(b *Build) { b.EndedAt, b.Failure = logEnded, true })
+	}()
+
+	b, err := WaitForBuild(context.Background(), s.service(), BuildSpec{BID: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !b.EndedAt.Valid || !b.Failure {
+		t.Errorf("got build %+v, want failed build", b)
+	}
+}
+
+func TestWaitForBuild_ended(t *testing.T) {
+	s := newEventBuilds()
+	s.build.Killed = true
+
+	// The build has no events, so WaitForBuild must not watch for them.
+	b, err := WaitForBuild(context.Background(), s.service(), BuildSpec{BID: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !b.Killed {
+		t.Errorf("got build %+v, want killed build", b)
+	}
+}
@@ -0,0 +1,242 @@
+package sourcegraph
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
+)
+
+// A BuildHook is a webhook that is notified when builds of a
+// repository end. The server POSTs a JSON BuildHookPayload to its URL,
+// signed with its secret (see SignBuildHookPayload).
+type BuildHook struct {
+	// ID is the unique ID of the hook. It is set by the server.
+	ID int64 `json:",omitempty"`
+
+	// Repo is the RID of the repository whose builds the hook is
+	// notified of. It is set by the server.
+	Repo int `json:",omitempty"`
+
+	// URL is the URL that payloads are POSTed to.
+	URL string
+
+	// Secret is the key that payloads are signed with. It is only
+	// sent when creating the hook; the server does not return it.
+	Secret string `json:",omitempty"`
+
+	// CreatedAt is when the hook was created.
+	CreatedAt time.Time
+}
+
+// A BuildHookPayload is the body of the requests that are sent to
+// build hooks.
+type BuildHookPayload struct {
+	// HookID is the ID of the hook that the payload is sent to.
+	HookID int64
+
+	// Event is the event that ended the build (one of the event types
+	// whose Ended method returns true).
+	Event BuildEventType
+
+	// Build is the ended build.
+	Build *Build
+}
+
+// Headers of the requests that are sent to build hooks.
+const (
+	// BuildHookEventHeader holds the payload's event type.
+	BuildHookEventHeader = "X-Sourcegraph-Event"
+
+	// BuildHookTimestampHeader holds the time at which the request
+	// was signed, in seconds since the Unix epoch.
+	BuildHookTimestampHeader = "X-Sourcegraph-Timestamp"
+
+	// BuildHookSignatureHeader holds the payload's signature (see
+	// SignBuildHookPayload).
+	BuildHookSignatureHeader = "X-Sourcegraph-Signature"
+)
+
+// BuildHookTolerance is the maximum difference between a build hook
+// request's timestamp and the receiver's clock. Requests outside this
+// window are rejected, so that a captured request can't be replayed
+// later.
+const BuildHookTolerance = 5 * time.Minute
+
+var (
+	// ErrBuildHookSignature is returned by VerifyBuildHookSignature
+	// and ReadBuildHookPayload when a request's signature is missing
+	// or invalid.
+	ErrBuildHookSignature = errors.New("invalid build hook payload signature")
+
+	// ErrBuildHookTimestamp is returned by VerifyBuildHookSignature
+	// and ReadBuildHookPayload when a request's timestamp is missing,
+	// invalid, or outside of BuildHookTolerance.
+	ErrBuildHookTimestamp = errors.New("build hook payload timestamp is missing or outside of the tolerance window")
+)
+
+// SignBuildHookPayload returns the signature of a build hook request
+// body sent at time t: "sha256=" followed by the hex-encoded
+// HMAC-SHA256 of the Unix timestamp of t, ".", and body, keyed with
+// secret.
+func SignBuildHookPayload(secret string, t time.Time, body []byte) string {
+	return signBuildHookPayload(secret, strconv.FormatInt(t.Unix(), 10), body)
+}
+
+func signBuildHookPayload(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyBuildHookSignature checks that sig is the valid signature of
+// body and timestamp (the values of a request's signature and
+// timestamp headers; see SignBuildHookPayload), and that the
+// timestamp is within BuildHookTolerance of now. It returns
+// ErrBuildHookSignature or ErrBuildHookTimestamp if the request may
+// not be trusted.
+func VerifyBuildHookSignature(secret string, body []byte, timestamp, sig string, now time.Time) error {
+	if !hmac.Equal([]byte(sig), []byte(signBuildHookPayload(secret, timestamp, body))) {
+		return ErrBuildHookSignature
+	}
+	secs, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrBuildHookTimestamp
+	}
+	if d := now.Sub(time.Unix(secs, 0)); d > BuildHookTolerance || d < -BuildHookTolerance {
+		return ErrBuildHookTimestamp
+	}
+	return nil
+}
+
+// PostBuildHook sends payload to a build hook. It returns an error if
+// the request fails or the hook responds with a non-2xx status.
+// httpClient is used to send the request; if nil, http.DefaultClient
+// is used.
+func PostBuildHook(ctx context.Context, httpClient *http.Client, hook *BuildHook, payload *BuildHookPayload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("POST", hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set(BuildHookEventHeader, string(payload.Event))
+	now := time.Now()
+	req.Header.Set(BuildHookTimestampHeader, strconv.FormatInt(now.Unix(), 10))
+	req.Header.Set(BuildHookSignatureHeader, SignBuildHookPayload(hook.Secret, now, body))
+
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 1<<16))
+	if c := resp.StatusCode; c < 200 || c > 299 {
+		return fmt.Errorf("build hook %d (%s) responded with %s", hook.ID, hook.URL, resp.Status)
+	}
+	return nil
+}
+
+// ReadBuildHookPayload reads and verifies the payload of a request
+// sent to a build hook (in an HTTP handler that receives build hook
+// requests). It returns ErrBuildHookSignature if the request was not
+// signed with secret, and ErrBuildHookTimestamp if it was not signed
+// within BuildHookTolerance of the current time.
+func ReadBuildHookPayload(r *http.Request, secret string) (*BuildHookPayload, error) {
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, 10<<20))
+	if err != nil {
+		return nil, err
+	}
+	if err := VerifyBuildHookSignature(secret, body, r.Header.Get(BuildHookTimestampHeader), r.Header.Get(BuildHookSignatureHeader), time.Now()); err != nil {
+		return nil, err
+	}
+	var payload BuildHookPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, err
+	}
+	return &payload, nil
+}
+
+func buildHookRouteVars(repo RepoSpec, hookID int64) map[string]string {
+	v := repo.RouteVars()
+	v["HookID"] = strconv.FormatInt(hookID, 10)
+	return v
+}
+
+func (s *buildsService) ListRepoHooks(ctx context.Context, repo RepoSpec) ([]*BuildHook, Response, error) {
+	url, err := s.client.URL(router.RepoBuildHooks, repo.RouteVars(), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.client.NewRequest(ctx, "GET", url.String(), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var hooks []*BuildHook
+	resp, err := s.client.Do(req, &hooks)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return hooks, resp, nil
+}
+
+func (s *buildsService) CreateRepoHook(ctx context.Context, repo RepoSpec, hook *BuildHook) (*BuildHook, Response, error) {
+	url, err := s.client.URL(router.RepoBuildHooksCreate, repo.RouteVars(), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.client.NewRequest(ctx, "POST", url.String(), hook)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var created *BuildHook
+	resp, err := s.client.Do(req, &created)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return created, resp, nil
+}
+
+func (s *buildsService) DeleteRepoHook(ctx context.Context, repo RepoSpec, hookID int64) (Response, error) {
+	url, err := s.client.URL(router.RepoBuildHookDelete, buildHookRouteVars(repo, hookID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := s.client.NewRequest(ctx, "DELETE", url.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.Do(req, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,129 @@
+package sourcegraph
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
+)
+
+func TestVerifyBuildHookSignature(t *testing.T) {
+	now := time.Unix(1500000000, 0)
+	ts := strconv.FormatInt(now.Unix(), 10)
+	body := []byte(`{"HookID":1}`)
+	sig := SignBuildHookPayload("s", now, body)
+	tests := map[string]struct {
+		secret    string
+		body      []byte
+		timestamp string
+		sig       string
+		now       time.Time
+		wantErr   error
+	}{
+		"valid":            {"s", body, ts, sig, now, nil},
+		"within tolerance": {"s", body, ts, sig, now.Add(BuildHookTolerance), nil},
+		"clock skew":       {"s", body, ts, sig, now.Add(-BuildHookTolerance), nil},
+		"other secret":     {"t", body, ts, sig, now, ErrBuildHookSignature},
+		"other body":       {"s", []byte(`{"HookID":2}`), ts, sig, now, ErrBuildHookSignature},
+		"other timestamp":  {"s", body, strconv.FormatInt(now.Unix()+1, 10), sig, now, ErrBuildHookSignature},
+		"empty signature":  {"s", body, ts, "", now, ErrBuildHookSignature},
+		"replayed":         {"s", body, ts, sig, now.Add(BuildHookTolerance + time.Second), ErrBuildHookTimestamp},
+		"future":           {"s", body, ts, sig, now.Add(-BuildHookTolerance - time.Second), ErrBuildHookTimestamp},
+		"bad timestamp": {
+			"s", body, "x", signBuildHookPayload("s", "x", body), now, ErrBuildHookTimestamp,
+		},
+	}
+	for label, test := range tests {
+		if err := VerifyBuildHookSignature(test.secret, test.body, test.timestamp, test.sig, test.now); err != test.wantErr {
+			t.Errorf("%s: got error %v, want %v", label, err, test.wantErr)
+		}
+	}
+}
+
+func TestPostBuildHook(t *testing.T) {
+	payloads := make(chan *BuildHookPayload, 1)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.Header.Get(BuildHookEventHeader), string(BuildEventSucceeded); got != want {
+			t.Errorf("got event header %q, want %q", got, want)
+		}
+		payload, err := ReadBuildHookPayload(r, "s")
+		if err == ErrBuildHookSignature || err == ErrBuildHookTimestamp {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		payloads <- payload
+	}))
+	defer ts.Close()
+
+	want := &BuildHookPayload{HookID: 1, Event: BuildEventSucceeded, Build: &Build{BID: 2, Success: true}}
+	if err := PostBuildHook(context.Background(), nil, &BuildHook{ID: 1, URL: ts.URL, Secret: "s"}, want); err != nil {
+		t.Fatal(err)
+	}
+	payload := <-payloads
+	normalizeBuildTime(payload.Build)
+	normalizeBuildTime(want.Build)
+	if !reflect.DeepEqual(payload, want) {
+		t.Errorf("got payload %+v, want %+v", payload, want)
+	}
+
+	if err := PostBuildHook(context.Background(), nil, &BuildHook{ID: 1, URL: ts.URL, Secret: "t"}, want); err == nil {
+		t.Error("got nil error posting payload signed with wrong secret, want non-nil")
+	}
+}
+
+func TestBuildsService_CreateRepoHook(t *testing.T) {
+	setup()
+	defer teardown()
+
+	want := &BuildHook{ID: 1, Repo: 2, URL: "https://example.com"}
+
+	var called bool
+	mux.HandleFunc(urlPath(t, router.RepoBuildHooksCreate, map[string]string{"RepoSpec": "r.com/x"}), func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		testMethod(t, r, "POST")
+		testBody(t, r, `{"URL":"https://example.com","Secret":"s","CreatedAt":"0001-01-01T00:00:00Z"}`+"\n")
+
+		writeJSON(w, want)
+	})
+
+	hook, _, err := client.Builds.CreateRepoHook(context.Background(), RepoSpec{URI: "r.com/x"}, &BuildHook{URL: "https://example.com", Secret: "s"})
+	if err != nil {
+		t.Errorf("Builds.CreateRepoHook returned error: %v", err)
+	}
+
+	if !called {
+		t.Fatal("!called")
+	}
+
+	hook.CreatedAt = want.CreatedAt
+	if !reflect.DeepEqual(hook, want) {
+		t.Errorf("Builds.CreateRepoHook returned %+v, want %+v", hook, want)
+	}
+}
+
+func TestBuildsService_DeleteRepoHook(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var called bool
+	mux.HandleFunc(urlPath(t, router.RepoBuildHookDelete, map[string]string{"RepoSpec": "r.com/x", "HookID": "1"}), func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		testMethod(t, r, "DELETE")
+	})
+
+	_, err := client.Builds.DeleteRepoHook(context.Background(), RepoSpec{URI: "r.com/x"}, 1)
+	if err != nil {
+		t.Errorf("Builds.DeleteRepoHook returned error: %v", err)
+	}
+
+	if !called {
+		t.Fatal("!called")
+	}
+}
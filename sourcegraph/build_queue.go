@@ -0,0 +1,189 @@
+package sourcegraph
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/db_common"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
+)
+
+// ErrBuildNotQueued is returned by BuildsService.GetQueueStatus and
+// BuildsService.UpdatePriorities for builds that are not waiting in
+// the queue (because they are not queued, or have already started).
+var ErrBuildNotQueued = errors.New("build is not waiting in the queue")
+
+// SortBuildQueue sorts queued builds in the order in which they are
+// dequeued: builds with higher priorities come first, and builds with
+// equal priorities are dequeued in the order they were created.
+func SortBuildQueue(builds []*Build) {
+	sort.SliceStable(builds, func(i, j int) bool {
+		bi, bj := builds[i], builds[j]
+		if bi.Priority != bj.Priority {
+			return bi.Priority > bj.Priority
+		}
+		if !bi.CreatedAt.Equal(bj.CreatedAt) {
+			return bi.CreatedAt.Before(bj.CreatedAt)
+		}
+		return bi.BID < bj.BID
+	})
+}
+
+// BuildQueueStatus describes the position of a build that is waiting
+// in the queue.
+type BuildQueueStatus struct {
+	// BID is the build's BID.
+	BID int64
+
+	// Position is the build's position in the queue (counting from 1),
+	// in the order of SortBuildQueue. It does not take concurrency
+	// limits into account, so builds of other repositories may be
+	// dequeued before builds ahead of them that are held back by the
+	// limits.
+	Position int
+
+	// AheadByRepo maps the URIs of repositories to the number of their
+	// builds that are ahead of the build in the queue.
+	AheadByRepo map[string]int `json:",omitempty"`
+
+	// Running is the number of builds that are currently running.
+	Running int
+
+	// EstimatedStartAt is a rough estimate of when the build will start
+	// (see EstimateBuildStart). It is null if no builds have ended yet,
+	// so there is nothing to base an estimate on.
+	EstimatedStartAt db_common.NullTime `json:",omitempty"`
+}
+
+// EstimateBuildStart estimates how long it will take for a queued build
+// to start, given the number of builds ahead of it in the queue, the
+// number of builds that are currently running, and the average
+// duration of builds. The number of running builds is taken as the
+// number of builds that can run at once (or 1 if none are running),
+// so the estimate is rough.
+func EstimateBuildStart(ahead, running int, avgDuration time.Duration) time.Duration {
+	slots := running
+	if slots < 1 {
+		slots = 1
+	}
+	return avgDuration * time.Duration(ahead+running) / time.Duration(slots)
+}
+
+func (s *buildsService) GetQueueStatus(ctx context.Context, build BuildSpec) (*BuildQueueStatus, Response, error) {
+	url, err := s.client.URL(router.BuildQueueStatus, build.RouteVars(), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.client.NewRequest(ctx, "GET", url.String(), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var status *BuildQueueStatus
+	resp, err := s.client.Do(req, &status)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return status, resp, nil
+}
+
+// BuildPriorityUpdate specifies a change to the priorities of builds
+// that are waiting in the queue.
+type BuildPriorityUpdate struct {
+	// BIDs are the builds to update. Each of them must be waiting in
+	// the queue.
+	BIDs []int64 `json:",omitempty"`
+
+	// Repo, if set, also updates all of the builds of the repository
+	// with this URI that are waiting in the queue.
+	Repo string `json:",omitempty"`
+
+	// Priority, if set, is the new priority of the builds.
+	Priority *int `json:",omitempty"`
+
+	// Delta is added to the priority of each build if Priority is not
+	// set.
+	Delta int `json:",omitempty"`
+}
+
+func (s *buildsService) UpdatePriorities(ctx context.Context, update BuildPriorityUpdate) ([]*Build, Response, error) {
+	url, err := s.client.URL(router.BuildsUpdatePriorities, nil, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.client.NewRequest(ctx, "POST", url.String(), update)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var builds []*Build
+	resp, err := s.client.Do(req, &builds)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return builds, resp, nil
+}
+
+// BuildConcurrencyLimits limits the number of builds that run at once,
+// so that a single repository or code host can't take up all of the
+// workers. DequeueNext skips queued builds that would exceed a limit.
+// A limit of zero means no limit.
+type BuildConcurrencyLimits struct {
+	// PerRepo is the maximum number of running builds of each
+	// repository.
+	PerRepo int `json:",omitempty"`
+
+	// PerHost is the maximum number of running builds of repositories
+	// on each code host (see RepoHost).
+	PerHost int `json:",omitempty"`
+
+	// Hosts overrides PerHost for the code hosts that it contains.
+	Hosts map[string]int `json:",omitempty"`
+}
+
+// Allow reports whether build b may start while the running builds
+// are running. The builds must have their RepoURI fields set.
+func (l *BuildConcurrencyLimits) Allow(b *Build, running []*Build) bool {
+	hostLimit := l.PerHost
+	host := RepoHost(repoURI(b))
+	if n, ok := l.Hosts[host]; ok {
+		hostLimit = n
+	}
+	if l.PerRepo == 0 && hostLimit == 0 {
+		return true
+	}
+
+	var repoN, hostN int
+	for _, r := range running {
+		if r.Repo == b.Repo {
+			repoN++
+		}
+		if RepoHost(repoURI(r)) == host {
+			hostN++
+		}
+	}
+	return (l.PerRepo == 0 || repoN < l.PerRepo) && (hostLimit == 0 || hostN < hostLimit)
+}
+
+// RepoHost returns the code host of the repository with the given URI,
+// which is the URI's first path component (such as "github.com").
+func RepoHost(uri string) string {
+	if i := strings.Index(uri, "/"); i != -1 {
+		return uri[:i]
+	}
+	return uri
+}
+
+func repoURI(b *Build) string {
+	if b.RepoURI == nil {
+		return ""
+	}
+	return *b.RepoURI
+}
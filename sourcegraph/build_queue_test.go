@@ -0,0 +1,135 @@
+package sourcegraph
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
+)
+
+func TestSortBuildQueue(t *testing.T) {
+	t0 := time.Unix(100, 0)
+	builds := []*Build{
+		{BID: 1, CreatedAt: t0.Add(time.Second)},
+		{BID: 2, CreatedAt: t0, BuildConfig: BuildConfig{Priority: 1}},
+		{BID: 3, CreatedAt: t0},
+		{BID: 4, CreatedAt: t0},
+		{BID: 5, CreatedAt: t0.Add(time.Second), BuildConfig: BuildConfig{Priority: 1}},
+	}
+	SortBuildQueue(builds)
+	var bids []int64
+	for _, b := range builds {
+		bids = append(bids, b.BID)
+	}
+	if want := []int64{2, 5, 3, 4, 1}; !reflect.DeepEqual(bids, want) {
+		t.Errorf("got queue order %v, want %v", bids, want)
+	}
+}
+
+func TestEstimateBuildStart(t *testing.T) {
+	tests := []struct {
+		ahead, running int
+		want           time.Duration
+	}{
+		{ahead: 0, running: 0, want: 0},
+		{ahead: 3, running: 0, want: 3 * time.Minute},
+		{ahead: 0, running: 2, want: time.Minute},
+		{ahead: 4, running: 2, want: 3 * time.Minute},
+	}
+	for _, test := range tests {
+		if got := EstimateBuildStart(test.ahead, test.running, time.Minute); got != test.want {
+			t.Errorf("%d ahead, %d running: got %s, want %s", test.ahead, test.running, got, test.want)
+		}
+	}
+}
+
+func TestBuildConcurrencyLimits_Allow(t *testing.T) {
+	build := func(rid int, uri string) *Build { return &Build{Repo: rid, RepoURI: &uri} }
+	running := []*Build{build(1, "github.com/a/a"), build(2, "github.com/b/b"), build(3, "example.com/c")}
+
+	tests := map[string]struct {
+		limits BuildConcurrencyLimits
+		b      *Build
+		want   bool
+	}{
+		"no limits":           {b: build(1, "github.com/a/a"), want: true},
+		"repo at limit":       {limits: BuildConcurrencyLimits{PerRepo: 1}, b: build(1, "github.com/a/a"), want: false},
+		"other repo":          {limits: BuildConcurrencyLimits{PerRepo: 1}, b: build(4, "github.com/d/d"), want: true},
+		"host at limit":       {limits: BuildConcurrencyLimits{PerHost: 2}, b: build(4, "github.com/d/d"), want: false},
+		"host under limit":    {limits: BuildConcurrencyLimits{PerHost: 2}, b: build(4, "example.com/d"), want: true},
+		"host override":       {limits: BuildConcurrencyLimits{PerHost: 2, Hosts: map[string]int{"github.com": 3}}, b: build(4, "github.com/d/d"), want: true},
+		"host override limit": {limits: BuildConcurrencyLimits{Hosts: map[string]int{"example.com": 1}}, b: build(4, "example.com/d"), want: false},
+	}
+	for label, test := range tests {
+		if got := test.limits.Allow(test.b, running); got != test.want {
+			t.Errorf("%s: got %v, want %v", label, got, test.want)
+		}
+	}
+}
+
+func TestBuildsService_GetQueueStatus(t *testing.T) {
+	setup()
+	defer teardown()
+
+	want := &BuildQueueStatus{BID: 1, Position: 2, AheadByRepo: map[string]int{"r.com/x": 1}}
+
+	var called bool
+	mux.HandleFunc(urlPath(t, router.BuildQueueStatus, map[string]string{"BID": "1"}), func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		testMethod(t, r, "GET")
+
+		writeJSON(w, want)
+	})
+
+	status, _, err := client.Builds.GetQueueStatus(context.Background(), BuildSpec{BID: 1})
+	if err != nil {
+		t.Errorf("Builds.GetQueueStatus returned error: %v", err)
+	}
+
+	if !called {
+		t.Fatal("!called")
+	}
+
+	if !reflect.DeepEqual(status, want) {
+		t.Errorf("Builds.GetQueueStatus returned %+v, want %+v", status, want)
+	}
+}
+
+func TestBuildsService_UpdatePriorities(t *testing.T) {
+	setup()
+	defer teardown()
+
+	want := []*Build{{BID: 1, BuildConfig: BuildConfig{Priority: 5}}}
+
+	var called bool
+	mux.HandleFunc(urlPath(t, router.BuildsUpdatePriorities, nil), func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		testMethod(t, r, "POST")
+		testBody(t, r, `{"BIDs":[1],"Priority":5}`+"\n")
+
+		writeJSON(w, want)
+	})
+
+	priority := 5
+	builds, _, err := client.Builds.UpdatePriorities(context.Background(), BuildPriorityUpdate{BIDs: []int64{1}, Priority: &priority})
+	if err != nil {
+		t.Errorf("Builds.UpdatePriorities returned error: %v", err)
+	}
+
+	if !called {
+		t.Fatal("!called")
+	}
+
+	for _, b := range builds {
+		normalizeBuildTime(b)
+	}
+	for _, b := range want {
+		normalizeBuildTime(b)
+	}
+	if !reflect.DeepEqual(builds, want) {
+		t.Errorf("Builds.UpdatePriorities returned %+v, want %+v", builds, want)
+	}
+}
@@ -1,6 +1,7 @@
 package sourcegraph
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
@@ -16,34 +17,53 @@ import (
 // Sourcegraph API.
 type BuildsService interface {
 	// Get fetches a build.
-	Get(build BuildSpec, opt *BuildGetOptions) (*Build, Response, error)
+	Get(ctx context.Context, build BuildSpec, opt *BuildGetOptions) (*Build, Response, error)
 
 	// List builds.
-	List(opt *BuildListOptions) ([]*Build, Response, error)
+	List(ctx context.Context, opt *BuildListOptions) ([]*Build, Response, error)
 
 	// Create a new build. The build will run asynchronously (Create does not
 	// wait for it to return. To monitor the build's status, use Get.)
-	Create(repoRev RepoRevSpec, opt *BuildCreateOptions) (*Build, Response, error)
+	Create(ctx context.Context, repoRev RepoRevSpec, opt *BuildCreateOptions) (*Build, Response, error)
 
 	// Update updates information about a build and returns the build
-	// after the update has been applied.
-	Update(build BuildSpec, info BuildUpdate) (*Build, Response, error)
-
-	// ListBuildTasks lists the tasks associated with a build.
-	ListBuildTasks(build BuildSpec, opt *BuildTaskListOptions) ([]*BuildTask, Response, error)
+	// after the update has been applied. It returns ErrBuildEnded if
+	// the update sets the result of a build that has already ended
+	// (for example, because it was canceled while its worker was
+	// finishing it; see BuildUpdate.SetsResult).
+	Update(ctx context.Context, build BuildSpec, info BuildUpdate) (*Build, Response, error)
+
+	// Cancel cancels a queued or running build and returns the
+	// canceled build. The build is marked as ended and canceled (but
+	// not as failed or killed), so it is not dequeued. If it is
+	// running, its worker learns of the cancellation from the build
+	// returned by its next heartbeat Update and stops it. Cancel
+	// returns ErrBuildEnded if the build has already ended.
+	Cancel(ctx context.Context, build BuildSpec) (*Build, Response, error)
+
+	// Retry creates and returns a new build of the same commit, with
+	// the same BuildConfig and BuildMeta, as an ended (for example,
+	// failed or canceled) build. It returns ErrBuildNotEnded if the
+	// build has not ended.
+	Retry(ctx context.Context, build BuildSpec) (*Build, Response, error)
+
+	// ListBuildTasks lists the tasks associated with a build,
+	// including their dependencies (in their DependsOn fields).
+	ListBuildTasks(ctx context.Context, build BuildSpec, opt *BuildTaskListOptions) ([]*BuildTask, Response, error)
 
 	// CreateTasks creates tasks associated with a build and returns
-	// them with their TID fields set.
-	CreateTasks(build BuildSpec, tasks []*BuildTask) ([]*BuildTask, Response, error)
+	// them with their TaskID fields set (and their DependsOn fields
+	// resolved; see BuildTask.DependsOn).
+	CreateTasks(ctx context.Context, build BuildSpec, tasks []*BuildTask) ([]*BuildTask, Response, error)
 
 	// UpdateTask updates a task associated with a build.
-	UpdateTask(task TaskSpec, info TaskUpdate) (*BuildTask, Response, error)
+	UpdateTask(ctx context.Context, task TaskSpec, info TaskUpdate) (*BuildTask, Response, error)
 
 	// GetLog gets log entries associated with a build.
-	GetLog(build BuildSpec, opt *BuildGetLogOptions) (*LogEntries, Response, error)
+	GetLog(ctx context.Context, build BuildSpec, opt *BuildGetLogOptions) (*LogEntries, Response, error)
 
 	// GetTaskLog gets log entries associated with a task.
-	GetTaskLog(task TaskSpec, opt *BuildGetLogOptions) (*LogEntries, Response, error)
+	GetTaskLog(ctx context.Context, task TaskSpec, opt *BuildGetLogOptions) (*LogEntries, Response, error)
 
 	// DequeueNext returns the next queued build and marks it as
 	// having started (atomically). It is not considered an error if
@@ -52,9 +72,40 @@ type BuildsService interface {
 	//
 	// The HTTP response may contain tickets that grant the necessary
 	// permissions to build and upload build data for the build's
-	// repository. Call auth.SignedTicketStrings on the response's
-	// HTTP response field to obtain the tickets.
-	DequeueNext() (*Build, Response, error)
+	// repository. Call auth.GetSignedTicketStrings on the response's
+	// HTTP response header to obtain the tickets, and auth.ParseTicket
+	// or auth.VerifyTicket to inspect or check them.
+	//
+	// Queued builds are dequeued in the order of SortBuildQueue, except
+	// that builds that would exceed the server's concurrency limits are
+	// skipped (see BuildConcurrencyLimits).
+	DequeueNext(ctx context.Context) (*Build, Response, error)
+
+	// GetQueueStatus returns the position of a build that is waiting in
+	// the queue. It returns ErrBuildNotQueued if the build is not
+	// waiting in the queue.
+	GetQueueStatus(ctx context.Context, build BuildSpec) (*BuildQueueStatus, Response, error)
+
+	// UpdatePriorities changes the priorities of builds that are
+	// waiting in the queue, and returns the updated builds in queue
+	// order. If any of the builds in update.BIDs is not waiting in the
+	// queue, it returns ErrBuildNotQueued and updates no builds.
+	UpdatePriorities(ctx context.Context, update BuildPriorityUpdate) ([]*Build, Response, error)
+
+	// ListEvents lists build and task state transitions, oldest
+	// first. If there are none and opt.Wait is set, it waits for one
+	// to occur (see WatchBuildEvents and WaitForBuild).
+	ListEvents(ctx context.Context, opt *BuildEventListOptions) ([]*BuildEvent, Response, error)
+
+	// ListRepoHooks lists the build hooks of a repository.
+	ListRepoHooks(ctx context.Context, repo RepoSpec) ([]*BuildHook, Response, error)
+
+	// CreateRepoHook creates a build hook that is notified when builds
+	// of a repository end, and returns it with its ID set.
+	CreateRepoHook(ctx context.Context, repo RepoSpec, hook *BuildHook) (*BuildHook, Response, error)
+
+	// DeleteRepoHook deletes a build hook of a repository.
+	DeleteRepoHook(ctx context.Context, repo RepoSpec, hookID int64) (Response, error)
 }
 
 type buildsService struct {
@@ -146,6 +197,11 @@ type Build struct {
 	// for lack of a heartbeat.
 	Killed bool `json:",omitempty"`
 
+	// Canceled is true if this build was canceled (with
+	// BuildsService.Cancel) before it ended. A canceled build has
+	// EndedAt set, but Success, Failure and Killed are all false.
+	Canceled bool `json:",omitempty"`
+
 	// Host is the hostname of the machine that is working on this build.
 	Host string `json:",omitempty"`
 
@@ -194,8 +250,23 @@ type BuildTask struct {
 	// Order is the order in which this task is performed, relative to other
 	// tasks in the same build. Lower-number-ordered tasks are built first.
 	// Multiple tasks may have the same order.
+	//
+	// If tasks declare dependencies (with DependsOn), those take
+	// precedence: Order only orders the tasks whose dependencies have
+	// all been performed (see TaskScheduler).
 	Order int `json:",omitempty"`
 
+	// DependsOn lists the TaskIDs of the tasks in the same build that
+	// must be performed before this task.
+	//
+	// When tasks are created with CreateTasks, a negative entry -n
+	// refers to the nth task (counting from 1) of the same call, whose
+	// TaskID is not yet known; the created tasks have these entries
+	// replaced with TaskIDs. Dependencies that form a cycle are
+	// rejected with ErrTaskDepCycle, and dependencies on nonexistent
+	// tasks with ErrTaskDepNotExist.
+	DependsOn []int64 `db:"-" json:",omitempty"`
+
 	// CreatedAt is when this task was initially created.
 	CreatedAt db_common.NullTime `db:"created_at"`
 
@@ -308,15 +379,26 @@ type BuildCreateOptions struct {
 
 var ErrBuildNotFound = errors.New("build not found")
 
+var (
+	// ErrBuildEnded is returned by BuildsService.Cancel for builds
+	// that have already ended, and by BuildsService.Update for updates
+	// that set the result of builds that have already ended.
+	ErrBuildEnded = errors.New("build has already ended")
+
+	// ErrBuildNotEnded is returned by BuildsService.Retry for builds
+	// that have not ended yet.
+	ErrBuildNotEnded = errors.New("build has not ended")
+)
+
 type BuildGetOptions struct{}
 
-func (s *buildsService) Get(build BuildSpec, opt *BuildGetOptions) (*Build, Response, error) {
+func (s *buildsService) Get(ctx context.Context, build BuildSpec, opt *BuildGetOptions) (*Build, Response, error) {
 	url, err := s.client.URL(router.Build, build.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", url.String(), nil)
+	req, err := s.client.NewRequest(ctx, "GET", url.String(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -336,6 +418,7 @@ type BuildListOptions struct {
 	Ended     bool `url:",omitempty"`
 	Succeeded bool `url:",omitempty"`
 	Failed    bool `url:",omitempty"`
+	Canceled  bool `url:",omitempty"`
 
 	Purged bool `url:",omitempty"`
 
@@ -348,13 +431,13 @@ type BuildListOptions struct {
 	ListOptions
 }
 
-func (s *buildsService) List(opt *BuildListOptions) ([]*Build, Response, error) {
+func (s *buildsService) List(ctx context.Context, opt *BuildListOptions) ([]*Build, Response, error) {
 	url, err := s.client.URL(router.Builds, nil, opt)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", url.String(), nil)
+	req, err := s.client.NewRequest(ctx, "GET", url.String(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -368,13 +451,13 @@ func (s *buildsService) List(opt *BuildListOptions) ([]*Build, Response, error)
 	return builds, resp, nil
 }
 
-func (s *buildsService) Create(repoRev RepoRevSpec, opt *BuildCreateOptions) (*Build, Response, error) {
+func (s *buildsService) Create(ctx context.Context, repoRev RepoRevSpec, opt *BuildCreateOptions) (*Build, Response, error) {
 	url, err := s.client.URL(router.RepoBuildsCreate, repoRev.RouteVars(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("POST", url.String(), opt)
+	req, err := s.client.NewRequest(ctx, "POST", url.String(), opt)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -390,13 +473,53 @@ func (s *buildsService) Create(repoRev RepoRevSpec, opt *BuildCreateOptions) (*B
 
 type BuildTaskListOptions struct{ ListOptions }
 
-func (s *buildsService) ListBuildTasks(build BuildSpec, opt *BuildTaskListOptions) ([]*BuildTask, Response, error) {
+func (s *buildsService) Cancel(ctx context.Context, build BuildSpec) (*Build, Response, error) {
+	url, err := s.client.URL(router.BuildCancel, build.RouteVars(), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.client.NewRequest(ctx, "POST", url.String(), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var canceled *Build
+	resp, err := s.client.Do(req, &canceled)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return canceled, resp, nil
+}
+
+func (s *buildsService) Retry(ctx context.Context, build BuildSpec) (*Build, Response, error) {
+	url, err := s.client.URL(router.BuildRetry, build.RouteVars(), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.client.NewRequest(ctx, "POST", url.String(), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var retried *Build
+	resp, err := s.client.Do(req, &retried)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return retried, resp, nil
+}
+
+func (s *buildsService) ListBuildTasks(ctx context.Context, build BuildSpec, opt *BuildTaskListOptions) ([]*BuildTask, Response, error) {
 	url, err := s.client.URL(router.BuildTasks, build.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", url.String(), nil)
+	req, err := s.client.NewRequest(ctx, "GET", url.String(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -424,13 +547,20 @@ type BuildUpdate struct {
 	Priority    *int
 }
 
-func (s *buildsService) Update(build BuildSpec, info BuildUpdate) (*Build, Response, error) {
+// SetsResult reports whether u sets the result of a build (when it
+// ended, and whether it succeeded, failed, or was killed). Such
+// updates are rejected for builds that have already ended.
+func (u *BuildUpdate) SetsResult() bool {
+	return u.EndedAt != nil || u.Success != nil || u.Failure != nil || u.Killed != nil
+}
+
+func (s *buildsService) Update(ctx context.Context, build BuildSpec, info BuildUpdate) (*Build, Response, error) {
 	url, err := s.client.URL(router.BuildUpdate, build.RouteVars(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("PUT", url.String(), info)
+	req, err := s.client.NewRequest(ctx, "PUT", url.String(), info)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -444,13 +574,13 @@ func (s *buildsService) Update(build BuildSpec, info BuildUpdate) (*Build, Respo
 	return updated, resp, nil
 }
 
-func (s *buildsService) CreateTasks(build BuildSpec, tasks []*BuildTask) ([]*BuildTask, Response, error) {
+func (s *buildsService) CreateTasks(ctx context.Context, build BuildSpec, tasks []*BuildTask) ([]*BuildTask, Response, error) {
 	url, err := s.client.URL(router.BuildTasksCreate, build.RouteVars(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("POST", url.String(), tasks)
+	req, err := s.client.NewRequest(ctx, "POST", url.String(), tasks)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -473,13 +603,13 @@ type TaskUpdate struct {
 	Failure   *bool
 }
 
-func (s *buildsService) UpdateTask(task TaskSpec, info TaskUpdate) (*BuildTask, Response, error) {
+func (s *buildsService) UpdateTask(ctx context.Context, task TaskSpec, info TaskUpdate) (*BuildTask, Response, error) {
 	url, err := s.client.URL(router.BuildTaskUpdate, task.RouteVars(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("PUT", url.String(), info)
+	req, err := s.client.NewRequest(ctx, "PUT", url.String(), info)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -508,13 +638,13 @@ type LogEntries struct {
 	Entries []string
 }
 
-func (s *buildsService) GetLog(build BuildSpec, opt *BuildGetLogOptions) (*LogEntries, Response, error) {
+func (s *buildsService) GetLog(ctx context.Context, build BuildSpec, opt *BuildGetLogOptions) (*LogEntries, Response, error) {
 	url, err := s.client.URL(router.BuildLog, build.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", url.String(), nil)
+	req, err := s.client.NewRequest(ctx, "GET", url.String(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -528,13 +658,13 @@ func (s *buildsService) GetLog(build BuildSpec, opt *BuildGetLogOptions) (*LogEn
 	return entries, resp, nil
 }
 
-func (s *buildsService) GetTaskLog(task TaskSpec, opt *BuildGetLogOptions) (*LogEntries, Response, error) {
+func (s *buildsService) GetTaskLog(ctx context.Context, task TaskSpec, opt *BuildGetLogOptions) (*LogEntries, Response, error) {
 	url, err := s.client.URL(router.BuildTaskLog, task.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", url.String(), nil)
+	req, err := s.client.NewRequest(ctx, "GET", url.String(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -548,13 +678,13 @@ func (s *buildsService) GetTaskLog(task TaskSpec, opt *BuildGetLogOptions) (*Log
 	return entries, resp, nil
 }
 
-func (s *buildsService) DequeueNext() (*Build, Response, error) {
+func (s *buildsService) DequeueNext(ctx context.Context) (*Build, Response, error) {
 	url, err := s.client.URL(router.BuildDequeueNext, nil, nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("POST", url.String(), nil)
+	req, err := s.client.NewRequest(ctx, "POST", url.String(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -1,52 +1,97 @@
 package sourcegraph
 
+import "context"
+
 type MockBuildsService struct {
-	Get_            func(build BuildSpec, opt *BuildGetOptions) (*Build, Response, error)
-	List_           func(opt *BuildListOptions) ([]*Build, Response, error)
-	Create_         func(repoRev RepoRevSpec, opt *BuildCreateOptions) (*Build, Response, error)
-	Update_         func(build BuildSpec, info BuildUpdate) (*Build, Response, error)
-	ListBuildTasks_ func(build BuildSpec, opt *BuildTaskListOptions) ([]*BuildTask, Response, error)
-	CreateTasks_    func(build BuildSpec, tasks []*BuildTask) ([]*BuildTask, Response, error)
-	UpdateTask_     func(task TaskSpec, info TaskUpdate) (*BuildTask, Response, error)
-	GetLog_         func(build BuildSpec, opt *BuildGetLogOptions) (*LogEntries, Response, error)
-	GetTaskLog_     func(task TaskSpec, opt *BuildGetLogOptions) (*LogEntries, Response, error)
-	DequeueNext_    func() (*Build, Response, error)
+	Get_            func(ctx context.Context, build BuildSpec, opt *BuildGetOptions) (*Build, Response, error)
+	List_           func(ctx context.Context, opt *BuildListOptions) ([]*Build, Response, error)
+	Create_         func(ctx context.Context, repoRev RepoRevSpec, opt *BuildCreateOptions) (*Build, Response, error)
+	Update_         func(ctx context.Context, build BuildSpec, info BuildUpdate) (*Build, Response, error)
+	Cancel_         func(ctx context.Context, build BuildSpec) (*Build, Response, error)
+	Retry_          func(ctx context.Context, build BuildSpec) (*Build, Response, error)
+	ListBuildTasks_ func(ctx context.Context, build BuildSpec, opt *BuildTaskListOptions) ([]*BuildTask, Response, error)
+	CreateTasks_    func(ctx context.Context, build BuildSpec, tasks []*BuildTask) ([]*BuildTask, Response, error)
+	UpdateTask_     func(ctx context.Context, task TaskSpec, info TaskUpdate) (*BuildTask, Response, error)
+	GetLog_         func(ctx context.Context, build BuildSpec, opt *BuildGetLogOptions) (*LogEntries, Response, error)
+	GetTaskLog_     func(ctx context.Context, task TaskSpec, opt *BuildGetLogOptions) (*LogEntries, Response, error)
+	DequeueNext_    func(ctx context.Context) (*Build, Response, error)
+	ListEvents_     func(ctx context.Context, opt *BuildEventListOptions) ([]*BuildEvent, Response, error)
+	ListRepoHooks_  func(ctx context.Context, repo RepoSpec) ([]*BuildHook, Response, error)
+	CreateRepoHook_ func(ctx context.Context, repo RepoSpec, hook *BuildHook) (*BuildHook, Response, error)
+	DeleteRepoHook_ func(ctx context.Context, repo RepoSpec, hookID int64) (Response, error)
+
+	GetQueueStatus_   func(ctx context.Context, build BuildSpec) (*BuildQueueStatus, Response, error)
+	UpdatePriorities_ func(ctx context.Context, update BuildPriorityUpdate) ([]*Build, Response, error)
+}
+
+func (s MockBuildsService) Get(ctx context.Context, build BuildSpec, opt *BuildGetOptions) (*Build, Response, error) {
+	return s.Get_(ctx, build, opt)
+}
+
+func (s MockBuildsService) List(ctx context.Context, opt *BuildListOptions) ([]*Build, Response, error) {
+	return s.List_(ctx, opt)
+}
+
+func (s MockBuildsService) Create(ctx context.Context, repoRev RepoRevSpec, opt *BuildCreateOptions) (*Build, Response, error) {
+	return s.Create_(ctx, repoRev, opt)
+}
+
+func (s MockBuildsService) Update(ctx context.Context, build BuildSpec, info BuildUpdate) (*Build, Response, error) {
+	return s.Update_(ctx, build, info)
 }
 
-func (s MockBuildsService) Get(build BuildSpec, opt *BuildGetOptions) (*Build, Response, error) {
-	return s.Get_(build, opt)
+func (s MockBuildsService) Cancel(ctx context.Context, build BuildSpec) (*Build, Response, error) {
+	return s.Cancel_(ctx, build)
 }
 
-func (s MockBuildsService) List(opt *BuildListOptions) ([]*Build, Response, error) {
-	return s.List_(opt)
+func (s MockBuildsService) Retry(ctx context.Context, build BuildSpec) (*Build, Response, error) {
+	return s.Retry_(ctx, build)
 }
 
-func (s MockBuildsService) Create(repoRev RepoRevSpec, opt *BuildCreateOptions) (*Build, Response, error) {
-	return s.Create_(repoRev, opt)
+func (s MockBuildsService) ListBuildTasks(ctx context.Context, build BuildSpec, opt *BuildTaskListOptions) ([]*BuildTask, Response, error) {
+	return s.ListBuildTasks_(ctx, build, opt)
 }
 
-func (s MockBuildsService) Update(build BuildSpec, info BuildUpdate) (*Build, Response, error) {
-	return s.Update_(build, info)
+func (s MockBuildsService) CreateTasks(ctx context.Context, build BuildSpec, tasks []*BuildTask) ([]*BuildTask, Response, error) {
+	return s.CreateTasks_(ctx, build, tasks)
 }
 
-func (s MockBuildsService) ListBuildTasks(build BuildSpec, opt *BuildTaskListOptions) ([]*BuildTask, Response, error) {
-	return s.ListBuildTasks_(build, opt)
+func (s MockBuildsService) UpdateTask(ctx context.Context, task TaskSpec, info TaskUpdate) (*BuildTask, Response, error) {
+	return s.UpdateTask_(ctx, task, info)
 }
 
-func (s MockBuildsService) CreateTasks(build BuildSpec, tasks []*BuildTask) ([]*BuildTask, Response, error) {
-	return s.CreateTasks_(build, tasks)
+func (s MockBuildsService) GetLog(ctx context.Context, build BuildSpec, opt *BuildGetLogOptions) (*LogEntries, Response, error) {
+	return s.GetLog_(ctx, build, opt)
 }
 
-func (s MockBuildsService) UpdateTask(task TaskSpec, info TaskUpdate) (*BuildTask, Response, error) {
-	return s.UpdateTask_(task, info)
+func (s MockBuildsService) GetTaskLog(ctx context.Context, task TaskSpec, opt *BuildGetLogOptions) (*LogEntries, Response, error) {
+	return s.GetTaskLog_(ctx, task, opt)
 }
 
-func (s MockBuildsService) GetLog(build BuildSpec, opt *BuildGetLogOptions) (*LogEntries, Response, error) {
-	return s.GetLog_(build, opt)
+func (s MockBuildsService) DequeueNext(ctx context.Context) (*Build, Response, error) {
+	return s.DequeueNext_(ctx)
 }
 
-func (s MockBuildsService) GetTaskLog(task TaskSpec, opt *BuildGetLogOptions) (*LogEntries, Response, error) {
-	return s.GetTaskLog_(task, opt)
+func (s MockBuildsService) GetQueueStatus(ctx context.Context, build BuildSpec) (*BuildQueueStatus, Response, error) {
+	return s.GetQueueStatus_(ctx, build)
 }
 
-func (s MockBuildsService) DequeueNext() (*Build, Response, error) { return s.DequeueNext_() }
+func (s MockBuildsService) UpdatePriorities(ctx context.Context, update BuildPriorityUpdate) ([]*Build, Response, error) {
+	return s.UpdatePriorities_(ctx, update)
+}
+
+func (s MockBuildsService) ListEvents(ctx context.Context, opt *BuildEventListOptions) ([]*BuildEvent, Response, error) {
+	return s.ListEvents_(ctx, opt)
+}
+
+func (s MockBuildsService) ListRepoHooks(ctx context.Context, repo RepoSpec) ([]*BuildHook, Response, error) {
+	return s.ListRepoHooks_(ctx, repo)
+}
+
+func (s MockBuildsService) CreateRepoHook(ctx context.Context, repo RepoSpec, hook *BuildHook) (*BuildHook, Response, error) {
+	return s.CreateRepoHook_(ctx, repo, hook)
+}
+
+func (s MockBuildsService) DeleteRepoHook(ctx context.Context, repo RepoSpec, hookID int64) (Response, error) {
+	return s.DeleteRepoHook_(ctx, repo, hookID)
+}
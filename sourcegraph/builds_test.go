@@ -1,6 +1,7 @@
 package sourcegraph
 
 import (
+	"context"
 	"net/http"
 	"reflect"
 	"testing"
@@ -23,7 +24,7 @@ func TestBuildsService_Get(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	build, _, err := client.Builds.Get(BuildSpec{BID: 1}, nil)
+	build, _, err := client.Builds.Get(context.Background(), BuildSpec{BID: 1}, nil)
 	if err != nil {
 		t.Errorf("Builds.Get returned error: %v", err)
 	}
@@ -52,7 +53,7 @@ func TestBuildsService_List(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	builds, _, err := client.Builds.List(nil)
+	builds, _, err := client.Builds.List(context.Background(), nil)
 	if err != nil {
 		t.Errorf("Builds.List returned error: %v", err)
 	}
@@ -84,7 +85,7 @@ func TestBuildsService_Create(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	build_, _, err := client.Builds.Create(RepoRevSpec{RepoSpec: RepoSpec{URI: "r.com/x"}, Rev: "c"}, config)
+	build_, _, err := client.Builds.Create(context.Background(), RepoRevSpec{RepoSpec: RepoSpec{URI: "r.com/x"}, Rev: "c"}, config)
 	if err != nil {
 		t.Errorf("Builds.Create returned error: %v", err)
 	}
@@ -116,7 +117,7 @@ func TestBuildsService_Update(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	build, _, err := client.Builds.Update(BuildSpec{BID: 123}, update)
+	build, _, err := client.Builds.Update(context.Background(), BuildSpec{BID: 123}, update)
 	if err != nil {
 		t.Errorf("Builds.Update returned error: %v", err)
 	}
@@ -132,6 +133,66 @@ func TestBuildsService_Update(t *testing.T) {
 	}
 }
 
+func TestBuildsService_Cancel(t *testing.T) {
+	setup()
+	defer teardown()
+
+	want := &Build{BID: 123, Canceled: true}
+
+	var called bool
+	mux.HandleFunc(urlPath(t, router.BuildCancel, map[string]string{"BID": "123"}), func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		testMethod(t, r, "POST")
+
+		writeJSON(w, want)
+	})
+
+	build, _, err := client.Builds.Cancel(context.Background(), BuildSpec{BID: 123})
+	if err != nil {
+		t.Errorf("Builds.Cancel returned error: %v", err)
+	}
+
+	if !called {
+		t.Fatal("!called")
+	}
+
+	normalizeBuildTime(build)
+	normalizeBuildTime(want)
+	if !reflect.DeepEqual(build, want) {
+		t.Errorf("Builds.Cancel returned %+v, want %+v", build, want)
+	}
+}
+
+func TestBuildsService_Retry(t *testing.T) {
+	setup()
+	defer teardown()
+
+	want := &Build{BID: 124}
+
+	var called bool
+	mux.HandleFunc(urlPath(t, router.BuildRetry, map[string]string{"BID": "123"}), func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		testMethod(t, r, "POST")
+
+		writeJSON(w, want)
+	})
+
+	build, _, err := client.Builds.Retry(context.Background(), BuildSpec{BID: 123})
+	if err != nil {
+		t.Errorf("Builds.Retry returned error: %v", err)
+	}
+
+	if !called {
+		t.Fatal("!called")
+	}
+
+	normalizeBuildTime(build)
+	normalizeBuildTime(want)
+	if !reflect.DeepEqual(build, want) {
+		t.Errorf("Builds.Retry returned %+v, want %+v", build, want)
+	}
+}
+
 func TestBuildsService_UpdateTask(t *testing.T) {
 	setup()
 	defer teardown()
@@ -148,7 +209,7 @@ func TestBuildsService_UpdateTask(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	task, _, err := client.Builds.UpdateTask(TaskSpec{BuildSpec: BuildSpec{BID: 123}, TaskID: 456}, update)
+	task, _, err := client.Builds.UpdateTask(context.Background(), TaskSpec{BuildSpec: BuildSpec{BID: 123}, TaskID: 456}, update)
 	if err != nil {
 		t.Errorf("Builds.UpdateTask returned error: %v", err)
 	}
@@ -178,7 +239,7 @@ func TestBuildsService_CreateTasks(t *testing.T) {
 		writeJSON(w, create)
 	})
 
-	tasks, _, err := client.Builds.CreateTasks(BuildSpec{BID: 123}, create)
+	tasks, _, err := client.Builds.CreateTasks(context.Background(), BuildSpec{BID: 123}, create)
 	if err != nil {
 		t.Errorf("Builds.CreateTasks returned error: %v", err)
 	}
@@ -205,7 +266,7 @@ func TestBuildsService_GetLog(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	entries, _, err := client.Builds.GetLog(BuildSpec{BID: 1}, nil)
+	entries, _, err := client.Builds.GetLog(context.Background(), BuildSpec{BID: 1}, nil)
 	if err != nil {
 		t.Errorf("Builds.GetLog returned error: %v", err)
 	}
@@ -233,7 +294,7 @@ func TestBuildsService_GetTaskLog(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	entries, _, err := client.Builds.GetTaskLog(TaskSpec{BuildSpec: BuildSpec{BID: 1}, TaskID: 2}, nil)
+	entries, _, err := client.Builds.GetTaskLog(context.Background(), TaskSpec{BuildSpec: BuildSpec{BID: 1}, TaskID: 2}, nil)
 	if err != nil {
 		t.Errorf("Builds.GetTaskLog returned error: %v", err)
 	}
@@ -261,7 +322,7 @@ func TestBuildsService_DequeueNext(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	build, _, err := client.Builds.DequeueNext()
+	build, _, err := client.Builds.DequeueNext(context.Background())
 	if err != nil {
 		t.Errorf("Builds.DequeueNext returned error: %v", err)
 	}
@@ -287,7 +348,7 @@ func TestBuildsService_DequeueNext_emptyQueue(t *testing.T) {
 		w.WriteHeader(http.StatusNotFound)
 	})
 
-	build, _, err := client.Builds.DequeueNext()
+	build, _, err := client.Builds.DequeueNext(context.Background())
 	if err != nil {
 		t.Errorf("Builds.DequeueNext returned error: %v", err)
 	}
@@ -0,0 +1,325 @@
+package sourcegraph
+
+import (
+	"bufio"
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httputil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
+)
+
+// A Cache stores serialized HTTP responses, keyed on the request URL
+// and credential headers.
+// Implementations must be safe for concurrent use.
+type Cache interface {
+	// Get returns the cached response for key, if any.
+	Get(key string) (resp []byte, ok bool)
+
+	// Set stores resp in the cache under key.
+	Set(key string, resp []byte)
+
+	// Delete removes the cached response for key, if any.
+	Delete(key string)
+}
+
+// CacheStats holds counts of how a ResponseCache handled requests.
+type CacheStats struct {
+	Hits          int64 // responses served from the cache without contacting the server
+	Revalidations int64 // responses served from the cache after the server reported they were not modified
+	Misses        int64 // responses fetched from the server
+}
+
+// A ResponseCache caches the responses to GET requests made by a
+// Client (see the Client's Cache field).
+//
+// Responses from routes whose route variables contain a resolved
+// commit ID (i.e., "Rev===CommitID"; see RepoRevSpec) are immutable,
+// so they are cached permanently and served without contacting the
+// server. Other responses are cached only if they have an ETag or
+// Last-Modified header, and they are revalidated (using
+// If-None-Match or If-Modified-Since) each time they are requested.
+//
+// Responses are cached under the request's URL and its Authorization
+// and Cookie headers (see coalesceKey), so requests that carry
+// different credentials never share a cached response. Credentials
+// that are added by the Client's HTTP transport are not visible to
+// the cache, so a ResponseCache should not be shared by clients whose
+// transports authenticate as different users.
+type ResponseCache struct {
+	Cache Cache
+
+	hits, revalidations, misses int64
+}
+
+// NewResponseCache returns a ResponseCache that stores responses in
+// cache.
+func NewResponseCache(cache Cache) *ResponseCache {
+	return &ResponseCache{Cache: cache}
+}
+
+// Stats returns the number of cache hits, revalidations, and misses
+// so far.
+func (c *ResponseCache) Stats() CacheStats {
+	return CacheStats{
+		Hits:          atomic.LoadInt64(&c.hits),
+		Revalidations: atomic.LoadInt64(&c.revalidations),
+		Misses:        atomic.LoadInt64(&c.misses),
+	}
+}
+
+// mutableCommitRoutes holds the names of routes whose responses may
+// change even when the route's revision is a resolved commit ID (for
+// example, because they describe a build of the commit).
+var mutableCommitRoutes = map[string]bool{
+	router.RepoBadge:          true,
+	router.RepoBuild:          true,
+	router.RepoBuildDataEntry: true,
+	router.RepoCombinedStatus: true,
+	router.RepoStats:          true,
+}
+
+// isImmutableRoute returns whether responses from the named route
+// with the given route variables never change.
+func isImmutableRoute(name string, vars map[string]string) bool {
+	if mutableCommitRoutes[name] || !hasCommitID(vars["Rev"]) {
+		return false
+	}
+	if headRev, present := vars["DeltaHeadRev"]; present && !hasCommitID(headRev) {
+		return false
+	}
+	return true
+}
+
+// hasCommitID returns whether rev (a "Rev" route variable) contains a
+// resolved commit ID.
+func hasCommitID(rev string) bool {
+	i := strings.Index(rev, repoRevSpecCommitSep)
+	return i != -1 && i+len(repoRevSpecCommitSep) < len(rev)
+}
+
+// sendCached sends req using the client's HTTP client (see send), or
+// (if the client has a Cache) serves it from the cache when possible.
+func (c *Client) sendCached(req *http.Request) (*http.Response, error) {
+	if c.Cache == nil || req.Method != "GET" {
+		return c.send(req)
+	}
+	rc := c.Cache
+
+	key := coalesceKey(req)
+	name, vars, _ := c.matchRoute(req)
+	immutable := isImmutableRoute(name, vars)
+
+	cached := rc.get(key, req)
+	if cached != nil && immutable {
+		atomic.AddInt64(&rc.hits, 1)
+		return cached, nil
+	}
+
+	if cached != nil {
+		etag, lastMod := cached.Header.Get("etag"), cached.Header.Get("last-modified")
+		if etag != "" || lastMod != "" {
+			req = req.Clone(req.Context())
+			if etag != "" {
+				req.Header.Set("If-None-Match", etag)
+			}
+			if lastMod != "" {
+				req.Header.Set("If-Modified-Since", lastMod)
+			}
+		}
+	}
+
+	resp, err := c.send(req)
+	if err != nil {
+		return resp, err
+	}
+	if cached != nil && resp.StatusCode == http.StatusNotModified {
+		resp.Body.Close()
+		atomic.AddInt64(&rc.revalidations, 1)
+		updateCachedHeader(cached.Header, resp.Header)
+		// DumpResponse reads the body and replaces it with an
+		// equivalent in-memory reader.
+		b, err := httputil.DumpResponse(cached, true)
+		if err != nil {
+			return nil, err
+		}
+		rc.Cache.Set(key, b)
+		return cached, nil
+	}
+	atomic.AddInt64(&rc.misses, 1)
+
+	if resp.StatusCode == http.StatusOK && !strings.Contains(resp.Header.Get("cache-control"), "no-store") &&
+		(immutable || resp.Header.Get("etag") != "" || resp.Header.Get("last-modified") != "") {
+		// DumpResponse reads the body and replaces it with an
+		// equivalent in-memory reader.
+		b, err := httputil.DumpResponse(resp, true)
+		if err != nil {
+			return nil, err
+		}
+		rc.Cache.Set(key, b)
+	}
+	return resp, nil
+}
+
+// updateCachedHeader updates the header of a cached response with the
+// header of a 304 Not Modified response that revalidated it (such as
+// its Date, Cache-Control, and new validators). Headers that describe
+// the body are kept, because a 304 response has no body.
+func updateCachedHeader(cached, notModified http.Header) {
+	for k, v := range notModified {
+		switch k {
+		case "Content-Length", "Content-Encoding", "Transfer-Encoding":
+			continue
+		}
+		cached[k] = v
+	}
+}
+
+// get returns the cached response to req (whose cache key is key),
+// or nil if there is none.
+func (c *ResponseCache) get(key string, req *http.Request) *http.Response {
+	b, ok := c.Cache.Get(key)
+	if !ok {
+		return nil
+	}
+	resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(b)), req)
+	if err != nil {
+		c.Cache.Delete(key)
+		return nil
+	}
+	return resp
+}
+
+// A MemoryCache is a Cache that keeps a bounded number of responses
+// in memory, evicting the least recently used response when it is
+// full.
+type MemoryCache struct {
+	maxEntries int
+
+	mu      sync.Mutex
+	ll      *list.List // most recently used at front
+	entries map[string]*list.Element
+}
+
+type memoryCacheEntry struct {
+	key  string
+	resp []byte
+}
+
+var _ Cache = &MemoryCache{}
+
+// NewMemoryCache returns a MemoryCache that holds up to maxEntries
+// responses. If maxEntries <= 0, the number of responses is not
+// limited.
+func NewMemoryCache(maxEntries int) *MemoryCache {
+	return &MemoryCache{maxEntries: maxEntries, ll: list.New(), entries: map[string]*list.Element{}}
+}
+
+// Get implements Cache.
+func (c *MemoryCache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.ll.MoveToFront(e)
+	return e.Value.(*memoryCacheEntry).resp, true
+}
+
+// Set implements Cache.
+func (c *MemoryCache) Set(key string, resp []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[key]; ok {
+		c.ll.MoveToFront(e)
+		e.Value.(*memoryCacheEntry).resp = resp
+		return
+	}
+	c.entries[key] = c.ll.PushFront(&memoryCacheEntry{key: key, resp: resp})
+	if c.maxEntries > 0 && c.ll.Len() > c.maxEntries {
+		oldest := c.ll.Back()
+		c.ll.Remove(oldest)
+		delete(c.entries, oldest.Value.(*memoryCacheEntry).key)
+	}
+}
+
+// Delete implements Cache.
+func (c *MemoryCache) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[key]; ok {
+		c.ll.Remove(e)
+		delete(c.entries, key)
+	}
+}
+
+// Len returns the number of responses in the cache.
+func (c *MemoryCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ll.Len()
+}
+
+// A DiskCache is a Cache that stores each response in a file in a
+// directory. Errors reading and writing files are treated as cache
+// misses.
+type DiskCache struct {
+	Dir string // the directory to store responses in (created if it doesn't exist)
+}
+
+var _ Cache = DiskCache{}
+
+// NewDiskCache returns a DiskCache that stores responses in dir.
+func NewDiskCache(dir string) DiskCache { return DiskCache{Dir: dir} }
+
+func (c DiskCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.Dir, hex.EncodeToString(sum[:]))
+}
+
+// Get implements Cache.
+func (c DiskCache) Get(key string) ([]byte, bool) {
+	b, err := ioutil.ReadFile(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+	return b, true
+}
+
+// Set implements Cache.
+func (c DiskCache) Set(key string, resp []byte) {
+	if err := os.MkdirAll(c.Dir, 0700); err != nil {
+		return
+	}
+	// Write to a temporary file and rename it so that concurrent
+	// readers never see a partially written response.
+	f, err := ioutil.TempFile(c.Dir, "tmp-")
+	if err != nil {
+		return
+	}
+	_, err = f.Write(resp)
+	if err2 := f.Close(); err == nil {
+		err = err2
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), c.path(key))
+	}
+	if err != nil {
+		os.Remove(f.Name())
+	}
+}
+
+// Delete implements Cache.
+func (c DiskCache) Delete(key string) {
+	os.Remove(c.path(key))
+}
@@ -0,0 +1,242 @@
+package sourcegraph
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
+	"sourcegraph.com/sourcegraph/vcsstore/vcsclient"
+)
+
+func TestClient_Cache_immutable(t *testing.T) {
+	setup()
+	defer teardown()
+
+	client.Cache = NewResponseCache(NewMemoryCache(0))
+
+	want := &vcsclient.TreeEntry{Name: "README"}
+
+	var calls int
+	mux.HandleFunc(urlPath(t, router.RepoReadme, map[string]string{"RepoSpec": "r.com/x", "Rev": "v===c"}), func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		testMethod(t, r, "GET")
+		writeJSON(w, want)
+	})
+
+	repoRev := RepoRevSpec{RepoSpec: RepoSpec{URI: "r.com/x"}, Rev: "v", CommitID: "c"}
+	for i := 0; i < 3; i++ {
+		readme, _, err := client.Repos.GetReadme(context.Background(), repoRev)
+		if err != nil {
+			t.Fatalf("Repos.GetReadme returned error: %v", err)
+		}
+		if !reflect.DeepEqual(readme, want) {
+			t.Errorf("Repos.GetReadme returned %+v, want %+v", readme, want)
+		}
+	}
+
+	if calls != 1 {
+		t.Errorf("got %d calls, want 1", calls)
+	}
+	if stats, want := client.Cache.Stats(), (CacheStats{Hits: 2, Misses: 1}); stats != want {
+		t.Errorf("got stats %+v, want %+v", stats, want)
+	}
+}
+
+func TestClient_Cache_revalidate(t *testing.T) {
+	setup()
+	defer teardown()
+
+	client.Cache = NewResponseCache(NewMemoryCache(0))
+
+	want := &Repo{RID: 1}
+
+	var calls int
+	mux.HandleFunc(urlPath(t, router.Repo, map[string]string{"RepoSpec": "r.com/x"}), func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		testMethod(t, r, "GET")
+		etag := `"` + strconv.Itoa(want.RID) + `"`
+		if calls > 1 && r.Header.Get("If-None-Match") != etag {
+			t.Errorf("call %d: got If-None-Match %q, want %q", calls, r.Header.Get("If-None-Match"), etag)
+		}
+		w.Header().Set("ETag", etag)
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		writeJSON(w, want)
+	})
+
+	for i := 0; i < 2; i++ {
+		repo_, _, err := client.Repos.Get(context.Background(), RepoSpec{URI: "r.com/x"}, nil)
+		if err != nil {
+			t.Fatalf("Repos.Get returned error: %v", err)
+		}
+		normRepo(want)
+		if !reflect.DeepEqual(repo_, want) {
+			t.Errorf("Repos.Get returned %+v, want %+v", repo_, want)
+		}
+	}
+
+	if calls != 2 {
+		t.Errorf("got %d calls, want 2", calls)
+	}
+	if stats, want := client.Cache.Stats(), (CacheStats{Revalidations: 1, Misses: 1}); stats != want {
+		t.Errorf("got stats %+v, want %+v", stats, want)
+	}
+}
+
+func TestClient_Cache_revalidateUpdatesHeader(t *testing.T) {
+	setup()
+	defer teardown()
+
+	client.Cache = NewResponseCache(NewMemoryCache(0))
+
+	// Each 304 response has a new ETag, which must be sent on the next
+	// revalidation.
+	var calls int
+	mux.HandleFunc(urlPath(t, router.Repo, map[string]string{"RepoSpec": "r.com/x"}), func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if want := `"` + strconv.Itoa(calls-1) + `"`; calls > 1 && r.Header.Get("If-None-Match") != want {
+			t.Errorf("call %d: got If-None-Match %q, want %q", calls, r.Header.Get("If-None-Match"), want)
+		}
+		w.Header().Set("ETag", `"`+strconv.Itoa(calls)+`"`)
+		if calls > 1 {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		writeJSON(w, &Repo{RID: 1})
+	})
+
+	for i := 0; i < 3; i++ {
+		repo_, resp, err := client.Repos.Get(context.Background(), RepoSpec{URI: "r.com/x"}, nil)
+		if err != nil {
+			t.Fatalf("Repos.Get returned error: %v", err)
+		}
+		if repo_.RID != 1 {
+			t.Errorf("Repos.Get returned %+v, want RID 1", repo_)
+		}
+		if got, want := resp.(*HTTPResponse).Header.Get("ETag"), `"`+strconv.Itoa(i+1)+`"`; got != want {
+			t.Errorf("request %d: got ETag %q, want %q", i, got, want)
+		}
+	}
+
+	if stats, want := client.Cache.Stats(), (CacheStats{Revalidations: 2, Misses: 1}); stats != want {
+		t.Errorf("got stats %+v, want %+v", stats, want)
+	}
+}
+
+func TestClient_Cache_credentials(t *testing.T) {
+	setup()
+	defer teardown()
+
+	client.Cache = NewResponseCache(NewMemoryCache(0))
+
+	vars := map[string]string{"RepoSpec": "r.com/x", "Rev": "v===c"}
+	var calls int
+	mux.HandleFunc(urlPath(t, router.RepoReadme, vars), func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		writeJSON(w, &vcsclient.TreeEntry{Name: r.Header.Get("Authorization")})
+	})
+
+	get := func(auth string) string {
+		u, err := client.URL(router.RepoReadme, vars, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req, err := client.NewRequest(context.Background(), "GET", u.String(), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Authorization", auth)
+		var entry *vcsclient.TreeEntry
+		if _, err := client.Do(req, &entry); err != nil {
+			t.Fatal(err)
+		}
+		return entry.Name
+	}
+
+	// Requests with different credentials must not share a cached
+	// response, even for an immutable route.
+	for _, auth := range []string{"a", "b", "a", "b"} {
+		if got := get(auth); got != auth {
+			t.Errorf("Authorization %q: got response for %q", auth, got)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("got %d calls, want 2", calls)
+	}
+	if stats, want := client.Cache.Stats(), (CacheStats{Hits: 2, Misses: 2}); stats != want {
+		t.Errorf("got stats %+v, want %+v", stats, want)
+	}
+}
+
+func TestIsImmutableRoute(t *testing.T) {
+	tests := []struct {
+		name string
+		vars map[string]string
+		want bool
+	}{
+		{router.RepoTreeEntry, map[string]string{"RepoSpec": "r.com/x", "Rev": "v===c", "Path": "p"}, true},
+		{router.RepoTreeEntry, map[string]string{"RepoSpec": "r.com/x", "Rev": "v", "Path": "p"}, false},
+		{router.RepoTreeEntry, map[string]string{"RepoSpec": "r.com/x", "Rev": "v===", "Path": "p"}, false},
+		{router.Repo, map[string]string{"RepoSpec": "r.com/x"}, false},
+		{router.RepoBuild, map[string]string{"RepoSpec": "r.com/x", "Rev": "v===c"}, false},
+		{router.Delta, map[string]string{"RepoSpec": "r.com/x", "Rev": "a===b", "DeltaHeadRev": "c===d"}, true},
+		{router.Delta, map[string]string{"RepoSpec": "r.com/x", "Rev": "a===b", "DeltaHeadRev": "c"}, false},
+	}
+	for _, test := range tests {
+		if got := isImmutableRoute(test.name, test.vars); got != test.want {
+			t.Errorf("%s %v: got %v, want %v", test.name, test.vars, got, test.want)
+		}
+	}
+}
+
+func testCache(t *testing.T, c Cache) {
+	if _, ok := c.Get("a"); ok {
+		t.Error("Get on empty cache: got ok == true")
+	}
+	c.Set("a", []byte("1"))
+	c.Set("b", []byte("2"))
+	c.Set("a", []byte("3"))
+	if v, ok := c.Get("a"); !ok || string(v) != "3" {
+		t.Errorf("Get(a): got (%q, %v), want (%q, true)", v, ok, "3")
+	}
+	c.Delete("a")
+	if _, ok := c.Get("a"); ok {
+		t.Error("Get after Delete: got ok == true")
+	}
+	if v, ok := c.Get("b"); !ok || string(v) != "2" {
+		t.Errorf("Get(b): got (%q, %v), want (%q, true)", v, ok, "2")
+	}
+}
+
+func TestMemoryCache(t *testing.T) {
+	testCache(t, NewMemoryCache(0))
+}
+
+func TestMemoryCache_evict(t *testing.T) {
+	c := NewMemoryCache(2)
+	c.Set("a", []byte("1"))
+	c.Set("b", []byte("2"))
+	c.Get("a") // make "b" the least recently used
+	c.Set("c", []byte("3"))
+
+	if _, ok := c.Get("b"); ok {
+		t.Error("least recently used entry was not evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, ok := c.Get(key); !ok {
+			t.Errorf("entry %q was evicted", key)
+		}
+	}
+	if n := c.Len(); n != 2 {
+		t.Errorf("got Len %d, want 2", n)
+	}
+}
+
+func TestDiskCache(t *testing.T) {
+	testCache(t, NewDiskCache(t.TempDir()))
+}
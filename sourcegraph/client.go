@@ -3,6 +3,7 @@ package sourcegraph
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -13,6 +14,7 @@ import (
 	"strings"
 
 	"github.com/google/go-querystring/query"
+	muxpkg "github.com/sourcegraph/mux"
 	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
 )
 
@@ -24,20 +26,21 @@ const (
 // A Client communicates with the Sourcegraph API.
 type Client struct {
 	// Services used to communicate with different parts of the Sourcegraph API.
-	BuildData    BuildDataService
-	Builds       BuildsService
-	Deltas       DeltasService
-	Issues       IssuesService
-	Orgs         OrgsService
-	People       PeopleService
-	PullRequests PullRequestsService
-	Repos        ReposService
-	RepoTree     RepoTreeService
-	Search       SearchService
-	Units        UnitsService
-	Users        UsersService
-	Defs         DefsService
-	Markdown     MarkdownService
+	BuildArtifacts BuildArtifactsService
+	BuildData      BuildDataService
+	Builds         BuildsService
+	Deltas         DeltasService
+	Issues         IssuesService
+	Orgs           OrgsService
+	People         PeopleService
+	PullRequests   PullRequestsService
+	Repos          ReposService
+	RepoTree       RepoTreeService
+	Search         SearchService
+	Units          UnitsService
+	Users          UsersService
+	Defs           DefsService
+	Markdown       MarkdownService
 
 	// Base URL for API requests, which should have a trailing slash.
 	BaseURL *url.URL
@@ -45,6 +48,44 @@ type Client struct {
 	// User agent used for HTTP requests to the Sourcegraph API.
 	UserAgent string
 
+	// RetryPolicy, if non-nil, specifies how requests that fail with
+	// a transient error are retried by Do. If nil, requests are never
+	// retried.
+	RetryPolicy *RetryPolicy
+
+	// RateLimiter, if non-nil, limits the rate at which HTTP requests
+	// (including retries) are sent to the Sourcegraph API.
+	RateLimiter *RateLimiter
+
+	// ConcurrencyLimiter, if non-nil, limits the number of HTTP
+	// requests to the Sourcegraph API that are in flight at once.
+	ConcurrencyLimiter *ConcurrencyLimiter
+
+	// Cache, if non-nil, caches the responses to GET requests. See
+	// ResponseCache for which responses are cached and for how long.
+	Cache *ResponseCache
+
+	// CoalesceRequests is whether identical GET requests (with the
+	// same URL and credentials headers) that are in flight at the same
+	// time are sent only once, with each caller receiving its own copy
+	// of the response.
+	CoalesceRequests bool
+
+	// Renames, if non-nil, makes Do follow repository renames and
+	// redirects. When the server responds to a request for a
+	// repository with ErrRenamed or ErrRedirect, the request is
+	// retried with the repository's new URI and the rename is recorded
+	// in Renames. Requests for repositories that Renames knows were
+	// renamed are sent to the new URI.
+	Renames *RenameTable
+
+	// Middleware intercepts every call made with Do. The first
+	// Middleware in the list is the outermost (i.e., it is called
+	// first and returns last).
+	Middleware []Middleware
+
+	flights flightGroup // in-flight GET requests (if CoalesceRequests)
+
 	// HTTP client used to communicate with the Sourcegraph API.
 	httpClient *http.Client
 }
@@ -59,6 +100,7 @@ func NewClient(httpClient *http.Client) *Client {
 
 	c := new(Client)
 	c.httpClient = httpClient
+	c.BuildArtifacts = &buildArtifactsService{c}
 	c.BuildData = &buildDataService{c}
 	c.Builds = &buildsService{c}
 	c.Deltas = &deltasService{c}
@@ -89,6 +131,7 @@ var Router = router.NewAPIRouter(nil)
 // func but only during init time.
 func ResetRouter() {
 	Router = router.NewAPIRouter(nil)
+	router.ClearDescribeCache()
 }
 
 // URL generates a URL for the given route, route variables, and
@@ -100,6 +143,13 @@ func URL(route string, routeVars map[string]string, opt interface{}) (*url.URL,
 	if rt == nil {
 		return nil, fmt.Errorf("no Sourcegraph API route named %q", route)
 	}
+	ri, err := router.Describe(rt)
+	if err != nil {
+		return nil, err
+	}
+	if err := ri.CheckVars(routeVars); err != nil {
+		return nil, err
+	}
 
 	routeVarsList := make([]string, 2*len(routeVars))
 	i := 0
@@ -146,11 +196,42 @@ func (c *Client) URL(route string, routeVars map[string]string, opt interface{})
 	return url, nil
 }
 
+// MatchRoute returns the name and route variables of the API route
+// that u refers to, when requested with the given HTTP method, for an
+// API whose base URL is baseURL. It returns false if u is not under
+// baseURL or does not match any route.
+func MatchRoute(method string, u, baseURL *url.URL) (name string, vars map[string]string, ok bool) {
+	if baseURL == nil || u.Host != baseURL.Host {
+		return "", nil, false
+	}
+	basePath := strings.TrimSuffix(baseURL.Path, "/")
+	if !strings.HasPrefix(u.Path, basePath+"/") {
+		return "", nil, false
+	}
+
+	rel := *u
+	rel.Path = strings.TrimPrefix(u.Path, basePath)
+	var match muxpkg.RouteMatch
+	if !Router.Match(&http.Request{Method: method, URL: &rel}, &match) || match.Route == nil {
+		return "", nil, false
+	}
+	return match.Route.GetName(), match.Vars, true
+}
+
+// matchRoute returns the name and route variables of the API route
+// that req refers to (see MatchRoute).
+func (c *Client) matchRoute(req *http.Request) (name string, vars map[string]string, ok bool) {
+	return MatchRoute(req.Method, req.URL, c.BaseURL)
+}
+
 // NewRequest creates an API request. A relative URL can be provided in urlStr,
 // in which case it is resolved relative to the BaseURL of the Client. Relative
 // URLs should always be specified without a preceding slash. If specified, the
 // value pointed to by body is JSON encoded and included as the request body.
-func (c *Client) NewRequest(method, urlStr string, body interface{}) (*http.Request, error) {
+//
+// The request is bound to ctx, so canceling ctx (or exceeding its
+// deadline) aborts the request when it is sent with Do.
+func (c *Client) NewRequest(ctx context.Context, method, urlStr string, body interface{}) (*http.Request, error) {
 	url, err := url.Parse(urlStr)
 	if err != nil {
 		return nil, err
@@ -164,7 +245,7 @@ func (c *Client) NewRequest(method, urlStr string, body interface{}) (*http.Requ
 		}
 	}
 
-	req, err := http.NewRequest(method, url.String(), buf)
+	req, err := http.NewRequestWithContext(ctx, method, url.String(), buf)
 	if err != nil {
 		return nil, err
 	}
@@ -178,7 +259,14 @@ func newResponse(r *http.Response) Response {
 	if r == nil {
 		return nil
 	}
-	return &HTTPResponse{Response: r}
+	resp := &HTTPResponse{Response: r, RateLimit: parseRateLimit(r.Header)}
+	if links := parseLinks(r.Header); links != nil {
+		resp.FirstPage = links["first"]
+		resp.PrevPage = links["prev"]
+		resp.NextPage = links["next"]
+		resp.LastPage = links["last"]
+	}
+	return resp
 }
 
 // HTTPResponse is a wrapped HTTP response from the Sourcegraph API with
@@ -186,6 +274,16 @@ func newResponse(r *http.Response) Response {
 // implements Response.
 type HTTPResponse struct {
 	*http.Response
+
+	// RateLimit is the rate limit status reported by the server, or
+	// nil if the response did not include rate limit headers.
+	RateLimit *RateLimit
+
+	// These fields hold the page numbers of the first, previous,
+	// next, and last pages of a paginated list, as specified in the
+	// response's Link header. They are 0 if the Link header is absent
+	// or has no such link (e.g., NextPage is 0 on the last page).
+	FirstPage, PrevPage, NextPage, LastPage int
 }
 
 // TotalCount implements Response.
@@ -255,9 +353,52 @@ const preserveBody doKey = iota // when passed as v to (*Client).Do, the resp bo
 // returned as an error if an API error has occurred. If v is
 // preserveBody, then the HTTP response body is not closed by Do; the
 // caller is responsible for closing it.
+//
+// The request's context (see NewRequest) governs the whole call. If
+// it is canceled or its deadline is exceeded, the context's error is
+// returned.
+//
+// If the client has a RetryPolicy, requests that fail with a
+// transient error are retried (replaying the request body); the
+// returned response and error are those of the final attempt.
+//
+// If the client has a Cache, GET responses may be served from it. If
+// the client's CoalesceRequests is set, identical concurrent GET
+// requests share a single HTTP round trip.
+//
+// The call passes through the client's Middleware (if any) before it
+// is sent. If the client has a Renames table, repository renames and
+// redirects are followed after the Middleware.
 func (c *Client) Do(req *http.Request, v interface{}) (Response, error) {
-	var resp Response
-	rawResp, err := c.httpClient.Do(req)
+	if len(c.Middleware) == 0 && c.Renames == nil {
+		return c.do(req, v)
+	}
+	call := &Call{Request: req, Result: v}
+	call.Route, call.RouteVars, _ = c.matchRoute(req)
+	return c.callMiddleware(0, call)
+}
+
+// do sends an API request and decodes the response into v, as
+// described in the Do docs, without passing through the client's
+// Middleware.
+func (c *Client) do(req *http.Request, v interface{}) (Response, error) {
+	var (
+		resp    Response
+		rawResp *http.Response
+		err     error
+	)
+	if c.CoalesceRequests && req.Method == "GET" && v != preserveBody {
+		rawResp, err = c.sendCoalesced(req)
+	} else {
+		rawResp, err = c.sendCached(req)
+	}
+	if err != nil {
+		// Prefer the context's error (which is more useful to the
+		// caller) over the *url.Error wrapping it.
+		if ctxErr := req.Context().Err(); ctxErr != nil {
+			err = ctxErr
+		}
+	}
 	if rawResp != nil {
 		if v != preserveBody && rawResp.Body != nil {
 			defer rawResp.Body.Close()
@@ -311,18 +452,20 @@ func addOptions(u *url.URL, opt interface{}) error {
 // NewMockClient returns a mockable Client for use in tests.
 func NewMockClient() *Client {
 	return &Client{
-		BuildData:    &MockBuildDataService{},
-		Builds:       &MockBuildsService{},
-		Deltas:       &MockDeltasService{},
-		Issues:       &MockIssuesService{},
-		Orgs:         &MockOrgsService{},
-		People:       &MockPeopleService{},
-		PullRequests: &MockPullRequestsService{},
-		Repos:        &MockReposService{},
-		RepoTree:     &MockRepoTreeService{},
-		Search:       &MockSearchService{},
-		Units:        &MockUnitsService{},
-		Users:        &MockUsersService{},
-		Defs:         &MockDefsService{},
+		BuildArtifacts: &MockBuildArtifactsService{},
+		BuildData:      &MockBuildDataService{},
+		Builds:         &MockBuildsService{},
+		Deltas:         &MockDeltasService{},
+		Issues:         &MockIssuesService{},
+		Orgs:           &MockOrgsService{},
+		People:         &MockPeopleService{},
+		PullRequests:   &MockPullRequestsService{},
+		Repos:          &MockReposService{},
+		RepoTree:       &MockRepoTreeService{},
+		Search:         &MockSearchService{},
+		Units:          &MockUnitsService{},
+		Users:          &MockUsersService{},
+		Defs:           &MockDefsService{},
+		Markdown:       &MockMarkdownService{},
 	}
 }
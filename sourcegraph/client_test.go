@@ -1,6 +1,7 @@
 package sourcegraph
 
 import (
+	"context"
 	"encoding/json"
 	"io/ioutil"
 	"net/http"
@@ -141,6 +142,48 @@ func TestClient_URL(t *testing.T) {
 	}
 }
 
+func TestClient_URL_missingRouteVars(t *testing.T) {
+	c := NewClient(nil)
+	_, err := c.URL(router.RepoCommit, map[string]string{"RepoSpec": "github.com/gorilla/mux"}, nil)
+	want := &router.RouteVarsError{Route: router.RepoCommit, Missing: []string{"Rev"}}
+	if !reflect.DeepEqual(err, want) {
+		t.Errorf("got error %v, want %v", err, want)
+	}
+}
+
+func TestClient_Do_contextCanceled(t *testing.T) {
+	setup()
+	defer teardown()
+
+	unblock := make(chan struct{})
+	defer close(unblock)
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		<-unblock
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req, err := client.NewRequest(ctx, "GET", server.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan error)
+	go func() {
+		_, err := client.Do(req, nil)
+		done <- err
+	}()
+	cancel()
+
+	select {
+	case err := <-done:
+		if err != context.Canceled {
+			t.Errorf("got error %v, want %v", err, context.Canceled)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Do did not return after its context was canceled")
+	}
+}
+
 func normalizeTime(tm *time.Time) {
 	*tm = tm.In(time.UTC)
 }
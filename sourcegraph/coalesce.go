@@ -0,0 +1,123 @@
+package sourcegraph
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// coalesceKeyHeaders are the request headers that, in addition to the
+// URL, must be identical for two GET requests to be coalesced. They
+// are the headers that may carry credentials, so that requests made
+// on behalf of different users are never coalesced.
+var coalesceKeyHeaders = []string{"Authorization", "Cookie"}
+
+// coalesceKey returns the key under which req is coalesced with
+// identical concurrent requests. It is also the key under which the
+// response to req is cached (see sendCached).
+func coalesceKey(req *http.Request) string {
+	parts := []string{req.URL.String()}
+	for _, h := range coalesceKeyHeaders {
+		parts = append(parts, strings.Join(req.Header[h], "\x00"))
+	}
+	return strings.Join(parts, "\x01")
+}
+
+// A flightGroup tracks in-flight GET requests so that identical
+// concurrent requests are sent only once. The zero value is ready to
+// use.
+type flightGroup struct {
+	mu      sync.Mutex
+	flights map[string]*flight
+}
+
+// A flight is an in-flight request whose response is shared by all
+// of its waiters.
+type flight struct {
+	done    chan struct{} // closed when resp/body/err are set
+	cancel  context.CancelFunc
+	waiters int // guarded by flightGroup.mu
+
+	resp *http.Response
+	body []byte
+	err  error
+}
+
+// sendCoalesced sends req (see sendCached), or, if an identical GET
+// request is already in flight, waits for and shares its response.
+// Each caller receives its own copy of the response with an
+// independent body, so callers may decode it concurrently.
+//
+// The shared request is not bound to any single caller's context: it
+// is canceled only when all of the callers waiting for it have given
+// up.
+func (c *Client) sendCoalesced(req *http.Request) (*http.Response, error) {
+	g := &c.flights
+	key := coalesceKey(req)
+
+	g.mu.Lock()
+	f, ok := g.flights[key]
+	if ok {
+		f.waiters++
+	} else {
+		ctx, cancel := context.WithCancel(context.WithoutCancel(req.Context()))
+		f = &flight{done: make(chan struct{}), cancel: cancel, waiters: 1}
+		if g.flights == nil {
+			g.flights = map[string]*flight{}
+		}
+		g.flights[key] = f
+		go c.fly(g, key, f, req.WithContext(ctx))
+	}
+	g.mu.Unlock()
+
+	select {
+	case <-f.done:
+	case <-req.Context().Done():
+		g.mu.Lock()
+		f.waiters--
+		if f.waiters == 0 {
+			// Nobody is waiting for the response anymore.
+			f.cancel()
+			if g.flights[key] == f {
+				delete(g.flights, key)
+			}
+		}
+		g.mu.Unlock()
+		return nil, req.Context().Err()
+	}
+
+	if f.resp == nil {
+		return nil, f.err
+	}
+	resp := *f.resp
+	resp.Header = f.resp.Header.Clone()
+	resp.Body = ioutil.NopCloser(bytes.NewReader(f.body))
+	resp.Request = req
+	return &resp, f.err
+}
+
+// fly sends req and records its response (reading the whole body) in
+// f.
+func (c *Client) fly(g *flightGroup, key string, f *flight, req *http.Request) {
+	resp, err := c.sendCached(req)
+	if resp != nil {
+		var readErr error
+		f.body, readErr = ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err == nil {
+			err = readErr
+		}
+	}
+	f.resp, f.err = resp, err
+
+	g.mu.Lock()
+	if g.flights[key] == f {
+		delete(g.flights, key)
+	}
+	g.mu.Unlock()
+	f.cancel()
+	close(f.done)
+}
@@ -0,0 +1,142 @@
+package sourcegraph
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
+)
+
+// waitForWaiters blocks until n callers are waiting for the single
+// in-flight request of client.
+func waitForWaiters(t *testing.T, n int) {
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		client.flights.mu.Lock()
+		var waiters int
+		for _, f := range client.flights.flights {
+			waiters += f.waiters
+		}
+		client.flights.mu.Unlock()
+		if waiters == n {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %d waiters (got %d)", n, waiters)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestClient_CoalesceRequests(t *testing.T) {
+	setup()
+	defer teardown()
+
+	client.CoalesceRequests = true
+
+	want := &Repo{RID: 1}
+
+	var (
+		mu      sync.Mutex
+		calls   int
+		release = make(chan struct{})
+	)
+	mux.HandleFunc(urlPath(t, router.Repo, map[string]string{"RepoSpec": "r.com/x"}), func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		<-release
+		writeJSON(w, want)
+	})
+
+	const n = 5
+	repos := make([]*Repo, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			var err error
+			repos[i], _, err = client.Repos.Get(context.Background(), RepoSpec{URI: "r.com/x"}, nil)
+			if err != nil {
+				t.Errorf("Repos.Get returned error: %v", err)
+			}
+		}(i)
+	}
+	waitForWaiters(t, n)
+	close(release)
+	wg.Wait()
+
+	if calls != 1 {
+		t.Errorf("got %d calls, want 1", calls)
+	}
+	normRepo(want)
+	for i, repo := range repos {
+		if !reflect.DeepEqual(repo, want) {
+			t.Errorf("Repos.Get #%d returned %+v, want %+v", i, repo, want)
+		}
+		if i > 0 && repo == repos[0] {
+			t.Errorf("Repos.Get #%d returned the same *Repo as #0", i)
+		}
+	}
+}
+
+func TestClient_CoalesceRequests_cancel(t *testing.T) {
+	setup()
+	defer teardown()
+
+	client.CoalesceRequests = true
+
+	release := make(chan struct{})
+	mux.HandleFunc(urlPath(t, router.Repo, map[string]string{"RepoSpec": "r.com/x"}), func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		writeJSON(w, &Repo{RID: 1})
+	})
+
+	// The first caller gives up, but that must not affect the second.
+	ctx, cancel := context.WithCancel(context.Background())
+	errc := make(chan error, 2)
+	go func() {
+		_, _, err := client.Repos.Get(ctx, RepoSpec{URI: "r.com/x"}, nil)
+		errc <- err
+	}()
+	waitForWaiters(t, 1)
+	go func() {
+		_, _, err := client.Repos.Get(context.Background(), RepoSpec{URI: "r.com/x"}, nil)
+		errc <- err
+	}()
+	waitForWaiters(t, 2)
+
+	cancel()
+	if err := <-errc; err != context.Canceled {
+		t.Errorf("canceled caller: got error %v, want %v", err, context.Canceled)
+	}
+	close(release)
+	if err := <-errc; err != nil {
+		t.Errorf("other caller: got error %v", err)
+	}
+}
+
+func TestCoalesceKey(t *testing.T) {
+	newReq := func(url, auth string) *http.Request {
+		req, _ := http.NewRequest("GET", url, nil)
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		return req
+	}
+	a := coalesceKey(newReq("http://example.com/a", "x"))
+	if b := coalesceKey(newReq("http://example.com/a", "x")); a != b {
+		t.Errorf("identical requests: got different keys %q and %q", a, b)
+	}
+	if b := coalesceKey(newReq("http://example.com/a", "y")); a == b {
+		t.Error("requests with different credentials: got same key")
+	}
+	if b := coalesceKey(newReq("http://example.com/b", "x")); a == b {
+		t.Error("requests for different URLs: got same key")
+	}
+}
@@ -1,6 +1,7 @@
 package sourcegraph
 
 import (
+	"context"
 	"fmt"
 	"html/template"
 	"log"
@@ -19,31 +20,31 @@ import (
 // the Sourcegraph API.
 type DefsService interface {
 	// Get fetches a def.
-	Get(def DefSpec, opt *DefGetOptions) (*Def, Response, error)
+	Get(ctx context.Context, def DefSpec, opt *DefGetOptions) (*Def, Response, error)
 
 	// List defs.
-	List(opt *DefListOptions) ([]*Def, Response, error)
+	List(ctx context.Context, opt *DefListOptions) ([]*Def, Response, error)
 
 	// ListRefs lists references to def.
-	ListRefs(def DefSpec, opt *DefListRefsOptions) ([]*Ref, Response, error)
+	ListRefs(ctx context.Context, def DefSpec, opt *DefListRefsOptions) ([]*Ref, Response, error)
 
 	// ListExamples lists examples for def.
-	ListExamples(def DefSpec, opt *DefListExamplesOptions) ([]*Example, Response, error)
+	ListExamples(ctx context.Context, def DefSpec, opt *DefListExamplesOptions) ([]*Example, Response, error)
 
 	// ListExamples lists people who committed parts of def's definition.
-	ListAuthors(def DefSpec, opt *DefListAuthorsOptions) ([]*AugmentedDefAuthor, Response, error)
+	ListAuthors(ctx context.Context, def DefSpec, opt *DefListAuthorsOptions) ([]*AugmentedDefAuthor, Response, error)
 
 	// ListClients lists people who use def in their code.
-	ListClients(def DefSpec, opt *DefListClientsOptions) ([]*AugmentedDefClient, Response, error)
+	ListClients(ctx context.Context, def DefSpec, opt *DefListClientsOptions) ([]*AugmentedDefClient, Response, error)
 
 	// ListDependents lists repositories that use def in their code.
-	ListDependents(def DefSpec, opt *DefListDependentsOptions) ([]*AugmentedDefDependent, Response, error)
+	ListDependents(ctx context.Context, def DefSpec, opt *DefListDependentsOptions) ([]*AugmentedDefDependent, Response, error)
 
 	// ListVersions lists all available versions of a definition in
 	// the various repository commits in which it has appeared.
 	//
 	// TODO(sqs): how to deal with renames, etc.?
-	ListVersions(def DefSpec, opt *DefListVersionsOptions) ([]*Def, Response, error)
+	ListVersions(ctx context.Context, def DefSpec, opt *DefListVersionsOptions) ([]*Def, Response, error)
 }
 
 // DefSpec specifies a def.
@@ -163,13 +164,13 @@ type DefGetOptions struct {
 	Stats bool `url:",omitempty"`
 }
 
-func (s *defsService) Get(def DefSpec, opt *DefGetOptions) (*Def, Response, error) {
+func (s *defsService) Get(ctx context.Context, def DefSpec, opt *DefGetOptions) (*Def, Response, error) {
 	url, err := s.client.URL(router.Def, def.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", url.String(), nil)
+	req, err := s.client.NewRequest(ctx, "GET", url.String(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -320,13 +321,13 @@ func (o *DefListOptions) DefFilters() []store.DefFilter {
 	return fs
 }
 
-func (s *defsService) List(opt *DefListOptions) ([]*Def, Response, error) {
+func (s *defsService) List(ctx context.Context, opt *DefListOptions) ([]*Def, Response, error) {
 	url, err := s.client.URL(router.Defs, nil, opt)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", url.String(), nil)
+	req, err := s.client.NewRequest(ctx, "GET", url.String(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -358,13 +359,13 @@ type DefListRefsOptions struct {
 	ListOptions
 }
 
-func (s *defsService) ListRefs(def DefSpec, opt *DefListRefsOptions) ([]*Ref, Response, error) {
+func (s *defsService) ListRefs(ctx context.Context, def DefSpec, opt *DefListRefsOptions) ([]*Ref, Response, error) {
 	url, err := s.client.URL(router.DefRefs, def.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", url.String(), nil)
+	req, err := s.client.NewRequest(ctx, "GET", url.String(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -424,13 +425,13 @@ type DefListExamplesOptions struct {
 	ListOptions
 }
 
-func (s *defsService) ListExamples(def DefSpec, opt *DefListExamplesOptions) ([]*Example, Response, error) {
+func (s *defsService) ListExamples(ctx context.Context, def DefSpec, opt *DefListExamplesOptions) ([]*Example, Response, error) {
 	url, err := s.client.URL(router.DefExamples, def.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", url.String(), nil)
+	req, err := s.client.NewRequest(ctx, "GET", url.String(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -485,13 +486,13 @@ type DefListAuthorsOptions struct {
 	ListOptions
 }
 
-func (s *defsService) ListAuthors(def DefSpec, opt *DefListAuthorsOptions) ([]*AugmentedDefAuthor, Response, error) {
+func (s *defsService) ListAuthors(ctx context.Context, def DefSpec, opt *DefListAuthorsOptions) ([]*AugmentedDefAuthor, Response, error) {
 	url, err := s.client.URL(router.DefAuthors, def.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", url.String(), nil)
+	req, err := s.client.NewRequest(ctx, "GET", url.String(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -525,13 +526,13 @@ type DefListClientsOptions struct {
 	ListOptions
 }
 
-func (s *defsService) ListClients(def DefSpec, opt *DefListClientsOptions) ([]*AugmentedDefClient, Response, error) {
+func (s *defsService) ListClients(ctx context.Context, def DefSpec, opt *DefListClientsOptions) ([]*AugmentedDefClient, Response, error) {
 	url, err := s.client.URL(router.DefClients, def.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", url.String(), nil)
+	req, err := s.client.NewRequest(ctx, "GET", url.String(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -560,13 +561,13 @@ type DefListDependentsOptions struct {
 	ListOptions
 }
 
-func (s *defsService) ListDependents(def DefSpec, opt *DefListDependentsOptions) ([]*AugmentedDefDependent, Response, error) {
+func (s *defsService) ListDependents(ctx context.Context, def DefSpec, opt *DefListDependentsOptions) ([]*AugmentedDefDependent, Response, error) {
 	url, err := s.client.URL(router.DefDependents, def.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", url.String(), nil)
+	req, err := s.client.NewRequest(ctx, "GET", url.String(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -584,13 +585,13 @@ type DefListVersionsOptions struct {
 	ListOptions
 }
 
-func (s *defsService) ListVersions(def DefSpec, opt *DefListVersionsOptions) ([]*Def, Response, error) {
+func (s *defsService) ListVersions(ctx context.Context, def DefSpec, opt *DefListVersionsOptions) ([]*Def, Response, error) {
 	url, err := s.client.URL(router.DefVersions, def.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", url.String(), nil)
+	req, err := s.client.NewRequest(ctx, "GET", url.String(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
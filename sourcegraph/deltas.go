@@ -1,6 +1,7 @@
 package sourcegraph
 
 import (
+	"context"
 	"encoding/base64"
 	"strings"
 
@@ -17,38 +18,38 @@ import (
 // impact information, etc.
 type DeltasService interface {
 	// Get fetches a summary of a delta.
-	Get(ds DeltaSpec, opt *DeltaGetOptions) (*Delta, Response, error)
+	Get(ctx context.Context, ds DeltaSpec, opt *DeltaGetOptions) (*Delta, Response, error)
 
 	// ListUnits lists units added/changed/deleted in a delta.
-	ListUnits(ds DeltaSpec, opt *DeltaListUnitsOptions) ([]*UnitDelta, Response, error)
+	ListUnits(ctx context.Context, ds DeltaSpec, opt *DeltaListUnitsOptions) ([]*UnitDelta, Response, error)
 
 	// ListDefs lists definitions added/changed/deleted in a delta.
-	ListDefs(ds DeltaSpec, opt *DeltaListDefsOptions) (*DeltaDefs, Response, error)
+	ListDefs(ctx context.Context, ds DeltaSpec, opt *DeltaListDefsOptions) (*DeltaDefs, Response, error)
 
 	// ListDependencies lists dependencies added/changed/deleted in a
 	// delta.
-	ListDependencies(ds DeltaSpec, opt *DeltaListDependenciesOptions) (*DeltaDependencies, Response, error)
+	ListDependencies(ctx context.Context, ds DeltaSpec, opt *DeltaListDependenciesOptions) (*DeltaDependencies, Response, error)
 
 	// ListFiles fetches the file diff for a delta.
-	ListFiles(ds DeltaSpec, opt *DeltaListFilesOptions) (*DeltaFiles, Response, error)
+	ListFiles(ctx context.Context, ds DeltaSpec, opt *DeltaListFilesOptions) (*DeltaFiles, Response, error)
 
 	// ListAffectedAuthors lists authors whose code is added/deleted/changed
 	// in a delta.
-	ListAffectedAuthors(ds DeltaSpec, opt *DeltaListAffectedAuthorsOptions) ([]*DeltaAffectedPerson, Response, error)
+	ListAffectedAuthors(ctx context.Context, ds DeltaSpec, opt *DeltaListAffectedAuthorsOptions) ([]*DeltaAffectedPerson, Response, error)
 
 	// ListAffectedClients lists clients whose code is affected by a delta.
-	ListAffectedClients(ds DeltaSpec, opt *DeltaListAffectedClientsOptions) ([]*DeltaAffectedPerson, Response, error)
+	ListAffectedClients(ctx context.Context, ds DeltaSpec, opt *DeltaListAffectedClientsOptions) ([]*DeltaAffectedPerson, Response, error)
 
 	// ListAffectedDependents lists dependent repositories that are affected
 	// by a delta.
-	ListAffectedDependents(ds DeltaSpec, opt *DeltaListAffectedDependentsOptions) ([]*DeltaAffectedRepo, Response, error)
+	ListAffectedDependents(ctx context.Context, ds DeltaSpec, opt *DeltaListAffectedDependentsOptions) ([]*DeltaAffectedRepo, Response, error)
 
 	// ListReviewers lists people who are reviewing or are suggested
 	// reviewers for this delta.
-	ListReviewers(ds DeltaSpec, opt *DeltaListReviewersOptions) ([]*DeltaReviewer, Response, error)
+	ListReviewers(ctx context.Context, ds DeltaSpec, opt *DeltaListReviewersOptions) ([]*DeltaReviewer, Response, error)
 
 	// ListIncoming lists deltas that affect the given repo.
-	ListIncoming(rr RepoRevSpec, opt *DeltaListIncomingOptions) ([]*Delta, Response, error)
+	ListIncoming(ctx context.Context, rr RepoRevSpec, opt *DeltaListIncomingOptions) ([]*Delta, Response, error)
 }
 
 // deltasService implements DeltasService.
@@ -145,13 +146,13 @@ func (d *Delta) BaseAndHeadBuildsSuccessful() bool {
 // DeltaGetOptions specifies options for getting a delta.
 type DeltaGetOptions struct{}
 
-func (s *deltasService) Get(ds DeltaSpec, opt *DeltaGetOptions) (*Delta, Response, error) {
+func (s *deltasService) Get(ctx context.Context, ds DeltaSpec, opt *DeltaGetOptions) (*Delta, Response, error) {
 	url, err := s.client.URL(router.Delta, ds.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", url.String(), nil)
+	req, err := s.client.NewRequest(ctx, "GET", url.String(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -204,13 +205,13 @@ func deltaUnitLess(a, b *unit.SourceUnit) bool {
 // DeltaListUnitsOptions specifies options for ListUnits.
 type DeltaListUnitsOptions struct{}
 
-func (s *deltasService) ListUnits(ds DeltaSpec, opt *DeltaListUnitsOptions) ([]*UnitDelta, Response, error) {
+func (s *deltasService) ListUnits(ctx context.Context, ds DeltaSpec, opt *DeltaListUnitsOptions) ([]*UnitDelta, Response, error) {
 	url, err := s.client.URL(router.DeltaUnits, ds.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", url.String(), nil)
+	req, err := s.client.NewRequest(ctx, "GET", url.String(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -285,13 +286,13 @@ func deltaDefLess(a, b *Def) bool {
 	return a.UnitType < b.UnitType || (a.UnitType == b.UnitType && a.Unit < b.Unit) || (a.UnitType == b.UnitType && a.Unit == b.Unit && a.Path < b.Path)
 }
 
-func (s *deltasService) ListDefs(ds DeltaSpec, opt *DeltaListDefsOptions) (*DeltaDefs, Response, error) {
+func (s *deltasService) ListDefs(ctx context.Context, ds DeltaSpec, opt *DeltaListDefsOptions) (*DeltaDefs, Response, error) {
 	url, err := s.client.URL(router.DeltaDefs, ds.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", url.String(), nil)
+	req, err := s.client.NewRequest(ctx, "GET", url.String(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -322,13 +323,13 @@ type DeltaDependencies struct {
 	// Deleted []*Dependency
 }
 
-func (s *deltasService) ListDependencies(ds DeltaSpec, opt *DeltaListDependenciesOptions) (*DeltaDependencies, Response, error) {
+func (s *deltasService) ListDependencies(ctx context.Context, ds DeltaSpec, opt *DeltaListDependenciesOptions) (*DeltaDependencies, Response, error) {
 	url, err := s.client.URL(router.DeltaDependencies, ds.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", url.String(), nil)
+	req, err := s.client.NewRequest(ctx, "GET", url.String(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -410,13 +411,13 @@ func (d *DeltaFiles) DiffStat() diff.Stat {
 	return ds
 }
 
-func (s *deltasService) ListFiles(ds DeltaSpec, opt *DeltaListFilesOptions) (*DeltaFiles, Response, error) {
+func (s *deltasService) ListFiles(ctx context.Context, ds DeltaSpec, opt *DeltaListFilesOptions) (*DeltaFiles, Response, error) {
 	url, err := s.client.URL(router.DeltaFiles, ds.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", url.String(), nil)
+	req, err := s.client.NewRequest(ctx, "GET", url.String(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -453,13 +454,13 @@ type DeltaListAffectedAuthorsOptions struct {
 	ListOptions
 }
 
-func (s *deltasService) ListAffectedAuthors(ds DeltaSpec, opt *DeltaListAffectedAuthorsOptions) ([]*DeltaAffectedPerson, Response, error) {
+func (s *deltasService) ListAffectedAuthors(ctx context.Context, ds DeltaSpec, opt *DeltaListAffectedAuthorsOptions) ([]*DeltaAffectedPerson, Response, error) {
 	url, err := s.client.URL(router.DeltaAffectedAuthors, ds.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", url.String(), nil)
+	req, err := s.client.NewRequest(ctx, "GET", url.String(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -480,13 +481,13 @@ type DeltaListAffectedClientsOptions struct {
 	ListOptions
 }
 
-func (s *deltasService) ListAffectedClients(ds DeltaSpec, opt *DeltaListAffectedClientsOptions) ([]*DeltaAffectedPerson, Response, error) {
+func (s *deltasService) ListAffectedClients(ctx context.Context, ds DeltaSpec, opt *DeltaListAffectedClientsOptions) ([]*DeltaAffectedPerson, Response, error) {
 	url, err := s.client.URL(router.DeltaAffectedClients, ds.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", url.String(), nil)
+	req, err := s.client.NewRequest(ctx, "GET", url.String(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -524,13 +525,13 @@ type DeltaListAffectedDependentsOptions struct {
 	ListOptions
 }
 
-func (s *deltasService) ListAffectedDependents(ds DeltaSpec, opt *DeltaListAffectedDependentsOptions) ([]*DeltaAffectedRepo, Response, error) {
+func (s *deltasService) ListAffectedDependents(ctx context.Context, ds DeltaSpec, opt *DeltaListAffectedDependentsOptions) ([]*DeltaAffectedRepo, Response, error) {
 	url, err := s.client.URL(router.DeltaAffectedDependents, ds.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", url.String(), nil)
+	req, err := s.client.NewRequest(ctx, "GET", url.String(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -560,13 +561,13 @@ type DeltaListReviewersOptions struct {
 	ListOptions
 }
 
-func (s *deltasService) ListReviewers(ds DeltaSpec, opt *DeltaListReviewersOptions) ([]*DeltaReviewer, Response, error) {
+func (s *deltasService) ListReviewers(ctx context.Context, ds DeltaSpec, opt *DeltaListReviewersOptions) ([]*DeltaReviewer, Response, error) {
 	url, err := s.client.URL(router.DeltaReviewers, ds.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", url.String(), nil)
+	req, err := s.client.NewRequest(ctx, "GET", url.String(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -587,13 +588,13 @@ type DeltaListIncomingOptions struct {
 	ListOptions
 }
 
-func (s *deltasService) ListIncoming(rr RepoRevSpec, opt *DeltaListIncomingOptions) ([]*Delta, Response, error) {
+func (s *deltasService) ListIncoming(ctx context.Context, rr RepoRevSpec, opt *DeltaListIncomingOptions) ([]*Delta, Response, error) {
 	url, err := s.client.URL(router.DeltasIncoming, rr.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", url.String(), nil)
+	req, err := s.client.NewRequest(ctx, "GET", url.String(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -2,6 +2,7 @@ package sourcegraph
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -11,12 +12,174 @@ import (
 )
 
 // An ErrorResponse reports errors caused by an API request.
+//
+// If the server identified the error as one of the errors defined in
+// this package (such as ErrNotExist or ErrRenamed), Code and Details
+// describe it, and errors.Is and errors.As can be used to check for
+// that error (see Unwrap).
 type ErrorResponse struct {
 	Response *http.Response `json:",omitempty"` // HTTP response that caused this error
 	Message  string         // error message
+
+	// Code is a machine-readable error code (one of the ErrorCode*
+	// constants), or "" if the error has no code.
+	Code string `json:",omitempty"`
+
+	// Details holds the JSON-encoded fields of errors with error codes
+	// that carry data (such as ErrRenamed's OldURI and NewURI).
+	Details json.RawMessage `json:",omitempty"`
+}
+
+// Error codes that API servers send in ErrorResponse.Code.
+const (
+	ErrorCodeNotExist       = "not_exist"        // ErrNotExist
+	ErrorCodeForbidden      = "forbidden"        // ErrForbidden
+	ErrorCodeNotPersisted   = "not_persisted"    // ErrNotPersisted
+	ErrorCodeNonStandardURI = "non_standard_uri" // ErrNonStandardURI
+	ErrorCodeNoScheme       = "no_scheme"        // ErrNoScheme
+	ErrorCodeNoRepoBuild    = "no_repo_build"    // ErrNoRepoBuild
+	ErrorCodeBuildNotFound  = "build_not_found"  // ErrBuildNotFound
+	ErrorCodeBuildEnded     = "build_ended"      // ErrBuildEnded
+	ErrorCodeBuildNotEnded  = "build_not_ended"  // ErrBuildNotEnded
+	ErrorCodeUserNotExist   = "user_not_exist"   // ErrUserNotExist
+	ErrorCodeDefNotExist    = "def_not_exist"    // graph.ErrDefNotExist
+	ErrorCodeRenamed        = "renamed"          // ErrRenamed
+	ErrorCodeRedirect       = "redirect"         // ErrRedirect
+	ErrorCodeUserRenamed    = "user_renamed"     // ErrUserRenamed
+
+	ErrorCodeTaskDepCycle    = "task_dep_cycle"     // ErrTaskDepCycle
+	ErrorCodeTaskDepNotExist = "task_dep_not_exist" // ErrTaskDepNotExist
+	ErrorCodeBuildNotQueued  = "build_not_queued"   // ErrBuildNotQueued
+
+	ErrorCodeBuildArtifactNotExist     = "build_artifact_not_exist"     // ErrBuildArtifactNotExist
+	ErrorCodeBuildArtifactTooLarge     = "build_artifact_too_large"     // ErrBuildArtifactTooLarge
+	ErrorCodeBuildArtifactHashMismatch = "build_artifact_hash_mismatch" // ErrBuildArtifactHashMismatch
+)
+
+// codedErrors are the error values that have error codes. Error types
+// that carry data are handled separately by errorCode and
+// errorFromCode.
+var codedErrors = []struct {
+	code string
+	err  error
+}{
+	{ErrorCodeNotExist, ErrNotExist},
+	{ErrorCodeForbidden, ErrForbidden},
+	{ErrorCodeNotPersisted, ErrNotPersisted},
+	{ErrorCodeNonStandardURI, ErrNonStandardURI},
+	{ErrorCodeNoScheme, ErrNoScheme},
+	{ErrorCodeNoRepoBuild, ErrNoRepoBuild},
+	{ErrorCodeBuildNotFound, ErrBuildNotFound},
+	{ErrorCodeBuildEnded, ErrBuildEnded},
+	{ErrorCodeBuildNotEnded, ErrBuildNotEnded},
+	{ErrorCodeBuildNotQueued, ErrBuildNotQueued},
+	{ErrorCodeTaskDepCycle, ErrTaskDepCycle},
+	{ErrorCodeTaskDepNotExist, ErrTaskDepNotExist},
+	{ErrorCodeBuildArtifactNotExist, ErrBuildArtifactNotExist},
+	{ErrorCodeBuildArtifactTooLarge, ErrBuildArtifactTooLarge},
+	{ErrorCodeBuildArtifactHashMismatch, ErrBuildArtifactHashMismatch},
+	{ErrorCodeUserNotExist, ErrUserNotExist},
+	{ErrorCodeDefNotExist, graph.ErrDefNotExist},
 }
 
+// NewErrorResponse returns the ErrorResponse (without a Response) that
+// an API server should send to describe err. If err is or wraps one of
+// the errors defined in this package, its Code and Details are set so
+// that clients can reconstruct the error.
+func NewErrorResponse(err error) *ErrorResponse {
+	var errResp *ErrorResponse
+	if errors.As(err, &errResp) {
+		return &ErrorResponse{Message: errResp.Message, Code: errResp.Code, Details: errResp.Details}
+	}
+	r := &ErrorResponse{Message: err.Error()}
+	r.Code, r.Details = errorCode(err)
+	return r
+}
+
+// errorCode returns the error code and details for err, or "" if err
+// has no error code.
+func errorCode(err error) (code string, details json.RawMessage) {
+	for _, c := range codedErrors {
+		if errors.Is(err, c.err) {
+			return c.code, nil
+		}
+	}
+
+	var (
+		renamed     ErrRenamed
+		userRenamed ErrUserRenamed
+		redirect    ErrRedirect
+		redirectPtr *ErrRedirect
+		v           interface{}
+	)
+	switch {
+	case errors.As(err, &renamed):
+		code, v = ErrorCodeRenamed, renamed
+	case errors.As(err, &userRenamed):
+		code, v = ErrorCodeUserRenamed, userRenamed
+	case errors.As(err, &redirect):
+		code, v = ErrorCodeRedirect, redirect
+	case errors.As(err, &redirectPtr) && redirectPtr != nil:
+		code, v = ErrorCodeRedirect, *redirectPtr
+	default:
+		return "", nil
+	}
+	details, _ = json.Marshal(v)
+	return code, details
+}
+
+// errorFromCode returns the error described by an error code and its
+// details, or nil if the code is unknown or the details are invalid.
+func errorFromCode(code string, details json.RawMessage) error {
+	for _, c := range codedErrors {
+		if code == c.code {
+			return c.err
+		}
+	}
+
+	var err error
+	switch code {
+	case ErrorCodeRenamed:
+		var e ErrRenamed
+		if json.Unmarshal(details, &e) == nil {
+			err = e
+		}
+	case ErrorCodeUserRenamed:
+		var e ErrUserRenamed
+		if json.Unmarshal(details, &e) == nil {
+			err = e
+		}
+	case ErrorCodeRedirect:
+		var e ErrRedirect
+		if json.Unmarshal(details, &e) == nil {
+			err = e
+		}
+	}
+	return err
+}
+
+// errorFromMessage returns the error in this package whose message is
+// msg, or nil if there is none. It is used for responses from servers
+// that don't send error codes.
+func errorFromMessage(msg string) error {
+	for _, c := range codedErrors {
+		if msg == c.err.Error() {
+			return c.err
+		}
+	}
+	if e := ErrRedirectFromString(msg); e != nil {
+		return *e
+	}
+	return nil
+}
+
+// IsDefError returns whether err is (or describes) a
+// graph.ErrDefNotExist error.
 func IsDefError(err error) bool {
+	if errors.Is(err, graph.ErrDefNotExist) {
+		return true
+	}
+	// Servers that don't send error codes only send the message.
 	return err != nil && strings.Contains(err.Error(), graph.ErrDefNotExist.Error())
 }
 
@@ -28,6 +191,19 @@ func (r *ErrorResponse) Error() string {
 
 func (r *ErrorResponse) HTTPStatusCode() int { return r.Response.StatusCode }
 
+// Unwrap returns the error in this package that r describes, so that
+// errors.Is(err, ErrNotExist), errors.As(err, &ErrRenamed{}), etc.,
+// work on errors returned by the API client. Errors with data are
+// returned as values (ErrRenamed, ErrUserRenamed and ErrRedirect, not
+// pointers to them). If r has no Code, the error is identified by its
+// Message. Unwrap returns nil if r doesn't describe such an error.
+func (r *ErrorResponse) Unwrap() error {
+	if r.Code == "" {
+		return errorFromMessage(r.Message)
+	}
+	return errorFromCode(r.Code, r.Details)
+}
+
 // CheckResponse checks the API response for errors, and returns them if
 // present.  A response is considered an error if it has a status code outside
 // the 200 range.  API error responses are expected to have either no response
@@ -0,0 +1,99 @@
+package sourcegraph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
+)
+
+func TestErrorResponse_roundTrip(t *testing.T) {
+	tests := []error{
+		ErrNotExist,
+		ErrForbidden,
+		ErrNotPersisted,
+		ErrBuildNotFound,
+		fmt.Errorf("wrapped: %w", ErrUserNotExist),
+		ErrRenamed{OldURI: "a.com/b", NewURI: "a.com/c"},
+		ErrUserRenamed{OldLogin: "alice", NewLogin: "bob"},
+		ErrRedirect{RedirectURI: "a.com/c"},
+		&ErrRedirect{RedirectURI: "a.com/d"},
+	}
+	for _, sent := range tests {
+		setup()
+		mux.HandleFunc(urlPath(t, router.Repo, map[string]string{"RepoSpec": "a.com/b"}), func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			writeJSON(w, NewErrorResponse(sent))
+		})
+		_, _, err := client.Repos.Get(context.Background(), RepoSpec{URI: "a.com/b"}, nil)
+		teardown()
+
+		if _, ok := err.(*ErrorResponse); !ok {
+			t.Errorf("%v: got error type %T, want *ErrorResponse", sent, err)
+			continue
+		}
+		switch want := sent.(type) {
+		case ErrRenamed:
+			var got ErrRenamed
+			if !errors.As(err, &got) || got != want {
+				t.Errorf("%v: got %+v, want errors.As to find %+v", sent, got, want)
+			}
+		case ErrUserRenamed:
+			var got ErrUserRenamed
+			if !errors.As(err, &got) || got != want {
+				t.Errorf("%v: got %+v, want errors.As to find %+v", sent, got, want)
+			}
+		case ErrRedirect:
+			var got ErrRedirect
+			if !errors.As(err, &got) || got != want {
+				t.Errorf("%v: got %+v, want errors.As to find %+v", sent, got, want)
+			}
+		case *ErrRedirect:
+			var got ErrRedirect
+			if !errors.As(err, &got) || got != *want {
+				t.Errorf("%v: got %+v, want errors.As to find %+v", sent, got, *want)
+			}
+		default:
+			if want := errors.Unwrap(want); want != nil {
+				sent = want
+			}
+			if !errors.Is(err, sent) {
+				t.Errorf("%v: got error %v, want errors.Is to match", sent, err)
+			}
+		}
+	}
+}
+
+func TestErrorResponse_Unwrap(t *testing.T) {
+	tests := []struct {
+		resp *ErrorResponse
+		want error
+	}{
+		{&ErrorResponse{Message: "x"}, nil},
+		{&ErrorResponse{Message: "x", Code: "unknown"}, nil},
+		{&ErrorResponse{Message: "x", Code: ErrorCodeForbidden}, ErrForbidden},
+		{&ErrorResponse{Message: "x", Code: ErrorCodeRenamed, Details: []byte("invalid")}, nil},
+
+		// Servers that don't send error codes.
+		{&ErrorResponse{Message: ErrNotPersisted.Error()}, ErrNotPersisted},
+		{&ErrorResponse{Message: ErrRedirect{RedirectURI: "a.com/b"}.Error()}, ErrRedirect{RedirectURI: "a.com/b"}},
+	}
+	for _, test := range tests {
+		if got := test.resp.Unwrap(); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%+v: got %v, want %v", test.resp, got, test.want)
+		}
+	}
+}
+
+func TestIsDefError(t *testing.T) {
+	if !IsDefError(&ErrorResponse{Message: "x", Code: ErrorCodeDefNotExist}) {
+		t.Error("got IsDefError false for error code, want true")
+	}
+	if !IsDefError(errors.New("get def: def does not exist")) {
+		t.Error("got IsDefError false for error message, want true")
+	}
+}
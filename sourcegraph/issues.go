@@ -1,6 +1,7 @@
 package sourcegraph
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/sourcegraph/go-github/github"
@@ -15,22 +16,22 @@ import (
 // Sourcegraph API.
 type IssuesService interface {
 	// Get fetches a issue.
-	Get(issue IssueSpec, opt *IssueGetOptions) (*Issue, Response, error)
+	Get(ctx context.Context, issue IssueSpec, opt *IssueGetOptions) (*Issue, Response, error)
 
 	// List issues for a repository.
-	ListByRepo(repo RepoSpec, opt *IssueListOptions) ([]*Issue, Response, error)
+	ListByRepo(ctx context.Context, repo RepoSpec, opt *IssueListOptions) ([]*Issue, Response, error)
 
 	// ListComments lists comments on a issue.
-	ListComments(issue IssueSpec, opt *IssueListCommentsOptions) ([]*IssueComment, Response, error)
+	ListComments(ctx context.Context, issue IssueSpec, opt *IssueListCommentsOptions) ([]*IssueComment, Response, error)
 
 	// CreateComment creates a comment on an issue.
-	CreateComment(issue IssueSpec, comment *IssueComment) (*IssueComment, Response, error)
+	CreateComment(ctx context.Context, issue IssueSpec, comment *IssueComment) (*IssueComment, Response, error)
 
 	// EditComment updates a comment on an issue.
-	EditComment(issue IssueSpec, comment *IssueComment) (*IssueComment, Response, error)
+	EditComment(ctx context.Context, issue IssueSpec, comment *IssueComment) (*IssueComment, Response, error)
 
 	// DeleteComment deletes a comment on an issue.
-	DeleteComment(issue IssueSpec, commentID int) (Response, error)
+	DeleteComment(ctx context.Context, issue IssueSpec, commentID int) (Response, error)
 }
 
 // issuesService implements IssuesService.
@@ -83,13 +84,13 @@ func (r *Issue) Spec() IssueSpec {
 
 type IssueGetOptions struct{}
 
-func (s *issuesService) Get(issue IssueSpec, opt *IssueGetOptions) (*Issue, Response, error) {
+func (s *issuesService) Get(ctx context.Context, issue IssueSpec, opt *IssueGetOptions) (*Issue, Response, error) {
 	url, err := s.client.URL(router.RepoIssue, issue.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", url.String(), nil)
+	req, err := s.client.NewRequest(ctx, "GET", url.String(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -108,13 +109,13 @@ type IssueListOptions struct {
 	ListOptions
 }
 
-func (s *issuesService) ListByRepo(repo RepoSpec, opt *IssueListOptions) ([]*Issue, Response, error) {
+func (s *issuesService) ListByRepo(ctx context.Context, repo RepoSpec, opt *IssueListOptions) ([]*Issue, Response, error) {
 	url, err := s.client.URL(router.RepoIssues, repo.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", url.String(), nil)
+	req, err := s.client.NewRequest(ctx, "GET", url.String(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -149,13 +150,13 @@ func (s IssueCommentSpec) RouteVars() map[string]string {
 	return rv
 }
 
-func (s *issuesService) ListComments(issue IssueSpec, opt *IssueListCommentsOptions) ([]*IssueComment, Response, error) {
+func (s *issuesService) ListComments(ctx context.Context, issue IssueSpec, opt *IssueListCommentsOptions) ([]*IssueComment, Response, error) {
 	url, err := s.client.URL(router.RepoIssueComments, issue.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", url.String(), nil)
+	req, err := s.client.NewRequest(ctx, "GET", url.String(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -169,13 +170,13 @@ func (s *issuesService) ListComments(issue IssueSpec, opt *IssueListCommentsOpti
 	return comments, resp, nil
 }
 
-func (s *issuesService) CreateComment(issue IssueSpec, comment *IssueComment) (*IssueComment, Response, error) {
+func (s *issuesService) CreateComment(ctx context.Context, issue IssueSpec, comment *IssueComment) (*IssueComment, Response, error) {
 	url, err := s.client.URL(router.RepoIssueCommentsCreate, issue.RouteVars(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("POST", url.String(), comment)
+	req, err := s.client.NewRequest(ctx, "POST", url.String(), comment)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -189,7 +190,7 @@ func (s *issuesService) CreateComment(issue IssueSpec, comment *IssueComment) (*
 	return &createdComment, resp, nil
 }
 
-func (s *issuesService) EditComment(issue IssueSpec, comment *IssueComment) (*IssueComment, Response, error) {
+func (s *issuesService) EditComment(ctx context.Context, issue IssueSpec, comment *IssueComment) (*IssueComment, Response, error) {
 	if comment.ID == nil {
 		return nil, nil, fmt.Errorf("comment ID not specified")
 	}
@@ -199,7 +200,7 @@ func (s *issuesService) EditComment(issue IssueSpec, comment *IssueComment) (*Is
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("PATCH", url.String(), comment)
+	req, err := s.client.NewRequest(ctx, "PATCH", url.String(), comment)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -213,13 +214,13 @@ func (s *issuesService) EditComment(issue IssueSpec, comment *IssueComment) (*Is
 	return &updatedComment, resp, nil
 }
 
-func (s *issuesService) DeleteComment(issue IssueSpec, commentID int) (Response, error) {
+func (s *issuesService) DeleteComment(ctx context.Context, issue IssueSpec, commentID int) (Response, error) {
 	url, err := s.client.URL(router.RepoIssueCommentsDelete, IssueCommentSpec{Issue: issue, Comment: commentID}.RouteVars(), nil)
 	if err != nil {
 		return nil, err
 	}
 
-	req, err := s.client.NewRequest("DELETE", url.String(), nil)
+	req, err := s.client.NewRequest(ctx, "DELETE", url.String(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -1,34 +1,36 @@
 package sourcegraph
 
+import "context"
+
 type MockIssuesService struct {
-	Get_           func(issue IssueSpec, opt *IssueGetOptions) (*Issue, Response, error)
-	ListByRepo_    func(repo RepoSpec, opt *IssueListOptions) ([]*Issue, Response, error)
-	ListComments_  func(issue IssueSpec, opt *IssueListCommentsOptions) ([]*IssueComment, Response, error)
-	CreateComment_ func(issue IssueSpec, comment *IssueComment) (*IssueComment, Response, error)
-	EditComment_   func(issue IssueSpec, comment *IssueComment) (*IssueComment, Response, error)
-	DeleteComment_ func(issue IssueSpec, commentID int) (Response, error)
+	Get_           func(ctx context.Context, issue IssueSpec, opt *IssueGetOptions) (*Issue, Response, error)
+	ListByRepo_    func(ctx context.Context, repo RepoSpec, opt *IssueListOptions) ([]*Issue, Response, error)
+	ListComments_  func(ctx context.Context, issue IssueSpec, opt *IssueListCommentsOptions) ([]*IssueComment, Response, error)
+	CreateComment_ func(ctx context.Context, issue IssueSpec, comment *IssueComment) (*IssueComment, Response, error)
+	EditComment_   func(ctx context.Context, issue IssueSpec, comment *IssueComment) (*IssueComment, Response, error)
+	DeleteComment_ func(ctx context.Context, issue IssueSpec, commentID int) (Response, error)
 }
 
-func (s MockIssuesService) Get(issue IssueSpec, opt *IssueGetOptions) (*Issue, Response, error) {
-	return s.Get_(issue, opt)
+func (s MockIssuesService) Get(ctx context.Context, issue IssueSpec, opt *IssueGetOptions) (*Issue, Response, error) {
+	return s.Get_(ctx, issue, opt)
 }
 
-func (s MockIssuesService) ListByRepo(repo RepoSpec, opt *IssueListOptions) ([]*Issue, Response, error) {
-	return s.ListByRepo_(repo, opt)
+func (s MockIssuesService) ListByRepo(ctx context.Context, repo RepoSpec, opt *IssueListOptions) ([]*Issue, Response, error) {
+	return s.ListByRepo_(ctx, repo, opt)
 }
 
-func (s MockIssuesService) ListComments(issue IssueSpec, opt *IssueListCommentsOptions) ([]*IssueComment, Response, error) {
-	return s.ListComments_(issue, opt)
+func (s MockIssuesService) ListComments(ctx context.Context, issue IssueSpec, opt *IssueListCommentsOptions) ([]*IssueComment, Response, error) {
+	return s.ListComments_(ctx, issue, opt)
 }
 
-func (s MockIssuesService) CreateComment(issue IssueSpec, comment *IssueComment) (*IssueComment, Response, error) {
-	return s.CreateComment_(issue, comment)
+func (s MockIssuesService) CreateComment(ctx context.Context, issue IssueSpec, comment *IssueComment) (*IssueComment, Response, error) {
+	return s.CreateComment_(ctx, issue, comment)
 }
 
-func (s MockIssuesService) EditComment(issue IssueSpec, comment *IssueComment) (*IssueComment, Response, error) {
-	return s.EditComment_(issue, comment)
+func (s MockIssuesService) EditComment(ctx context.Context, issue IssueSpec, comment *IssueComment) (*IssueComment, Response, error) {
+	return s.EditComment_(ctx, issue, comment)
 }
 
-func (s MockIssuesService) DeleteComment(issue IssueSpec, commentID int) (Response, error) {
-	return s.DeleteComment_(issue, commentID)
+func (s MockIssuesService) DeleteComment(ctx context.Context, issue IssueSpec, commentID int) (Response, error) {
+	return s.DeleteComment_(ctx, issue, commentID)
 }
@@ -1,6 +1,7 @@
 package sourcegraph
 
 import (
+	"context"
 	"net/http"
 	"reflect"
 	"testing"
@@ -54,7 +55,7 @@ func TestIssuesService_Get(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	issue, _, err := client.Issues.Get(IssueSpec{Repo: RepoSpec{URI: "r.com/x"}, Number: 1}, nil)
+	issue, _, err := client.Issues.Get(context.Background(), IssueSpec{Repo: RepoSpec{URI: "r.com/x"}, Number: 1}, nil)
 	if err != nil {
 		t.Errorf("Issues.Get returned error: %v", err)
 	}
@@ -88,6 +89,7 @@ func TestIssuesService_ListByRepo(t *testing.T) {
 	})
 
 	issues, _, err := client.Issues.ListByRepo(
+		context.Background(),
 		repoSpec,
 		&IssueListOptions{
 			ListOptions: ListOptions{PerPage: 1, Page: 2},
@@ -126,6 +128,7 @@ func TestIssuesService_ListComments(t *testing.T) {
 	})
 
 	comments, _, err := client.Issues.ListComments(
+		context.Background(),
 		issueSpec,
 		&IssueListCommentsOptions{
 			ListOptions: ListOptions{PerPage: 1, Page: 2},
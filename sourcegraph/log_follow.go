@@ -0,0 +1,294 @@
+package sourcegraph
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// LogFollowOptions configures how build and task logs are followed.
+type LogFollowOptions struct {
+	// MinID, if set, is the ID of the last log entry that has already
+	// been read, so that following starts after it (see
+	// BuildGetLogOptions). It is ignored by FollowBuildTaskLogs.
+	MinID string
+
+	// MinInterval is the initial interval between polls for new log
+	// entries. The interval is reset to MinInterval whenever new
+	// entries arrive. If zero, 500ms is used.
+	MinInterval time.Duration
+
+	// MaxInterval is the maximum interval between polls. While no new
+	// entries arrive, the interval doubles until it reaches
+	// MaxInterval. If zero, 10s is used.
+	MaxInterval time.Duration
+}
+
+func (o *LogFollowOptions) minInterval() time.Duration {
+	if o != nil && o.MinInterval > 0 {
+		return o.MinInterval
+	}
+	return 500 * time.Millisecond
+}
+
+func (o *LogFollowOptions) maxInterval() time.Duration {
+	if o != nil && o.MaxInterval > 0 {
+		return o.MaxInterval
+	}
+	return 10 * time.Second
+}
+
+// A LogLine is a line of a build or task log.
+type LogLine struct {
+	// Task is the task whose log the line is from, or nil if the line
+	// is from the build's own log.
+	Task *BuildTask
+
+	// Text is the log entry, without a trailing newline.
+	Text string
+}
+
+// A LogFollower follows a build or task log (like "tail -f"), polling
+// for new entries until the build or task ends.
+type LogFollower struct {
+	lines  chan LogLine
+	cancel context.CancelFunc
+	err    error // set before lines is closed
+}
+
+// FollowBuildLog starts following the log of a build. It stops after
+// reading the last entries of the log once the build has ended (i.e.,
+// its EndedAt is set).
+func FollowBuildLog(ctx context.Context, s BuildsService, build BuildSpec, opt *LogFollowOptions) *LogFollower {
+	return startLogFollower(ctx, func(ctx context.Context, f *LogFollower) error {
+		ended := func(ctx context.Context) (bool, error) {
+			b, _, err := s.Get(ctx, build, nil)
+			if err != nil {
+				return false, err
+			}
+			return b.EndedAt.Valid, nil
+		}
+		fetch := func(ctx context.Context, opt *BuildGetLogOptions) (*LogEntries, Response, error) {
+			return s.GetLog(ctx, build, opt)
+		}
+		return f.follow(ctx, nil, ended, fetch, opt)
+	})
+}
+
+// FollowTaskLog starts following the log of a task. It stops after
+// reading the last entries of the log once the task or its build has
+// ended. The Task field of the lines it sends has only BID and TaskID
+// set.
+func FollowTaskLog(ctx context.Context, s BuildsService, task TaskSpec, opt *LogFollowOptions) *LogFollower {
+	return startLogFollower(ctx, func(ctx context.Context, f *LogFollower) error {
+		return f.followTask(ctx, s, &BuildTask{BID: task.BID, TaskID: task.TaskID}, opt)
+	})
+}
+
+// FollowBuildTaskLogs starts following the logs of all of a build's
+// tasks, including tasks that are created while it runs. The logs are
+// followed one at a time in order of the tasks' Order (and then
+// TaskID) fields: all lines of a task's log are delivered before the
+// lines of the next task's log. It stops once the build has ended and
+// the logs of all of its tasks have been read.
+func FollowBuildTaskLogs(ctx context.Context, s BuildsService, build BuildSpec, opt *LogFollowOptions) *LogFollower {
+	return startLogFollower(ctx, func(ctx context.Context, f *LogFollower) error {
+		var taskOpt LogFollowOptions
+		if opt != nil {
+			taskOpt = *opt
+			taskOpt.MinID = ""
+		}
+
+		followed := map[int64]bool{}
+		interval := opt.minInterval()
+		for {
+			b, _, err := s.Get(ctx, build, nil)
+			if err != nil {
+				return err
+			}
+			tasks, err := IterateBuildTasks(ctx, s, build, BuildTaskListOptions{}).All()
+			if err != nil {
+				return err
+			}
+			sortTasks(tasks)
+
+			var next *BuildTask
+			for _, task := range tasks {
+				if !followed[task.TaskID] {
+					next = task
+					break
+				}
+			}
+			if next == nil {
+				if b.EndedAt.Valid {
+					return nil
+				}
+				// Wait for more tasks to be created.
+				if err := sleepContext(ctx, interval); err != nil {
+					return err
+				}
+				interval = nextLogInterval(interval, false, opt)
+				continue
+			}
+
+			followed[next.TaskID] = true
+			interval = opt.minInterval()
+			if err := f.followTask(ctx, s, next, &taskOpt); err != nil {
+				return err
+			}
+		}
+	})
+}
+
+func startLogFollower(ctx context.Context, run func(context.Context, *LogFollower) error) *LogFollower {
+	ctx, cancel := context.WithCancel(ctx)
+	f := &LogFollower{lines: make(chan LogLine), cancel: cancel}
+	go func() {
+		defer close(f.lines)
+		defer cancel()
+		f.err = run(ctx, f)
+	}()
+	return f
+}
+
+// Lines returns the channel that the log lines are sent on. It is
+// closed when following stops, after which Err reports why.
+func (f *LogFollower) Lines() <-chan LogLine { return f.lines }
+
+// Err returns the error that stopped following, or nil if the log
+// ended. It may only be called after the Lines channel is closed.
+func (f *LogFollower) Err() error { return f.err }
+
+// Close stops following the log. The Lines channel is closed soon
+// afterward.
+func (f *LogFollower) Close() error {
+	f.cancel()
+	return nil
+}
+
+// Reader returns an io.Reader that reads the log lines (each followed
+// by a newline). Reading returns io.EOF when the log ends, or the
+// error that stopped following. The Reader and the Lines channel
+// should not both be used.
+func (f *LogFollower) Reader() io.Reader { return &logReader{f: f} }
+
+type logReader struct {
+	f   *LogFollower
+	buf []byte
+}
+
+func (r *logReader) Read(p []byte) (int, error) {
+	if len(r.buf) == 0 {
+		line, ok := <-r.f.lines
+		if !ok {
+			if err := r.f.Err(); err != nil {
+				return 0, err
+			}
+			return 0, io.EOF
+		}
+		r.buf = append(r.buf[:0], line.Text...)
+		r.buf = append(r.buf, '\n')
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+// followTask follows the log of task until the task or its build has
+// ended.
+func (f *LogFollower) followTask(ctx context.Context, s BuildsService, task *BuildTask, opt *LogFollowOptions) error {
+	ended := func(ctx context.Context) (bool, error) {
+		b, _, err := s.Get(ctx, task.Spec().BuildSpec, nil)
+		if err != nil {
+			return false, err
+		}
+		if b.EndedAt.Valid {
+			return true, nil
+		}
+		it := IterateBuildTasks(ctx, s, task.Spec().BuildSpec, BuildTaskListOptions{})
+		defer it.Close()
+		for it.Next() {
+			if t := it.Value(); t.TaskID == task.TaskID {
+				return t.EndedAt.Valid, nil
+			}
+		}
+		return false, it.Err()
+	}
+	fetch := func(ctx context.Context, opt *BuildGetLogOptions) (*LogEntries, Response, error) {
+		return s.GetTaskLog(ctx, task.Spec(), opt)
+	}
+	return f.follow(ctx, task, ended, fetch, opt)
+}
+
+// follow polls a log with fetch and sends its lines until ended
+// reports that the log is complete. It checks whether the log has
+// ended before each fetch, so that the entries that were written
+// before the end are always read.
+func (f *LogFollower) follow(ctx context.Context, task *BuildTask, ended func(context.Context) (bool, error), fetch func(context.Context, *BuildGetLogOptions) (*LogEntries, Response, error), opt *LogFollowOptions) error {
+	var minID string
+	if opt != nil {
+		minID = opt.MinID
+	}
+	interval := opt.minInterval()
+	for {
+		done, err := ended(ctx)
+		if err != nil {
+			return err
+		}
+		entries, _, err := fetch(ctx, &BuildGetLogOptions{MinID: minID})
+		if err != nil {
+			return err
+		}
+		var got bool
+		if entries != nil {
+			for _, e := range entries.Entries {
+				select {
+				case f.lines <- LogLine{Task: task, Text: e}:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+			got = len(entries.Entries) > 0
+			if entries.MaxID != "" {
+				minID = entries.MaxID
+			}
+		}
+		if done {
+			return nil
+		}
+		if got {
+			interval = opt.minInterval()
+		}
+		if err := sleepContext(ctx, interval); err != nil {
+			return err
+		}
+		interval = nextLogInterval(interval, got, opt)
+	}
+}
+
+// nextLogInterval returns the polling interval to use after interval,
+// which is reset if new entries were received and doubled (up to the
+// maximum) otherwise.
+func nextLogInterval(interval time.Duration, got bool, opt *LogFollowOptions) time.Duration {
+	if got {
+		return opt.minInterval()
+	}
+	interval *= 2
+	if max := opt.maxInterval(); interval > max {
+		interval = max
+	}
+	return interval
+}
+
+// sleepContext waits for d to elapse or ctx to be canceled, whichever
+// happens first, and returns ctx.Err() in the latter case.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
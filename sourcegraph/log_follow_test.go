@@ -0,0 +1,217 @@
+package sourcegraph
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/db_common"
+)
+
+// logBuilds is an in-memory BuildsService with a single build (BID 1)
+// whose logs use entry numbers as IDs.
+type logBuilds struct {
+	mu       sync.Mutex
+	build    Build
+	tasks    []*BuildTask
+	buildLog []string
+	taskLogs map[int64][]string
+
+	// onPoll, if set, is called (without mu held) after each log fetch.
+	onPoll func(n int)
+	polls  int
+}
+
+func newLogBuilds() *logBuilds {
+	return &logBuilds{build: Build{BID: 1}, taskLogs: map[int64][]string{}}
+}
+
+func (s *logBuilds) service() BuildsService {
+	return MockBuildsService{
+		Get_: func(ctx context.Context, build BuildSpec, opt *BuildGetOptions) (*Build, Response, error) {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			b := s.build
+			return &b, nil, nil
+		},
+		ListBuildTasks_: func(ctx context.Context, build BuildSpec, opt *BuildTaskListOptions) ([]*BuildTask, Response, error) {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			var tasks []*BuildTask
+			for _, t := range s.tasks {
+				t := *t
+				tasks = append(tasks, &t)
+			}
+			return tasks, nil, nil
+		},
+		GetLog_: func(ctx context.Context, build BuildSpec, opt *BuildGetLogOptions) (*LogEntries, Response, error) {
+			return s.fetch(func() []string { return s.buildLog }, opt)
+		},
+		GetTaskLog_: func(ctx context.Context, task TaskSpec, opt *BuildGetLogOptions) (*LogEntries, Response, error) {
+			return s.fetch(func() []string { return s.taskLogs[task.TaskID] }, opt)
+		},
+	}
+}
+
+func (s *logBuilds) fetch(log func() []string, opt *BuildGetLogOptions) (*LogEntries, Response, error) {
+	s.mu.Lock()
+	entries := log()
+	min := 0
+	if opt != nil && opt.MinID != "" {
+		min, _ = strconv.Atoi(opt.MinID)
+	}
+	e := &LogEntries{MaxID: strconv.Itoa(len(entries)), Entries: append([]string(nil), entries[min:]...)}
+	s.polls++
+	n := s.polls
+	s.mu.Unlock()
+	if s.onPoll != nil {
+		s.onPoll(n)
+	}
+	return e, nil, nil
+}
+
+func (s *logBuilds) update(f func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f()
+}
+
+var logEnded = db_common.NullTime{Time: time.Unix(1, 0), Valid: true}
+
+var fastLogFollow = &LogFollowOptions{MinInterval: time.Millisecond, MaxInterval: 2 * time.Millisecond}
+
+func TestFollowBuildLog(t *testing.T) {
+	s := newLogBuilds()
+	s.buildLog = []string{"a"}
+	s.onPoll = func(n int) {
+		s.update(func() {
+			switch n {
+			case 1:
+				s.buildLog = append(s.buildLog, "b", "c")
+			case 3:
+				s.buildLog = append(s.buildLog, "d")
+				s.build.EndedAt = logEnded
+			}
+		})
+	}
+
+	f := FollowBuildLog(context.Background(), s.service(), BuildSpec{BID: 1}, fastLogFollow)
+	var lines []string
+	for line := range f.Lines() {
+		if line.Task != nil {
+			t.Errorf("got line with task %+v, want nil", line.Task)
+		}
+		lines = append(lines, line.Text)
+	}
+	if err := f.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"a", "b", "c", "d"}; !reflect.DeepEqual(lines, want) {
+		t.Errorf("got lines %q, want %q", lines, want)
+	}
+}
+
+func TestFollowBuildLog_minID(t *testing.T) {
+	s := newLogBuilds()
+	s.buildLog = []string{"a", "b", "c"}
+	s.build.EndedAt = logEnded
+
+	opt := *fastLogFollow
+	opt.MinID = "2"
+	data, err := ioutil.ReadAll(FollowBuildLog(context.Background(), s.service(), BuildSpec{BID: 1}, &opt).Reader())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "c\n"; string(data) != want {
+		t.Errorf("got %q, want %q", data, want)
+	}
+}
+
+func TestFollowTaskLog(t *testing.T) {
+	s := newLogBuilds()
+	s.tasks = []*BuildTask{{BID: 1, TaskID: 1}}
+	s.onPoll = func(n int) {
+		s.update(func() {
+			switch n {
+			case 1:
+				s.taskLogs[1] = append(s.taskLogs[1], "a")
+			case 2:
+				s.taskLogs[1] = append(s.taskLogs[1], "b")
+				s.tasks[0].EndedAt = logEnded
+			}
+		})
+	}
+
+	data, err := ioutil.ReadAll(FollowTaskLog(context.Background(), s.service(), TaskSpec{BuildSpec: BuildSpec{BID: 1}, TaskID: 1}, fastLogFollow).Reader())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "a\nb\n"; string(data) != want {
+		t.Errorf("got %q, want %q", data, want)
+	}
+}
+
+func TestFollowBuildTaskLogs(t *testing.T) {
+	s := newLogBuilds()
+	s.tasks = []*BuildTask{{BID: 1, TaskID: 2, Order: 1}, {BID: 1, TaskID: 1, Order: 2}}
+	s.taskLogs[1] = []string{"t1a"}
+	s.taskLogs[2] = []string{"t2a"}
+	s.onPoll = func(n int) {
+		s.update(func() {
+			switch n {
+			case 1:
+				// Task 1 logs while task 2 is still being followed.
+				s.taskLogs[1] = append(s.taskLogs[1], "t1b")
+				s.taskLogs[2] = append(s.taskLogs[2], "t2b")
+				s.tasks[0].EndedAt = logEnded
+			case 3:
+				s.tasks = append(s.tasks, &BuildTask{BID: 1, TaskID: 3, Order: 3})
+				s.taskLogs[3] = []string{"t3a"}
+				s.tasks[1].EndedAt = logEnded
+			case 4:
+				s.build.EndedAt = logEnded
+			}
+		})
+	}
+
+	f := FollowBuildTaskLogs(context.Background(), s.service(), BuildSpec{BID: 1}, fastLogFollow)
+	var lines []string
+	for line := range f.Lines() {
+		lines = append(lines, strconv.FormatInt(line.Task.TaskID, 10)+":"+line.Text)
+	}
+	if err := f.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"2:t2a", "2:t2b", "1:t1a", "1:t1b", "3:t3a"}; !reflect.DeepEqual(lines, want) {
+		t.Errorf("got lines %q, want %q", lines, want)
+	}
+}
+
+func TestLogFollower_Close(t *testing.T) {
+	s := newLogBuilds()
+	f := FollowBuildLog(context.Background(), s.service(), BuildSpec{BID: 1}, fastLogFollow)
+	f.Close()
+	for range f.Lines() {
+	}
+	if err := f.Err(); !errors.Is(err, context.Canceled) {
+		t.Errorf("got error %v, want context.Canceled", err)
+	}
+}
+
+func TestNextLogInterval(t *testing.T) {
+	opt := &LogFollowOptions{MinInterval: time.Second, MaxInterval: 3 * time.Second}
+	if got, want := nextLogInterval(time.Second, false, opt), 2*time.Second; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+	if got, want := nextLogInterval(2*time.Second, false, opt), 3*time.Second; got != want {
+		t.Errorf("got %s, want %s (capped)", got, want)
+	}
+	if got, want := nextLogInterval(3*time.Second, true, opt), time.Second; got != want {
+		t.Errorf("got %s, want %s (reset)", got, want)
+	}
+}
@@ -1,9 +1,13 @@
 package sourcegraph
 
-import "sourcegraph.com/sourcegraph/go-sourcegraph/router"
+import (
+	"context"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
+)
 
 type MarkdownService interface {
-	Render(markdown []byte, opt MarkdownOpt) (*MarkdownData, Response, error)
+	Render(ctx context.Context, markdown []byte, opt MarkdownOpt) (*MarkdownData, Response, error)
 }
 
 type markdownService struct {
@@ -24,13 +28,13 @@ type MarkdownData struct {
 	Checklist *Checklist
 }
 
-func (m *markdownService) Render(markdown []byte, opt MarkdownOpt) (*MarkdownData, Response, error) {
+func (m *markdownService) Render(ctx context.Context, markdown []byte, opt MarkdownOpt) (*MarkdownData, Response, error) {
 	url, err := m.client.URL(router.Markdown, nil, nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := m.client.NewRequest("POST", url.String(), &MarkdownRequestBody{
+	req, err := m.client.NewRequest(ctx, "POST", url.String(), &MarkdownRequestBody{
 		Markdown:    markdown,
 		MarkdownOpt: opt,
 	})
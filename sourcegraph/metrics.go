@@ -0,0 +1,186 @@
+package sourcegraph
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// DefaultLatencyBuckets are the default upper bounds (in seconds) of
+// the buckets of the latency histograms kept by Metrics. They are the
+// same as the Prometheus client libraries' default buckets.
+var DefaultLatencyBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// Metrics is a SpanExporter that aggregates API call spans into
+// per-route metrics: call counts by status code, retry counts,
+// payload sizes, and latency histograms. Use it with the Tracing
+// middleware:
+//
+//	m := sourcegraph.NewMetrics(nil)
+//	client.Middleware = append(client.Middleware, sourcegraph.Tracing(m))
+//
+// The metrics can be read with Routes or written in the Prometheus
+// text exposition format with WriteTo (e.g., from an HTTP handler
+// that Prometheus scrapes).
+type Metrics struct {
+	buckets []float64
+
+	mu     sync.Mutex
+	routes map[string]*RouteMetrics
+}
+
+// RouteMetrics holds the metrics for calls to a single API route.
+type RouteMetrics struct {
+	// Calls is the number of calls, keyed on the response's HTTP
+	// status code (or 0 for calls that received no response).
+	Calls map[int]int64
+
+	Retries       int64 // total number of retries
+	RequestBytes  int64 // total size of request bodies
+	ResponseBytes int64 // total size of response bodies
+
+	// LatencyBuckets[i] is the number of calls that took at most
+	// Metrics' ith bucket bound (in seconds). The counts are
+	// cumulative, as in Prometheus histograms.
+	LatencyBuckets []int64
+
+	LatencyCount int64   // the total number of calls
+	LatencySum   float64 // the total duration of all calls, in seconds
+}
+
+var _ SpanExporter = &Metrics{}
+
+// NewMetrics returns a Metrics that keeps latency histograms with the
+// given bucket upper bounds (in seconds, in increasing order). If
+// buckets is nil, DefaultLatencyBuckets is used.
+func NewMetrics(buckets []float64) *Metrics {
+	if buckets == nil {
+		buckets = DefaultLatencyBuckets
+	}
+	return &Metrics{buckets: buckets, routes: map[string]*RouteMetrics{}}
+}
+
+// ExportSpan implements SpanExporter.
+func (m *Metrics) ExportSpan(s *Span) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	rm, ok := m.routes[s.Route]
+	if !ok {
+		rm = &RouteMetrics{Calls: map[int]int64{}, LatencyBuckets: make([]int64, len(m.buckets))}
+		m.routes[s.Route] = rm
+	}
+	rm.Calls[s.StatusCode]++
+	if s.Attempts > 1 {
+		rm.Retries += int64(s.Attempts - 1)
+	}
+	rm.RequestBytes += s.RequestBytes
+	rm.ResponseBytes += s.ResponseBytes
+
+	secs := s.Duration().Seconds()
+	for i, le := range m.buckets {
+		if secs <= le {
+			rm.LatencyBuckets[i]++
+		}
+	}
+	rm.LatencyCount++
+	rm.LatencySum += secs
+}
+
+// Routes returns a copy of the current metrics, keyed on route name.
+func (m *Metrics) Routes() map[string]RouteMetrics {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	routes := make(map[string]RouteMetrics, len(m.routes))
+	for name, rm := range m.routes {
+		cp := *rm
+		cp.Calls = make(map[int]int64, len(rm.Calls))
+		for code, n := range rm.Calls {
+			cp.Calls[code] = n
+		}
+		cp.LatencyBuckets = append([]int64(nil), rm.LatencyBuckets...)
+		routes[name] = cp
+	}
+	return routes
+}
+
+// WriteTo writes the metrics to w in the Prometheus text exposition
+// format.
+func (m *Metrics) WriteTo(w io.Writer) (int64, error) {
+	routes := m.Routes()
+	names := make([]string, 0, len(routes))
+	for name := range routes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	cw := &countingWriter{w: bufio.NewWriter(w)}
+	p := func(format string, args ...interface{}) { fmt.Fprintf(cw, format, args...) }
+
+	p("# HELP sourcegraph_client_requests_total Number of Sourcegraph API calls, by route and HTTP status code.\n")
+	p("# TYPE sourcegraph_client_requests_total counter\n")
+	for _, name := range names {
+		codes := make([]int, 0, len(routes[name].Calls))
+		for code := range routes[name].Calls {
+			codes = append(codes, code)
+		}
+		sort.Ints(codes)
+		for _, code := range codes {
+			p("sourcegraph_client_requests_total{route=%q,code=\"%d\"} %d\n", name, code, routes[name].Calls[code])
+		}
+	}
+
+	counters := []struct {
+		name, help string
+		value      func(RouteMetrics) int64
+	}{
+		{"sourcegraph_client_retries_total", "Number of retried Sourcegraph API requests, by route.", func(rm RouteMetrics) int64 { return rm.Retries }},
+		{"sourcegraph_client_request_bytes_total", "Total size of Sourcegraph API request bodies, by route.", func(rm RouteMetrics) int64 { return rm.RequestBytes }},
+		{"sourcegraph_client_response_bytes_total", "Total size of Sourcegraph API response bodies, by route.", func(rm RouteMetrics) int64 { return rm.ResponseBytes }},
+	}
+	for _, c := range counters {
+		p("# HELP %s %s\n# TYPE %s counter\n", c.name, c.help, c.name)
+		for _, name := range names {
+			p("%s{route=%q} %d\n", c.name, name, c.value(routes[name]))
+		}
+	}
+
+	const hist = "sourcegraph_client_request_duration_seconds"
+	p("# HELP %s Latency of Sourcegraph API calls, by route.\n# TYPE %s histogram\n", hist, hist)
+	for _, name := range names {
+		rm := routes[name]
+		for i, le := range m.buckets {
+			p("%s_bucket{route=%q,le=%q} %d\n", hist, name, strconv.FormatFloat(le, 'g', -1, 64), rm.LatencyBuckets[i])
+		}
+		p("%s_bucket{route=%q,le=\"+Inf\"} %d\n", hist, name, rm.LatencyCount)
+		p("%s_sum{route=%q} %s\n", hist, name, strconv.FormatFloat(rm.LatencySum, 'g', -1, 64))
+		p("%s_count{route=%q} %d\n", hist, name, rm.LatencyCount)
+	}
+
+	if cw.err != nil {
+		return cw.n, cw.err
+	}
+	return cw.n, cw.w.Flush()
+}
+
+// countingWriter counts the bytes written to w and records the first
+// error.
+type countingWriter struct {
+	w   *bufio.Writer
+	n   int64
+	err error
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+	n, err := w.w.Write(p)
+	w.n += int64(n)
+	w.err = err
+	return n, err
+}
@@ -0,0 +1,70 @@
+package sourcegraph
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
+)
+
+func TestMetrics(t *testing.T) {
+	m := NewMetrics([]float64{0.1, 1})
+	start := time.Date(2015, 4, 1, 12, 0, 0, 0, time.UTC)
+	spans := []*Span{
+		{Route: router.DefRefs, StatusCode: 200, Attempts: 1, RequestBytes: 0, ResponseBytes: 100, Start: start, End: start.Add(50 * time.Millisecond)},
+		{Route: router.DefRefs, StatusCode: 200, Attempts: 3, RequestBytes: 0, ResponseBytes: 200, Start: start, End: start.Add(500 * time.Millisecond)},
+		{Route: router.DefRefs, StatusCode: 404, Attempts: 1, Start: start, End: start.Add(2 * time.Second)},
+		{Route: router.Repo, StatusCode: 0, Attempts: 1, RequestBytes: 10, Start: start, End: start.Add(time.Second)},
+	}
+	for _, s := range spans {
+		m.ExportSpan(s)
+	}
+
+	want := map[string]RouteMetrics{
+		router.DefRefs: {
+			Calls:          map[int]int64{200: 2, 404: 1},
+			Retries:        2,
+			ResponseBytes:  300,
+			LatencyBuckets: []int64{1, 2},
+			LatencyCount:   3,
+			LatencySum:     2.55,
+		},
+		router.Repo: {
+			Calls:          map[int]int64{0: 1},
+			RequestBytes:   10,
+			LatencyBuckets: []int64{0, 1},
+			LatencyCount:   1,
+			LatencySum:     1,
+		},
+	}
+	if routes := m.Routes(); !reflect.DeepEqual(routes, want) {
+		t.Errorf("got routes %+v, want %+v", routes, want)
+	}
+
+	var buf bytes.Buffer
+	n, err := m.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != int64(buf.Len()) {
+		t.Errorf("WriteTo returned n == %d, but wrote %d bytes", n, buf.Len())
+	}
+	for _, line := range []string{
+		`sourcegraph_client_requests_total{route="def.refs",code="404"} 1`,
+		`sourcegraph_client_requests_total{route="repo",code="0"} 1`,
+		`sourcegraph_client_retries_total{route="def.refs"} 2`,
+		`sourcegraph_client_request_bytes_total{route="repo"} 10`,
+		`sourcegraph_client_response_bytes_total{route="def.refs"} 300`,
+		`# TYPE sourcegraph_client_request_duration_seconds histogram`,
+		`sourcegraph_client_request_duration_seconds_bucket{route="def.refs",le="0.1"} 1`,
+		`sourcegraph_client_request_duration_seconds_bucket{route="def.refs",le="+Inf"} 3`,
+		`sourcegraph_client_request_duration_seconds_sum{route="def.refs"} 2.55`,
+		`sourcegraph_client_request_duration_seconds_count{route="repo"} 1`,
+	} {
+		if !bytes.Contains(buf.Bytes(), []byte(line+"\n")) {
+			t.Errorf("output does not contain line %q:\n%s", line, buf.String())
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package sourcegraph
+
+import "net/http"
+
+// A Call is an API call made with (*Client).Do, as seen by
+// Middleware.
+type Call struct {
+	// Route is the name of the API route (one of the constants in
+	// package router) that the request is for, or "" if the request
+	// URL does not match any API route.
+	Route string
+
+	// RouteVars are the route variables of the request URL (e.g.,
+	// "RepoSpec" and "Rev"), as produced by the RouteVars methods of
+	// spec types such as RepoRevSpec. It is nil if Route is "".
+	RouteVars map[string]string
+
+	// Request is the HTTP request to send. Middleware may modify it
+	// (or replace it) before calling the next handler.
+	Request *http.Request
+
+	// Result is the value (passed to Do) that the response body is
+	// decoded into. After the next handler returns without error, it
+	// holds the decoded result.
+	Result interface{}
+
+	sent int // number of times the call has been sent
+}
+
+// A CallHandler handles an API call, returning the response and
+// error that Do returns.
+type CallHandler func(call *Call) (Response, error)
+
+// A Middleware intercepts API calls made with (*Client).Do. It may
+// inspect or modify the call, handle it itself, or pass it to next
+// (any number of times) and inspect or modify the returned response,
+// error, and decoded call.Result.
+//
+// For example, a Middleware that logs each call looks like:
+//
+//	func logCalls(call *Call, next CallHandler) (Response, error) {
+//		resp, err := next(call)
+//		log.Printf("%s %v: %v", call.Route, call.RouteVars, err)
+//		return resp, err
+//	}
+//
+// If next is called more than once, the request body is replayed
+// (using the request's GetBody func, which NewRequest sets).
+type Middleware func(call *Call, next CallHandler) (Response, error)
+
+// callMiddleware passes call to the i'th Middleware, or (if there are
+// no more Middleware) sends it.
+func (c *Client) callMiddleware(i int, call *Call) (Response, error) {
+	if i == len(c.Middleware) {
+		if c.Renames != nil {
+			return c.sendFollowingRenames(call)
+		}
+		return c.sendCall(call)
+	}
+	return c.Middleware[i](call, func(call *Call) (Response, error) {
+		return c.callMiddleware(i+1, call)
+	})
+}
+
+// sendCall sends call.Request and decodes the response into
+// call.Result.
+func (c *Client) sendCall(call *Call) (Response, error) {
+	req := call.Request
+	if call.sent > 0 && req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = body
+	}
+	call.sent++
+	return c.do(req, call.Result)
+}
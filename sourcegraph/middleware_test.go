@@ -0,0 +1,126 @@
+package sourcegraph
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
+	"sourcegraph.com/sourcegraph/vcsstore/vcsclient"
+)
+
+func TestClient_Middleware(t *testing.T) {
+	setup()
+	defer teardown()
+
+	want := &vcsclient.TreeEntry{Name: "README"}
+	mux.HandleFunc(urlPath(t, router.RepoReadme, map[string]string{"RepoSpec": "r.com/x", "Rev": "v"}), func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, want)
+	})
+
+	var (
+		order     []string
+		gotRoute  string
+		gotVars   map[string]string
+		gotResult interface{}
+	)
+	client.Middleware = []Middleware{
+		func(call *Call, next CallHandler) (Response, error) {
+			order = append(order, "a")
+			resp, err := next(call)
+			order = append(order, "a done")
+			return resp, err
+		},
+		func(call *Call, next CallHandler) (Response, error) {
+			order = append(order, "b")
+			gotRoute, gotVars = call.Route, call.RouteVars
+			resp, err := next(call)
+			gotResult = call.Result
+			order = append(order, "b done")
+			return resp, err
+		},
+	}
+
+	readme, _, err := client.Repos.GetReadme(context.Background(), RepoRevSpec{RepoSpec: RepoSpec{URI: "r.com/x"}, Rev: "v"})
+	if err != nil {
+		t.Fatalf("Repos.GetReadme returned error: %v", err)
+	}
+	if !reflect.DeepEqual(readme, want) {
+		t.Errorf("Repos.GetReadme returned %+v, want %+v", readme, want)
+	}
+
+	if want := []string{"a", "b", "b done", "a done"}; !reflect.DeepEqual(order, want) {
+		t.Errorf("got middleware order %v, want %v", order, want)
+	}
+	if gotRoute != router.RepoReadme {
+		t.Errorf("got route %q, want %q", gotRoute, router.RepoReadme)
+	}
+	if want := map[string]string{"RepoSpec": "r.com/x", "Rev": "v"}; !reflect.DeepEqual(gotVars, want) {
+		t.Errorf("got route vars %v, want %v", gotVars, want)
+	}
+	if res, ok := gotResult.(**vcsclient.TreeEntry); !ok || !reflect.DeepEqual(*res, want) {
+		t.Errorf("got result %#v, want %+v", gotResult, want)
+	}
+}
+
+func TestClient_Middleware_shortCircuit(t *testing.T) {
+	setup()
+	defer teardown()
+
+	wantErr := errors.New("x")
+	client.Middleware = []Middleware{
+		func(call *Call, next CallHandler) (Response, error) {
+			return nil, wantErr
+		},
+	}
+
+	if _, _, err := client.Repos.Get(context.Background(), RepoSpec{URI: "r.com/x"}, nil); err != wantErr {
+		t.Errorf("got error %v, want %v", err, wantErr)
+	}
+}
+
+func TestClient_Middleware_resend(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var bodies []string
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		bodies = append(bodies, string(body))
+		if r.Header.Get("Authorization") != "new" {
+			w.WriteHeader(http.StatusUnauthorized)
+			writeJSON(w, ErrorResponse{Message: "expired"})
+			return
+		}
+		writeJSON(w, nil)
+	})
+
+	// Refresh credentials and resend the call after a 401.
+	client.Middleware = []Middleware{
+		func(call *Call, next CallHandler) (Response, error) {
+			resp, err := next(call)
+			if IsHTTPErrorCode(err, http.StatusUnauthorized) {
+				call.Request.Header.Set("Authorization", "new")
+				return next(call)
+			}
+			return resp, err
+		},
+	}
+
+	req, err := client.NewRequest(context.Background(), "POST", server.URL, map[string]int{"A": 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Do(req, nil); err != nil {
+		t.Fatalf("Do returned error: %v", err)
+	}
+	if want := []string{`{"A":1}` + "\n", `{"A":1}` + "\n"}; !reflect.DeepEqual(bodies, want) {
+		t.Errorf("got request bodies %q, want %q", bodies, want)
+	}
+}
@@ -1,6 +1,7 @@
 package sourcegraph
 
 import (
+	"context"
 	"strconv"
 	"strings"
 
@@ -11,16 +12,16 @@ import (
 // Sourcegraph API.
 type OrgsService interface {
 	// Get fetches an organization.
-	Get(org OrgSpec) (*Org, Response, error)
+	Get(ctx context.Context, org OrgSpec) (*Org, Response, error)
 
 	// ListMembers lists members of an organization.
-	ListMembers(org OrgSpec, opt *OrgListMembersOptions) ([]*User, Response, error)
+	ListMembers(ctx context.Context, org OrgSpec, opt *OrgListMembersOptions) ([]*User, Response, error)
 
 	// GetSettings fetches an org's configuration settings.
-	GetSettings(org OrgSpec) (*OrgSettings, Response, error)
+	GetSettings(ctx context.Context, org OrgSpec) (*OrgSettings, Response, error)
 
 	// UpdateSettings updates an org's configuration settings.
-	UpdateSettings(org OrgSpec, settings OrgSettings) (Response, error)
+	UpdateSettings(ctx context.Context, org OrgSpec, settings OrgSettings) (Response, error)
 }
 
 // orgsService implements OrgsService.
@@ -69,13 +70,13 @@ func ParseOrgSpec(pathComponent string) (OrgSpec, error) {
 	return OrgSpec{Org: pathComponent}, nil
 }
 
-func (s *orgsService) Get(org OrgSpec) (*Org, Response, error) {
+func (s *orgsService) Get(ctx context.Context, org OrgSpec) (*Org, Response, error) {
 	url, err := s.client.URL(router.Org, org.RouteVars(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", url.String(), nil)
+	req, err := s.client.NewRequest(ctx, "GET", url.String(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -93,13 +94,13 @@ type OrgListMembersOptions struct {
 	ListOptions
 }
 
-func (s *orgsService) ListMembers(org OrgSpec, opt *OrgListMembersOptions) ([]*User, Response, error) {
+func (s *orgsService) ListMembers(ctx context.Context, org OrgSpec, opt *OrgListMembersOptions) ([]*User, Response, error) {
 	url, err := s.client.URL(router.OrgMembers, org.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", url.String(), nil)
+	req, err := s.client.NewRequest(ctx, "GET", url.String(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -118,13 +119,13 @@ type OrgSettings struct {
 	PlanSettings `json:",omitempty"`
 }
 
-func (s *orgsService) GetSettings(org OrgSpec) (*OrgSettings, Response, error) {
+func (s *orgsService) GetSettings(ctx context.Context, org OrgSpec) (*OrgSettings, Response, error) {
 	url, err := s.client.URL(router.OrgSettings, org.RouteVars(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", url.String(), nil)
+	req, err := s.client.NewRequest(ctx, "GET", url.String(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -138,13 +139,13 @@ func (s *orgsService) GetSettings(org OrgSpec) (*OrgSettings, Response, error) {
 	return settings, resp, nil
 }
 
-func (s *orgsService) UpdateSettings(org OrgSpec, settings OrgSettings) (Response, error) {
+func (s *orgsService) UpdateSettings(ctx context.Context, org OrgSpec, settings OrgSettings) (Response, error) {
 	url, err := s.client.URL(router.OrgSettingsUpdate, org.RouteVars(), nil)
 	if err != nil {
 		return nil, err
 	}
 
-	req, err := s.client.NewRequest("PUT", url.String(), settings)
+	req, err := s.client.NewRequest(ctx, "PUT", url.String(), settings)
 	if err != nil {
 		return nil, err
 	}
@@ -1,22 +1,26 @@
 package sourcegraph
 
+import "context"
+
 type MockOrgsService struct {
-	Get_            func(org OrgSpec) (*Org, Response, error)
-	ListMembers_    func(org OrgSpec, opt *OrgListMembersOptions) ([]*User, Response, error)
-	GetSettings_    func(org OrgSpec) (*OrgSettings, Response, error)
-	UpdateSettings_ func(org OrgSpec, settings OrgSettings) (Response, error)
+	Get_            func(ctx context.Context, org OrgSpec) (*Org, Response, error)
+	ListMembers_    func(ctx context.Context, org OrgSpec, opt *OrgListMembersOptions) ([]*User, Response, error)
+	GetSettings_    func(ctx context.Context, org OrgSpec) (*OrgSettings, Response, error)
+	UpdateSettings_ func(ctx context.Context, org OrgSpec, settings OrgSettings) (Response, error)
 }
 
-func (s MockOrgsService) Get(org OrgSpec) (*Org, Response, error) { return s.Get_(org) }
+func (s MockOrgsService) Get(ctx context.Context, org OrgSpec) (*Org, Response, error) {
+	return s.Get_(ctx, org)
+}
 
-func (s MockOrgsService) ListMembers(org OrgSpec, opt *OrgListMembersOptions) ([]*User, Response, error) {
-	return s.ListMembers_(org, opt)
+func (s MockOrgsService) ListMembers(ctx context.Context, org OrgSpec, opt *OrgListMembersOptions) ([]*User, Response, error) {
+	return s.ListMembers_(ctx, org, opt)
 }
 
-func (s MockOrgsService) GetSettings(org OrgSpec) (*OrgSettings, Response, error) {
-	return s.GetSettings_(org)
+func (s MockOrgsService) GetSettings(ctx context.Context, org OrgSpec) (*OrgSettings, Response, error) {
+	return s.GetSettings_(ctx, org)
 }
 
-func (s MockOrgsService) UpdateSettings(org OrgSpec, settings OrgSettings) (Response, error) {
-	return s.UpdateSettings_(org, settings)
+func (s MockOrgsService) UpdateSettings(ctx context.Context, org OrgSpec, settings OrgSettings) (Response, error) {
+	return s.UpdateSettings_(ctx, org, settings)
 }
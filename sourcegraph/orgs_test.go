@@ -1,6 +1,7 @@
 package sourcegraph
 
 import (
+	"context"
 	"net/http"
 	"reflect"
 	"testing"
@@ -50,7 +51,7 @@ func TestOrgsService_Get(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	org_, _, err := client.Orgs.Get(OrgSpec{Org: "a"})
+	org_, _, err := client.Orgs.Get(context.Background(), OrgSpec{Org: "a"})
 	if err != nil {
 		t.Errorf("Orgs.Get returned error: %v", err)
 	}
@@ -78,7 +79,7 @@ func TestOrgsService_ListMembers(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	members, _, err := client.Orgs.ListMembers(OrgSpec{Org: "a"}, nil)
+	members, _, err := client.Orgs.ListMembers(context.Background(), OrgSpec{Org: "a"}, nil)
 	if err != nil {
 		t.Errorf("Orgs.ListMembers returned error: %v", err)
 	}
@@ -106,7 +107,7 @@ func TestOrgsService_GetSettings(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	settings, _, err := client.Orgs.GetSettings(OrgSpec{Org: "a"})
+	settings, _, err := client.Orgs.GetSettings(context.Background(), OrgSpec{Org: "a"})
 	if err != nil {
 		t.Errorf("Orgs.GetSettings returned error: %v", err)
 	}
@@ -135,7 +136,7 @@ func TestOrgsService_UpdateSettings(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	_, err := client.Orgs.UpdateSettings(OrgSpec{Org: "a"}, want)
+	_, err := client.Orgs.UpdateSettings(context.Background(), OrgSpec{Org: "a"}, want)
 	if err != nil {
 		t.Errorf("Orgs.UpdateSettings returned error: %v", err)
 	}
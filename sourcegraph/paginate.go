@@ -0,0 +1,293 @@
+package sourcegraph
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// parseLinks parses the RFC 5988 Link header in hdr and returns the
+// page numbers (the "Page" querystring parameter) of the first, prev,
+// next, and last pages, keyed on their link relation. Links without a
+// valid page number are omitted.
+func parseLinks(hdr http.Header) map[string]int {
+	var pages map[string]int
+	for _, v := range hdr[http.CanonicalHeaderKey("link")] {
+		for _, link := range strings.Split(v, ",") {
+			segs := strings.Split(strings.TrimSpace(link), ";")
+			if len(segs) < 2 {
+				continue
+			}
+			urlStr := strings.TrimSpace(segs[0])
+			if !strings.HasPrefix(urlStr, "<") || !strings.HasSuffix(urlStr, ">") {
+				continue
+			}
+			u, err := url.Parse(urlStr[1 : len(urlStr)-1])
+			if err != nil {
+				continue
+			}
+			page, err := strconv.Atoi(u.Query().Get("Page"))
+			if err != nil {
+				continue
+			}
+			for _, param := range segs[1:] {
+				param = strings.TrimSpace(param)
+				if !strings.HasPrefix(param, "rel=") {
+					continue
+				}
+				for _, rel := range strings.Fields(strings.Trim(param[len("rel="):], `"`)) {
+					if pages == nil {
+						pages = map[string]int{}
+					}
+					pages[rel] = page
+				}
+			}
+		}
+	}
+	return pages
+}
+
+// A PageFunc fetches a single page of a paginated list, as specified
+// by opt.
+type PageFunc[T any] func(ctx context.Context, opt ListOptions) ([]T, Response, error)
+
+// An Iterator iterates over all of the items in a paginated list,
+// fetching pages lazily as they are needed. It stops when it receives
+// an empty or short page, when it has fetched the last page implied
+// by the server's total count, or
+// (if the server sends Link headers) when there is no next page.
+//
+// Use it like this:
+//
+//	it := IterateRepos(ctx, client.Repos, RepoListOptions{})
+//	defer it.Close()
+//	for it.Next() {
+//		repo := it.Value()
+//		// ...
+//	}
+//	if err := it.Err(); err != nil {
+//		// ...
+//	}
+//
+// Pages are prefetched (see Prefetch) on a context that is canceled
+// when iteration ends or fails. Callers that stop calling Next before
+// it returns false must call Close to cancel any prefetches that are
+// still in flight.
+//
+// An Iterator is not safe for concurrent use.
+type Iterator[T any] struct {
+	// Prefetch is the number of pages to fetch ahead, in parallel,
+	// once the number of pages is known (from the response's total
+	// count or Link header). If zero, pages are fetched one at a
+	// time. It must be set before the first call to Next.
+	Prefetch int
+
+	ctx     context.Context
+	cancel  context.CancelFunc
+	fetch   PageFunc[T]
+	perPage int
+
+	nextPage int // the next page to fetch (or schedule for prefetching)
+	lastPage int // the last page, or 0 if unknown
+	pending  []*pendingPage[T]
+
+	buf   []T
+	cur   T
+	total int
+	done  bool
+	err   error
+}
+
+type pendingPage[T any] struct {
+	page int
+	c    chan pageResult[T]
+}
+
+type pageResult[T any] struct {
+	items []T
+	resp  Response
+	err   error
+}
+
+// NewIterator returns an Iterator over the items returned by fetch,
+// starting at the page specified by opt.
+func NewIterator[T any](ctx context.Context, opt ListOptions, fetch PageFunc[T]) *Iterator[T] {
+	ctx, cancel := context.WithCancel(ctx)
+	return &Iterator[T]{
+		ctx:      ctx,
+		cancel:   cancel,
+		fetch:    fetch,
+		perPage:  opt.PerPageOrDefault(),
+		nextPage: opt.PageOrDefault(),
+		total:    -1,
+	}
+}
+
+// Next advances the iterator to the next item, which is then
+// available through Value. It returns false when there are no more
+// items or an error occurred.
+func (it *Iterator[T]) Next() bool {
+	for len(it.buf) == 0 {
+		if it.done || it.err != nil {
+			return false
+		}
+		it.fetchPage()
+	}
+	it.cur = it.buf[0]
+	it.buf = it.buf[1:]
+	return true
+}
+
+// Close stops the iteration, cancels any pages that are being
+// prefetched and waits for their requests to return. Subsequent calls
+// to Next return false. It is safe to call Close more than once, and
+// after Next has returned false.
+func (it *Iterator[T]) Close() {
+	it.buf = nil
+	it.done = true
+	it.stop()
+}
+
+// stop cancels the iterator's context and waits for pending
+// prefetches to return, discarding their results.
+func (it *Iterator[T]) stop() {
+	it.cancel()
+	for _, p := range it.pending {
+		<-p.c
+	}
+	it.pending = nil
+}
+
+// Value returns the current item.
+func (it *Iterator[T]) Value() T { return it.cur }
+
+// Err returns the error, if any, that occurred while fetching pages.
+func (it *Iterator[T]) Err() error { return it.err }
+
+// TotalCount returns the total number of items reported by the
+// server, or -1 if it is not (yet) known.
+func (it *Iterator[T]) TotalCount() int { return it.total }
+
+// All fetches all remaining items and returns them.
+func (it *Iterator[T]) All() ([]T, error) {
+	var all []T
+	for it.Next() {
+		all = append(all, it.Value())
+	}
+	return all, it.Err()
+}
+
+func (it *Iterator[T]) fetchPage() {
+	var (
+		page int
+		res  pageResult[T]
+	)
+	if len(it.pending) > 0 {
+		p := it.pending[0]
+		it.pending = it.pending[1:]
+		page, res = p.page, <-p.c
+	} else {
+		page = it.nextPage
+		it.nextPage++
+		res = it.get(page)
+	}
+	if res.err != nil {
+		it.err = res.err
+		it.stop()
+		return
+	}
+
+	it.buf = res.items
+
+	if resp, ok := res.resp.(*HTTPResponse); ok && resp.Response != nil {
+		if tc := resp.TotalCount(); tc >= 0 {
+			it.total = tc
+			it.lastPage = (tc + it.perPage - 1) / it.perPage
+		}
+		if resp.Header.Get("link") != "" {
+			switch {
+			case resp.NextPage == 0:
+				it.lastPage = page
+			case len(it.pending) == 0:
+				it.nextPage = resp.NextPage
+			}
+			if resp.LastPage != 0 {
+				it.lastPage = resp.LastPage
+			}
+		}
+	}
+
+	if len(res.items) < it.perPage || (it.lastPage > 0 && page >= it.lastPage) {
+		it.done = true
+		it.stop()
+		return
+	}
+
+	for it.lastPage > 0 && len(it.pending) < it.Prefetch && it.nextPage <= it.lastPage {
+		p := &pendingPage[T]{page: it.nextPage, c: make(chan pageResult[T], 1)}
+		it.nextPage++
+		it.pending = append(it.pending, p)
+		go func() { p.c <- it.get(p.page) }()
+	}
+}
+
+func (it *Iterator[T]) get(page int) pageResult[T] {
+	items, resp, err := it.fetch(it.ctx, ListOptions{Page: page, PerPage: it.perPage})
+	return pageResult[T]{items: items, resp: resp, err: err}
+}
+
+// IterateRepos returns an Iterator over the repositories listed by
+// s.List.
+func IterateRepos(ctx context.Context, s ReposService, opt RepoListOptions) *Iterator[*Repo] {
+	return NewIterator(ctx, opt.ListOptions, func(ctx context.Context, lo ListOptions) ([]*Repo, Response, error) {
+		opt := opt
+		opt.ListOptions = lo
+		return s.List(ctx, &opt)
+	})
+}
+
+// IterateDefRefs returns an Iterator over the refs to def listed by
+// s.ListRefs.
+func IterateDefRefs(ctx context.Context, s DefsService, def DefSpec, opt DefListRefsOptions) *Iterator[*Ref] {
+	return NewIterator(ctx, opt.ListOptions, func(ctx context.Context, lo ListOptions) ([]*Ref, Response, error) {
+		opt := opt
+		opt.ListOptions = lo
+		return s.ListRefs(ctx, def, &opt)
+	})
+}
+
+// IterateBuilds returns an Iterator over the builds listed by
+// s.List.
+func IterateBuilds(ctx context.Context, s BuildsService, opt BuildListOptions) *Iterator[*Build] {
+	return NewIterator(ctx, opt.ListOptions, func(ctx context.Context, lo ListOptions) ([]*Build, Response, error) {
+		opt := opt
+		opt.ListOptions = lo
+		return s.List(ctx, &opt)
+	})
+}
+
+// IterateDeltaDefs returns an Iterator over the def deltas listed by
+// s.ListDefs.
+func IterateDeltaDefs(ctx context.Context, s DeltasService, ds DeltaSpec, opt DeltaListDefsOptions) *Iterator[*DefDelta] {
+	return NewIterator(ctx, opt.ListOptions, func(ctx context.Context, lo ListOptions) ([]*DefDelta, Response, error) {
+		opt := opt
+		opt.ListOptions = lo
+		dd, resp, err := s.ListDefs(ctx, ds, &opt)
+		if dd == nil {
+			return nil, resp, err
+		}
+		return dd.Defs, resp, err
+	})
+}
+
+// IterateBuildTasks returns an Iterator over the tasks of a build
+// listed by s.ListBuildTasks.
+func IterateBuildTasks(ctx context.Context, s BuildsService, build BuildSpec, opt BuildTaskListOptions) *Iterator[*BuildTask] {
+	return NewIterator(ctx, opt.ListOptions, func(ctx context.Context, lo ListOptions) ([]*BuildTask, Response, error) {
+		opt := opt
+		opt.ListOptions = lo
+		return s.ListBuildTasks(ctx, build, &opt)
+	})
+}
@@ -0,0 +1,223 @@
+package sourcegraph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
+)
+
+func TestParseLinks(t *testing.T) {
+	hdr := http.Header{"Link": {`<https://example.com/api/repos?Page=3&PerPage=10>; rel="next", <https://example.com/api/repos?Page=5&PerPage=10>; rel="last", <https://example.com/api/repos>; rel="first"`}}
+	want := map[string]int{"next": 3, "last": 5}
+	if pages := parseLinks(hdr); !reflect.DeepEqual(pages, want) {
+		t.Errorf("got %v, want %v", pages, want)
+	}
+
+	if pages := parseLinks(http.Header{}); pages != nil {
+		t.Errorf("no Link header: got %v, want nil", pages)
+	}
+}
+
+// intPages returns a PageFunc that serves the ints [0, n).
+func intPages(n int, calls *[]int) PageFunc[int] {
+	var mu sync.Mutex
+	return func(ctx context.Context, opt ListOptions) ([]int, Response, error) {
+		mu.Lock()
+		*calls = append(*calls, opt.Page)
+		mu.Unlock()
+		var items []int
+		for i := opt.Offset(); i < opt.Offset()+opt.Limit() && i < n; i++ {
+			items = append(items, i)
+		}
+		return items, nil, nil
+	}
+}
+
+func TestIterator_shortPage(t *testing.T) {
+	var calls []int
+	it := NewIterator(context.Background(), ListOptions{PerPage: 3}, intPages(7, &calls))
+	all, err := it.All()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{0, 1, 2, 3, 4, 5, 6}; !reflect.DeepEqual(all, want) {
+		t.Errorf("got items %v, want %v", all, want)
+	}
+	if want := []int{1, 2, 3}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("got pages %v, want %v", calls, want)
+	}
+	if tc := it.TotalCount(); tc != -1 {
+		t.Errorf("got TotalCount %d, want -1", tc)
+	}
+}
+
+func TestIterator_emptyPage(t *testing.T) {
+	var calls []int
+	it := NewIterator(context.Background(), ListOptions{PerPage: 3}, intPages(6, &calls))
+	all, err := it.All()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 6 {
+		t.Errorf("got %d items, want 6", len(all))
+	}
+	if want := []int{1, 2, 3}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("got pages %v, want %v", calls, want)
+	}
+}
+
+func TestIterator_error(t *testing.T) {
+	wantErr := errors.New("x")
+	it := NewIterator(context.Background(), ListOptions{PerPage: 1}, func(ctx context.Context, opt ListOptions) ([]int, Response, error) {
+		if opt.Page == 2 {
+			return nil, nil, wantErr
+		}
+		return []int{opt.Page}, nil, nil
+	})
+	all, err := it.All()
+	if err != wantErr {
+		t.Errorf("got error %v, want %v", err, wantErr)
+	}
+	if want := []int{1}; !reflect.DeepEqual(all, want) {
+		t.Errorf("got items %v, want %v", all, want)
+	}
+	if it.Next() {
+		t.Error("Next returned true after error")
+	}
+}
+
+func TestIterator_closeEarly(t *testing.T) {
+	var started, inFlight int32
+	it := NewIterator(context.Background(), ListOptions{PerPage: 2}, func(ctx context.Context, opt ListOptions) ([]int, Response, error) {
+		resp := &HTTPResponse{Response: &http.Response{Header: http.Header{"X-Total-Count": {"10"}}}}
+		if opt.Page == 1 {
+			return []int{0, 1}, resp, nil
+		}
+		// Prefetched pages block until they are canceled.
+		atomic.AddInt32(&started, 1)
+		atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		<-ctx.Done()
+		return nil, resp, ctx.Err()
+	})
+	it.Prefetch = 3
+
+	if !it.Next() {
+		t.Fatalf("Next returned false (err %v)", it.Err())
+	}
+	it.Close()
+
+	if n := atomic.LoadInt32(&inFlight); n != 0 {
+		t.Errorf("got %d requests in flight after Close, want 0", n)
+	}
+	if n := atomic.LoadInt32(&started); n != 3 {
+		t.Errorf("got %d prefetches, want 3", n)
+	}
+	if it.Next() {
+		t.Error("Next returned true after Close")
+	}
+	if err := it.Err(); err != nil {
+		t.Errorf("got error %v after Close, want nil", err)
+	}
+	it.Close()
+}
+
+// serveRepoPages registers a handler that serves n repos, a page at a
+// time, with an x-total-count header and (if links is true) a Link
+// header. It returns a func that returns the pages requested so far.
+func serveRepoPages(t *testing.T, n int, links bool) func() []int {
+	var (
+		mu    sync.Mutex
+		pages []int
+	)
+	mux.HandleFunc(urlPath(t, router.Repos, nil), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		page, _ := strconv.Atoi(r.FormValue("Page"))
+		perPage, _ := strconv.Atoi(r.FormValue("PerPage"))
+		if r.FormValue("Owner") != "o" {
+			t.Errorf("got Owner %q, want %q", r.FormValue("Owner"), "o")
+		}
+		mu.Lock()
+		pages = append(pages, page)
+		mu.Unlock()
+
+		opt := ListOptions{Page: page, PerPage: perPage}
+		var repos []*Repo
+		for i := opt.Offset(); i < opt.Offset()+opt.Limit() && i < n; i++ {
+			repos = append(repos, &Repo{RID: i + 1})
+		}
+		w.Header().Set("x-total-count", strconv.Itoa(n))
+		if links {
+			last := (n + perPage - 1) / perPage
+			link := fmt.Sprintf(`<%s?Page=%d&PerPage=%d>; rel="last"`, r.URL.Path, last, perPage)
+			if page < last {
+				link += fmt.Sprintf(`, <%s?Page=%d&PerPage=%d>; rel="next"`, r.URL.Path, page+1, perPage)
+			}
+			w.Header().Set("Link", link)
+		}
+		writeJSON(w, repos)
+	})
+	return func() []int {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]int(nil), pages...)
+	}
+}
+
+func TestIterateRepos(t *testing.T) {
+	setup()
+	defer teardown()
+
+	// The last page is full, so the iterator must rely on the total
+	// count to avoid requesting an empty page.
+	pages := serveRepoPages(t, 4, false)
+
+	it := IterateRepos(context.Background(), client.Repos, RepoListOptions{Owner: "o", ListOptions: ListOptions{PerPage: 2}})
+	var rids []int
+	for it.Next() {
+		rids = append(rids, it.Value().RID)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{1, 2, 3, 4}; !reflect.DeepEqual(rids, want) {
+		t.Errorf("got RIDs %v, want %v", rids, want)
+	}
+	if want := []int{1, 2}; !reflect.DeepEqual(pages(), want) {
+		t.Errorf("got pages %v, want %v", pages(), want)
+	}
+	if tc := it.TotalCount(); tc != 4 {
+		t.Errorf("got TotalCount %d, want 4", tc)
+	}
+}
+
+func TestIterateRepos_prefetch(t *testing.T) {
+	setup()
+	defer teardown()
+
+	pages := serveRepoPages(t, 9, true)
+
+	it := IterateRepos(context.Background(), client.Repos, RepoListOptions{Owner: "o", ListOptions: ListOptions{PerPage: 2}})
+	it.Prefetch = 2
+	var rids []int
+	for it.Next() {
+		rids = append(rids, it.Value().RID)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{1, 2, 3, 4, 5, 6, 7, 8, 9}; !reflect.DeepEqual(rids, want) {
+		t.Errorf("got RIDs %v, want %v", rids, want)
+	}
+	if got := len(pages()); got != 5 {
+		t.Errorf("got %d page requests (%v), want 5", got, pages())
+	}
+}
@@ -1,6 +1,7 @@
 package sourcegraph
 
 import (
+	"context"
 	"database/sql/driver"
 	"fmt"
 	"strconv"
@@ -15,7 +16,7 @@ type PeopleService interface {
 	// Get gets a person. If an email is provided and it resolves to a
 	// registered user, information about that user is
 	// returned. Otherwise a transient person is created and returned.
-	Get(person PersonSpec) (*Person, Response, error)
+	Get(ctx context.Context, person PersonSpec) (*Person, Response, error)
 }
 
 // peopleService implements PeopleService.
@@ -114,13 +115,13 @@ func ParsePersonSpec(pathComponent string) (PersonSpec, error) {
 	return PersonSpec{Login: pathComponent}, nil
 }
 
-func (s *peopleService) Get(spec PersonSpec) (*Person, Response, error) {
+func (s *peopleService) Get(ctx context.Context, spec PersonSpec) (*Person, Response, error) {
 	url, err := s.client.URL(router.Person, spec.RouteVars(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", url.String(), nil)
+	req, err := s.client.NewRequest(ctx, "GET", url.String(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -1,6 +1,7 @@
 package sourcegraph
 
 import (
+	"context"
 	"net/http"
 	"reflect"
 	"testing"
@@ -85,7 +86,7 @@ func TestPeopleService_Get(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	person_, _, err := client.People.Get(PersonSpec{Login: "a"})
+	person_, _, err := client.People.Get(context.Background(), PersonSpec{Login: "a"})
 	if err != nil {
 		t.Errorf("People.Get returned error: %v", err)
 	}
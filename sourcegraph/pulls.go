@@ -1,6 +1,7 @@
 package sourcegraph
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/sourcegraph/go-github/github"
@@ -15,25 +16,25 @@ import (
 // Sourcegraph API.
 type PullRequestsService interface {
 	// Get fetches a pull request.
-	Get(pull PullRequestSpec, opt *PullRequestGetOptions) (*PullRequest, Response, error)
+	Get(ctx context.Context, pull PullRequestSpec, opt *PullRequestGetOptions) (*PullRequest, Response, error)
 
 	// List pull requests for a repository.
-	ListByRepo(repo RepoSpec, opt *PullRequestListOptions) ([]*PullRequest, Response, error)
+	ListByRepo(ctx context.Context, repo RepoSpec, opt *PullRequestListOptions) ([]*PullRequest, Response, error)
 
 	// ListComments lists comments on a pull request.
-	ListComments(pull PullRequestSpec, opt *PullRequestListCommentsOptions) ([]*PullRequestComment, Response, error)
+	ListComments(ctx context.Context, pull PullRequestSpec, opt *PullRequestListCommentsOptions) ([]*PullRequestComment, Response, error)
 
 	// CreateComment creates a comment on a pull request.
-	CreateComment(pull PullRequestSpec, comment *PullRequestComment) (*PullRequestComment, Response, error)
+	CreateComment(ctx context.Context, pull PullRequestSpec, comment *PullRequestComment) (*PullRequestComment, Response, error)
 
 	// EditComment updates an existing comment on a pull request.
-	EditComment(pull PullRequestSpec, comment *PullRequestComment) (*PullRequestComment, Response, error)
+	EditComment(ctx context.Context, pull PullRequestSpec, comment *PullRequestComment) (*PullRequestComment, Response, error)
 
 	// DeleteComment deletes a comment on a pull request.
-	DeleteComment(pull PullRequestSpec, commentID int) (Response, error)
+	DeleteComment(ctx context.Context, pull PullRequestSpec, commentID int) (Response, error)
 
 	// Merge merges a pull request
-	Merge(pull PullRequestSpec, mergeRequest *PullRequestMergeRequest) (*PullRequestMergeResult, Response, error)
+	Merge(ctx context.Context, pull PullRequestSpec, mergeRequest *PullRequestMergeRequest) (*PullRequestMergeResult, Response, error)
 }
 
 // pullRequestsService implements PullRequestsService.
@@ -99,13 +100,13 @@ type PullRequestGetOptions struct {
 	Checklist bool
 }
 
-func (s *pullRequestsService) Get(pull PullRequestSpec, opt *PullRequestGetOptions) (*PullRequest, Response, error) {
+func (s *pullRequestsService) Get(ctx context.Context, pull PullRequestSpec, opt *PullRequestGetOptions) (*PullRequest, Response, error) {
 	url, err := s.client.URL(router.RepoPullRequest, pull.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", url.String(), nil)
+	req, err := s.client.NewRequest(ctx, "GET", url.String(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -124,13 +125,13 @@ type PullRequestListOptions struct {
 	ListOptions
 }
 
-func (s *pullRequestsService) ListByRepo(repo RepoSpec, opt *PullRequestListOptions) ([]*PullRequest, Response, error) {
+func (s *pullRequestsService) ListByRepo(ctx context.Context, repo RepoSpec, opt *PullRequestListOptions) ([]*PullRequest, Response, error) {
 	url, err := s.client.URL(router.RepoPullRequests, repo.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", url.String(), nil)
+	req, err := s.client.NewRequest(ctx, "GET", url.String(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -186,13 +187,13 @@ func (c PullRequestCommentSpec) RouteVars() map[string]string {
 	return rv
 }
 
-func (s *pullRequestsService) ListComments(pull PullRequestSpec, opt *PullRequestListCommentsOptions) ([]*PullRequestComment, Response, error) {
+func (s *pullRequestsService) ListComments(ctx context.Context, pull PullRequestSpec, opt *PullRequestListCommentsOptions) ([]*PullRequestComment, Response, error) {
 	url, err := s.client.URL(router.RepoPullRequestComments, pull.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", url.String(), nil)
+	req, err := s.client.NewRequest(ctx, "GET", url.String(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -206,13 +207,13 @@ func (s *pullRequestsService) ListComments(pull PullRequestSpec, opt *PullReques
 	return comments, resp, nil
 }
 
-func (s *pullRequestsService) CreateComment(pull PullRequestSpec, comment *PullRequestComment) (*PullRequestComment, Response, error) {
+func (s *pullRequestsService) CreateComment(ctx context.Context, pull PullRequestSpec, comment *PullRequestComment) (*PullRequestComment, Response, error) {
 	url, err := s.client.URL(router.RepoPullRequestCommentsCreate, pull.RouteVars(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("POST", url.String(), comment)
+	req, err := s.client.NewRequest(ctx, "POST", url.String(), comment)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -226,7 +227,7 @@ func (s *pullRequestsService) CreateComment(pull PullRequestSpec, comment *PullR
 	return &createdComment, resp, nil
 }
 
-func (s *pullRequestsService) EditComment(pull PullRequestSpec, comment *PullRequestComment) (*PullRequestComment, Response, error) {
+func (s *pullRequestsService) EditComment(ctx context.Context, pull PullRequestSpec, comment *PullRequestComment) (*PullRequestComment, Response, error) {
 	if comment.ID == nil {
 		return nil, nil, fmt.Errorf("comment ID not specified")
 	}
@@ -236,7 +237,7 @@ func (s *pullRequestsService) EditComment(pull PullRequestSpec, comment *PullReq
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("PATCH", url.String(), comment)
+	req, err := s.client.NewRequest(ctx, "PATCH", url.String(), comment)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -250,13 +251,13 @@ func (s *pullRequestsService) EditComment(pull PullRequestSpec, comment *PullReq
 	return &updatedComment, resp, nil
 }
 
-func (s *pullRequestsService) DeleteComment(pull PullRequestSpec, commentID int) (Response, error) {
+func (s *pullRequestsService) DeleteComment(ctx context.Context, pull PullRequestSpec, commentID int) (Response, error) {
 	url, err := s.client.URL(router.RepoPullRequestCommentsDelete, PullRequestCommentSpec{Pull: pull, Comment: commentID}.RouteVars(), nil)
 	if err != nil {
 		return nil, err
 	}
 
-	req, err := s.client.NewRequest("DELETE", url.String(), nil)
+	req, err := s.client.NewRequest(ctx, "DELETE", url.String(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -277,13 +278,13 @@ type PullRequestMergeRequest struct {
 	CommitMessage string
 }
 
-func (s *pullRequestsService) Merge(pull PullRequestSpec, mergeRequest *PullRequestMergeRequest) (*PullRequestMergeResult, Response, error) {
+func (s *pullRequestsService) Merge(ctx context.Context, pull PullRequestSpec, mergeRequest *PullRequestMergeRequest) (*PullRequestMergeResult, Response, error) {
 	url, err := s.client.URL(router.RepoPullRequestMerge, pull.RouteVars(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("PUT", url.String(), mergeRequest)
+	req, err := s.client.NewRequest(ctx, "PUT", url.String(), mergeRequest)
 	if err != nil {
 		return nil, nil, err
 	}
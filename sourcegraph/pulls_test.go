@@ -2,6 +2,7 @@ package sourcegraph
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"reflect"
@@ -36,7 +37,7 @@ func TestPullRequestsService_Get(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	pull, _, err := client.PullRequests.Get(PullRequestSpec{Repo: RepoSpec{URI: "r.com/x"}, Number: 1}, opts)
+	pull, _, err := client.PullRequests.Get(context.Background(), PullRequestSpec{Repo: RepoSpec{URI: "r.com/x"}, Number: 1}, opts)
 	if err != nil {
 		t.Errorf("PullRequests.Get returned error: %v", err)
 	}
@@ -70,6 +71,7 @@ func TestPullRequestsService_ListByRepo(t *testing.T) {
 	})
 
 	pulls, _, err := client.PullRequests.ListByRepo(
+		context.Background(),
 		repoSpec,
 		&PullRequestListOptions{
 			ListOptions: ListOptions{PerPage: 1, Page: 2},
@@ -108,6 +110,7 @@ func TestPullRequestsService_ListComments(t *testing.T) {
 	})
 
 	comments, _, err := client.PullRequests.ListComments(
+		context.Background(),
 		pullSpec,
 		&PullRequestListCommentsOptions{
 			ListOptions: ListOptions{PerPage: 1, Page: 2},
@@ -162,7 +165,7 @@ func TestPullRequestsService_CreateComment(t *testing.T) {
 		writeJSON(w, wantComment)
 	})
 
-	gotComment, _, err := client.PullRequests.CreateComment(pullSpec, &comment)
+	gotComment, _, err := client.PullRequests.CreateComment(context.Background(), pullSpec, &comment)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -211,7 +214,7 @@ func TestPullRequestsService_EditComment(t *testing.T) {
 		writeJSON(w, comment)
 	})
 
-	updatedComment, _, err := client.PullRequests.EditComment(pullSpec, &comment)
+	updatedComment, _, err := client.PullRequests.EditComment(context.Background(), pullSpec, &comment)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -242,7 +245,7 @@ func TestPullRequestsService_EditComment_ErrorNoID(t *testing.T) {
 		},
 	}
 
-	_, _, err := client.PullRequests.EditComment(pullSpec, &comment)
+	_, _, err := client.PullRequests.EditComment(context.Background(), pullSpec, &comment)
 	if err.Error() != "comment ID not specified" {
 		t.Errorf(`expected error "comment ID not specified", but got none`)
 	}
@@ -262,7 +265,7 @@ func TestPullRequestsService_DeleteComment(t *testing.T) {
 		testMethod(t, req, "DELETE")
 	})
 
-	_, err := client.PullRequests.DeleteComment(pullSpec, commentID)
+	_, err := client.PullRequests.DeleteComment(context.Background(), pullSpec, commentID)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -317,7 +320,7 @@ func TestPullRequestsService_Merge(t *testing.T) {
 		writeJSON(w, wantMergeResult)
 	})
 
-	mergeResult, _, err := client.PullRequests.Merge(pullSpec, mergeRequest)
+	mergeResult, _, err := client.PullRequests.Merge(context.Background(), pullSpec, mergeRequest)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -0,0 +1,212 @@
+package sourcegraph
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimit describes the API rate limit status reported by the
+// server in the X-RateLimit-* response headers.
+type RateLimit struct {
+	// Limit is the maximum number of requests that the client may
+	// make in the current rate limit window.
+	Limit int
+
+	// Remaining is the number of requests remaining in the current
+	// rate limit window.
+	Remaining int
+
+	// Reset is when the current rate limit window resets.
+	Reset time.Time
+}
+
+// parseRateLimit parses the X-RateLimit-* headers in hdr. It returns
+// nil if the headers are absent or malformed.
+func parseRateLimit(hdr http.Header) *RateLimit {
+	limit, err := strconv.Atoi(hdr.Get("x-ratelimit-limit"))
+	if err != nil {
+		return nil
+	}
+	remaining, err := strconv.Atoi(hdr.Get("x-ratelimit-remaining"))
+	if err != nil {
+		return nil
+	}
+	rl := &RateLimit{Limit: limit, Remaining: remaining}
+	if v := hdr.Get("x-ratelimit-reset"); v != "" {
+		reset, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return nil
+		}
+		rl.Reset = time.Unix(reset, 0)
+	}
+	return rl
+}
+
+// A RateLimiter is a token bucket that limits the rate at which a
+// Client sends HTTP requests. It also pauses the client when the
+// server reports (in its X-RateLimit-* response headers) that the
+// rate limit has been exhausted, until the rate limit window resets.
+//
+// A RateLimiter may be shared by multiple Clients to limit their
+// combined request rate.
+type RateLimiter struct {
+	rate  float64 // tokens added per second (or unlimited if <= 0)
+	burst float64 // bucket capacity
+
+	mu          sync.Mutex
+	tokens      float64
+	last        time.Time // when tokens was last updated
+	pausedUntil time.Time // set when the server rate limit is exhausted
+}
+
+// NewRateLimiter returns a RateLimiter that allows rate requests per
+// second on average, with bursts of up to burst requests. If rate <=
+// 0, the request rate is only limited by the server's reported rate
+// limit status.
+func NewRateLimiter(rate float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter{rate: rate, burst: float64(burst), tokens: float64(burst)}
+}
+
+// Wait blocks until a request may be sent, or until ctx is done (in
+// which case ctx's error is returned).
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	for {
+		wait := l.reserve(time.Now())
+		if wait <= 0 {
+			return nil
+		}
+		t := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// reserve takes a token from the bucket and returns 0, or (if no
+// token is available at time now) returns how long to wait before
+// trying again.
+func (l *RateLimiter) reserve(now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Before(l.pausedUntil) {
+		return l.pausedUntil.Sub(now)
+	}
+	if l.rate <= 0 {
+		return 0
+	}
+
+	if !l.last.IsZero() {
+		l.tokens += now.Sub(l.last).Seconds() * l.rate
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+	}
+	l.last = now
+
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0
+	}
+	return time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+}
+
+// observe updates the limiter with the rate limit status reported by
+// the server.
+func (l *RateLimiter) observe(rl *RateLimit) {
+	if rl == nil || rl.Remaining > 0 || rl.Reset.IsZero() {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if rl.Reset.After(l.pausedUntil) {
+		l.pausedUntil = rl.Reset
+	}
+}
+
+// A ConcurrencyLimiter limits the number of HTTP requests that a
+// Client has in flight at once. A request is in flight from when it
+// is sent until its response body is closed.
+//
+// A ConcurrencyLimiter may be shared by multiple Clients to limit
+// their combined concurrency.
+type ConcurrencyLimiter struct {
+	sem chan struct{}
+}
+
+// NewConcurrencyLimiter returns a ConcurrencyLimiter that allows at
+// most max requests to be in flight at once.
+func NewConcurrencyLimiter(max int) *ConcurrencyLimiter {
+	if max < 1 {
+		max = 1
+	}
+	return &ConcurrencyLimiter{sem: make(chan struct{}, max)}
+}
+
+// acquire blocks until a request may be sent, or until ctx is done
+// (in which case ctx's error is returned).
+func (l *ConcurrencyLimiter) acquire(ctx context.Context) error {
+	select {
+	case l.sem <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (l *ConcurrencyLimiter) release() { <-l.sem }
+
+// releaseOnClose is a response body that calls release (once) when
+// it is closed.
+type releaseOnClose struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+func (b *releaseOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
+
+// roundTrip sends a single HTTP request, waiting first for the
+// client's RateLimiter and ConcurrencyLimiter (if any).
+func (c *Client) roundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	if c.RateLimiter != nil {
+		if err := c.RateLimiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if c.ConcurrencyLimiter != nil {
+		if err := c.ConcurrencyLimiter.acquire(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := c.httpClient.Do(req)
+	callStatsFromContext(ctx).observeAttempt(resp)
+
+	if c.ConcurrencyLimiter != nil {
+		if resp != nil && resp.Body != nil {
+			resp.Body = &releaseOnClose{ReadCloser: resp.Body, release: c.ConcurrencyLimiter.release}
+		} else {
+			c.ConcurrencyLimiter.release()
+		}
+	}
+	if c.RateLimiter != nil && resp != nil {
+		c.RateLimiter.observe(parseRateLimit(resp.Header))
+	}
+	return resp, err
+}
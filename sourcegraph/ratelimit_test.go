@@ -0,0 +1,131 @@
+package sourcegraph
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestParseRateLimit(t *testing.T) {
+	tests := []struct {
+		hdr  http.Header
+		want *RateLimit
+	}{
+		{http.Header{}, nil},
+		{http.Header{"X-Ratelimit-Limit": {"x"}, "X-Ratelimit-Remaining": {"1"}}, nil},
+		{
+			http.Header{"X-Ratelimit-Limit": {"10"}, "X-Ratelimit-Remaining": {"3"}},
+			&RateLimit{Limit: 10, Remaining: 3},
+		},
+		{
+			http.Header{"X-Ratelimit-Limit": {"10"}, "X-Ratelimit-Remaining": {"0"}, "X-Ratelimit-Reset": {"1428000000"}},
+			&RateLimit{Limit: 10, Remaining: 0, Reset: time.Unix(1428000000, 0)},
+		},
+	}
+	for _, test := range tests {
+		if rl := parseRateLimit(test.hdr); !reflect.DeepEqual(rl, test.want) {
+			t.Errorf("%v: got %+v, want %+v", test.hdr, rl, test.want)
+		}
+	}
+}
+
+func TestRateLimiter_reserve(t *testing.T) {
+	l := NewRateLimiter(2, 2)
+	now := time.Date(2015, 4, 1, 12, 0, 0, 0, time.UTC)
+
+	// Burst.
+	for i := 0; i < 2; i++ {
+		if wait := l.reserve(now); wait != 0 {
+			t.Fatalf("burst request %d: got wait %v, want 0", i, wait)
+		}
+	}
+	if wait := l.reserve(now); wait != 500*time.Millisecond {
+		t.Errorf("got wait %v, want 500ms", wait)
+	}
+
+	// Refill.
+	now = now.Add(500 * time.Millisecond)
+	if wait := l.reserve(now); wait != 0 {
+		t.Errorf("after refill: got wait %v, want 0", wait)
+	}
+
+	// Server reports the rate limit is exhausted.
+	l.observe(&RateLimit{Limit: 10, Remaining: 0, Reset: now.Add(time.Minute)})
+	if wait := l.reserve(now.Add(10 * time.Second)); wait != 50*time.Second {
+		t.Errorf("when paused: got wait %v, want 50s", wait)
+	}
+	if wait := l.reserve(now.Add(time.Minute)); wait != 0 {
+		t.Errorf("after reset: got wait %v, want 0", wait)
+	}
+}
+
+func TestRateLimiter_Wait_canceled(t *testing.T) {
+	l := NewRateLimiter(0.001, 1)
+	l.reserve(time.Now())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := l.Wait(ctx); err != context.Canceled {
+		t.Errorf("got error %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestClient_ConcurrencyLimiter(t *testing.T) {
+	setup()
+	defer teardown()
+
+	const max = 2
+	client.ConcurrencyLimiter = NewConcurrencyLimiter(max)
+
+	var (
+		mu             sync.Mutex
+		inFlight, peak int
+	)
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		if inFlight > peak {
+			peak = inFlight
+		}
+		mu.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+
+		w.Header().Set("X-RateLimit-Limit", "100")
+		w.Header().Set("X-RateLimit-Remaining", "99")
+		writeJSON(w, nil)
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 3*max; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req, err := client.NewRequest(context.Background(), "GET", server.URL, nil)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			resp, err := client.Do(req, nil)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if rl := resp.(*HTTPResponse).RateLimit; rl == nil || rl.Remaining != 99 {
+				t.Errorf("got RateLimit %+v, want Remaining == 99", rl)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if peak > max {
+		t.Errorf("got %d requests in flight at once, want <= %d", peak, max)
+	}
+}
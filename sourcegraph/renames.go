@@ -0,0 +1,236 @@
+package sourcegraph
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+)
+
+// A RenameTable records repository renames and redirects (from an old
+// URI to the repository's current URI). When it is set as a Client's
+// Renames field, the client follows renames and redirects reported by
+// the server and records them in the table, and rewrites requests for
+// repositories that are known to have been renamed.
+//
+// A RenameTable can be persisted by encoding it as JSON (as an object
+// that maps old URIs to new URIs) and reloaded by decoding the JSON
+// into a new RenameTable. The zero value is an empty table that is
+// ready to use. It is safe for concurrent use.
+type RenameTable struct {
+	// OnRename, if non-nil, is called (synchronously) when a rename
+	// or redirect that was not already in the table is recorded.
+	OnRename func(oldURI, newURI string)
+
+	mu sync.Mutex
+	m  map[string]string // old URI -> new URI
+}
+
+// Add records that the repository at oldURI is now at newURI. Earlier
+// renames to oldURI are updated to point to newURI, so that each old
+// URI maps directly to the current URI.
+func (t *RenameTable) Add(oldURI, newURI string) {
+	t.mu.Lock()
+	newURI, added := t.add(oldURI, newURI)
+	onRename := t.OnRename
+	t.mu.Unlock()
+
+	if added && onRename != nil {
+		onRename(oldURI, newURI)
+	}
+}
+
+// add records a rename and returns the current URI that oldURI now
+// maps to and whether the table changed. t.mu must be held.
+func (t *RenameTable) add(oldURI, newURI string) (string, bool) {
+	if oldURI == "" || newURI == "" || oldURI == newURI {
+		return newURI, false
+	}
+	if t.m == nil {
+		t.m = map[string]string{}
+	}
+	if target, present := t.m[newURI]; present {
+		if target == oldURI {
+			delete(t.m, newURI) // renamed back
+		} else {
+			newURI = target // newURI was itself renamed
+		}
+	}
+	if t.m[oldURI] == newURI {
+		return newURI, false
+	}
+	for k, v := range t.m {
+		if v == oldURI {
+			t.m[k] = newURI
+		}
+	}
+	t.m[oldURI] = newURI
+	return newURI, true
+}
+
+// Resolve returns the current URI of the repository at uri (which is
+// uri itself if the repository isn't known to have been renamed).
+func (t *RenameTable) Resolve(uri string) string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if newURI, present := t.m[uri]; present {
+		return newURI
+	}
+	return uri
+}
+
+// RepoSpec returns spec with its URI replaced by the repository's
+// current URI. Use it to update stored RepoSpecs after renames.
+func (t *RenameTable) RepoSpec(spec RepoSpec) RepoSpec {
+	if spec.URI != "" {
+		spec.URI = t.Resolve(spec.URI)
+	}
+	return spec
+}
+
+// Renames returns a copy of the table's contents, which maps old URIs
+// to new URIs.
+func (t *RenameTable) Renames() map[string]string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	m := make(map[string]string, len(t.m))
+	for k, v := range t.m {
+		m[k] = v
+	}
+	return m
+}
+
+// MarshalJSON implements json.Marshaler.
+func (t *RenameTable) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.Renames())
+}
+
+// UnmarshalJSON implements json.Unmarshaler. The decoded renames are
+// added to the table (without calling OnRename).
+func (t *RenameTable) UnmarshalJSON(data []byte) error {
+	var m map[string]string
+	if err := json.Unmarshal(data, &m); err != nil {
+		return err
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for oldURI, newURI := range m {
+		t.add(oldURI, newURI)
+	}
+	return nil
+}
+
+// maxRenameHops is the maximum number of renames and redirects that
+// are followed for a single call.
+const maxRenameHops = 5
+
+// sendFollowingRenames sends call, following repository renames and
+// redirects and recording them in c.Renames.
+func (c *Client) sendFollowingRenames(call *Call) (Response, error) {
+	if uri := callRepoURI(call); uri != "" {
+		if newURI := c.Renames.Resolve(uri); newURI != uri {
+			if err := c.setCallRepoURI(call, newURI); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for hops := 0; ; hops++ {
+		resp, err := c.sendCall(call)
+		uri := callRepoURI(call)
+		if uri == "" {
+			return resp, err
+		}
+		if err == nil {
+			if alias, current := repoAlias(call.Result); alias == uri && current != "" {
+				c.Renames.Add(alias, current)
+			}
+			return resp, nil
+		}
+
+		newURI := renamedTo(err, uri)
+		if newURI == "" || hops == maxRenameHops {
+			return resp, err
+		}
+		c.Renames.Add(uri, newURI)
+		if c.setCallRepoURI(call, newURI) != nil {
+			return resp, err
+		}
+	}
+}
+
+// callRepoURI returns the repository URI in call's RepoSpec route
+// variable, or "" if it has none (or if it refers to the repository by
+// its RID).
+func callRepoURI(call *Call) string {
+	pc, present := call.RouteVars["RepoSpec"]
+	if !present {
+		return ""
+	}
+	spec, err := ParseRepoSpec(pc)
+	if err != nil {
+		return ""
+	}
+	return spec.URI
+}
+
+// setCallRepoURI replaces the repository URI in call's route variables
+// and request URL.
+func (c *Client) setCallRepoURI(call *Call, uri string) error {
+	vars := make(map[string]string, len(call.RouteVars))
+	for k, v := range call.RouteVars {
+		vars[k] = v
+	}
+	vars["RepoSpec"] = RepoSpec{URI: uri}.PathComponent()
+
+	u, err := c.URL(call.Route, vars, nil)
+	if err != nil {
+		return err
+	}
+	u.RawQuery = call.Request.URL.RawQuery
+
+	req := call.Request.Clone(call.Request.Context())
+	req.URL, req.Host = u, u.Host
+	call.Request, call.RouteVars = req, vars
+	return nil
+}
+
+// renamedTo returns the new URI of the repository at uri if err
+// reports that it was renamed or redirected, or "" otherwise.
+func renamedTo(err error, uri string) string {
+	var (
+		renamed     ErrRenamed
+		redirect    ErrRedirect
+		redirectPtr *ErrRedirect
+		newURI      string
+	)
+	switch {
+	case errors.As(err, &renamed):
+		if renamed.OldURI == uri {
+			newURI = renamed.NewURI
+		}
+	case errors.As(err, &redirect):
+		newURI = redirect.RedirectURI
+	case errors.As(err, &redirectPtr) && redirectPtr != nil:
+		newURI = redirectPtr.RedirectURI
+	}
+	if newURI == uri {
+		return ""
+	}
+	return newURI
+}
+
+// repoAlias returns the URIAlias and URI of the repository that result
+// (the decoded result of a call) holds, if any.
+func repoAlias(result interface{}) (alias, uri string) {
+	var repo *Repo
+	switch v := result.(type) {
+	case *Repo:
+		repo = v
+	case **Repo:
+		repo = *v
+	}
+	if repo == nil {
+		return "", ""
+	}
+	return string(repo.URIAlias), repo.URI
+}
@@ -0,0 +1,110 @@
+package sourcegraph
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"sourcegraph.com/sourcegraph/go-nnz/nnz"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
+)
+
+func TestRenameTable(t *testing.T) {
+	var got [][2]string
+	tbl := &RenameTable{OnRename: func(oldURI, newURI string) {
+		got = append(got, [2]string{oldURI, newURI})
+	}}
+	tbl.Add("a.com/a", "a.com/b")
+	tbl.Add("a.com/a", "a.com/b") // already recorded
+	tbl.Add("a.com/b", "a.com/c")
+	tbl.Add("a.com/x", "a.com/a")
+
+	want := map[string]string{"a.com/a": "a.com/c", "a.com/b": "a.com/c", "a.com/x": "a.com/c"}
+	if renames := tbl.Renames(); !reflect.DeepEqual(renames, want) {
+		t.Errorf("got renames %v, want %v", renames, want)
+	}
+	if wantCalls := [][2]string{{"a.com/a", "a.com/b"}, {"a.com/b", "a.com/c"}, {"a.com/x", "a.com/c"}}; !reflect.DeepEqual(got, wantCalls) {
+		t.Errorf("got OnRename calls %v, want %v", got, wantCalls)
+	}
+	if spec := tbl.RepoSpec(RepoSpec{URI: "a.com/a"}); spec.URI != "a.com/c" {
+		t.Errorf("got RepoSpec URI %q, want a.com/c", spec.URI)
+	}
+
+	// Renaming back removes the rename of the new URI.
+	tbl.Add("a.com/c", "a.com/a")
+	want = map[string]string{"a.com/b": "a.com/a", "a.com/c": "a.com/a", "a.com/x": "a.com/a"}
+	if renames := tbl.Renames(); !reflect.DeepEqual(renames, want) {
+		t.Errorf("after renaming back, got renames %v, want %v", renames, want)
+	}
+
+	data, err := json.Marshal(tbl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tbl2 RenameTable
+	if err := json.Unmarshal(data, &tbl2); err != nil {
+		t.Fatal(err)
+	}
+	if renames := tbl2.Renames(); !reflect.DeepEqual(renames, want) {
+		t.Errorf("after JSON round trip, got renames %v, want %v", renames, want)
+	}
+}
+
+func TestClient_Renames(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var oldCalled, newCalled int
+	mux.HandleFunc(urlPath(t, router.Repo, map[string]string{"RepoSpec": "r.com/old"}), func(w http.ResponseWriter, r *http.Request) {
+		oldCalled++
+		w.WriteHeader(http.StatusMovedPermanently)
+		writeJSON(w, NewErrorResponse(ErrRenamed{OldURI: "r.com/old", NewURI: "r.com/new"}))
+	})
+	mux.HandleFunc(urlPath(t, router.Repo, map[string]string{"RepoSpec": "r.com/new"}), func(w http.ResponseWriter, r *http.Request) {
+		newCalled++
+		if r.URL.RawQuery != "Stats=true" {
+			t.Errorf("got query %q, want it to be preserved", r.URL.RawQuery)
+		}
+		writeJSON(w, &Repo{URI: "r.com/new"})
+	})
+
+	var renames [][2]string
+	client.Renames = &RenameTable{OnRename: func(oldURI, newURI string) {
+		renames = append(renames, [2]string{oldURI, newURI})
+	}}
+
+	for i := 0; i < 2; i++ {
+		repo, _, err := client.Repos.Get(context.Background(), RepoSpec{URI: "r.com/old"}, &RepoGetOptions{Stats: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if repo.URI != "r.com/new" {
+			t.Errorf("got repo URI %q, want r.com/new", repo.URI)
+		}
+	}
+	if oldCalled != 1 || newCalled != 2 {
+		t.Errorf("got %d requests for old URI and %d for new URI, want 1 and 2", oldCalled, newCalled)
+	}
+	if want := [][2]string{{"r.com/old", "r.com/new"}}; !reflect.DeepEqual(renames, want) {
+		t.Errorf("got OnRename calls %v, want %v", renames, want)
+	}
+}
+
+func TestClient_Renames_alias(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc(urlPath(t, router.Repo, map[string]string{"RepoSpec": "alias.com/r"}), func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, &Repo{URI: "r.com/r", URIAlias: nnz.String("alias.com/r")})
+	})
+	client.Renames = &RenameTable{}
+
+	if _, _, err := client.Repos.Get(context.Background(), RepoSpec{URI: "alias.com/r"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if uri := client.Renames.Resolve("alias.com/r"); uri != "r.com/r" {
+		t.Errorf("got alias resolved to %q, want r.com/r", uri)
+	}
+}
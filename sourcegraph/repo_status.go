@@ -1,6 +1,8 @@
 package sourcegraph
 
 import (
+	"context"
+
 	"github.com/sourcegraph/go-github/github"
 	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
 )
@@ -13,13 +15,13 @@ type CombinedStatus struct {
 	github.CombinedStatus
 }
 
-func (s *repositoriesService) GetCombinedStatus(spec RepoRevSpec) (*CombinedStatus, Response, error) {
+func (s *repositoriesService) GetCombinedStatus(ctx context.Context, spec RepoRevSpec) (*CombinedStatus, Response, error) {
 	url, err := s.client.URL(router.RepoCombinedStatus, spec.RouteVars(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", url.String(), nil)
+	req, err := s.client.NewRequest(ctx, "GET", url.String(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -33,13 +35,13 @@ func (s *repositoriesService) GetCombinedStatus(spec RepoRevSpec) (*CombinedStat
 	return &status, resp, nil
 }
 
-func (s *repositoriesService) CreateStatus(spec RepoRevSpec, st RepoStatus) (*RepoStatus, Response, error) {
+func (s *repositoriesService) CreateStatus(ctx context.Context, spec RepoRevSpec, st RepoStatus) (*RepoStatus, Response, error) {
 	url, err := s.client.URL(router.RepoStatusCreate, spec.RouteVars(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("POST", url.String(), st)
+	req, err := s.client.NewRequest(ctx, "POST", url.String(), st)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -1,6 +1,7 @@
 package sourcegraph
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"text/template"
@@ -19,33 +20,33 @@ import (
 // Sourcegraph API.
 type ReposService interface {
 	// Get fetches a repository.
-	Get(repo RepoSpec, opt *RepoGetOptions) (*Repo, Response, error)
+	Get(ctx context.Context, repo RepoSpec, opt *RepoGetOptions) (*Repo, Response, error)
 
 	// GetStats gets statistics about a repository at a specific
 	// commit. Some statistics are per-commit and some are global to
 	// the repository. If you only care about global repository
 	// statistics, pass an empty Rev to the RepoRevSpec (which will be
 	// resolved to the repository's default branch).
-	GetStats(repo RepoRevSpec) (RepoStats, Response, error)
+	GetStats(ctx context.Context, repo RepoRevSpec) (RepoStats, Response, error)
 
 	// CreateStatus creates a repository status for the given commit.
-	CreateStatus(spec RepoRevSpec, st RepoStatus) (*RepoStatus, Response, error)
+	CreateStatus(ctx context.Context, spec RepoRevSpec, st RepoStatus) (*RepoStatus, Response, error)
 
 	// GetCombinedStatus fetches the combined repository status for
 	// the given commit.
-	GetCombinedStatus(spec RepoRevSpec) (*CombinedStatus, Response, error)
+	GetCombinedStatus(ctx context.Context, spec RepoRevSpec) (*CombinedStatus, Response, error)
 
 	// GetOrCreate fetches a repository using Get. If no such repository exists
 	// with the URI, and the URI refers to a recognized repository host (such as
 	// github.com), the repository's information is fetched from the external
 	// host and the repository is created.
-	GetOrCreate(repo RepoSpec, opt *RepoGetOptions) (*Repo, Response, error)
+	GetOrCreate(ctx context.Context, repo RepoSpec, opt *RepoGetOptions) (*Repo, Response, error)
 
 	// GetSettings fetches a repository's configuration settings.
-	GetSettings(repo RepoSpec) (*RepoSettings, Response, error)
+	GetSettings(ctx context.Context, repo RepoSpec) (*RepoSettings, Response, error)
 
 	// UpdateSettings updates a repository's configuration settings.
-	UpdateSettings(repo RepoSpec, settings RepoSettings) (Response, error)
+	UpdateSettings(ctx context.Context, repo RepoSpec, settings RepoSettings) (Response, error)
 
 	// RefreshProfile updates the repository metadata for a repository, fetching
 	// it from an external host if the host is recognized (such as GitHub).
@@ -53,7 +54,7 @@ type ReposService interface {
 	// This operation is performed asynchronously on the server side (after
 	// receiving the request) and the API currently has no way of notifying
 	// callers when the operation completes.
-	RefreshProfile(repo RepoSpec) (Response, error)
+	RefreshProfile(ctx context.Context, repo RepoSpec) (Response, error)
 
 	// RefreshVCSData updates the repository VCS (git/hg) data, fetching all new
 	// commits, branches, tags, and blobs.
@@ -61,76 +62,76 @@ type ReposService interface {
 	// This operation is performed asynchronously on the server side (after
 	// receiving the request) and the API currently has no way of notifying
 	// callers when the operation completes.
-	RefreshVCSData(repo RepoSpec) (Response, error)
+	RefreshVCSData(ctx context.Context, repo RepoSpec) (Response, error)
 
 	// ComputeStats updates the statistics about a repository.
 	//
 	// This operation is performed asynchronously on the server side (after
 	// receiving the request) and the API currently has no way of notifying
 	// callers when the operation completes.
-	ComputeStats(repo RepoRevSpec) (Response, error)
+	ComputeStats(ctx context.Context, repo RepoRevSpec) (Response, error)
 
 	// GetBuild gets the build for a specific revspec. It returns
 	// additional information about the build, such as whether it is
 	// exactly up-to-date with the revspec or a few commits behind the
 	// revspec. The opt param controls what is returned in this case.
-	GetBuild(repo RepoRevSpec, opt *RepoGetBuildOptions) (*RepoBuildInfo, Response, error)
+	GetBuild(ctx context.Context, repo RepoRevSpec, opt *RepoGetBuildOptions) (*RepoBuildInfo, Response, error)
 
 	// Create adds the repository at cloneURL, filling in all information about
 	// the repository that can be inferred from the URL (or, for GitHub
 	// repositories, fetched from the GitHub API). If a repository with the
 	// specified clone URL, or the same URI, already exists, it is returned.
-	Create(newRepoSpec NewRepoSpec) (*Repo, Response, error)
+	Create(ctx context.Context, newRepoSpec NewRepoSpec) (*Repo, Response, error)
 
 	// GetReadme fetches the formatted README file for a repository.
-	GetReadme(repo RepoRevSpec) (*vcsclient.TreeEntry, Response, error)
+	GetReadme(ctx context.Context, repo RepoRevSpec) (*vcsclient.TreeEntry, Response, error)
 
 	// List repositories.
-	List(opt *RepoListOptions) ([]*Repo, Response, error)
+	List(ctx context.Context, opt *RepoListOptions) ([]*Repo, Response, error)
 
 	// List commits.
-	ListCommits(repo RepoSpec, opt *RepoListCommitsOptions) ([]*Commit, Response, error)
+	ListCommits(ctx context.Context, repo RepoSpec, opt *RepoListCommitsOptions) ([]*Commit, Response, error)
 
 	// GetCommit gets a commit.
-	GetCommit(rev RepoRevSpec, opt *RepoGetCommitOptions) (*Commit, Response, error)
+	GetCommit(ctx context.Context, rev RepoRevSpec, opt *RepoGetCommitOptions) (*Commit, Response, error)
 
 	// ListBranches lists a repository's branches.
-	ListBranches(repo RepoSpec, opt *RepoListBranchesOptions) ([]*vcs.Branch, Response, error)
+	ListBranches(ctx context.Context, repo RepoSpec, opt *RepoListBranchesOptions) ([]*vcs.Branch, Response, error)
 
 	// ListTags lists a repository's tags.
-	ListTags(repo RepoSpec, opt *RepoListTagsOptions) ([]*vcs.Tag, Response, error)
+	ListTags(ctx context.Context, repo RepoSpec, opt *RepoListTagsOptions) ([]*vcs.Tag, Response, error)
 
 	// ListBadges lists the available badges for repo.
-	ListBadges(repo RepoSpec) ([]*Badge, Response, error)
+	ListBadges(ctx context.Context, repo RepoSpec) ([]*Badge, Response, error)
 
 	// ListCounters lists the available counters for repo.
-	ListCounters(repo RepoSpec) ([]*Counter, Response, error)
+	ListCounters(ctx context.Context, repo RepoSpec) ([]*Counter, Response, error)
 
 	// ListAuthors lists people who have contributed (i.e., committed) code to
 	// repo.
-	ListAuthors(repo RepoRevSpec, opt *RepoListAuthorsOptions) ([]*AugmentedRepoAuthor, Response, error)
+	ListAuthors(ctx context.Context, repo RepoRevSpec, opt *RepoListAuthorsOptions) ([]*AugmentedRepoAuthor, Response, error)
 
 	// ListClients lists people who reference defs defined in repo.
-	ListClients(repo RepoSpec, opt *RepoListClientsOptions) ([]*AugmentedRepoClient, Response, error)
+	ListClients(ctx context.Context, repo RepoSpec, opt *RepoListClientsOptions) ([]*AugmentedRepoClient, Response, error)
 
 	// ListDependents lists repositories that contain defs referenced by
 	// repo.
-	ListDependencies(repo RepoRevSpec, opt *RepoListDependenciesOptions) ([]*AugmentedRepoDependency, Response, error)
+	ListDependencies(ctx context.Context, repo RepoRevSpec, opt *RepoListDependenciesOptions) ([]*AugmentedRepoDependency, Response, error)
 
 	// ListDependents lists repositories that reference defs defined in repo.
-	ListDependents(repo RepoSpec, opt *RepoListDependentsOptions) ([]*AugmentedRepoDependent, Response, error)
+	ListDependents(ctx context.Context, repo RepoSpec, opt *RepoListDependentsOptions) ([]*AugmentedRepoDependent, Response, error)
 
 	// ListByContributor lists repositories that user has contributed (i.e.,
 	// committed) code to.
-	ListByContributor(user UserSpec, opt *RepoListByContributorOptions) ([]*AugmentedRepoContribution, Response, error)
+	ListByContributor(ctx context.Context, user UserSpec, opt *RepoListByContributorOptions) ([]*AugmentedRepoContribution, Response, error)
 
 	// ListByClient lists repositories that contain defs referenced by
 	// user.
-	ListByClient(user UserSpec, opt *RepoListByClientOptions) ([]*AugmentedRepoUsageByClient, Response, error)
+	ListByClient(ctx context.Context, user UserSpec, opt *RepoListByClientOptions) ([]*AugmentedRepoUsageByClient, Response, error)
 
 	// ListByRefdAuthor lists repositories that reference code authored by
 	// user.
-	ListByRefdAuthor(user UserSpec, opt *RepoListByRefdAuthorOptions) ([]*AugmentedRepoUsageOfAuthor, Response, error)
+	ListByRefdAuthor(ctx context.Context, user UserSpec, opt *RepoListByRefdAuthorOptions) ([]*AugmentedRepoUsageOfAuthor, Response, error)
 }
 
 // repositoriesService implements ReposService.
@@ -291,13 +292,13 @@ type RepoGetOptions struct {
 	Stats bool `url:",omitempty" json:",omitempty"` // whether to fetch and include stats in the returned repository
 }
 
-func (s *repositoriesService) Get(repo RepoSpec, opt *RepoGetOptions) (*Repo, Response, error) {
+func (s *repositoriesService) Get(ctx context.Context, repo RepoSpec, opt *RepoGetOptions) (*Repo, Response, error) {
 	url, err := s.client.URL(router.Repo, repo.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", url.String(), nil)
+	req, err := s.client.NewRequest(ctx, "GET", url.String(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -311,13 +312,13 @@ func (s *repositoriesService) Get(repo RepoSpec, opt *RepoGetOptions) (*Repo, Re
 	return repo_, resp, nil
 }
 
-func (s *repositoriesService) GetStats(repoRev RepoRevSpec) (RepoStats, Response, error) {
+func (s *repositoriesService) GetStats(ctx context.Context, repoRev RepoRevSpec) (RepoStats, Response, error) {
 	url, err := s.client.URL(router.RepoStats, repoRev.RouteVars(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", url.String(), nil)
+	req, err := s.client.NewRequest(ctx, "GET", url.String(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -331,13 +332,13 @@ func (s *repositoriesService) GetStats(repoRev RepoRevSpec) (RepoStats, Response
 	return stats, resp, nil
 }
 
-func (s *repositoriesService) GetOrCreate(repo_ RepoSpec, opt *RepoGetOptions) (*Repo, Response, error) {
+func (s *repositoriesService) GetOrCreate(ctx context.Context, repo_ RepoSpec, opt *RepoGetOptions) (*Repo, Response, error) {
 	url, err := s.client.URL(router.ReposGetOrCreate, repo_.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("PUT", url.String(), nil)
+	req, err := s.client.NewRequest(ctx, "PUT", url.String(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -391,13 +392,13 @@ type RepoSettings struct {
 	UseSSHPrivateKey *bool `db:"use_ssh_private_key" json:",omitempty"`
 }
 
-func (s *repositoriesService) GetSettings(repo RepoSpec) (*RepoSettings, Response, error) {
+func (s *repositoriesService) GetSettings(ctx context.Context, repo RepoSpec) (*RepoSettings, Response, error) {
 	url, err := s.client.URL(router.RepoSettings, repo.RouteVars(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", url.String(), nil)
+	req, err := s.client.NewRequest(ctx, "GET", url.String(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -411,13 +412,13 @@ func (s *repositoriesService) GetSettings(repo RepoSpec) (*RepoSettings, Respons
 	return settings, resp, nil
 }
 
-func (s *repositoriesService) UpdateSettings(repo RepoSpec, settings RepoSettings) (Response, error) {
+func (s *repositoriesService) UpdateSettings(ctx context.Context, repo RepoSpec, settings RepoSettings) (Response, error) {
 	url, err := s.client.URL(router.RepoSettingsUpdate, repo.RouteVars(), nil)
 	if err != nil {
 		return nil, err
 	}
 
-	req, err := s.client.NewRequest("PUT", url.String(), settings)
+	req, err := s.client.NewRequest(ctx, "PUT", url.String(), settings)
 	if err != nil {
 		return nil, err
 	}
@@ -430,13 +431,13 @@ func (s *repositoriesService) UpdateSettings(repo RepoSpec, settings RepoSetting
 	return resp, nil
 }
 
-func (s *repositoriesService) RefreshProfile(repo RepoSpec) (Response, error) {
+func (s *repositoriesService) RefreshProfile(ctx context.Context, repo RepoSpec) (Response, error) {
 	url, err := s.client.URL(router.RepoRefreshProfile, repo.RouteVars(), nil)
 	if err != nil {
 		return nil, err
 	}
 
-	req, err := s.client.NewRequest("PUT", url.String(), nil)
+	req, err := s.client.NewRequest(ctx, "PUT", url.String(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -449,13 +450,13 @@ func (s *repositoriesService) RefreshProfile(repo RepoSpec) (Response, error) {
 	return resp, nil
 }
 
-func (s *repositoriesService) RefreshVCSData(repo RepoSpec) (Response, error) {
+func (s *repositoriesService) RefreshVCSData(ctx context.Context, repo RepoSpec) (Response, error) {
 	url, err := s.client.URL(router.RepoRefreshVCSData, repo.RouteVars(), nil)
 	if err != nil {
 		return nil, err
 	}
 
-	req, err := s.client.NewRequest("PUT", url.String(), nil)
+	req, err := s.client.NewRequest(ctx, "PUT", url.String(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -468,13 +469,13 @@ func (s *repositoriesService) RefreshVCSData(repo RepoSpec) (Response, error) {
 	return resp, nil
 }
 
-func (s *repositoriesService) ComputeStats(repo RepoRevSpec) (Response, error) {
+func (s *repositoriesService) ComputeStats(ctx context.Context, repo RepoRevSpec) (Response, error) {
 	url, err := s.client.URL(router.RepoComputeStats, repo.RouteVars(), nil)
 	if err != nil {
 		return nil, err
 	}
 
-	req, err := s.client.NewRequest("PUT", url.String(), nil)
+	req, err := s.client.NewRequest(ctx, "PUT", url.String(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -521,13 +522,13 @@ type RepoBuildInfo struct {
 	LastSuccessfulCommit *Commit // the commit of the LastSuccessful build
 }
 
-func (s *repositoriesService) GetBuild(repo RepoRevSpec, opt *RepoGetBuildOptions) (*RepoBuildInfo, Response, error) {
+func (s *repositoriesService) GetBuild(ctx context.Context, repo RepoRevSpec, opt *RepoGetBuildOptions) (*RepoBuildInfo, Response, error) {
 	url, err := s.client.URL(router.RepoBuild, repo.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", url.String(), nil)
+	req, err := s.client.NewRequest(ctx, "GET", url.String(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -546,13 +547,13 @@ type NewRepoSpec struct {
 	CloneURLStr string `json:"CloneURL"`
 }
 
-func (s *repositoriesService) Create(newRepoSpec NewRepoSpec) (*Repo, Response, error) {
+func (s *repositoriesService) Create(ctx context.Context, newRepoSpec NewRepoSpec) (*Repo, Response, error) {
 	url, err := s.client.URL(router.ReposCreate, nil, nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("POST", url.String(), newRepoSpec)
+	req, err := s.client.NewRequest(ctx, "POST", url.String(), newRepoSpec)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -566,13 +567,13 @@ func (s *repositoriesService) Create(newRepoSpec NewRepoSpec) (*Repo, Response,
 	return repo_, resp, nil
 }
 
-func (s *repositoriesService) GetReadme(repo RepoRevSpec) (*vcsclient.TreeEntry, Response, error) {
+func (s *repositoriesService) GetReadme(ctx context.Context, repo RepoRevSpec) (*vcsclient.TreeEntry, Response, error) {
 	url, err := s.client.URL(router.RepoReadme, repo.RouteVars(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", url.String(), nil)
+	req, err := s.client.NewRequest(ctx, "GET", url.String(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -612,13 +613,13 @@ type RepoListOptions struct {
 	ListOptions
 }
 
-func (s *repositoriesService) List(opt *RepoListOptions) ([]*Repo, Response, error) {
+func (s *repositoriesService) List(ctx context.Context, opt *RepoListOptions) ([]*Repo, Response, error) {
 	url, err := s.client.URL(router.Repos, nil, opt)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", url.String(), nil)
+	req, err := s.client.NewRequest(ctx, "GET", url.String(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -642,13 +643,13 @@ type RepoListCommitsOptions struct {
 	ListOptions
 }
 
-func (s *repositoriesService) ListCommits(repo RepoSpec, opt *RepoListCommitsOptions) ([]*Commit, Response, error) {
+func (s *repositoriesService) ListCommits(ctx context.Context, repo RepoSpec, opt *RepoListCommitsOptions) ([]*Commit, Response, error) {
 	url, err := s.client.URL(router.RepoCommits, repo.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", url.String(), nil)
+	req, err := s.client.NewRequest(ctx, "GET", url.String(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -665,13 +666,13 @@ func (s *repositoriesService) ListCommits(repo RepoSpec, opt *RepoListCommitsOpt
 type RepoGetCommitOptions struct {
 }
 
-func (s *repositoriesService) GetCommit(rev RepoRevSpec, opt *RepoGetCommitOptions) (*Commit, Response, error) {
+func (s *repositoriesService) GetCommit(ctx context.Context, rev RepoRevSpec, opt *RepoGetCommitOptions) (*Commit, Response, error) {
 	url, err := s.client.URL(router.RepoCommit, rev.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", url.String(), nil)
+	req, err := s.client.NewRequest(ctx, "GET", url.String(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -689,13 +690,13 @@ type RepoListBranchesOptions struct {
 	ListOptions
 }
 
-func (s *repositoriesService) ListBranches(repo RepoSpec, opt *RepoListBranchesOptions) ([]*vcs.Branch, Response, error) {
+func (s *repositoriesService) ListBranches(ctx context.Context, repo RepoSpec, opt *RepoListBranchesOptions) ([]*vcs.Branch, Response, error) {
 	url, err := s.client.URL(router.RepoBranches, repo.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", url.String(), nil)
+	req, err := s.client.NewRequest(ctx, "GET", url.String(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -713,13 +714,13 @@ type RepoListTagsOptions struct {
 	ListOptions
 }
 
-func (s *repositoriesService) ListTags(repo RepoSpec, opt *RepoListTagsOptions) ([]*vcs.Tag, Response, error) {
+func (s *repositoriesService) ListTags(ctx context.Context, repo RepoSpec, opt *RepoListTagsOptions) ([]*vcs.Tag, Response, error) {
 	url, err := s.client.URL(router.RepoTags, repo.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", url.String(), nil)
+	req, err := s.client.NewRequest(ctx, "GET", url.String(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -745,13 +746,13 @@ func (b *Badge) HTML() string {
 	return fmt.Sprintf(`<img src="%s" alt="%s">`, template.HTMLEscapeString(b.ImageURL), template.HTMLEscapeString(b.Name))
 }
 
-func (s *repositoriesService) ListBadges(repo RepoSpec) ([]*Badge, Response, error) {
+func (s *repositoriesService) ListBadges(ctx context.Context, repo RepoSpec) ([]*Badge, Response, error) {
 	url, err := s.client.URL(router.RepoBadges, repo.RouteVars(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", url.String(), nil)
+	req, err := s.client.NewRequest(ctx, "GET", url.String(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -777,13 +778,13 @@ func (c *Counter) HTML() string {
 	return fmt.Sprintf(`<img src="%s" alt="%s">`, template.HTMLEscapeString(c.ImageURL), template.HTMLEscapeString(c.Name))
 }
 
-func (s *repositoriesService) ListCounters(repo RepoSpec) ([]*Counter, Response, error) {
+func (s *repositoriesService) ListCounters(ctx context.Context, repo RepoSpec) ([]*Counter, Response, error) {
 	url, err := s.client.URL(router.RepoCounters, repo.RouteVars(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", url.String(), nil)
+	req, err := s.client.NewRequest(ctx, "GET", url.String(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -814,13 +815,13 @@ type RepoListAuthorsOptions struct {
 	ListOptions
 }
 
-func (s *repositoriesService) ListAuthors(repo RepoRevSpec, opt *RepoListAuthorsOptions) ([]*AugmentedRepoAuthor, Response, error) {
+func (s *repositoriesService) ListAuthors(ctx context.Context, repo RepoRevSpec, opt *RepoListAuthorsOptions) ([]*AugmentedRepoAuthor, Response, error) {
 	url, err := s.client.URL(router.RepoAuthors, repo.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", url.String(), nil)
+	req, err := s.client.NewRequest(ctx, "GET", url.String(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -870,13 +871,13 @@ type RepoListClientsOptions struct {
 	ListOptions
 }
 
-func (s *repositoriesService) ListClients(repo RepoSpec, opt *RepoListClientsOptions) ([]*AugmentedRepoClient, Response, error) {
+func (s *repositoriesService) ListClients(ctx context.Context, repo RepoSpec, opt *RepoListClientsOptions) ([]*AugmentedRepoClient, Response, error) {
 	url, err := s.client.URL(router.RepoClients, repo.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", url.String(), nil)
+	req, err := s.client.NewRequest(ctx, "GET", url.String(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -903,13 +904,13 @@ type RepoListDependenciesOptions struct {
 	ListOptions
 }
 
-func (s *repositoriesService) ListDependencies(repo RepoRevSpec, opt *RepoListDependenciesOptions) ([]*AugmentedRepoDependency, Response, error) {
+func (s *repositoriesService) ListDependencies(ctx context.Context, repo RepoRevSpec, opt *RepoListDependenciesOptions) ([]*AugmentedRepoDependency, Response, error) {
 	url, err := s.client.URL(router.RepoDependencies, repo.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", url.String(), nil)
+	req, err := s.client.NewRequest(ctx, "GET", url.String(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -934,13 +935,13 @@ type AugmentedRepoDependent struct {
 
 type RepoListDependentsOptions struct{ ListOptions }
 
-func (s *repositoriesService) ListDependents(repo RepoSpec, opt *RepoListDependentsOptions) ([]*AugmentedRepoDependent, Response, error) {
+func (s *repositoriesService) ListDependents(ctx context.Context, repo RepoSpec, opt *RepoListDependentsOptions) ([]*AugmentedRepoDependent, Response, error) {
 	url, err := s.client.URL(router.RepoDependents, repo.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", url.String(), nil)
+	req, err := s.client.NewRequest(ctx, "GET", url.String(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -988,13 +989,13 @@ type RepoListByContributorOptions struct {
 	ListOptions
 }
 
-func (s *repositoriesService) ListByContributor(user UserSpec, opt *RepoListByContributorOptions) ([]*AugmentedRepoContribution, Response, error) {
+func (s *repositoriesService) ListByContributor(ctx context.Context, user UserSpec, opt *RepoListByContributorOptions) ([]*AugmentedRepoContribution, Response, error) {
 	url, err := s.client.URL(router.UserRepoContributions, user.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", url.String(), nil)
+	req, err := s.client.NewRequest(ctx, "GET", url.String(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -1033,13 +1034,13 @@ type RepoListByClientOptions struct {
 	ListOptions
 }
 
-func (s *repositoriesService) ListByClient(user UserSpec, opt *RepoListByClientOptions) ([]*AugmentedRepoUsageByClient, Response, error) {
+func (s *repositoriesService) ListByClient(ctx context.Context, user UserSpec, opt *RepoListByClientOptions) ([]*AugmentedRepoUsageByClient, Response, error) {
 	url, err := s.client.URL(router.UserRepoDependencies, user.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", url.String(), nil)
+	req, err := s.client.NewRequest(ctx, "GET", url.String(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -1072,13 +1073,13 @@ type RepoListByRefdAuthorOptions struct {
 	ListOptions
 }
 
-func (s *repositoriesService) ListByRefdAuthor(user UserSpec, opt *RepoListByRefdAuthorOptions) ([]*AugmentedRepoUsageOfAuthor, Response, error) {
+func (s *repositoriesService) ListByRefdAuthor(ctx context.Context, user UserSpec, opt *RepoListByRefdAuthorOptions) ([]*AugmentedRepoUsageOfAuthor, Response, error) {
 	url, err := s.client.URL(router.UserRepoDependents, user.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", url.String(), nil)
+	req, err := s.client.NewRequest(ctx, "GET", url.String(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -1,6 +1,7 @@
 package sourcegraph
 
 import (
+	"context"
 	"net/http"
 	"reflect"
 	"testing"
@@ -89,7 +90,7 @@ func TestReposService_Get(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	repo_, _, err := client.Repos.Get(RepoSpec{URI: "r.com/x"}, nil)
+	repo_, _, err := client.Repos.Get(context.Background(), RepoSpec{URI: "r.com/x"}, nil)
 	if err != nil {
 		t.Errorf("Repos.Get returned error: %v", err)
 	}
@@ -118,7 +119,7 @@ func TestReposService_GetStats(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	stats, _, err := client.Repos.GetStats(RepoRevSpec{RepoSpec: RepoSpec{URI: "r.com/x"}, Rev: "c"})
+	stats, _, err := client.Repos.GetStats(context.Background(), RepoRevSpec{RepoSpec: RepoSpec{URI: "r.com/x"}, Rev: "c"})
 	if err != nil {
 		t.Errorf("Repos.GetStats returned error: %v", err)
 	}
@@ -146,7 +147,7 @@ func TestReposService_GetOrCreate(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	repo_, _, err := client.Repos.GetOrCreate(RepoSpec{URI: "r.com/x"}, nil)
+	repo_, _, err := client.Repos.GetOrCreate(context.Background(), RepoSpec{URI: "r.com/x"}, nil)
 	if err != nil {
 		t.Errorf("Repos.GetOrCreate returned error: %v", err)
 	}
@@ -175,7 +176,7 @@ func TestReposService_GetSettings(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	settings, _, err := client.Repos.GetSettings(RepoSpec{URI: "r.com/x"})
+	settings, _, err := client.Repos.GetSettings(context.Background(), RepoSpec{URI: "r.com/x"})
 	if err != nil {
 		t.Errorf("Repos.GetSettings returned error: %v", err)
 	}
@@ -204,7 +205,7 @@ func TestReposService_UpdateSettings(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	_, err := client.Repos.UpdateSettings(RepoSpec{URI: "r.com/x"}, want)
+	_, err := client.Repos.UpdateSettings(context.Background(), RepoSpec{URI: "r.com/x"}, want)
 	if err != nil {
 		t.Errorf("Repos.UpdateSettings returned error: %v", err)
 	}
@@ -224,7 +225,7 @@ func TestReposService_RefreshProfile(t *testing.T) {
 		testMethod(t, r, "PUT")
 	})
 
-	_, err := client.Repos.RefreshProfile(RepoSpec{URI: "r.com/x"})
+	_, err := client.Repos.RefreshProfile(context.Background(), RepoSpec{URI: "r.com/x"})
 	if err != nil {
 		t.Errorf("Repos.RefreshProfile returned error: %v", err)
 	}
@@ -244,7 +245,7 @@ func TestReposService_RefreshVCSData(t *testing.T) {
 		testMethod(t, r, "PUT")
 	})
 
-	_, err := client.Repos.RefreshVCSData(RepoSpec{URI: "r.com/x"})
+	_, err := client.Repos.RefreshVCSData(context.Background(), RepoSpec{URI: "r.com/x"})
 	if err != nil {
 		t.Errorf("Repos.RefreshVCSData returned error: %v", err)
 	}
@@ -264,7 +265,7 @@ func TestReposService_ComputeStats(t *testing.T) {
 		testMethod(t, r, "PUT")
 	})
 
-	_, err := client.Repos.ComputeStats(RepoRevSpec{RepoSpec: RepoSpec{URI: "r.com/x"}, Rev: "c"})
+	_, err := client.Repos.ComputeStats(context.Background(), RepoRevSpec{RepoSpec: RepoSpec{URI: "r.com/x"}, Rev: "c"})
 	if err != nil {
 		t.Errorf("Repos.ComputeStats returned error: %v", err)
 	}
@@ -296,7 +297,7 @@ func TestReposService_GetBuild(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	buildInfo, _, err := client.Repos.GetBuild(RepoRevSpec{RepoSpec: RepoSpec{URI: "r.com/x"}, Rev: "r"}, nil)
+	buildInfo, _, err := client.Repos.GetBuild(context.Background(), RepoRevSpec{RepoSpec: RepoSpec{URI: "r.com/x"}, Rev: "r"}, nil)
 	if err != nil {
 		t.Errorf("Repos.GetBuild returned error: %v", err)
 	}
@@ -329,7 +330,7 @@ func TestReposService_Create(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	repo_, _, err := client.Repos.Create(newRepo)
+	repo_, _, err := client.Repos.Create(context.Background(), newRepo)
 	if err != nil {
 		t.Errorf("Repos.Create returned error: %v", err)
 	}
@@ -359,7 +360,7 @@ func TestReposService_GetReadme(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	readme, _, err := client.Repos.GetReadme(RepoRevSpec{RepoSpec: RepoSpec{URI: "r.com/x"}})
+	readme, _, err := client.Repos.GetReadme(context.Background(), RepoRevSpec{RepoSpec: RepoSpec{URI: "r.com/x"}})
 	if err != nil {
 		t.Errorf("Repos.GetReadme returned error: %v", err)
 	}
@@ -397,7 +398,7 @@ func TestReposService_List(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	repos, _, err := client.Repos.List(&RepoListOptions{
+	repos, _, err := client.Repos.List(context.Background(), &RepoListOptions{
 		URIs:        []string{"a", "b"},
 		Name:        "n",
 		Owner:       "o",
@@ -436,7 +437,7 @@ func TestReposService_ListCommits(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	commits, _, err := client.Repos.ListCommits(RepoSpec{URI: "r.com/x"}, &RepoListCommitsOptions{Head: "myhead"})
+	commits, _, err := client.Repos.ListCommits(context.Background(), RepoSpec{URI: "r.com/x"}, &RepoListCommitsOptions{Head: "myhead"})
 	if err != nil {
 		t.Errorf("Repos.ListCommits returned error: %v", err)
 	}
@@ -465,7 +466,7 @@ func TestReposService_GetCommit(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	commit, _, err := client.Repos.GetCommit(RepoRevSpec{RepoSpec: RepoSpec{URI: "r.com/x"}, Rev: "r"}, nil)
+	commit, _, err := client.Repos.GetCommit(context.Background(), RepoRevSpec{RepoSpec: RepoSpec{URI: "r.com/x"}, Rev: "r"}, nil)
 	if err != nil {
 		t.Errorf("Repos.GetCommit returned error: %v", err)
 	}
@@ -493,7 +494,7 @@ func TestReposService_ListBranches(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	branches, _, err := client.Repos.ListBranches(RepoSpec{URI: "r.com/x"}, nil)
+	branches, _, err := client.Repos.ListBranches(context.Background(), RepoSpec{URI: "r.com/x"}, nil)
 	if err != nil {
 		t.Errorf("Repos.ListBranches returned error: %v", err)
 	}
@@ -521,7 +522,7 @@ func TestReposService_ListTags(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	tags, _, err := client.Repos.ListTags(RepoSpec{URI: "r.com/x"}, nil)
+	tags, _, err := client.Repos.ListTags(context.Background(), RepoSpec{URI: "r.com/x"}, nil)
 	if err != nil {
 		t.Errorf("Repos.ListTags returned error: %v", err)
 	}
@@ -549,7 +550,7 @@ func TestReposService_ListBadges(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	badges, _, err := client.Repos.ListBadges(RepoSpec{URI: "r.com/x"})
+	badges, _, err := client.Repos.ListBadges(context.Background(), RepoSpec{URI: "r.com/x"})
 	if err != nil {
 		t.Errorf("Repos.ListBadges returned error: %v", err)
 	}
@@ -577,7 +578,7 @@ func TestReposService_ListCounters(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	counters, _, err := client.Repos.ListCounters(RepoSpec{URI: "r.com/x"})
+	counters, _, err := client.Repos.ListCounters(context.Background(), RepoSpec{URI: "r.com/x"})
 	if err != nil {
 		t.Errorf("Repos.ListCounters returned error: %v", err)
 	}
@@ -605,7 +606,7 @@ func TestReposService_ListAuthors(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	authors, _, err := client.Repos.ListAuthors(RepoRevSpec{RepoSpec: RepoSpec{URI: "r.com/x"}, Rev: "c"}, nil)
+	authors, _, err := client.Repos.ListAuthors(context.Background(), RepoRevSpec{RepoSpec: RepoSpec{URI: "r.com/x"}, Rev: "c"}, nil)
 	if err != nil {
 		t.Errorf("Repos.ListAuthors returned error: %v", err)
 	}
@@ -633,7 +634,7 @@ func TestReposService_ListClients(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	clients, _, err := client.Repos.ListClients(RepoSpec{URI: "r.com/x"}, nil)
+	clients, _, err := client.Repos.ListClients(context.Background(), RepoSpec{URI: "r.com/x"}, nil)
 	if err != nil {
 		t.Errorf("Repos.ListClients returned error: %v", err)
 	}
@@ -661,7 +662,7 @@ func TestReposService_ListDependents(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	dependents, _, err := client.Repos.ListDependents(RepoSpec{URI: "r.com/x"}, nil)
+	dependents, _, err := client.Repos.ListDependents(context.Background(), RepoSpec{URI: "r.com/x"}, nil)
 	if err != nil {
 		t.Errorf("Repos.ListDependents returned error: %v", err)
 	}
@@ -690,7 +691,7 @@ func TestReposService_ListDependencies(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	dependencies, _, err := client.Repos.ListDependencies(RepoRevSpec{RepoSpec: RepoSpec{URI: "r.com/x"}, Rev: "c"}, nil)
+	dependencies, _, err := client.Repos.ListDependencies(context.Background(), RepoRevSpec{RepoSpec: RepoSpec{URI: "r.com/x"}, Rev: "c"}, nil)
 	if err != nil {
 		t.Errorf("Repos.ListDependencies returned error: %v", err)
 	}
@@ -720,7 +721,7 @@ func TestReposService_ListByContributor(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	repos, _, err := client.Repos.ListByContributor(UserSpec{Login: "a"}, &RepoListByContributorOptions{NoFork: true})
+	repos, _, err := client.Repos.ListByContributor(context.Background(), UserSpec{Login: "a"}, &RepoListByContributorOptions{NoFork: true})
 	if err != nil {
 		t.Errorf("Repos.ListByContributor returned error: %v", err)
 	}
@@ -749,7 +750,7 @@ func TestReposService_ListByClient(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	repos, _, err := client.Repos.ListByClient(UserSpec{Login: "a"}, nil)
+	repos, _, err := client.Repos.ListByClient(context.Background(), UserSpec{Login: "a"}, nil)
 	if err != nil {
 		t.Errorf("Repos.ListByClient returned error: %v", err)
 	}
@@ -778,7 +779,7 @@ func TestReposService_ListByRefdAuthor(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	repos, _, err := client.Repos.ListByRefdAuthor(UserSpec{Login: "a"}, nil)
+	repos, _, err := client.Repos.ListByRefdAuthor(context.Background(), UserSpec{Login: "a"}, nil)
 	if err != nil {
 		t.Errorf("Repos.ListByRefdAuthor returned error: %v", err)
 	}
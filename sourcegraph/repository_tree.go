@@ -1,6 +1,7 @@
 package sourcegraph
 
 import (
+	"context"
 	"fmt"
 
 	"sourcegraph.com/sourcegraph/vcsstore/vcsclient"
@@ -12,8 +13,8 @@ import (
 // RepoTreeService communicates with the Sourcegraph API endpoints that
 // fetch file and directory entries in repositories.
 type RepoTreeService interface {
-	Get(entry TreeEntrySpec, opt *RepoTreeGetOptions) (*TreeEntry, Response, error)
-	Search(RepoRevSpec, *RepoTreeSearchOptions) ([]*vcs.SearchResult, Response, error)
+	Get(ctx context.Context, entry TreeEntrySpec, opt *RepoTreeGetOptions) (*TreeEntry, Response, error)
+	Search(context.Context, RepoRevSpec, *RepoTreeSearchOptions) ([]*vcs.SearchResult, Response, error)
 }
 
 type repoTreeService struct {
@@ -143,13 +144,13 @@ type RepoTreeGetOptions struct {
 	vcsclient.GetFileOptions
 }
 
-func (s *repoTreeService) Get(entry TreeEntrySpec, opt *RepoTreeGetOptions) (*TreeEntry, Response, error) {
+func (s *repoTreeService) Get(ctx context.Context, entry TreeEntrySpec, opt *RepoTreeGetOptions) (*TreeEntry, Response, error) {
 	url, err := s.client.URL(router.RepoTreeEntry, entry.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", url.String(), nil)
+	req, err := s.client.NewRequest(ctx, "GET", url.String(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -168,13 +169,13 @@ type RepoTreeSearchOptions struct {
 	Formatted bool
 }
 
-func (s *repoTreeService) Search(repoRev RepoRevSpec, opt *RepoTreeSearchOptions) ([]*vcs.SearchResult, Response, error) {
+func (s *repoTreeService) Search(ctx context.Context, repoRev RepoRevSpec, opt *RepoTreeSearchOptions) ([]*vcs.SearchResult, Response, error) {
 	url, err := s.client.URL(router.RepoTreeSearch, repoRev.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", url.String(), nil)
+	req, err := s.client.NewRequest(ctx, "GET", url.String(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -1,16 +1,20 @@
 package sourcegraph
 
-import "sourcegraph.com/sourcegraph/go-vcs/vcs"
+import (
+	"context"
+
+	"sourcegraph.com/sourcegraph/go-vcs/vcs"
+)
 
 type MockRepoTreeService struct {
-	Get_    func(entry TreeEntrySpec, opt *RepoTreeGetOptions) (*TreeEntry, Response, error)
-	Search_ func(RepoRevSpec, *RepoTreeSearchOptions) ([]*vcs.SearchResult, Response, error)
+	Get_    func(ctx context.Context, entry TreeEntrySpec, opt *RepoTreeGetOptions) (*TreeEntry, Response, error)
+	Search_ func(context.Context, RepoRevSpec, *RepoTreeSearchOptions) ([]*vcs.SearchResult, Response, error)
 }
 
-func (s MockRepoTreeService) Get(entry TreeEntrySpec, opt *RepoTreeGetOptions) (*TreeEntry, Response, error) {
-	return s.Get_(entry, opt)
+func (s MockRepoTreeService) Get(ctx context.Context, entry TreeEntrySpec, opt *RepoTreeGetOptions) (*TreeEntry, Response, error) {
+	return s.Get_(ctx, entry, opt)
 }
 
-func (s MockRepoTreeService) Search(rev RepoRevSpec, opt *RepoTreeSearchOptions) ([]*vcs.SearchResult, Response, error) {
-	return s.Search_(rev, opt)
+func (s MockRepoTreeService) Search(ctx context.Context, rev RepoRevSpec, opt *RepoTreeSearchOptions) ([]*vcs.SearchResult, Response, error) {
+	return s.Search_(ctx, rev, opt)
 }
@@ -1,6 +1,7 @@
 package sourcegraph
 
 import (
+	"context"
 	"net/http"
 	"reflect"
 	"testing"
@@ -46,7 +47,7 @@ func TestRepoTreeService_Get(t *testing.T) {
 			ExpandContextLines: 2,
 		},
 	}
-	data, _, err := client.RepoTree.Get(TreeEntrySpec{
+	data, _, err := client.RepoTree.Get(context.Background(), TreeEntrySpec{
 		RepoRev: RepoRevSpec{RepoSpec: RepoSpec{URI: "r.com/x"}, Rev: "v"},
 		Path:    "p",
 	}, opt)
@@ -96,7 +97,7 @@ func TestRepoTreeService_Search(t *testing.T) {
 		Formatted:     true,
 		SearchOptions: vcs.SearchOptions{Query: "q", QueryType: "t", N: 3, ContextLines: 1},
 	}
-	data, _, err := client.RepoTree.Search(RepoRevSpec{RepoSpec: RepoSpec{URI: "r.com/x"}, Rev: "v"}, &opt)
+	data, _, err := client.RepoTree.Search(context.Background(), RepoRevSpec{RepoSpec: RepoSpec{URI: "r.com/x"}, Rev: "v"}, &opt)
 	if err != nil {
 		t.Errorf("RepoTree.Search returned error: %v", err)
 	}
@@ -0,0 +1,217 @@
+package sourcegraph
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// A RetryPolicy configures how (*Client).Do retries requests that
+// fail with a transient error (such as a 502, 503 or 429 response, or
+// a connection reset). The zero value never retries.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of times a request is sent,
+	// including the first attempt. If MaxAttempts <= 1, requests are
+	// never retried.
+	MaxAttempts int
+
+	// MinBackoff is the backoff before the first retry. Each
+	// subsequent retry doubles the backoff, up to MaxBackoff. A
+	// random jitter of up to half of the backoff is subtracted from
+	// each wait so that concurrent clients don't retry in lockstep.
+	MinBackoff time.Duration
+
+	// MaxBackoff is the maximum backoff between attempts (before
+	// jitter is applied). If zero, defaultMaxBackoff is used. It does
+	// not limit waits requested by the server in a Retry-After header
+	// (see MaxRetryAfter).
+	MaxBackoff time.Duration
+
+	// MaxRetryAfter is the longest wait requested by the server in a
+	// Retry-After header that will be honored. If the server asks the
+	// client to wait longer, the request is not retried and the
+	// response is returned as-is. If zero, any Retry-After wait is
+	// honored (subject to the request's context deadline).
+	MaxRetryAfter time.Duration
+
+	// RetryNonIdempotent is whether requests with non-idempotent
+	// methods (POST and PATCH) are retried after a failure that the
+	// server might have acted upon (e.g., a 502 or a connection
+	// reset). Such requests are always retried after a 429 Too Many
+	// Requests response, because the server rejected them without
+	// processing them.
+	RetryNonIdempotent bool
+}
+
+// DefaultRetryPolicy is a reasonable RetryPolicy for most clients. It
+// is not used unless it is assigned to a Client's RetryPolicy field.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:   4,
+	MinBackoff:    250 * time.Millisecond,
+	MaxBackoff:    10 * time.Second,
+	MaxRetryAfter: time.Minute,
+}
+
+// retryableStatus holds the HTTP status codes of responses that
+// indicate a transient server-side failure.
+var retryableStatus = map[int]bool{
+	http.StatusTooManyRequests:    true,
+	http.StatusBadGateway:         true,
+	http.StatusServiceUnavailable: true,
+	http.StatusGatewayTimeout:     true,
+}
+
+// isIdempotent returns whether requests with the given HTTP method
+// may safely be sent more than once.
+func isIdempotent(method string) bool {
+	switch method {
+	case "GET", "HEAD", "OPTIONS", "TRACE", "PUT", "DELETE":
+		return true
+	}
+	return false
+}
+
+// isTransientError returns whether err (returned by an HTTP client)
+// indicates that the connection failed in a way that a retry might
+// fix.
+func isTransientError(err error) bool {
+	return errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF)
+}
+
+// shouldRetry returns whether a request that resulted in resp and
+// err should be retried, and how long to wait before retrying it.
+// The attempt param is the 1-indexed attempt that was just made.
+func (p *RetryPolicy) shouldRetry(req *http.Request, resp *http.Response, err error, attempt int) (bool, time.Duration) {
+	if attempt >= p.MaxAttempts || req.Context().Err() != nil {
+		return false, 0
+	}
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		// The body can't be replayed.
+		return false, 0
+	}
+
+	switch {
+	case err != nil:
+		if !isTransientError(err) || (!isIdempotent(req.Method) && !p.RetryNonIdempotent) {
+			return false, 0
+		}
+	case resp != nil && retryableStatus[resp.StatusCode]:
+		if resp.StatusCode != http.StatusTooManyRequests && !isIdempotent(req.Method) && !p.RetryNonIdempotent {
+			return false, 0
+		}
+		if wait, ok := parseRetryAfter(resp.Header.Get("retry-after"), time.Now()); ok {
+			if p.MaxRetryAfter != 0 && wait > p.MaxRetryAfter {
+				return false, 0
+			}
+			return true, wait
+		}
+	default:
+		return false, 0
+	}
+	return true, p.backoff(attempt)
+}
+
+// defaultMaxBackoff is the maximum backoff used when a RetryPolicy's
+// MaxBackoff is unset.
+const defaultMaxBackoff = time.Minute
+
+var (
+	jitterMu   sync.Mutex
+	jitterRand = rand.New(rand.NewSource(time.Now().UnixNano()))
+)
+
+// backoff returns the (jittered) exponential backoff to wait after
+// the given 1-indexed attempt.
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	maxBackoff := p.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = defaultMaxBackoff
+	}
+	d := p.MinBackoff
+	for i := 1; i < attempt && d > 0 && d < maxBackoff; i++ {
+		if d > maxBackoff/2 {
+			// Doubling would pass maxBackoff (and might overflow).
+			d = maxBackoff
+			break
+		}
+		d *= 2
+	}
+	if d > maxBackoff {
+		d = maxBackoff
+	}
+	if d <= 1 {
+		return d
+	}
+	jitterMu.Lock()
+	jitter := time.Duration(jitterRand.Int63n(int64(d / 2)))
+	jitterMu.Unlock()
+	return d - jitter
+}
+
+// parseRetryAfter parses the value of a Retry-After HTTP header,
+// which is either a number of seconds or an HTTP date. It returns
+// false if the value is empty or invalid.
+func parseRetryAfter(v string, now time.Time) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	t, err := http.ParseTime(v)
+	if err != nil {
+		return 0, false
+	}
+	if d := t.Sub(now); d > 0 {
+		return d, true
+	}
+	return 0, true
+}
+
+// send sends req using the client's HTTP client, retrying it
+// according to the client's RetryPolicy. Only the final attempt's
+// response (or error) is returned; the bodies of earlier responses
+// are discarded.
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	if c.RetryPolicy == nil {
+		return c.roundTrip(req)
+	}
+
+	for attempt := 1; ; attempt++ {
+		if attempt > 1 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+
+		resp, err := c.roundTrip(req)
+		retry, wait := c.RetryPolicy.shouldRetry(req, resp, err, attempt)
+		if !retry {
+			return resp, err
+		}
+		if resp != nil {
+			io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		t := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			t.Stop()
+			return nil, req.Context().Err()
+		case <-t.C:
+		}
+	}
+}
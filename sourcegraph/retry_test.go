@@ -0,0 +1,158 @@
+package sourcegraph
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestClient_Do_retry(t *testing.T) {
+	setup()
+	defer teardown()
+
+	client.RetryPolicy = &RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond}
+
+	var calls int
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		testMethod(t, r, "PUT")
+		testBody(t, r, `{"A":1}`+"\n")
+		if calls < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		writeJSON(w, map[string]int{"B": 2})
+	})
+
+	req, err := client.NewRequest(context.Background(), "PUT", server.URL, map[string]int{"A": 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var v map[string]int
+	if _, err := client.Do(req, &v); err != nil {
+		t.Fatalf("Do returned error: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("got %d calls, want 3", calls)
+	}
+	if v["B"] != 2 {
+		t.Errorf("got %v, want B=2", v)
+	}
+}
+
+func TestClient_Do_retryExhausted(t *testing.T) {
+	setup()
+	defer teardown()
+
+	client.RetryPolicy = &RetryPolicy{MaxAttempts: 2, MinBackoff: time.Millisecond}
+
+	var calls int
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusBadGateway)
+		writeJSON(w, ErrorResponse{Message: "attempt"})
+	})
+
+	req, err := client.NewRequest(context.Background(), "GET", server.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.Do(req, nil)
+	if !IsHTTPErrorCode(err, http.StatusBadGateway) {
+		t.Fatalf("got error %v, want 502 error", err)
+	}
+	if msg := err.(*ErrorResponse).Message; msg != "attempt" {
+		t.Errorf("got message %q, want %q", msg, "attempt")
+	}
+	if calls != 2 {
+		t.Errorf("got %d calls, want 2", calls)
+	}
+}
+
+func TestClient_Do_retryNonIdempotent(t *testing.T) {
+	setup()
+	defer teardown()
+
+	client.RetryPolicy = &RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond}
+
+	var calls int
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			// Rejected without processing, so even a POST is retried.
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	req, err := client.NewRequest(context.Background(), "POST", server.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Do(req, nil); !IsHTTPErrorCode(err, http.StatusServiceUnavailable) {
+		t.Fatalf("got error %v, want 503 error", err)
+	}
+	if calls != 2 {
+		t.Errorf("got %d calls, want 2 (POST must not be retried after a 503)", calls)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2015, 4, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		v      string
+		want   time.Duration
+		wantOK bool
+	}{
+		{"", 0, false},
+		{"x", 0, false},
+		{"-1", 0, false},
+		{"0", 0, true},
+		{"120", 2 * time.Minute, true},
+		{"Wed, 01 Apr 2015 12:00:30 GMT", 30 * time.Second, true},
+		{"Wed, 01 Apr 2015 11:00:00 GMT", 0, true},
+	}
+	for _, test := range tests {
+		d, ok := parseRetryAfter(test.v, now)
+		if d != test.want || ok != test.wantOK {
+			t.Errorf("%q: got (%v, %v), want (%v, %v)", test.v, d, ok, test.want, test.wantOK)
+		}
+	}
+}
+
+func TestRetryPolicy_backoff(t *testing.T) {
+	p := RetryPolicy{MinBackoff: 100 * time.Millisecond, MaxBackoff: 300 * time.Millisecond}
+	tests := []struct {
+		attempt  int
+		min, max time.Duration
+	}{
+		{1, 50 * time.Millisecond, 100 * time.Millisecond},
+		{2, 100 * time.Millisecond, 200 * time.Millisecond},
+		{3, 150 * time.Millisecond, 300 * time.Millisecond},
+		{10, 150 * time.Millisecond, 300 * time.Millisecond},
+	}
+	for _, test := range tests {
+		if d := p.backoff(test.attempt); d < test.min || d > test.max {
+			t.Errorf("attempt %d: got backoff %v, want in [%v, %v]", test.attempt, d, test.min, test.max)
+		}
+	}
+}
+
+func TestRetryPolicy_backoff_noMax(t *testing.T) {
+	p := RetryPolicy{MinBackoff: 250 * time.Millisecond}
+	for _, attempt := range []int{40, 64, 1000, math.MaxInt32} {
+		if d := p.backoff(attempt); d < defaultMaxBackoff/2 || d > defaultMaxBackoff {
+			t.Errorf("attempt %d: got backoff %v, want in [%v, %v]", attempt, d, defaultMaxBackoff/2, defaultMaxBackoff)
+		}
+	}
+
+	// A MaxBackoff near the largest duration must not overflow.
+	p.MaxBackoff = math.MaxInt64
+	if d := p.backoff(100); d <= 0 {
+		t.Errorf("got backoff %v, want > 0", d)
+	}
+}
@@ -1,6 +1,8 @@
 package sourcegraph
 
 import (
+	"context"
+
 	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
 	"sourcegraph.com/sourcegraph/go-vcs/vcs"
 )
@@ -9,15 +11,15 @@ import (
 // the Sourcegraph API.
 type SearchService interface {
 	// Search searches the full index.
-	Search(opt *SearchOptions) (*SearchResults, Response, error)
+	Search(ctx context.Context, opt *SearchOptions) (*SearchResults, Response, error)
 
 	// Complete completes the token at the RawQuery's InsertionPoint.
-	Complete(q RawQuery) (*Completions, Response, error)
+	Complete(ctx context.Context, q RawQuery) (*Completions, Response, error)
 
 	// Suggest suggests queries given an existing query. It can be
 	// called with an empty query to get example queries that pertain
 	// to the current user's repositories, orgs, etc.
-	Suggest(q RawQuery) ([]*Suggestion, Response, error)
+	Suggest(ctx context.Context, q RawQuery) ([]*Suggestion, Response, error)
 }
 
 type SearchResults struct {
@@ -83,13 +85,13 @@ type SearchOptions struct {
 	ListOptions
 }
 
-func (s *searchService) Search(opt *SearchOptions) (*SearchResults, Response, error) {
+func (s *searchService) Search(ctx context.Context, opt *SearchOptions) (*SearchResults, Response, error) {
 	url, err := s.client.URL(router.Search, nil, opt)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", url.String(), nil)
+	req, err := s.client.NewRequest(ctx, "GET", url.String(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -117,13 +119,13 @@ type Completions struct {
 	ResolutionFatal bool         `json:",omitempty"`
 }
 
-func (s *searchService) Complete(q RawQuery) (*Completions, Response, error) {
+func (s *searchService) Complete(ctx context.Context, q RawQuery) (*Completions, Response, error) {
 	url, err := s.client.URL(router.SearchComplete, nil, q)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", url.String(), nil)
+	req, err := s.client.NewRequest(ctx, "GET", url.String(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -137,13 +139,13 @@ func (s *searchService) Complete(q RawQuery) (*Completions, Response, error) {
 	return comps, resp, nil
 }
 
-func (s *searchService) Suggest(q RawQuery) ([]*Suggestion, Response, error) {
+func (s *searchService) Suggest(ctx context.Context, q RawQuery) ([]*Suggestion, Response, error) {
 	url, err := s.client.URL(router.SearchSuggestions, nil, q)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", url.String(), nil)
+	req, err := s.client.NewRequest(ctx, "GET", url.String(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -1,6 +1,7 @@
 package sourcegraph
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"reflect"
@@ -34,7 +35,7 @@ func TestSearchService_Search(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	results, _, err := client.Search.Search(&SearchOptions{
+	results, _, err := client.Search.Search(context.Background(), &SearchOptions{
 		Query:       "q",
 		ListOptions: ListOptions{PerPage: 1, Page: 2},
 	})
@@ -72,7 +73,7 @@ func TestSearchService_Complete(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	comps, _, err := client.Search.Complete(RawQuery{String: "abc", InsertionPoint: 2})
+	comps, _, err := client.Search.Complete(context.Background(), RawQuery{String: "abc", InsertionPoint: 2})
 	if err != nil {
 		t.Errorf("Search.Complete returned error: %v", err)
 	}
@@ -109,7 +110,7 @@ func TestSearchService_Suggest(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	suggs, _, err := client.Search.Suggest(RawQuery{String: "abc", InsertionPoint: 2})
+	suggs, _, err := client.Search.Suggest(context.Background(), RawQuery{String: "abc", InsertionPoint: 2})
 	if err != nil {
 		t.Errorf("Search.Suggest returned error: %v", err)
 	}
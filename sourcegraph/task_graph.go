@@ -0,0 +1,233 @@
+package sourcegraph
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+var (
+	// ErrTaskDepCycle is returned (wrapped) when the dependencies of
+	// build tasks form a cycle.
+	ErrTaskDepCycle = errors.New("build task dependencies form a cycle")
+
+	// ErrTaskDepNotExist is returned (wrapped) when a build task
+	// depends on a task that does not exist.
+	ErrTaskDepNotExist = errors.New("build task depends on a nonexistent task")
+)
+
+// CheckTaskDeps checks the dependencies of tasks that are being
+// created (with CreateTasks) in a build that already has the existing
+// tasks. Negative entries in the tasks' DependsOn fields refer to the
+// tasks being created, and other entries refer to existing tasks (see
+// BuildTask.DependsOn).
+//
+// It returns an error wrapping ErrTaskDepNotExist if a task depends on
+// a task that neither exists nor is being created, or an error
+// wrapping ErrTaskDepCycle if the dependencies form a cycle. (Existing
+// tasks can't depend on the tasks being created, so any cycle is among
+// the latter.)
+func CheckTaskDeps(existing, tasks []*BuildTask) error {
+	exists := make(map[int64]bool, len(existing))
+	for _, t := range existing {
+		exists[t.TaskID] = true
+	}
+
+	// deps[i] are the indexes of the created tasks that tasks[i]
+	// depends on.
+	deps := make([][]int, len(tasks))
+	for i, t := range tasks {
+		for _, dep := range t.DependsOn {
+			switch {
+			case dep < 0 && -dep <= int64(len(tasks)):
+				deps[i] = append(deps[i], int(-dep)-1)
+			case dep > 0 && exists[dep]:
+			default:
+				return fmt.Errorf("%w: task %d (op %q) depends on %d", ErrTaskDepNotExist, i+1, t.Op, dep)
+			}
+		}
+	}
+
+	// Depth-first search for a back edge.
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make([]int, len(tasks))
+	var visit func(i int) error
+	visit = func(i int) error {
+		switch state[i] {
+		case visiting:
+			return fmt.Errorf("%w: task %d (op %q) depends on itself (transitively)", ErrTaskDepCycle, i+1, tasks[i].Op)
+		case visited:
+			return nil
+		}
+		state[i] = visiting
+		for _, j := range deps[i] {
+			if err := visit(j); err != nil {
+				return err
+			}
+		}
+		state[i] = visited
+		return nil
+	}
+	for i := range tasks {
+		if err := visit(i); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// A TaskScheduler determines the order in which a build's tasks can be
+// performed: a task is ready to be performed once all of the tasks it
+// depends on (in its DependsOn field) are done. Of the tasks that are
+// ready, those with lower Order (and then TaskID) fields come first.
+//
+// Tasks that don't depend on each other may be performed concurrently:
+//
+//	s, err := sourcegraph.NewTaskScheduler(tasks)
+//	for s.Remaining() > 0 {
+//		for _, task := range s.Ready() {
+//			// start task; when it finishes, call s.Done(task.TaskID)
+//		}
+//		// wait for a task to finish
+//	}
+//
+// A TaskScheduler is not safe for concurrent use.
+type TaskScheduler struct {
+	tasks      map[int64]*BuildTask
+	waiting    map[int64]int     // TaskID -> number of deps not yet done
+	dependents map[int64][]int64 // TaskID -> TaskIDs of tasks that depend on it
+	ready      []*BuildTask      // ready tasks not yet returned by Ready
+	remaining  int
+}
+
+// NewTaskScheduler returns a TaskScheduler for tasks, which must have
+// their TaskID fields set. Dependencies on tasks that are not among
+// tasks (such as tasks of the build that were created earlier, which
+// CheckTaskDeps accepts) are treated as already done. It returns an
+// error wrapping ErrTaskDepCycle if the tasks' dependencies form a
+// cycle.
+func NewTaskScheduler(tasks []*BuildTask) (*TaskScheduler, error) {
+	s := &TaskScheduler{
+		tasks:      make(map[int64]*BuildTask, len(tasks)),
+		waiting:    make(map[int64]int, len(tasks)),
+		dependents: map[int64][]int64{},
+		remaining:  len(tasks),
+	}
+	for _, t := range tasks {
+		if _, dup := s.tasks[t.TaskID]; dup {
+			return nil, fmt.Errorf("duplicate build task %d", t.TaskID)
+		}
+		s.tasks[t.TaskID] = t
+	}
+	for _, t := range tasks {
+		for _, dep := range t.DependsOn {
+			if _, ok := s.tasks[dep]; !ok {
+				continue // not scheduled here, so already done
+			}
+			s.waiting[t.TaskID]++
+			s.dependents[dep] = append(s.dependents[dep], t.TaskID)
+		}
+		if s.waiting[t.TaskID] == 0 {
+			s.ready = append(s.ready, t)
+		}
+	}
+
+	// Check that every task can eventually be performed, which is the
+	// case if and only if there are no cycles.
+	if _, err := s.clone().all(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Ready returns the tasks that have become ready to be performed since
+// the last call to Ready. The caller should call Done for each of them
+// when it has been performed.
+func (s *TaskScheduler) Ready() []*BuildTask {
+	ready := s.ready
+	s.ready = nil
+	sortTasks(ready)
+	return ready
+}
+
+// Done records that a task has been performed, which may make the
+// tasks that depend on it ready.
+func (s *TaskScheduler) Done(taskID int64) {
+	if _, ok := s.tasks[taskID]; !ok {
+		return
+	}
+	delete(s.tasks, taskID)
+	s.remaining--
+	for _, id := range s.dependents[taskID] {
+		s.waiting[id]--
+		if t, ok := s.tasks[id]; ok && s.waiting[id] == 0 {
+			s.ready = append(s.ready, t)
+		}
+	}
+}
+
+// Remaining returns the number of tasks that are not yet done.
+func (s *TaskScheduler) Remaining() int { return s.remaining }
+
+// clone returns a copy of s that can be advanced independently.
+func (s *TaskScheduler) clone() *TaskScheduler {
+	c := &TaskScheduler{
+		tasks:      make(map[int64]*BuildTask, len(s.tasks)),
+		waiting:    make(map[int64]int, len(s.waiting)),
+		dependents: s.dependents, // not modified
+		ready:      append([]*BuildTask(nil), s.ready...),
+		remaining:  s.remaining,
+	}
+	for id, t := range s.tasks {
+		c.tasks[id] = t
+	}
+	for id, n := range s.waiting {
+		c.waiting[id] = n
+	}
+	return c
+}
+
+// all performs the remaining tasks one at a time and returns them in
+// the order they were performed.
+func (s *TaskScheduler) all() ([]*BuildTask, error) {
+	var sorted []*BuildTask
+	for s.remaining > 0 {
+		ready := s.Ready()
+		if len(ready) == 0 {
+			return nil, fmt.Errorf("%w: %d tasks can't be performed", ErrTaskDepCycle, s.remaining)
+		}
+		// Perform only the first ready task, so that tasks that become
+		// ready are ordered among the ones that already were.
+		s.ready = ready[1:]
+		sorted = append(sorted, ready[0])
+		s.Done(ready[0].TaskID)
+	}
+	return sorted, nil
+}
+
+// SortTasks returns tasks in an order in which they can be performed
+// one at a time: each task comes after the tasks it depends on, and
+// otherwise tasks with lower Order (and then TaskID) fields come first.
+// The tasks must have their TaskID fields set. Dependencies on tasks
+// that are not among tasks are treated as already done (see
+// NewTaskScheduler).
+func SortTasks(tasks []*BuildTask) ([]*BuildTask, error) {
+	s, err := NewTaskScheduler(tasks)
+	if err != nil {
+		return nil, err
+	}
+	return s.all()
+}
+
+func sortTasks(tasks []*BuildTask) {
+	sort.SliceStable(tasks, func(i, j int) bool {
+		if tasks[i].Order != tasks[j].Order {
+			return tasks[i].Order < tasks[j].Order
+		}
+		return tasks[i].TaskID < tasks[j].TaskID
+	})
+}
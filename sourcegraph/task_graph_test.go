@@ -0,0 +1,113 @@
+package sourcegraph
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestCheckTaskDeps(t *testing.T) {
+	existing := []*BuildTask{{TaskID: 10}, {TaskID: 11}}
+	tests := map[string]struct {
+		tasks   []*BuildTask
+		wantErr error
+	}{
+		"none":          {tasks: []*BuildTask{{}, {}}},
+		"existing":      {tasks: []*BuildTask{{DependsOn: []int64{10, 11}}}},
+		"created":       {tasks: []*BuildTask{{DependsOn: []int64{-2, -3}}, {DependsOn: []int64{-3}}, {DependsOn: []int64{10}}}},
+		"nonexistent":   {tasks: []*BuildTask{{DependsOn: []int64{12}}}, wantErr: ErrTaskDepNotExist},
+		"out of range":  {tasks: []*BuildTask{{DependsOn: []int64{-2}}}, wantErr: ErrTaskDepNotExist},
+		"zero":          {tasks: []*BuildTask{{DependsOn: []int64{0}}}, wantErr: ErrTaskDepNotExist},
+		"self":          {tasks: []*BuildTask{{DependsOn: []int64{-1}}}, wantErr: ErrTaskDepCycle},
+		"cycle":         {tasks: []*BuildTask{{DependsOn: []int64{-2}}, {DependsOn: []int64{-3}}, {DependsOn: []int64{-1}}}, wantErr: ErrTaskDepCycle},
+		"cycle in tail": {tasks: []*BuildTask{{}, {DependsOn: []int64{-3, -1}}, {DependsOn: []int64{-2}}}, wantErr: ErrTaskDepCycle},
+	}
+	for label, test := range tests {
+		err := CheckTaskDeps(existing, test.tasks)
+		if (test.wantErr == nil && err != nil) || !errors.Is(err, test.wantErr) {
+			t.Errorf("%s: got error %v, want %v", label, err, test.wantErr)
+		}
+	}
+}
+
+func taskIDs(tasks []*BuildTask) []int64 {
+	ids := make([]int64, len(tasks))
+	for i, t := range tasks {
+		ids[i] = t.TaskID
+	}
+	return ids
+}
+
+func TestSortTasks(t *testing.T) {
+	tasks := []*BuildTask{
+		{TaskID: 1, Op: "import", DependsOn: []int64{3, 4}},
+		{TaskID: 2, Order: 1},
+		{TaskID: 3, Op: "graph", Order: 2},
+		{TaskID: 4, Op: "graph", DependsOn: []int64{2}},
+		{TaskID: 5, Order: 1},
+	}
+	sorted, err := SortTasks(tasks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := taskIDs(sorted), []int64{2, 4, 5, 3, 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got order %v, want %v", got, want)
+	}
+
+	// Without dependencies, tasks are sorted by Order.
+	sorted, err = SortTasks([]*BuildTask{{TaskID: 1, Order: 2}, {TaskID: 2, Order: 1}, {TaskID: 3, Order: 2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := taskIDs(sorted), []int64{2, 1, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got order %v, want %v", got, want)
+	}
+
+	if _, err := SortTasks([]*BuildTask{{TaskID: 1, DependsOn: []int64{2}}, {TaskID: 2, DependsOn: []int64{1}}}); !errors.Is(err, ErrTaskDepCycle) {
+		t.Errorf("got error %v, want ErrTaskDepCycle", err)
+	}
+
+	// Dependencies on other tasks (such as tasks created earlier) are
+	// already done.
+	sorted, err = SortTasks([]*BuildTask{{TaskID: 3, DependsOn: []int64{1, 2}}, {TaskID: 2, DependsOn: []int64{1}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := taskIDs(sorted), []int64{2, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got order %v, want %v", got, want)
+	}
+}
+
+func TestTaskScheduler(t *testing.T) {
+	s, err := NewTaskScheduler([]*BuildTask{
+		{TaskID: 1, Op: "graph"},
+		{TaskID: 2, Op: "graph"},
+		{TaskID: 3, Op: "depresolve", DependsOn: []int64{1}},
+		{TaskID: 4, Op: "import", DependsOn: []int64{1, 2, 3}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	steps := []struct {
+		done      int64
+		wantReady []int64
+	}{
+		{done: 0, wantReady: []int64{1, 2}},
+		{done: 2, wantReady: []int64{}},
+		{done: 1, wantReady: []int64{3}},
+		{done: 3, wantReady: []int64{4}},
+		{done: 4, wantReady: []int64{}},
+	}
+	for _, step := range steps {
+		if step.done != 0 {
+			s.Done(step.done)
+		}
+		if got := taskIDs(s.Ready()); !reflect.DeepEqual(got, step.wantReady) {
+			t.Errorf("after task %d is done: got ready tasks %v, want %v", step.done, got, step.wantReady)
+		}
+	}
+	if n := s.Remaining(); n != 0 {
+		t.Errorf("got %d remaining tasks, want 0", n)
+	}
+}
@@ -0,0 +1,206 @@
+package sourcegraph
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// A Span records a single API call made with (*Client).Do. Spans are
+// created by the Tracing middleware and passed to a SpanExporter when
+// the call ends.
+type Span struct {
+	// TraceID and SpanID identify the span, in the format used by the
+	// W3C Trace Context "traceparent" header (32 and 16 lowercase hex
+	// digits, respectively). ParentSpanID is the SpanID of the span's
+	// parent, or "" if it has none.
+	TraceID, SpanID, ParentSpanID string
+
+	// Route is the name of the API route (e.g., router.DefRefs), or
+	// "" if the request URL did not match any API route.
+	Route string
+
+	// RouteVars are the route variables of the request URL.
+	RouteVars map[string]string
+
+	Method string // the HTTP request method
+
+	Start, End time.Time
+
+	// StatusCode is the HTTP status code of the response, or 0 if no
+	// response was received.
+	StatusCode int
+
+	// Attempts is the number of HTTP requests that were sent for the
+	// call (so Attempts-1 is the number of retries). It is 0 if the
+	// response was served from a cache or shared with an identical
+	// concurrent call.
+	Attempts int
+
+	// RequestBytes is the size of the request body, and
+	// ResponseBytes is the total size of the response bodies that
+	// were read (including those of failed attempts).
+	RequestBytes, ResponseBytes int64
+
+	Err error // the error returned by Do, if any
+}
+
+// Duration returns how long the call took.
+func (s *Span) Duration() time.Duration { return s.End.Sub(s.Start) }
+
+// A SpanExporter receives each Span when its call ends. ExportSpan may
+// be called concurrently and must not modify the span.
+type SpanExporter interface {
+	ExportSpan(*Span)
+}
+
+// SpanExporterFunc is a func that implements SpanExporter.
+type SpanExporterFunc func(*Span)
+
+// ExportSpan implements SpanExporter.
+func (f SpanExporterFunc) ExportSpan(s *Span) { f(s) }
+
+// A SpanRecorder is a SpanExporter that stores spans in memory. It is
+// useful in tests.
+type SpanRecorder struct {
+	mu    sync.Mutex
+	spans []*Span
+}
+
+// ExportSpan implements SpanExporter.
+func (r *SpanRecorder) ExportSpan(s *Span) {
+	r.mu.Lock()
+	r.spans = append(r.spans, s)
+	r.mu.Unlock()
+}
+
+// Spans returns the spans recorded so far.
+func (r *SpanRecorder) Spans() []*Span {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]*Span(nil), r.spans...)
+}
+
+type contextKey int
+
+const (
+	spanKey contextKey = iota
+	callStatsKey
+)
+
+// ContextWithSpan returns a copy of ctx with the given span, which
+// becomes the parent of the spans of API calls made with the returned
+// context. To connect API call spans to an existing trace, only the
+// parent's TraceID and SpanID need to be set.
+func ContextWithSpan(ctx context.Context, s *Span) context.Context {
+	return context.WithValue(ctx, spanKey, s)
+}
+
+// SpanFromContext returns the span in ctx, or nil if there is none.
+func SpanFromContext(ctx context.Context) *Span {
+	s, _ := ctx.Value(spanKey).(*Span)
+	return s
+}
+
+// Tracing returns a Middleware that creates a Span for each API call,
+// propagates it to the server in a W3C Trace Context "traceparent"
+// request header, and passes it to exp when the call ends.
+func Tracing(exp SpanExporter) Middleware {
+	return func(call *Call, next CallHandler) (Response, error) {
+		req := call.Request
+		spanID, err := randomHex(8)
+		if err != nil {
+			// Make the call without tracing it rather than fail it.
+			return next(call)
+		}
+		span := &Span{
+			SpanID:       spanID,
+			Route:        call.Route,
+			RouteVars:    call.RouteVars,
+			Method:       req.Method,
+			RequestBytes: req.ContentLength,
+		}
+		if span.RequestBytes < 0 {
+			span.RequestBytes = 0
+		}
+		if parent := SpanFromContext(req.Context()); parent != nil && parent.TraceID != "" {
+			span.TraceID, span.ParentSpanID = parent.TraceID, parent.SpanID
+		} else if span.TraceID, err = randomHex(16); err != nil {
+			return next(call)
+		}
+
+		// Clone the request so that the traceparent header isn't set
+		// on the caller's request (which may be reused or sent
+		// concurrently).
+		stats := &callStats{}
+		call.Request = req.Clone(context.WithValue(ContextWithSpan(req.Context(), span), callStatsKey, stats))
+		call.Request.Header.Set("traceparent", fmt.Sprintf("00-%s-%s-01", span.TraceID, span.SpanID))
+
+		span.Start = time.Now()
+		resp, err := next(call)
+		span.End = time.Now()
+
+		if hr, ok := resp.(*HTTPResponse); ok && hr.Response != nil {
+			span.StatusCode = hr.StatusCode
+		}
+		span.Attempts = int(atomic.LoadInt64(&stats.attempts))
+		span.ResponseBytes = atomic.LoadInt64(&stats.responseBytes)
+		span.Err = err
+		exp.ExportSpan(span)
+		return resp, err
+	}
+}
+
+// randomHex returns n random bytes, hex-encoded.
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// callStats collects statistics about the HTTP requests sent for a
+// call. It is stored in the request's context by the Tracing
+// middleware and updated by roundTrip.
+type callStats struct {
+	attempts      int64
+	responseBytes int64
+}
+
+func callStatsFromContext(ctx context.Context) *callStats {
+	s, _ := ctx.Value(callStatsKey).(*callStats)
+	return s
+}
+
+// countingBody is a response body that adds the number of bytes read
+// to a callStats.
+type countingBody struct {
+	io.ReadCloser
+	stats *callStats
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	atomic.AddInt64(&b.stats.responseBytes, int64(n))
+	return n, err
+}
+
+// observeAttempt records an HTTP request (sent for a call with the
+// given stats, if non-nil) and arranges for its response body size
+// to be recorded.
+func (s *callStats) observeAttempt(resp *http.Response) {
+	if s == nil {
+		return
+	}
+	atomic.AddInt64(&s.attempts, 1)
+	if resp != nil && resp.Body != nil {
+		resp.Body = &countingBody{ReadCloser: resp.Body, stats: s}
+	}
+}
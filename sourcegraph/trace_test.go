@@ -0,0 +1,134 @@
+package sourcegraph
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
+)
+
+func TestTracing(t *testing.T) {
+	setup()
+	defer teardown()
+
+	client.RetryPolicy = &RetryPolicy{MaxAttempts: 2, MinBackoff: time.Millisecond}
+	rec := &SpanRecorder{}
+	client.Middleware = []Middleware{Tracing(rec)}
+
+	var (
+		calls       int
+		traceparent string
+	)
+	mux.HandleFunc(urlPath(t, router.Repo, map[string]string{"RepoSpec": "r.com/x"}), func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		traceparent = r.Header.Get("traceparent")
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"RID":1}`))
+	})
+
+	parent := &Span{TraceID: "0af7651916cd43dd8448eb211c80319c", SpanID: "b7ad6b7169203331"}
+	ctx := ContextWithSpan(context.Background(), parent)
+	if _, _, err := client.Repos.Get(ctx, RepoSpec{URI: "r.com/x"}, nil); err != nil {
+		t.Fatalf("Repos.Get returned error: %v", err)
+	}
+
+	spans := rec.Spans()
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(spans))
+	}
+	span := spans[0]
+	if span.Route != router.Repo {
+		t.Errorf("got Route %q, want %q", span.Route, router.Repo)
+	}
+	if want := map[string]string{"RepoSpec": "r.com/x"}; !reflect.DeepEqual(span.RouteVars, want) {
+		t.Errorf("got RouteVars %v, want %v", span.RouteVars, want)
+	}
+	if span.Method != "GET" || span.StatusCode != http.StatusOK || span.Attempts != 2 || span.ResponseBytes != int64(len(`{"RID":1}`)) || span.Err != nil {
+		t.Errorf("got span %+v, want GET with status 200, 2 attempts, 9 response bytes, and no error", span)
+	}
+	if span.TraceID != parent.TraceID || span.ParentSpanID != parent.SpanID {
+		t.Errorf("got TraceID %q and ParentSpanID %q, want %q and %q", span.TraceID, span.ParentSpanID, parent.TraceID, parent.SpanID)
+	}
+	if !regexp.MustCompile(`^[0-9a-f]{16}$`).MatchString(span.SpanID) {
+		t.Errorf("got invalid SpanID %q", span.SpanID)
+	}
+	if want := "00-" + parent.TraceID + "-" + span.SpanID + "-01"; traceparent != want {
+		t.Errorf("got traceparent header %q, want %q", traceparent, want)
+	}
+	if span.Duration() <= 0 {
+		t.Errorf("got Duration %v, want > 0", span.Duration())
+	}
+}
+
+func TestTracing_error(t *testing.T) {
+	setup()
+	defer teardown()
+
+	rec := &SpanRecorder{}
+	client.Middleware = []Middleware{Tracing(rec)}
+
+	mux.HandleFunc(urlPath(t, router.Repo, map[string]string{"RepoSpec": "r.com/x"}), func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	_, _, err := client.Repos.Get(context.Background(), RepoSpec{URI: "r.com/x"}, nil)
+	if !IsHTTPErrorCode(err, http.StatusNotFound) {
+		t.Fatalf("got error %v, want 404 error", err)
+	}
+
+	spans := rec.Spans()
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(spans))
+	}
+	if span := spans[0]; span.StatusCode != http.StatusNotFound || span.Err != err || span.ParentSpanID != "" || span.TraceID == "" {
+		t.Errorf("got span %+v, want a root span with status 404 and error %v", span, err)
+	}
+}
+
+func TestTracing_reusedRequest(t *testing.T) {
+	setup()
+	defer teardown()
+
+	rec := &SpanRecorder{}
+	client.Middleware = []Middleware{Tracing(rec)}
+
+	var traceparents []string
+	mux.HandleFunc(urlPath(t, router.Repo, map[string]string{"RepoSpec": "r.com/x"}), func(w http.ResponseWriter, r *http.Request) {
+		traceparents = append(traceparents, r.Header.Get("traceparent"))
+		w.Write([]byte(`{"RID":1}`))
+	})
+
+	url, err := client.URL(router.Repo, map[string]string{"RepoSpec": "r.com/x"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, err := client.NewRequest(context.Background(), "GET", url.String(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := client.Do(req, nil); err != nil {
+			t.Fatalf("Do returned error: %v", err)
+		}
+		if h := req.Header.Get("traceparent"); h != "" {
+			t.Fatalf("got traceparent header %q on the caller's request, want none", h)
+		}
+	}
+
+	spans := rec.Spans()
+	if len(spans) != 2 || len(traceparents) != 2 {
+		t.Fatalf("got %d spans and %d requests, want 2 of each", len(spans), len(traceparents))
+	}
+	for i, span := range spans {
+		if want := "00-" + span.TraceID + "-" + span.SpanID + "-01"; traceparents[i] != want {
+			t.Errorf("request %d: got traceparent header %q, want %q", i, traceparents[i], want)
+		}
+	}
+}
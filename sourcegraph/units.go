@@ -1,6 +1,8 @@
 package sourcegraph
 
 import (
+	"context"
+
 	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
 	"sourcegraph.com/sourcegraph/srclib/unit"
 )
@@ -9,10 +11,10 @@ import (
 // the Sourcegraph API.
 type UnitsService interface {
 	// Get fetches a unit.
-	Get(spec UnitSpec) (*unit.RepoSourceUnit, Response, error)
+	Get(ctx context.Context, spec UnitSpec) (*unit.RepoSourceUnit, Response, error)
 
 	// List units.
-	List(opt *UnitListOptions) ([]*unit.RepoSourceUnit, Response, error)
+	List(ctx context.Context, opt *UnitListOptions) ([]*unit.RepoSourceUnit, Response, error)
 }
 
 // UnitSpec specifies a source unit.
@@ -82,13 +84,13 @@ type UnitListOptions struct {
 	ListOptions
 }
 
-func (s *unitsService) Get(spec UnitSpec) (*unit.RepoSourceUnit, Response, error) {
+func (s *unitsService) Get(ctx context.Context, spec UnitSpec) (*unit.RepoSourceUnit, Response, error) {
 	url, err := s.client.URL(router.Unit, spec.RouteVars(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", url.String(), nil)
+	req, err := s.client.NewRequest(ctx, "GET", url.String(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -102,13 +104,13 @@ func (s *unitsService) Get(spec UnitSpec) (*unit.RepoSourceUnit, Response, error
 	return &u, resp, nil
 }
 
-func (s *unitsService) List(opt *UnitListOptions) ([]*unit.RepoSourceUnit, Response, error) {
+func (s *unitsService) List(ctx context.Context, opt *UnitListOptions) ([]*unit.RepoSourceUnit, Response, error) {
 	url, err := s.client.URL(router.Units, nil, opt)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", url.String(), nil)
+	req, err := s.client.NewRequest(ctx, "GET", url.String(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -1,16 +1,20 @@
 package sourcegraph
 
-import "sourcegraph.com/sourcegraph/srclib/unit"
+import (
+	"context"
+
+	"sourcegraph.com/sourcegraph/srclib/unit"
+)
 
 type MockUnitsService struct {
-	Get_  func(spec UnitSpec) (*unit.RepoSourceUnit, Response, error)
-	List_ func(opt *UnitListOptions) ([]*unit.RepoSourceUnit, Response, error)
+	Get_  func(ctx context.Context, spec UnitSpec) (*unit.RepoSourceUnit, Response, error)
+	List_ func(ctx context.Context, opt *UnitListOptions) ([]*unit.RepoSourceUnit, Response, error)
 }
 
-func (s MockUnitsService) Get(spec UnitSpec) (*unit.RepoSourceUnit, Response, error) {
-	return s.Get_(spec)
+func (s MockUnitsService) Get(ctx context.Context, spec UnitSpec) (*unit.RepoSourceUnit, Response, error) {
+	return s.Get_(ctx, spec)
 }
 
-func (s MockUnitsService) List(opt *UnitListOptions) ([]*unit.RepoSourceUnit, Response, error) {
-	return s.List_(opt)
+func (s MockUnitsService) List(ctx context.Context, opt *UnitListOptions) ([]*unit.RepoSourceUnit, Response, error) {
+	return s.List_(ctx, opt)
 }
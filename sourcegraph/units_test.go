@@ -1,6 +1,7 @@
 package sourcegraph
 
 import (
+	"context"
 	"net/http"
 	"reflect"
 	"testing"
@@ -29,7 +30,7 @@ func TestUnitsService_Get(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	unit, _, err := client.Units.Get(UnitSpec{RepoRevSpec: RepoRevSpec{RepoSpec: RepoSpec{URI: "x.com/r"}, Rev: "c"}, UnitType: "t", Unit: "u"})
+	unit, _, err := client.Units.Get(context.Background(), UnitSpec{RepoRevSpec: RepoRevSpec{RepoSpec: RepoSpec{URI: "x.com/r"}, Rev: "c"}, UnitType: "t", Unit: "u"})
 	if err != nil {
 		t.Errorf("Unit.Get returned error: %v", err)
 	}
@@ -68,7 +69,7 @@ func TestUnitsService_List(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	units, _, err := client.Units.List(&UnitListOptions{
+	units, _, err := client.Units.List(context.Background(), &UnitListOptions{
 		RepoRevs:    []string{"r1@x", "r2"},
 		ListOptions: ListOptions{PerPage: 1, Page: 2},
 	})
@@ -1,6 +1,7 @@
 package sourcegraph
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
@@ -17,20 +18,20 @@ import (
 // Sourcegraph API.
 type UsersService interface {
 	// Get fetches a user.
-	Get(user UserSpec, opt *UserGetOptions) (*User, Response, error)
+	Get(ctx context.Context, user UserSpec, opt *UserGetOptions) (*User, Response, error)
 
 	// GetSettings fetches a user's configuration settings. If err is
 	// nil, then the returned UserSettings must be non-nil.
-	GetSettings(user UserSpec) (*UserSettings, Response, error)
+	GetSettings(ctx context.Context, user UserSpec) (*UserSettings, Response, error)
 
 	// UpdateSettings updates an user's configuration settings.
-	UpdateSettings(user UserSpec, settings UserSettings) (Response, error)
+	UpdateSettings(ctx context.Context, user UserSpec, settings UserSettings) (Response, error)
 
 	// ListEmails returns a list of a user's email addresses.
-	ListEmails(user UserSpec) ([]*EmailAddr, Response, error)
+	ListEmails(ctx context.Context, user UserSpec) ([]*EmailAddr, Response, error)
 
 	// GetOrCreateFromGitHub creates a new user based a GitHub user.
-	GetOrCreateFromGitHub(user GitHubUserSpec, opt *UserGetOptions) (*User, Response, error)
+	GetOrCreateFromGitHub(ctx context.Context, user GitHubUserSpec, opt *UserGetOptions) (*User, Response, error)
 
 	// RefreshProfile updates the user's profile information from external
 	// sources, such as GitHub.
@@ -38,26 +39,26 @@ type UsersService interface {
 	// This operation is performed asynchronously on the server side
 	// (after receiving the request) and the API currently has no way
 	// of notifying callers when the operation completes.
-	RefreshProfile(userSpec UserSpec) (Response, error)
+	RefreshProfile(ctx context.Context, userSpec UserSpec) (Response, error)
 
 	// ComputeStats recomputes statistics about the user.
 	//
 	// This operation is performed asynchronously on the server side
 	// (after receiving the request) and the API currently has no way
 	// of notifying callers when the operation completes.
-	ComputeStats(userSpec UserSpec) (Response, error)
+	ComputeStats(ctx context.Context, userSpec UserSpec) (Response, error)
 
 	// List users.
-	List(opt *UsersListOptions) ([]*User, Response, error)
+	List(ctx context.Context, opt *UsersListOptions) ([]*User, Response, error)
 
 	// ListAuthors lists users who authored code that user uses.
-	ListAuthors(user UserSpec, opt *UsersListAuthorsOptions) ([]*AugmentedPersonUsageByClient, Response, error)
+	ListAuthors(ctx context.Context, user UserSpec, opt *UsersListAuthorsOptions) ([]*AugmentedPersonUsageByClient, Response, error)
 
 	// ListClients lists users who use code that user authored.
-	ListClients(user UserSpec, opt *UsersListClientsOptions) ([]*AugmentedPersonUsageOfAuthor, Response, error)
+	ListClients(ctx context.Context, user UserSpec, opt *UsersListClientsOptions) ([]*AugmentedPersonUsageOfAuthor, Response, error)
 
 	// ListOrgs lists organizations that a user is a member of.
-	ListOrgs(member UserSpec, opt *UsersListOrgsOptions) ([]*Org, Response, error)
+	ListOrgs(ctx context.Context, member UserSpec, opt *UsersListOrgsOptions) ([]*Org, Response, error)
 }
 
 // User represents a registered user.
@@ -222,13 +223,13 @@ type UserGetOptions struct {
 	Stats bool `url:",omitempty"`
 }
 
-func (s *usersService) Get(user_ UserSpec, opt *UserGetOptions) (*User, Response, error) {
+func (s *usersService) Get(ctx context.Context, user_ UserSpec, opt *UserGetOptions) (*User, Response, error) {
 	url, err := s.client.URL(router.User, user_.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", url.String(), nil)
+	req, err := s.client.NewRequest(ctx, "GET", url.String(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -255,13 +256,13 @@ type EmailAddr struct {
 	Blacklisted bool // indicates that this email should not be associated with the user (even if guessed in the future)
 }
 
-func (s *usersService) ListEmails(user UserSpec) ([]*EmailAddr, Response, error) {
+func (s *usersService) ListEmails(ctx context.Context, user UserSpec) ([]*EmailAddr, Response, error) {
 	url, err := s.client.URL(router.UserEmails, user.RouteVars(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", url.String(), nil)
+	req, err := s.client.NewRequest(ctx, "GET", url.String(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -288,13 +289,13 @@ type PlanSettings struct {
 	PlanID *string `json:",omitempty"`
 }
 
-func (s *usersService) GetSettings(user UserSpec) (*UserSettings, Response, error) {
+func (s *usersService) GetSettings(ctx context.Context, user UserSpec) (*UserSettings, Response, error) {
 	url, err := s.client.URL(router.UserSettings, user.RouteVars(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", url.String(), nil)
+	req, err := s.client.NewRequest(ctx, "GET", url.String(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -308,13 +309,13 @@ func (s *usersService) GetSettings(user UserSpec) (*UserSettings, Response, erro
 	return settings, resp, nil
 }
 
-func (s *usersService) UpdateSettings(user UserSpec, settings UserSettings) (Response, error) {
+func (s *usersService) UpdateSettings(ctx context.Context, user UserSpec, settings UserSettings) (Response, error) {
 	url, err := s.client.URL(router.UserSettingsUpdate, user.RouteVars(), nil)
 	if err != nil {
 		return nil, err
 	}
 
-	req, err := s.client.NewRequest("PUT", url.String(), settings)
+	req, err := s.client.NewRequest(ctx, "PUT", url.String(), settings)
 	if err != nil {
 		return nil, err
 	}
@@ -343,13 +344,13 @@ func (s GitHubUserSpec) RouteVars() map[string]string {
 	panic("empty GitHubUserSpec")
 }
 
-func (s *usersService) GetOrCreateFromGitHub(user GitHubUserSpec, opt *UserGetOptions) (*User, Response, error) {
+func (s *usersService) GetOrCreateFromGitHub(ctx context.Context, user GitHubUserSpec, opt *UserGetOptions) (*User, Response, error) {
 	url, err := s.client.URL(router.UserFromGitHub, user.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", url.String(), nil)
+	req, err := s.client.NewRequest(ctx, "GET", url.String(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -363,13 +364,13 @@ func (s *usersService) GetOrCreateFromGitHub(user GitHubUserSpec, opt *UserGetOp
 	return user__, resp, nil
 }
 
-func (s *usersService) RefreshProfile(user_ UserSpec) (Response, error) {
+func (s *usersService) RefreshProfile(ctx context.Context, user_ UserSpec) (Response, error) {
 	url, err := s.client.URL(router.UserRefreshProfile, user_.RouteVars(), nil)
 	if err != nil {
 		return nil, err
 	}
 
-	req, err := s.client.NewRequest("PUT", url.String(), nil)
+	req, err := s.client.NewRequest(ctx, "PUT", url.String(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -382,13 +383,13 @@ func (s *usersService) RefreshProfile(user_ UserSpec) (Response, error) {
 	return resp, nil
 }
 
-func (s *usersService) ComputeStats(user_ UserSpec) (Response, error) {
+func (s *usersService) ComputeStats(ctx context.Context, user_ UserSpec) (Response, error) {
 	url, err := s.client.URL(router.UserComputeStats, user_.RouteVars(), nil)
 	if err != nil {
 		return nil, err
 	}
 
-	req, err := s.client.NewRequest("PUT", url.String(), nil)
+	req, err := s.client.NewRequest(ctx, "PUT", url.String(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -414,13 +415,13 @@ type UsersListOptions struct {
 	ListOptions
 }
 
-func (s *usersService) List(opt *UsersListOptions) ([]*User, Response, error) {
+func (s *usersService) List(ctx context.Context, opt *UsersListOptions) ([]*User, Response, error) {
 	url, err := s.client.URL(router.Users, nil, opt)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", url.String(), nil)
+	req, err := s.client.NewRequest(ctx, "GET", url.String(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -449,13 +450,13 @@ type AugmentedPersonUsageByClient struct {
 // method.
 type UsersListAuthorsOptions UsersListOptions
 
-func (s *usersService) ListAuthors(user UserSpec, opt *UsersListAuthorsOptions) ([]*AugmentedPersonUsageByClient, Response, error) {
+func (s *usersService) ListAuthors(ctx context.Context, user UserSpec, opt *UsersListAuthorsOptions) ([]*AugmentedPersonUsageByClient, Response, error) {
 	url, err := s.client.URL(router.UserAuthors, user.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", url.String(), nil)
+	req, err := s.client.NewRequest(ctx, "GET", url.String(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -484,13 +485,13 @@ type AugmentedPersonUsageOfAuthor struct {
 // method.
 type UsersListClientsOptions UsersListOptions
 
-func (s *usersService) ListClients(user UserSpec, opt *UsersListClientsOptions) ([]*AugmentedPersonUsageOfAuthor, Response, error) {
+func (s *usersService) ListClients(ctx context.Context, user UserSpec, opt *UsersListClientsOptions) ([]*AugmentedPersonUsageOfAuthor, Response, error) {
 	url, err := s.client.URL(router.UserClients, user.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", url.String(), nil)
+	req, err := s.client.NewRequest(ctx, "GET", url.String(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -507,13 +508,13 @@ type UsersListOrgsOptions struct {
 	ListOptions
 }
 
-func (s *usersService) ListOrgs(member UserSpec, opt *UsersListOrgsOptions) ([]*Org, Response, error) {
+func (s *usersService) ListOrgs(ctx context.Context, member UserSpec, opt *UsersListOrgsOptions) ([]*Org, Response, error) {
 	url, err := s.client.URL(router.UserOrgs, member.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", url.String(), nil)
+	req, err := s.client.NewRequest(ctx, "GET", url.String(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -1,57 +1,61 @@
 package sourcegraph
 
+import "context"
+
 type MockUsersService struct {
-	Get_                   func(user UserSpec, opt *UserGetOptions) (*User, Response, error)
-	GetSettings_           func(user UserSpec) (*UserSettings, Response, error)
-	UpdateSettings_        func(user UserSpec, settings UserSettings) (Response, error)
-	ListEmails_            func(user UserSpec) ([]*EmailAddr, Response, error)
-	GetOrCreateFromGitHub_ func(user GitHubUserSpec, opt *UserGetOptions) (*User, Response, error)
-	RefreshProfile_        func(userSpec UserSpec) (Response, error)
-	ComputeStats_          func(userSpec UserSpec) (Response, error)
-	List_                  func(opt *UsersListOptions) ([]*User, Response, error)
-	ListAuthors_           func(user UserSpec, opt *UsersListAuthorsOptions) ([]*AugmentedPersonUsageByClient, Response, error)
-	ListClients_           func(user UserSpec, opt *UsersListClientsOptions) ([]*AugmentedPersonUsageOfAuthor, Response, error)
-	ListOrgs_              func(member UserSpec, opt *UsersListOrgsOptions) ([]*Org, Response, error)
+	Get_                   func(ctx context.Context, user UserSpec, opt *UserGetOptions) (*User, Response, error)
+	GetSettings_           func(ctx context.Context, user UserSpec) (*UserSettings, Response, error)
+	UpdateSettings_        func(ctx context.Context, user UserSpec, settings UserSettings) (Response, error)
+	ListEmails_            func(ctx context.Context, user UserSpec) ([]*EmailAddr, Response, error)
+	GetOrCreateFromGitHub_ func(ctx context.Context, user GitHubUserSpec, opt *UserGetOptions) (*User, Response, error)
+	RefreshProfile_        func(ctx context.Context, userSpec UserSpec) (Response, error)
+	ComputeStats_          func(ctx context.Context, userSpec UserSpec) (Response, error)
+	List_                  func(ctx context.Context, opt *UsersListOptions) ([]*User, Response, error)
+	ListAuthors_           func(ctx context.Context, user UserSpec, opt *UsersListAuthorsOptions) ([]*AugmentedPersonUsageByClient, Response, error)
+	ListClients_           func(ctx context.Context, user UserSpec, opt *UsersListClientsOptions) ([]*AugmentedPersonUsageOfAuthor, Response, error)
+	ListOrgs_              func(ctx context.Context, member UserSpec, opt *UsersListOrgsOptions) ([]*Org, Response, error)
 }
 
-func (s MockUsersService) Get(user UserSpec, opt *UserGetOptions) (*User, Response, error) {
-	return s.Get_(user, opt)
+func (s MockUsersService) Get(ctx context.Context, user UserSpec, opt *UserGetOptions) (*User, Response, error) {
+	return s.Get_(ctx, user, opt)
 }
 
-func (s MockUsersService) GetSettings(user UserSpec) (*UserSettings, Response, error) {
-	return s.GetSettings_(user)
+func (s MockUsersService) GetSettings(ctx context.Context, user UserSpec) (*UserSettings, Response, error) {
+	return s.GetSettings_(ctx, user)
 }
 
-func (s MockUsersService) UpdateSettings(user UserSpec, settings UserSettings) (Response, error) {
-	return s.UpdateSettings_(user, settings)
+func (s MockUsersService) UpdateSettings(ctx context.Context, user UserSpec, settings UserSettings) (Response, error) {
+	return s.UpdateSettings_(ctx, user, settings)
 }
 
-func (s MockUsersService) ListEmails(user UserSpec) ([]*EmailAddr, Response, error) {
-	return s.ListEmails_(user)
+func (s MockUsersService) ListEmails(ctx context.Context, user UserSpec) ([]*EmailAddr, Response, error) {
+	return s.ListEmails_(ctx, user)
 }
 
-func (s MockUsersService) GetOrCreateFromGitHub(user GitHubUserSpec, opt *UserGetOptions) (*User, Response, error) {
-	return s.GetOrCreateFromGitHub_(user, opt)
+func (s MockUsersService) GetOrCreateFromGitHub(ctx context.Context, user GitHubUserSpec, opt *UserGetOptions) (*User, Response, error) {
+	return s.GetOrCreateFromGitHub_(ctx, user, opt)
 }
 
-func (s MockUsersService) RefreshProfile(userSpec UserSpec) (Response, error) {
-	return s.RefreshProfile_(userSpec)
+func (s MockUsersService) RefreshProfile(ctx context.Context, userSpec UserSpec) (Response, error) {
+	return s.RefreshProfile_(ctx, userSpec)
 }
 
-func (s MockUsersService) ComputeStats(userSpec UserSpec) (Response, error) {
-	return s.ComputeStats_(userSpec)
+func (s MockUsersService) ComputeStats(ctx context.Context, userSpec UserSpec) (Response, error) {
+	return s.ComputeStats_(ctx, userSpec)
 }
 
-func (s MockUsersService) List(opt *UsersListOptions) ([]*User, Response, error) { return s.List_(opt) }
+func (s MockUsersService) List(ctx context.Context, opt *UsersListOptions) ([]*User, Response, error) {
+	return s.List_(ctx, opt)
+}
 
-func (s MockUsersService) ListAuthors(user UserSpec, opt *UsersListAuthorsOptions) ([]*AugmentedPersonUsageByClient, Response, error) {
-	return s.ListAuthors_(user, opt)
+func (s MockUsersService) ListAuthors(ctx context.Context, user UserSpec, opt *UsersListAuthorsOptions) ([]*AugmentedPersonUsageByClient, Response, error) {
+	return s.ListAuthors_(ctx, user, opt)
 }
 
-func (s MockUsersService) ListClients(user UserSpec, opt *UsersListClientsOptions) ([]*AugmentedPersonUsageOfAuthor, Response, error) {
-	return s.ListClients_(user, opt)
+func (s MockUsersService) ListClients(ctx context.Context, user UserSpec, opt *UsersListClientsOptions) ([]*AugmentedPersonUsageOfAuthor, Response, error) {
+	return s.ListClients_(ctx, user, opt)
 }
 
-func (s MockUsersService) ListOrgs(member UserSpec, opt *UsersListOrgsOptions) ([]*Org, Response, error) {
-	return s.ListOrgs_(member, opt)
+func (s MockUsersService) ListOrgs(ctx context.Context, member UserSpec, opt *UsersListOrgsOptions) ([]*Org, Response, error) {
+	return s.ListOrgs_(ctx, member, opt)
 }
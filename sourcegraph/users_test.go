@@ -1,6 +1,7 @@
 package sourcegraph
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"reflect"
@@ -59,7 +60,7 @@ func TestUsersService_Get(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	user_, _, err := client.Users.Get(UserSpec{Login: "a"}, nil)
+	user_, _, err := client.Users.Get(context.Background(), UserSpec{Login: "a"}, nil)
 	if err != nil {
 		t.Errorf("Users.Get returned error: %v", err)
 	}
@@ -88,7 +89,7 @@ func TestUsersService_GetSettings(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	settings, _, err := client.Users.GetSettings(UserSpec{Login: "a"})
+	settings, _, err := client.Users.GetSettings(context.Background(), UserSpec{Login: "a"})
 	if err != nil {
 		t.Errorf("Users.GetSettings returned error: %v", err)
 	}
@@ -103,7 +104,7 @@ func TestUsersService_GetSettings(t *testing.T) {
 
 	// Test failure.
 	expectErr := func(p UserSpec) {
-		_, _, err = client.Users.GetSettings(p)
+		_, _, err = client.Users.GetSettings(context.Background(), p)
 		if err == nil {
 			t.Error("Expected GetSettings to error for %v.", p)
 		}
@@ -126,7 +127,7 @@ func TestUsersService_UpdateSettings(t *testing.T) {
 		testBody(t, r, string(wantBody)+"\n")
 	})
 
-	_, err := client.Users.UpdateSettings(UserSpec{Login: "a"}, want)
+	_, err := client.Users.UpdateSettings(context.Background(), UserSpec{Login: "a"}, want)
 	if err != nil {
 		t.Errorf("Users.UpdateSettings returned error: %v", err)
 	}
@@ -150,7 +151,7 @@ func TestUsersService_ListEmails(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	emails, _, err := client.Users.ListEmails(UserSpec{Login: "a"})
+	emails, _, err := client.Users.ListEmails(context.Background(), UserSpec{Login: "a"})
 	if err != nil {
 		t.Errorf("Users.ListEmails returned error: %v", err)
 	}
@@ -178,7 +179,7 @@ func TestUsersService_GetOrCreateFromGitHub(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	user_, _, err := client.Users.GetOrCreateFromGitHub(GitHubUserSpec{Login: "a"}, nil)
+	user_, _, err := client.Users.GetOrCreateFromGitHub(context.Background(), GitHubUserSpec{Login: "a"}, nil)
 	if err != nil {
 		t.Errorf("Users.GetOrCreateFromGitHub returned error: %v", err)
 	}
@@ -202,7 +203,7 @@ func TestUsersService_RefreshProfile(t *testing.T) {
 		testMethod(t, r, "PUT")
 	})
 
-	_, err := client.Users.RefreshProfile(UserSpec{Login: "a"})
+	_, err := client.Users.RefreshProfile(context.Background(), UserSpec{Login: "a"})
 	if err != nil {
 		t.Errorf("Users.RefreshProfile returned error: %v", err)
 	}
@@ -222,7 +223,7 @@ func TestUsersService_ComputeStats(t *testing.T) {
 		testMethod(t, r, "PUT")
 	})
 
-	_, err := client.Users.ComputeStats(UserSpec{Login: "a"})
+	_, err := client.Users.ComputeStats(context.Background(), UserSpec{Login: "a"})
 	if err != nil {
 		t.Errorf("Users.ComputeStats returned error: %v", err)
 	}
@@ -253,7 +254,7 @@ func TestUsersService_List(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	users, _, err := client.Users.List(&UsersListOptions{
+	users, _, err := client.Users.List(context.Background(), &UsersListOptions{
 		Query:       "nl",
 		Sort:        "name",
 		Direction:   "asc",
@@ -286,7 +287,7 @@ func TestUsersService_ListAuthors(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	authors, _, err := client.Users.ListAuthors(UserSpec{Login: "a"}, nil)
+	authors, _, err := client.Users.ListAuthors(context.Background(), UserSpec{Login: "a"}, nil)
 	if err != nil {
 		t.Errorf("Users.ListAuthors returned error: %v", err)
 	}
@@ -314,7 +315,7 @@ func TestUsersService_ListClients(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	clients, _, err := client.Users.ListClients(UserSpec{Login: "a"}, nil)
+	clients, _, err := client.Users.ListClients(context.Background(), UserSpec{Login: "a"}, nil)
 	if err != nil {
 		t.Errorf("Users.ListClients returned error: %v", err)
 	}
@@ -342,7 +343,7 @@ func TestUsersService_ListOrgs(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	orgs, _, err := client.Users.ListOrgs(UserSpec{Login: "a"}, nil)
+	orgs, _, err := client.Users.ListOrgs(context.Background(), UserSpec{Login: "a"}, nil)
 	if err != nil {
 		t.Errorf("Users.ListOrgs returned error: %v", err)
 	}
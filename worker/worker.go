@@ -0,0 +1,338 @@
+// Package worker runs queued Sourcegraph builds.
+//
+// A Worker dequeues builds with Builds.DequeueNext and runs each one
+// through its lifecycle: it records the worker's host and sends
+// periodic heartbeats, creates the build's tasks (as planned by an
+// Executor) and executes them in order, and finally marks the tasks
+// and the build as succeeded or failed. If the server marks a running
+// build as killed or canceled (as reported in the responses to the
+// heartbeats), the worker stops executing it.
+//
+//	w := &worker.Worker{Builds: client.Builds, Executor: myExecutor}
+//	err := w.Run(ctx) // returns after ctx is canceled and running builds finish
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/sourcegraph"
+)
+
+// An Executor plans and executes the tasks of builds.
+type Executor interface {
+	// Tasks returns the tasks to perform for build. The worker creates
+	// them on the server (with Builds.CreateTasks) and then executes
+	// them one at a time, each after the tasks it depends on and
+	// otherwise in order of their Order field (see
+	// sourcegraph.SortTasks). Tasks may depend on each other using
+	// negative DependsOn entries (see sourcegraph.BuildTask). Tasks
+	// whose Queue field is set are performed by the server, not by the
+	// worker.
+	Tasks(ctx context.Context, build *sourcegraph.Build) ([]*sourcegraph.BuildTask, error)
+
+	// Execute performs a task of build. The task has its TaskID set.
+	// ctx is canceled if the build is killed or canceled or if the
+	// worker is shut down before the build finishes; Execute should
+	// return promptly when it is.
+	Execute(ctx context.Context, build *sourcegraph.Build, task *sourcegraph.BuildTask) error
+}
+
+// ErrKilled is the error that a build's run ends with when the server
+// marks the build as killed while it is running.
+var ErrKilled = errors.New("build was killed")
+
+// ErrCanceled is the error that a build's run ends with when the
+// build is canceled (with Builds.Cancel) while it is running.
+var ErrCanceled = errors.New("build was canceled")
+
+// A Worker dequeues and runs builds.
+type Worker struct {
+	// Builds is the builds API service used to dequeue and update
+	// builds (such as a sourcegraph.Client's Builds field).
+	Builds sourcegraph.BuildsService
+
+	// Executor plans and executes the builds' tasks.
+	Executor Executor
+
+	// Concurrency is the maximum number of builds that run at once. If
+	// zero, 1 is used.
+	Concurrency int
+
+	// Host is the host name that is recorded on builds that this
+	// worker runs. If empty, os.Hostname is used.
+	Host string
+
+	// PollInterval is how long to wait before dequeuing again when
+	// the queue is empty or dequeuing fails. If zero, 5 seconds is
+	// used.
+	PollInterval time.Duration
+
+	// HeartbeatInterval is how often the HeartbeatAt time of running
+	// builds is updated (which also detects builds that have been
+	// killed or canceled). If zero, 30 seconds is used.
+	HeartbeatInterval time.Duration
+
+	// ShutdownTimeout is how long running builds may continue after
+	// Run's context is canceled before their contexts are canceled
+	// too. If zero, running builds are waited for indefinitely.
+	ShutdownTimeout time.Duration
+
+	// Log, if non-nil, receives messages about builds and errors.
+	Log *log.Logger
+
+	// OnBuildDone, if non-nil, is called after a build's run ends,
+	// with the error it ended with (nil if it succeeded).
+	OnBuildDone func(build *sourcegraph.Build, err error)
+}
+
+// Run dequeues and runs builds until ctx is canceled. Then it stops
+// dequeuing, waits for running builds to finish (subject to
+// ShutdownTimeout), and returns nil.
+func (w *Worker) Run(ctx context.Context) error {
+	if w.Builds == nil || w.Executor == nil {
+		return errors.New("worker: Builds and Executor must be set")
+	}
+
+	// Builds run with a context that is not canceled when ctx is, so
+	// that they can finish after a graceful shutdown begins.
+	buildCtx, cancelBuilds := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelBuilds()
+	if w.ShutdownTimeout > 0 {
+		stop := context.AfterFunc(ctx, func() {
+			time.AfterFunc(w.ShutdownTimeout, cancelBuilds)
+		})
+		defer stop()
+	}
+
+	concurrency := w.Concurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w.loop(ctx, buildCtx)
+		}()
+	}
+	wg.Wait()
+	return nil
+}
+
+// loop dequeues and runs builds one at a time until ctx is canceled.
+func (w *Worker) loop(ctx, buildCtx context.Context) {
+	for ctx.Err() == nil {
+		build, _, err := w.Builds.DequeueNext(ctx)
+		if err != nil && ctx.Err() == nil {
+			w.logf("dequeuing build: %s", err)
+		}
+		if build == nil {
+			select {
+			case <-ctx.Done():
+			case <-time.After(w.pollInterval()):
+			}
+			continue
+		}
+
+		err = w.RunBuild(buildCtx, build)
+		if err != nil {
+			w.logf("build %s: %s", build.Spec().IDString(), err)
+		}
+		if w.OnBuildDone != nil {
+			w.OnBuildDone(build, err)
+		}
+	}
+}
+
+// RunBuild runs a build that has already been dequeued (or otherwise
+// assigned to this worker): it records the host, starts heartbeats,
+// creates and executes the build's tasks, and marks the build as
+// succeeded or failed. It returns ErrKilled or ErrCanceled if the
+// build is killed or canceled, and otherwise the error that failed the
+// build.
+func (w *Worker) RunBuild(ctx context.Context, build *sourcegraph.Build) error {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	now := time.Now()
+	host := w.host()
+	info := sourcegraph.BuildUpdate{Host: &host, HeartbeatAt: &now}
+	if !build.StartedAt.Valid {
+		info.StartedAt = &now
+	}
+	b, _, err := w.Builds.Update(ctx, build.Spec(), info)
+	if err != nil {
+		return fmt.Errorf("starting build: %s", err)
+	}
+	if b != nil && b.Canceled {
+		return ErrCanceled // canceled after it was dequeued
+	}
+
+	heartbeatDone := make(chan struct{})
+	go func() {
+		defer close(heartbeatDone)
+		w.heartbeat(ctx, build.Spec(), cancel)
+	}()
+
+	err = w.execute(ctx, build)
+	if cause := context.Cause(ctx); cause == ErrKilled || cause == ErrCanceled {
+		err = cause
+	}
+	cancel(nil)
+	<-heartbeatDone
+
+	if err == ErrKilled || err == ErrCanceled {
+		// The server already marked the build as ended.
+		return err
+	}
+
+	// Record the result even if ctx was canceled (because the worker
+	// is shutting down), so the build isn't left running.
+	updateCtx, cancelUpdate := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
+	defer cancelUpdate()
+	ended := time.Now()
+	success, failure := err == nil, err != nil
+	if _, _, uerr := w.Builds.Update(updateCtx, build.Spec(), sourcegraph.BuildUpdate{EndedAt: &ended, Success: &success, Failure: &failure}); uerr != nil {
+		if errors.Is(uerr, sourcegraph.ErrBuildEnded) {
+			// The server ended the build (by canceling or killing
+			// it) after the last heartbeat, so its result is not
+			// recorded.
+			return w.endedErr(updateCtx, build.Spec())
+		}
+		if err == nil {
+			err = fmt.Errorf("ending build: %s", uerr)
+		} else {
+			w.logf("build %s: ending build: %s", build.Spec().IDString(), uerr)
+		}
+	}
+	return err
+}
+
+// endedErr returns ErrKilled if the server killed the build, and
+// otherwise ErrCanceled. It is called when the server reports that the
+// build ended while it was running.
+func (w *Worker) endedErr(ctx context.Context, build sourcegraph.BuildSpec) error {
+	if b, _, err := w.Builds.Get(ctx, build, nil); err == nil && b.Killed {
+		return ErrKilled
+	}
+	return ErrCanceled
+}
+
+// execute creates the build's tasks and executes those that are not
+// queued, in dependency order, stopping at the first failure.
+func (w *Worker) execute(ctx context.Context, build *sourcegraph.Build) error {
+	tasks, err := w.Executor.Tasks(ctx, build)
+	if err != nil {
+		return fmt.Errorf("planning tasks: %s", err)
+	}
+	if len(tasks) == 0 {
+		return nil
+	}
+	tasks, _, err = w.Builds.CreateTasks(ctx, build.Spec(), tasks)
+	if err != nil {
+		return fmt.Errorf("creating tasks: %s", err)
+	}
+	tasks, err = sourcegraph.SortTasks(tasks)
+	if err != nil {
+		return fmt.Errorf("ordering tasks: %s", err)
+	}
+
+	for _, task := range tasks {
+		if task.Queue {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := w.executeTask(ctx, build, task); err != nil {
+			return fmt.Errorf("task %s (%s): %w", task.Spec().IDString(), task.Op, err)
+		}
+	}
+	return nil
+}
+
+// executeTask executes a task and records its start, end and result.
+func (w *Worker) executeTask(ctx context.Context, build *sourcegraph.Build, task *sourcegraph.BuildTask) error {
+	started := time.Now()
+	if _, _, err := w.Builds.UpdateTask(ctx, task.Spec(), sourcegraph.TaskUpdate{StartedAt: &started}); err != nil {
+		return fmt.Errorf("starting task: %s", err)
+	}
+
+	err := w.Executor.Execute(ctx, build, task)
+
+	updateCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
+	defer cancel()
+	ended := time.Now()
+	success, failure := err == nil, err != nil
+	if _, _, uerr := w.Builds.UpdateTask(updateCtx, task.Spec(), sourcegraph.TaskUpdate{EndedAt: &ended, Success: &success, Failure: &failure}); uerr != nil && err == nil {
+		err = fmt.Errorf("ending task: %s", uerr)
+	}
+	return err
+}
+
+// heartbeat updates the build's HeartbeatAt time periodically until
+// ctx is canceled. If the server reports that the build was killed or
+// canceled, it cancels the build's context with ErrKilled or
+// ErrCanceled.
+func (w *Worker) heartbeat(ctx context.Context, build sourcegraph.BuildSpec, cancel context.CancelCauseFunc) {
+	t := time.NewTicker(w.heartbeatInterval())
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+		now := time.Now()
+		b, _, err := w.Builds.Update(ctx, build, sourcegraph.BuildUpdate{HeartbeatAt: &now})
+		if err != nil {
+			if ctx.Err() == nil {
+				w.logf("build %s: heartbeat: %s", build.IDString(), err)
+			}
+			continue
+		}
+		if b != nil && b.Killed {
+			cancel(ErrKilled)
+			return
+		}
+		if b != nil && b.Canceled {
+			cancel(ErrCanceled)
+			return
+		}
+	}
+}
+
+func (w *Worker) host() string {
+	if w.Host != "" {
+		return w.Host
+	}
+	host, _ := os.Hostname()
+	return host
+}
+
+func (w *Worker) pollInterval() time.Duration {
+	if w.PollInterval > 0 {
+		return w.PollInterval
+	}
+	return 5 * time.Second
+}
+
+func (w *Worker) heartbeatInterval() time.Duration {
+	if w.HeartbeatInterval > 0 {
+		return w.HeartbeatInterval
+	}
+	return 30 * time.Second
+}
+
+func (w *Worker) logf(format string, args ...interface{}) {
+	if w.Log != nil {
+		w.Log.Printf(format, args...)
+	}
+}
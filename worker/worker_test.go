@@ -0,0 +1,241 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/fake"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/sourcegraph"
+)
+
+// testExecutor is an Executor that plans fixed tasks and records the
+// ops of the tasks it executes.
+type testExecutor struct {
+	tasks   []*sourcegraph.BuildTask
+	execute func(ctx context.Context, task *sourcegraph.BuildTask) error
+
+	mu       sync.Mutex
+	executed []string
+}
+
+func (e *testExecutor) Tasks(ctx context.Context, build *sourcegraph.Build) ([]*sourcegraph.BuildTask, error) {
+	return e.tasks, nil
+}
+
+func (e *testExecutor) Execute(ctx context.Context, build *sourcegraph.Build, task *sourcegraph.BuildTask) error {
+	e.mu.Lock()
+	e.executed = append(e.executed, task.Op)
+	e.mu.Unlock()
+	if e.execute != nil {
+		return e.execute(ctx, task)
+	}
+	return nil
+}
+
+func newQueuedBuild(t *testing.T) (*sourcegraph.Client, *sourcegraph.Build) {
+	client, store := fake.NewClient()
+	repo := store.AddRepo(&sourcegraph.Repo{URI: "github.com/o/r"}).RepoSpec()
+	build, _, err := client.Builds.Create(context.Background(), sourcegraph.RepoRevSpec{RepoSpec: repo, Rev: "c"}, &sourcegraph.BuildCreateOptions{BuildConfig: sourcegraph.BuildConfig{Queue: true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client, build
+}
+
+func TestWorker_Run(t *testing.T) {
+	client, build := newQueuedBuild(t)
+	exec := &testExecutor{tasks: []*sourcegraph.BuildTask{{Op: "a", Order: 2}, {Op: "b", Order: 1}, {Op: "import", Queue: true}}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	w := &Worker{
+		Builds:       client.Builds,
+		Executor:     exec,
+		Concurrency:  2,
+		Host:         "h",
+		PollInterval: time.Millisecond,
+		OnBuildDone: func(b *sourcegraph.Build, err error) {
+			done <- err
+			cancel()
+		},
+	}
+	if err := w.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"b", "a"}; !reflect.DeepEqual(exec.executed, want) {
+		t.Errorf("got executed tasks %v, want %v", exec.executed, want)
+	}
+	b, _, err := client.Builds.Get(context.Background(), build.Spec(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !b.Success || b.Failure || !b.EndedAt.Valid || !b.HeartbeatAt.Valid || b.Host != "h" {
+		t.Errorf("got build %+v, want successful build on host h", b)
+	}
+	tasks, _, err := client.Builds.ListBuildTasks(context.Background(), build.Spec(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, task := range tasks {
+		if task.Queue {
+			if task.StartedAt.Valid {
+				t.Errorf("queued task %+v was started by the worker", task)
+			}
+		} else if !task.Success || !task.StartedAt.Valid || !task.EndedAt.Valid {
+			t.Errorf("got task %+v, want successful task", task)
+		}
+	}
+}
+
+func TestWorker_RunBuild_deps(t *testing.T) {
+	client, build := newQueuedBuild(t)
+	exec := &testExecutor{tasks: []*sourcegraph.BuildTask{
+		{Op: "c", DependsOn: []int64{-2, -3}},
+		{Op: "a", Order: 2},
+		{Op: "b", Order: 1, DependsOn: []int64{-2}},
+	}}
+	w := &Worker{Builds: client.Builds, Executor: exec}
+	if err := w.RunBuild(context.Background(), build); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"a", "b", "c"}; !reflect.DeepEqual(exec.executed, want) {
+		t.Errorf("got executed tasks %v, want %v", exec.executed, want)
+	}
+}
+
+func TestWorker_RunBuild_existingTaskDeps(t *testing.T) {
+	client, build := newQueuedBuild(t)
+	existing, _, err := client.Builds.CreateTasks(context.Background(), build.Spec(), []*sourcegraph.BuildTask{{Op: "setup", Queue: true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	exec := &testExecutor{tasks: []*sourcegraph.BuildTask{
+		{Op: "b", DependsOn: []int64{existing[0].TaskID, -2}},
+		{Op: "a", DependsOn: []int64{existing[0].TaskID}},
+	}}
+	w := &Worker{Builds: client.Builds, Executor: exec}
+	if err := w.RunBuild(context.Background(), build); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"a", "b"}; !reflect.DeepEqual(exec.executed, want) {
+		t.Errorf("got executed tasks %v, want %v", exec.executed, want)
+	}
+}
+
+func TestWorker_RunBuild_failure(t *testing.T) {
+	client, build := newQueuedBuild(t)
+	exec := &testExecutor{
+		tasks:   []*sourcegraph.BuildTask{{Op: "a", Order: 1}, {Op: "b", Order: 2}},
+		execute: func(ctx context.Context, task *sourcegraph.BuildTask) error { return errors.New("x") },
+	}
+	w := &Worker{Builds: client.Builds, Executor: exec}
+	if err := w.RunBuild(context.Background(), build); err == nil {
+		t.Fatal("got nil error, want task failure")
+	}
+	if want := []string{"a"}; !reflect.DeepEqual(exec.executed, want) {
+		t.Errorf("got executed tasks %v, want %v (stopping at the first failure)", exec.executed, want)
+	}
+	b, _, _ := client.Builds.Get(context.Background(), build.Spec(), nil)
+	if b.Success || !b.Failure || !b.EndedAt.Valid {
+		t.Errorf("got build %+v, want failed build", b)
+	}
+}
+
+func TestWorker_RunBuild_killed(t *testing.T) {
+	client, build := newQueuedBuild(t)
+	exec := &testExecutor{
+		tasks: []*sourcegraph.BuildTask{{Op: "a"}},
+		execute: func(ctx context.Context, task *sourcegraph.BuildTask) error {
+			// Simulate the server killing the build.
+			killed, failure := true, true
+			if _, _, err := client.Builds.Update(ctx, build.Spec(), sourcegraph.BuildUpdate{Killed: &killed, Failure: &failure}); err != nil {
+				return err
+			}
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	}
+	w := &Worker{Builds: client.Builds, Executor: exec, HeartbeatInterval: time.Millisecond}
+	if err := w.RunBuild(context.Background(), build); err != ErrKilled {
+		t.Errorf("got error %v, want ErrKilled", err)
+	}
+}
+
+func TestWorker_RunBuild_canceled(t *testing.T) {
+	client, build := newQueuedBuild(t)
+	exec := &testExecutor{
+		tasks: []*sourcegraph.BuildTask{{Op: "a"}},
+		execute: func(ctx context.Context, task *sourcegraph.BuildTask) error {
+			if _, _, err := client.Builds.Cancel(ctx, build.Spec()); err != nil {
+				return err
+			}
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	}
+	w := &Worker{Builds: client.Builds, Executor: exec, HeartbeatInterval: time.Millisecond}
+	if err := w.RunBuild(context.Background(), build); err != ErrCanceled {
+		t.Errorf("got error %v, want ErrCanceled", err)
+	}
+	b, _, _ := client.Builds.Get(context.Background(), build.Spec(), nil)
+	if !b.Canceled || b.Failure || b.Success {
+		t.Errorf("got build %+v, want canceled build that neither succeeded nor failed", b)
+	}
+}
+
+func TestWorker_RunBuild_canceledBeforeResult(t *testing.T) {
+	client, build := newQueuedBuild(t)
+	// The build is canceled after its tasks finish but before the
+	// worker records its result (and there is no heartbeat in between).
+	exec := &testExecutor{
+		tasks: []*sourcegraph.BuildTask{{Op: "a"}},
+		execute: func(ctx context.Context, task *sourcegraph.BuildTask) error {
+			_, _, err := client.Builds.Cancel(ctx, build.Spec())
+			return err
+		},
+	}
+	w := &Worker{Builds: client.Builds, Executor: exec, HeartbeatInterval: time.Hour}
+	if err := w.RunBuild(context.Background(), build); err != ErrCanceled {
+		t.Errorf("got error %v, want ErrCanceled", err)
+	}
+	b, _, _ := client.Builds.Get(context.Background(), build.Spec(), nil)
+	if !b.Canceled || b.Failure || b.Success || b.Killed {
+		t.Errorf("got build %+v, want canceled build that neither succeeded nor failed", b)
+	}
+}
+
+func TestWorker_Run_shutdownTimeout(t *testing.T) {
+	client, build := newQueuedBuild(t)
+	started := make(chan struct{})
+	exec := &testExecutor{
+		tasks: []*sourcegraph.BuildTask{{Op: "a"}},
+		execute: func(ctx context.Context, task *sourcegraph.BuildTask) error {
+			close(started)
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-started
+		cancel()
+	}()
+	w := &Worker{Builds: client.Builds, Executor: exec, PollInterval: time.Millisecond, ShutdownTimeout: 10 * time.Millisecond}
+	if err := w.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	b, _, _ := client.Builds.Get(context.Background(), build.Spec(), nil)
+	if !b.Failure || !b.EndedAt.Valid {
+		t.Errorf("got build %+v, want failed build", b)
+	}
+}